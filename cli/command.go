@@ -61,6 +61,7 @@ Examples:
   show datacenter summary:       govc datacenter.info
   show all VMs:                  govc find -type m
   upload a ISO file:             govc datastore.upload -ds datastore1 ./config.iso vm-name/config.iso
+  switch to a saved context:     govc context.use vc1
 
 Common options:
   -h                        Show this message
@@ -71,6 +72,7 @@ Common options:
   -dump=false               Enable output dump
   -json=false               Enable JSON output
   -xml=false                Enable XML output
+  -o=                       Output format [GOVC_OUTPUT]
   -k=false                  Skip verification of server certificate [GOVC_INSECURE]
   -key=                     Private key [GOVC_PRIVATE_KEY]
   -persist-session=true     Persist session to disk [GOVC_PERSIST_SESSION]
@@ -190,6 +192,14 @@ func Run(args []string) int {
 		ctx = context.WithValue(ctx, types.ID{}, id)
 	}
 
+	// context.* commands do not apply the current context, such that they can be used to fix a stale or invalid one
+	if !strings.HasPrefix(name, "context.") {
+		if err = applyContext(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", os.Args[0], err)
+			return rc
+		}
+	}
+
 	cmd.Register(ctx, fs)
 
 	if err = fs.Parse(args[1:]); err != nil {
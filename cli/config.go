@@ -0,0 +1,218 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config is the govc configuration file, containing named contexts.
+// The file location defaults to $GOVMOMI_HOME/config.json and can be changed with GOVC_CONFIG.
+type Config struct {
+	Current  string                    `json:"current,omitempty"`
+	Contexts map[string]*ConfigContext `json:"contexts,omitempty"`
+
+	path string
+}
+
+// ConfigContext is a named set of connection parameters and defaults.
+// Each field corresponds to a GOVC_* environment variable, which takes precedence when set.
+type ConfigContext struct {
+	URL      string `json:"url,omitempty"`
+	Username string `json:"username,omitempty"`
+	// Password can be a path to a file containing the password, rather than the password itself.
+	Password     string `json:"password,omitempty"`
+	Insecure     bool   `json:"insecure,omitempty"`
+	Datacenter   string `json:"datacenter,omitempty"`
+	Datastore    string `json:"datastore,omitempty"`
+	Folder       string `json:"folder,omitempty"`
+	ResourcePool string `json:"resourcePool,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Network      string `json:"network,omitempty"`
+	TLSCACerts   string `json:"tlsCaCerts,omitempty"`
+	// Thumbprints maps host names to their TLS certificate thumbprint, as with GOVC_TLS_KNOWN_HOSTS.
+	Thumbprints map[string]string `json:"thumbprints,omitempty"`
+	Output      string            `json:"output,omitempty"`
+}
+
+// ContextEnv is the environment variable used to select a context, overriding Config.Current.
+const ContextEnv = "GOVC_CONTEXT"
+
+var currentContext *ConfigContext
+
+// ConfigDir returns the govmomi home directory, $GOVMOMI_HOME, defaulting to $HOME/.govmomi
+func ConfigDir() string {
+	if home := os.Getenv("GOVMOMI_HOME"); home != "" {
+		return home
+	}
+	return filepath.Join(os.Getenv("HOME"), ".govmomi")
+}
+
+// ConfigPath returns the path to the govc configuration file.
+func ConfigPath() string {
+	if path := os.Getenv("GOVC_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(ConfigDir(), "config.json")
+}
+
+// LoadConfig reads the configuration file, returning an empty Config if the file does not exist.
+func LoadConfig() (*Config, error) {
+	c := &Config{path: ConfigPath()}
+
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+
+	if err = json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%s: %s", c.path, err)
+	}
+
+	return c, nil
+}
+
+// Save writes the configuration file, which may contain credentials and is only readable by the owner.
+func (c *Config) Save() error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, append(b, '\n'), 0600)
+}
+
+// Names returns the sorted context names.
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CurrentName returns the name of the selected context: GOVC_CONTEXT if set, otherwise Config.Current.
+func (c *Config) CurrentName() string {
+	if name := os.Getenv(ContextEnv); name != "" {
+		return name
+	}
+	return c.Current
+}
+
+// Environ returns the environment variables for the context.
+func (c *ConfigContext) Environ() map[string]string {
+	env := map[string]string{
+		"GOVC_URL":           c.URL,
+		"GOVC_USERNAME":      c.Username,
+		"GOVC_PASSWORD":      c.Password,
+		"GOVC_DATACENTER":    c.Datacenter,
+		"GOVC_DATASTORE":     c.Datastore,
+		"GOVC_FOLDER":        c.Folder,
+		"GOVC_RESOURCE_POOL": c.ResourcePool,
+		"GOVC_HOST":          c.Host,
+		"GOVC_NETWORK":       c.Network,
+		"GOVC_TLS_CA_CERTS":  c.TLSCACerts,
+		"GOVC_OUTPUT":        c.Output,
+	}
+
+	if c.Insecure {
+		env["GOVC_INSECURE"] = strconv.FormatBool(c.Insecure)
+	}
+
+	for k, v := range env {
+		if v == "" {
+			delete(env, k)
+		}
+	}
+
+	return env
+}
+
+// CurrentContext returns the context applied to this process, if any.
+func CurrentContext() *ConfigContext {
+	return currentContext
+}
+
+// ValidateContextName returns an error if name cannot be used as a context name,
+// as the name is also used as the context's session cache directory name.
+func ValidateContextName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid context name %q", name)
+	}
+	return nil
+}
+
+func contextsDir() string {
+	return filepath.Join(ConfigDir(), "contexts")
+}
+
+// ContextSessionDir returns the session cache directory for the named context.
+func ContextSessionDir(name string) string {
+	return filepath.Join(contextsDir(), name)
+}
+
+// RemoveContextSessionDir removes the session cache directory for the named context.
+func RemoveContextSessionDir(name string) error {
+	if err := ValidateContextName(name); err != nil {
+		return err
+	}
+
+	dir := ContextSessionDir(name)
+	if filepath.Dir(dir) != filepath.Clean(contextsDir()) {
+		return fmt.Errorf("context %q session directory %s is outside of %s", name, dir, contextsDir())
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// applyContext sets the environment variables of the selected context, if any.
+// Variables already set in the environment are not changed, such that the environment
+// and then flags can override the context.
+func applyContext() error {
+	c, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	name := c.CurrentName()
+	if name == "" {
+		return nil
+	}
+
+	ctx, ok := c.Contexts[name]
+	if !ok {
+		if os.Getenv(ContextEnv) == "" {
+			return nil // current context was removed from the file
+		}
+		return errors.New(ContextEnv + ": context " + strconv.Quote(name) + " not found")
+	}
+
+	for k, v := range ctx.Environ() {
+		if _, ok := os.LookupEnv(k); !ok {
+			if err = os.Setenv(k, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	currentContext = ctx
+
+	return os.Setenv(ContextEnv, name)
+}
@@ -0,0 +1,179 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// unsetenv unsets the given variables, which are restored when the test completes
+func unsetenv(t *testing.T, keys ...string) {
+	for _, key := range keys {
+		t.Setenv(key, "")
+		if err := os.Unsetenv(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testConfigHome(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("GOVMOMI_HOME", dir)
+	unsetenv(t, "GOVC_CONFIG", ContextEnv, "GOVC_URL", "GOVC_DATACENTER", "GOVC_DATASTORE", "GOVC_INSECURE")
+	t.Cleanup(func() { currentContext = nil })
+	return dir
+}
+
+func TestConfigSave(t *testing.T) {
+	dir := testConfigHome(t)
+
+	c, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Contexts) != 0 {
+		t.Errorf("contexts=%v", c.Contexts)
+	}
+
+	c.Current = "vc1"
+	c.Contexts = map[string]*ConfigContext{
+		"vc1": {URL: "vc1.example.com", Insecure: true},
+		"vc2": {URL: "vc2.example.com", Thumbprints: map[string]string{"vc2.example.com": "AB:CD"}},
+	}
+	if err = c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode=%s", info.Mode())
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, config) {
+		t.Errorf("%#v != %#v", c, config)
+	}
+	if names := config.Names(); !reflect.DeepEqual(names, []string{"vc1", "vc2"}) {
+		t.Errorf("names=%v", names)
+	}
+
+	path := filepath.Join(t.TempDir(), "govc.json")
+	t.Setenv("GOVC_CONFIG", path)
+	if err = os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadConfig(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestApplyContext(t *testing.T) {
+	testConfigHome(t)
+
+	c, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no config file
+	if err = applyContext(); err != nil {
+		t.Fatal(err)
+	}
+	if CurrentContext() != nil {
+		t.Error("unexpected context")
+	}
+
+	c.Current = "vc1"
+	c.Contexts = map[string]*ConfigContext{
+		"vc1": {URL: "vc1.example.com", Datacenter: "DC1", Insecure: true},
+		"vc2": {URL: "vc2.example.com", Datacenter: "DC2"},
+	}
+	if err = c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the environment overrides the context
+	t.Setenv("GOVC_DATACENTER", "DC0")
+
+	if err = applyContext(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(CurrentContext(), c.Contexts["vc1"]) {
+		t.Errorf("context=%#v", CurrentContext())
+	}
+
+	expect := map[string]string{
+		ContextEnv:        "vc1",
+		"GOVC_URL":        "vc1.example.com",
+		"GOVC_DATACENTER": "DC0",
+		"GOVC_INSECURE":   "true",
+	}
+	for k, v := range expect {
+		if val := os.Getenv(k); val != v {
+			t.Errorf("%s=%q", k, val)
+		}
+	}
+
+	// GOVC_CONTEXT overrides the current context
+	unsetenv(t, "GOVC_URL")
+	t.Setenv(ContextEnv, "vc2")
+	if err = applyContext(); err != nil {
+		t.Fatal(err)
+	}
+	if val := os.Getenv("GOVC_URL"); val != "vc2.example.com" {
+		t.Errorf("GOVC_URL=%q", val)
+	}
+
+	t.Setenv(ContextEnv, "enoent")
+	if err = applyContext(); err == nil {
+		t.Error("expected error")
+	}
+
+	// the current context was removed from the file
+	unsetenv(t, ContextEnv)
+	c.Current = "enoent"
+	if err = c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err = applyContext(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRemoveContextSessionDir(t *testing.T) {
+	dir := testConfigHome(t)
+
+	for _, name := range []string{"", ".", "..", "../vc1", `a\b`} {
+		if err := ValidateContextName(name); err == nil {
+			t.Errorf("%q: expected error", name)
+		}
+		if err := RemoveContextSessionDir(name); err == nil {
+			t.Errorf("%q: expected error", name)
+		}
+	}
+
+	session := ContextSessionDir("vc1")
+	if filepath.Dir(session) != filepath.Join(dir, "contexts") {
+		t.Errorf("dir=%s", session)
+	}
+	if err := os.MkdirAll(session, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveContextSessionDir("vc1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(session); !os.IsNotExist(err) {
+		t.Errorf("err=%v", err)
+	}
+}
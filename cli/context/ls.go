@@ -0,0 +1,86 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package context
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+)
+
+type ls struct {
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("context.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *ls) Description() string {
+	return `List contexts.
+
+The current context is marked with '*'.
+Contexts are stored in $GOVMOMI_HOME/config.json, or the file specified by GOVC_CONFIG.
+
+Examples:
+  govc context.ls
+  govc context.ls -json`
+}
+
+type lsContext struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+	*cli.ConfigContext
+}
+
+type lsResult struct {
+	Contexts []lsContext `json:"contexts"`
+}
+
+func (r *lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "CURRENT\tNAME\tURL\tDATACENTER")
+
+	for _, c := range r.Contexts {
+		current := ""
+		if c.Current {
+			current = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", current, c.Name, c.URL, c.Datacenter)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	config, err := cli.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	current := config.CurrentName()
+	res := &lsResult{Contexts: []lsContext{}}
+
+	for _, name := range config.Names() {
+		c := *config.Contexts[name]
+		if c.Password != "" {
+			c.Password = "********"
+		}
+		res.Contexts = append(res.Contexts, lsContext{name, name == current, &c})
+	}
+
+	return cmd.WriteResult(res)
+}
@@ -0,0 +1,69 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package context
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+)
+
+type rm struct {
+	*flags.EmptyFlag
+}
+
+func init() {
+	cli.Register("context.rm", &rm{})
+}
+
+func (cmd *rm) Usage() string {
+	return "NAME..."
+}
+
+func (cmd *rm) Description() string {
+	return `Remove contexts and their cached sessions.
+
+Examples:
+  govc context.rm vc1 vc2`
+}
+
+func (cmd *rm) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	config, err := cli.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range f.Args() {
+		if _, ok := config.Contexts[name]; !ok {
+			return fmt.Errorf("context %q not found", name)
+		}
+
+		delete(config.Contexts, name)
+
+		if config.Current == name {
+			config.Current = ""
+		}
+	}
+
+	// remove the contexts from the config before their sessions, such that a failure leaves no dangling context
+	if err = config.Save(); err != nil {
+		return err
+	}
+
+	for _, name := range f.Args() {
+		if err = cli.RemoveContextSessionDir(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
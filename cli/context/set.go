@@ -0,0 +1,151 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package context
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+)
+
+type set struct {
+	*flags.EmptyFlag
+
+	cli.ConfigContext
+
+	use bool
+}
+
+func init() {
+	cli.Register("context.set", &set{})
+}
+
+type thumbprints map[string]string
+
+func (t *thumbprints) String() string {
+	return ""
+}
+
+func (t *thumbprints) Set(s string) error {
+	host, thumbprint, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("invalid thumbprint %q, expected HOST=THUMBPRINT", s)
+	}
+	if *t == nil {
+		*t = make(thumbprints)
+	}
+	(*t)[host] = thumbprint
+	return nil
+}
+
+func (cmd *set) Register(ctx context.Context, f *flag.FlagSet) {
+	f.StringVar(&cmd.URL, "u", "", "ESX or vCenter URL")
+	f.StringVar(&cmd.Username, "username", "", "Username")
+	f.StringVar(&cmd.Password, "password", "", "Password or path to a file containing the password")
+	f.BoolVar(&cmd.Insecure, "k", false, "Skip verification of server certificate")
+	f.StringVar(&cmd.Datacenter, "dc", "", "Default datacenter")
+	f.StringVar(&cmd.Datastore, "ds", "", "Default datastore")
+	f.StringVar(&cmd.Folder, "folder", "", "Default folder")
+	f.StringVar(&cmd.ResourcePool, "pool", "", "Default resource pool")
+	f.StringVar(&cmd.Host, "host", "", "Default host")
+	f.StringVar(&cmd.Network, "net", "", "Default network")
+	f.StringVar(&cmd.TLSCACerts, "tls-ca-certs", "", "TLS CA certificates file")
+	f.Var((*thumbprints)(&cmd.Thumbprints), "thumbprint", "TLS certificate thumbprint as HOST=THUMBPRINT, an empty THUMBPRINT removes HOST")
+	f.StringVar(&cmd.Output, "o", "", "Default output format")
+	f.BoolVar(&cmd.use, "use", false, "Set as the current context")
+}
+
+func (cmd *set) Usage() string {
+	return "NAME"
+}
+
+func (cmd *set) Description() string {
+	return `Create or update context NAME.
+
+Only the specified options are changed when NAME exists, an option can be cleared using an empty value.
+Each context has its own session cache, such that sessions persist when switching between contexts.
+
+Examples:
+  govc context.set -u vc1.example.com -username administrator@vsphere.local -password ~/.vc1-password -dc DC1 vc1
+  govc context.set -thumbprint vc1.example.com=AB:CD:...:EF vc1
+  govc context.set -ds "" -o table vc1
+  govc context.set -use vc1`
+}
+
+func (cmd *set) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	name := f.Arg(0)
+	if err := cli.ValidateContextName(name); err != nil {
+		return err
+	}
+
+	config, err := cli.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if config.Contexts == nil {
+		config.Contexts = make(map[string]*cli.ConfigContext)
+	}
+
+	c, ok := config.Contexts[name]
+	if !ok {
+		c = new(cli.ConfigContext)
+		config.Contexts[name] = c
+	}
+
+	f.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "u":
+			c.URL = cmd.URL
+		case "username":
+			c.Username = cmd.Username
+		case "password":
+			c.Password = cmd.Password
+		case "k":
+			c.Insecure = cmd.Insecure
+		case "dc":
+			c.Datacenter = cmd.Datacenter
+		case "ds":
+			c.Datastore = cmd.Datastore
+		case "folder":
+			c.Folder = cmd.Folder
+		case "pool":
+			c.ResourcePool = cmd.ResourcePool
+		case "host":
+			c.Host = cmd.Host
+		case "net":
+			c.Network = cmd.Network
+		case "tls-ca-certs":
+			c.TLSCACerts = cmd.TLSCACerts
+		case "thumbprint":
+			if c.Thumbprints == nil {
+				c.Thumbprints = make(map[string]string)
+			}
+			for host, thumbprint := range cmd.Thumbprints {
+				if thumbprint == "" {
+					delete(c.Thumbprints, host)
+				} else {
+					c.Thumbprints[host] = thumbprint
+				}
+			}
+		case "o":
+			c.Output = cmd.Output
+		}
+	})
+
+	if cmd.use || len(config.Contexts) == 1 {
+		config.Current = name
+	}
+
+	return config.Save()
+}
@@ -0,0 +1,58 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package context
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+)
+
+type use struct {
+	*flags.EmptyFlag
+}
+
+func init() {
+	cli.Register("context.use", &use{})
+}
+
+func (cmd *use) Usage() string {
+	return "NAME"
+}
+
+func (cmd *use) Description() string {
+	return `Set the current context to NAME.
+
+The GOVC_CONTEXT environment variable overrides the current context,
+and GOVC_* environment variables override the settings of a context.
+
+Examples:
+  govc context.use vc1
+  govc ls
+  GOVC_CONTEXT=vc2 govc ls`
+}
+
+func (cmd *use) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	config, err := cli.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	name := f.Arg(0)
+	if _, ok := config.Contexts[name]; !ok {
+		return fmt.Errorf("context %q not found", name)
+	}
+
+	config.Current = name
+
+	return config.Save()
+}
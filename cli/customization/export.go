@@ -0,0 +1,79 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package customization
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+)
+
+type export struct {
+	*flags.ClientFlag
+
+	format string
+}
+
+func init() {
+	cli.Register("customization.export", &export{})
+}
+
+func (cmd *export) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.format, "format", "json", "Export format (json|xml)")
+}
+
+func (cmd *export) Usage() string {
+	return "NAME [FILE]"
+}
+
+func (cmd *export) Description() string {
+	return `Export guest customization specification NAME.
+
+The specification is written to FILE, or to stdout if FILE is not specified or "-".
+The output can be imported using the customization.import command.
+
+Examples:
+  govc customization.export vcsim-linux
+  govc customization.export vcsim-linux vcsim-linux.json
+  govc customization.export -format xml vcsim-windows-static vcsim-windows-static.xml`
+}
+
+func (cmd *export) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() < 1 || f.NArg() > 2 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	data, err := guest.NewManager(c).ExportSpec(ctx, f.Arg(0), strings.ToUpper(cmd.format))
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasSuffix(data, "\n") {
+		data += "\n"
+	}
+
+	name := f.Arg(1)
+	if name == "" || name == "-" {
+		_, err = fmt.Print(data)
+		return err
+	}
+
+	return os.WriteFile(filepath.Clean(name), []byte(data), 0600)
+}
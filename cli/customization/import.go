@@ -0,0 +1,113 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package customization
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+)
+
+type importx struct {
+	*flags.ClientFlag
+
+	name        string
+	description string
+	overwrite   bool
+}
+
+func init() {
+	cli.Register("customization.import", &importx{})
+}
+
+func (cmd *importx) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.name, "name", "", "Specification name (defaults to the name in FILE)")
+	f.StringVar(&cmd.description, "description", "", "Specification description (defaults to the description in FILE)")
+	f.BoolVar(&cmd.overwrite, "overwrite", false, "Overwrite existing specification")
+}
+
+func (cmd *importx) Usage() string {
+	return "FILE"
+}
+
+func (cmd *importx) Description() string {
+	return `Import guest customization specification from FILE.
+
+FILE is in the JSON or XML format written by the customization.export command.
+If FILE name is "-", read the specification from stdin.
+
+Examples:
+  govc customization.import vcsim-linux.json
+  govc customization.import -name my-linux -overwrite vcsim-linux.json
+  govc customization.export vcsim-linux | govc customization.import -name vcsim-linux-copy -`
+}
+
+func (cmd *importx) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	var data []byte
+	var err error
+
+	name := f.Arg(0)
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filepath.Clean(name))
+	}
+	if err != nil {
+		return err
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := guest.NewManager(c)
+
+	spec, err := m.ImportSpec(ctx, string(data))
+	if err != nil {
+		return err
+	}
+
+	if cmd.name != "" {
+		spec.Name = cmd.name
+	}
+	if cmd.description != "" {
+		spec.Description = cmd.description
+	}
+
+	if cmd.overwrite {
+		specs, err := m.ListSpecs(ctx, guest.FilterSpec{Names: []string{spec.Name}})
+		if err != nil {
+			return err
+		}
+		if len(specs) == 1 {
+			info, err := m.GetSpec(ctx, spec.Name)
+			if err != nil {
+				return err
+			}
+			return m.SetSpec(ctx, spec.Name, guest.Spec{
+				Name:        spec.Name,
+				Description: spec.Description,
+				Fingerprint: info.Fingerprint,
+				Spec:        spec.Spec,
+			})
+		}
+	}
+
+	return m.CreateSpec(ctx, *spec)
+}
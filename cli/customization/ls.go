@@ -0,0 +1,89 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package customization
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+)
+
+type ls struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	os string
+}
+
+func init() {
+	cli.Register("customization.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.os, "os", "", "Filter by OS type (LINUX|WINDOWS)")
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *ls) Usage() string {
+	return "[NAME]..."
+}
+
+func (cmd *ls) Description() string {
+	return `List guest customization specifications.
+
+Examples:
+  govc customization.ls
+  govc customization.ls -os WINDOWS
+  govc customization.ls -json vcsim-linux`
+}
+
+type lsResult []guest.Summary
+
+func (r lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, spec := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			spec.Name, spec.OSType, spec.LastModified.Format(time.Stamp), spec.Description)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	specs, err := guest.NewManager(c).ListSpecs(ctx, guest.FilterSpec{
+		Names:  f.Args(),
+		OSType: cmd.os,
+	})
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(lsResult(specs))
+}
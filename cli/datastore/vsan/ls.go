@@ -29,11 +29,12 @@ func init() {
 }
 
 func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
-	cmd.DatastoreFlag, ctx = flags.NewDatastoreFlag(ctx)
-	cmd.DatastoreFlag.Register(ctx, f)
-
+	// Registered before DatastoreFlag, which would otherwise define '-o' as the output format
 	f.BoolVar(&cmd.long, "l", false, "Long listing")
 	f.BoolVar(&cmd.orphan, "o", false, "List orphan objects")
+
+	cmd.DatastoreFlag, ctx = flags.NewDatastoreFlag(ctx)
+	cmd.DatastoreFlag.Register(ctx, f)
 }
 
 func (cmd *ls) Process(ctx context.Context) error {
@@ -24,6 +24,8 @@ import (
 	"github.com/vmware/govmomi/cli/flags"
 	"github.com/vmware/govmomi/nfc"
 	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 )
@@ -39,6 +41,7 @@ type ovfx struct {
 	prefix   bool
 	sha      int
 	lease    bool
+	config   bool
 
 	mf bytes.Buffer
 }
@@ -64,6 +67,7 @@ func (cmd *ovfx) Register(ctx context.Context, f *flag.FlagSet) {
 	f.BoolVar(&cmd.prefix, "prefix", true, "Prepend target name to image filenames if missing")
 	f.IntVar(&cmd.sha, "sha", 0, "Generate manifest using SHA 1, 256, 512 or 0 to skip")
 	f.BoolVar(&cmd.lease, "lease", false, "Output NFC Lease only")
+	f.BoolVar(&cmd.config, "config", false, "Generate descriptor from VM config only, without exporting disks")
 }
 
 func (cmd *ovfx) Usage() string {
@@ -73,13 +77,19 @@ func (cmd *ovfx) Usage() string {
 func (cmd *ovfx) Description() string {
 	return `Export VM.
 
+With the '-config' flag, the OVF descriptor is generated client side from the VM config,
+without an NFC export lease or the server side OvfManager. Disks are described by capacity only.
+If DIR is not specified, the descriptor is written to stdout.
+
 Examples:
   govc export.ovf -vm $vm DIR
-  govc export.ovf -vm $vm -lease`
+  govc export.ovf -vm $vm -lease
+  govc export.ovf -vm $vm -config > $vm.ovf
+  govc export.ovf -vm $vm -config -json | jq .virtualSystem.virtualHardwareSection`
 }
 
 func (cmd *ovfx) Run(ctx context.Context, f *flag.FlagSet) error {
-	if f.NArg() != 1 && !cmd.lease {
+	if f.NArg() != 1 && !cmd.lease && !cmd.config {
 		// TODO: output summary similar to ovftool's
 		return flag.ErrHelp
 	}
@@ -107,6 +117,14 @@ func (cmd *ovfx) Run(ctx context.Context, f *flag.FlagSet) error {
 
 	target := filepath.Join(cmd.dest, cmd.name+".ovf")
 
+	if cmd.config && f.NArg() == 0 {
+		e, err := cmd.envelope(ctx, vm)
+		if err != nil {
+			return err
+		}
+		return cmd.WriteResult(e)
+	}
+
 	if !cmd.force {
 		if _, err = os.Stat(target); err == nil {
 			return fmt.Errorf("file already exists: %s", target)
@@ -117,6 +135,25 @@ func (cmd *ovfx) Run(ctx context.Context, f *flag.FlagSet) error {
 		return err
 	}
 
+	if cmd.config {
+		e, err := cmd.envelope(ctx, vm)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+
+		if err = e.Write(file); err != nil {
+			_ = file.Close()
+			return err
+		}
+
+		return file.Close()
+	}
+
 	lease, err := cmd.requestExport(ctx, vm)
 	if err != nil {
 		return err
@@ -210,6 +247,46 @@ func (cmd *ovfx) Run(ctx context.Context, f *flag.FlagSet) error {
 	return file.Close()
 }
 
+// envelope generates an OVF descriptor from the VM config,
+// using inventory names for distributed portgroup backed networks.
+func (cmd *ovfx) envelope(ctx context.Context, vm *object.VirtualMachine) (*ovf.Envelope, error) {
+	var props mo.VirtualMachine
+
+	err := vm.Properties(ctx, vm.Reference(), []string{"config", "network"}, &props)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ovf.FromVirtualMachineOptions{
+		Networks: make(map[string]string),
+	}
+
+	var refs []types.ManagedObjectReference
+	for _, ref := range props.Network {
+		if ref.Type == "DistributedVirtualPortgroup" {
+			refs = append(refs, ref)
+		}
+	}
+
+	if len(refs) != 0 {
+		var pgs []mo.DistributedVirtualPortgroup
+		pc := property.DefaultCollector(vm.Client())
+		if err = pc.Retrieve(ctx, refs, []string{"name", "key"}, &pgs); err != nil {
+			return nil, err
+		}
+
+		for _, pg := range pgs {
+			opts.Networks[pg.Key] = pg.Name
+		}
+	}
+
+	if props.Config != nil {
+		props.Config.Name = cmd.name
+	}
+
+	return ovf.FromVirtualMachineWithOptions(props, opts)
+}
+
 func (cmd *ovfx) requestExport(ctx context.Context, vm *object.VirtualMachine) (*nfc.Lease, error) {
 	if cmd.snapshot != "" {
 		snapRef, err := vm.FindSnapshot(ctx, cmd.snapshot)
@@ -18,6 +18,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/vmware/govmomi/cli"
 	"github.com/vmware/govmomi/cns"
 	"github.com/vmware/govmomi/pbm"
 	"github.com/vmware/govmomi/session"
@@ -199,6 +200,13 @@ func (flag *ClientFlag) Process(ctx context.Context) error {
 			flag.Session.Passthrough = true
 		}
 
+		if name := os.Getenv(cli.ContextEnv); name != "" && cli.CurrentContext() != nil {
+			// Each context has its own session cache
+			dir := cli.ContextSessionDir(name)
+			flag.Session.DirSOAP = filepath.Join(dir, "sessions")
+			flag.Session.DirREST = filepath.Join(dir, "rest_sessions")
+		}
+
 		flag.username, err = session.Secret(flag.username)
 		if err != nil {
 			return err
@@ -263,6 +271,12 @@ func (flag *ClientFlag) ConfigureTLS(sc *soap.Client) error {
 		return err
 	}
 
+	if c := cli.CurrentContext(); c != nil {
+		for host, thumbprint := range c.Thumbprints {
+			sc.SetThumbprint(host, thumbprint)
+		}
+	}
+
 	t := sc.DefaultTransport()
 	var err error
 
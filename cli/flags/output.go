@@ -40,6 +40,11 @@ type OutputFlag struct {
 	Out  io.Writer
 	Spec bool
 
+	// Format is the value of the '-o' flag
+	Format string
+
+	format       string
+	formatArg    string
 	formatError  bool
 	formatIndent bool
 }
@@ -61,6 +66,11 @@ func (flag *OutputFlag) Register(ctx context.Context, f *flag.FlagSet) {
 		f.BoolVar(&flag.JSON, "json", false, "Enable JSON output")
 		f.BoolVar(&flag.XML, "xml", false, "Enable XML output")
 		f.BoolVar(&flag.Dump, "dump", false, "Enable Go output")
+		if f.Lookup("o") == nil { // a few commands predate '-o' and define their own
+			env := "GOVC_OUTPUT"
+			usage := fmt.Sprintf("%s [%s]", formatUsage, env)
+			f.StringVar(&flag.Format, "o", os.Getenv(env), usage)
+		}
 		if cli.ShowUnreleased() {
 			f.BoolVar(&flag.Spec, "spec", false, "Output spec without sending request")
 		}
@@ -72,6 +82,12 @@ func (flag *OutputFlag) Register(ctx context.Context, f *flag.FlagSet) {
 
 func (flag *OutputFlag) Process(ctx context.Context) error {
 	return flag.ProcessOnce(func() error {
+		if flag.Format != "" {
+			if err := flag.parseFormat(); err != nil {
+				return err
+			}
+		}
+
 		if !flag.All() {
 			// Assume we have a tty if not outputting JSON
 			flag.TTY = true
@@ -114,7 +130,7 @@ func (flag *OutputFlag) WriteString(s string) (int, error) {
 }
 
 func (flag *OutputFlag) All() bool {
-	return flag.JSON || flag.XML || flag.Dump
+	return flag.JSON || flag.XML || flag.Dump || flag.format != ""
 }
 
 func dumpValue(val any) any {
@@ -195,6 +211,8 @@ func (flag *OutputFlag) WriteResult(result OutputWriter) error {
 		if err == nil {
 			fmt.Fprintln(flag.Out)
 		}
+	case flag.format != "":
+		err = flag.writeFormat(result)
 	default:
 		err = result.Write(flag.Out)
 	}
@@ -205,6 +223,9 @@ func (flag *OutputFlag) WriteResult(result OutputWriter) error {
 func (flag *OutputFlag) WriteError(err error) bool {
 	if flag.formatError {
 		flag.Out = os.Stderr
+		if flag.format != "" {
+			return errorOutput{err}.Write(flag.Out) == nil
+		}
 		return flag.WriteResult(&errorOutput{err}) == nil
 	}
 	return false
@@ -0,0 +1,419 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats supported by the '-o' flag, in addition to json and xml.
+// The table, csv, yaml, template and jsonpath formats operate on the JSON encoding of a result,
+// such that field names are the same as those of '-json' output.
+const (
+	formatTable    = "table"
+	formatCSV      = "csv"
+	formatYAML     = "yaml"
+	formatTemplate = "template"
+	formatJSONPath = "jsonpath"
+)
+
+const formatUsage = "Output format: json, xml, yaml, table[=COLUMNS], csv[=COLUMNS], template=TEMPLATE or jsonpath=EXPR"
+
+// parseFormat splits the '-o' flag value into format name and argument.
+func (flag *OutputFlag) parseFormat() error {
+	name, arg, _ := strings.Cut(flag.Format, "=")
+
+	switch name {
+	case "json":
+		flag.JSON = true
+		return nil
+	case "xml":
+		flag.XML = true
+		return nil
+	case formatTable, formatCSV, formatYAML:
+	case formatTemplate, formatJSONPath:
+		if arg == "" {
+			return fmt.Errorf("-o %s requires an argument, for example: -o %s=%s", name, name, formatExample[name])
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q (%s)", flag.Format, formatUsage)
+	}
+
+	flag.format, flag.formatArg = name, arg
+
+	return nil
+}
+
+var formatExample = map[string]string{
+	formatTemplate: "'{{.name}}'",
+	formatJSONPath: "'$.items[*].name'",
+}
+
+// writeFormat writes the result using one of the generic formats.
+func (flag *OutputFlag) writeFormat(result OutputWriter) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	var val any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err = dec.Decode(&val); err != nil {
+		return err
+	}
+
+	switch flag.format {
+	case formatYAML:
+		return flag.writeYAML(raw)
+	case formatTemplate:
+		t, err := template.New("output").Funcs(formatFuncs).Parse(flag.formatArg)
+		if err != nil {
+			return err
+		}
+		if err = t.Execute(flag.Out, val); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(flag.Out)
+		return err
+	case formatJSONPath:
+		vals, err := jsonPath(val, flag.formatArg)
+		if err != nil {
+			return err
+		}
+		for _, v := range vals {
+			if _, err = fmt.Fprintln(flag.Out, formatCell(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return flag.writeTable(raw, val)
+	}
+}
+
+var formatFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": func(sep string, v []any) string {
+		s := make([]string, len(v))
+		for i := range v {
+			s[i] = formatCell(v[i])
+		}
+		return strings.Join(s, sep)
+	},
+}
+
+// writeYAML converts the JSON encoding to YAML, retaining field order.
+func (flag *OutputFlag) writeYAML(raw []byte) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return err
+	}
+	yamlStyle(&node)
+
+	e := yaml.NewEncoder(flag.Out)
+	e.SetIndent(2)
+	if err := e.Encode(&node); err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+// yamlStyle resets the JSON (flow and double quoted) style of nodes to the default block style.
+func yamlStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		yamlStyle(n)
+	}
+}
+
+// tableRows returns the rows of a result: the elements of an array,
+// the elements of the only field of an object when that field is an array (the common govc 'infoResult' case),
+// or the object itself as a single row.
+func tableRows(raw []byte, val any) ([]any, json.RawMessage) {
+	switch v := val.(type) {
+	case []any:
+		var elems []json.RawMessage
+		_ = json.Unmarshal(raw, &elems)
+		if len(elems) != 0 {
+			return v, elems[0]
+		}
+		return v, nil
+	case map[string]any:
+		if len(v) == 1 {
+			for key, field := range v {
+				if rows, ok := field.([]any); ok {
+					var fields map[string]json.RawMessage
+					_ = json.Unmarshal(raw, &fields)
+					return tableRows(fields[key], rows)
+				}
+				if field == nil {
+					return nil, nil
+				}
+			}
+		}
+		return []any{v}, raw
+	case nil:
+		return nil, nil
+	default:
+		return []any{v}, nil
+	}
+}
+
+// tableColumns returns the keys of the non-null scalar fields of a JSON object, in encoded order.
+func tableColumns(raw json.RawMessage) []string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(raw, &fields) != nil {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil { // '{'
+		return nil
+	}
+
+	var keys []string
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			break
+		}
+		key := t.(string)
+
+		var skip json.RawMessage
+		if err = dec.Decode(&skip); err != nil {
+			break
+		}
+
+		switch bytes.TrimSpace(fields[key])[0] {
+		case '{', '[', 'n':
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (flag *OutputFlag) writeTable(raw []byte, val any) error {
+	rows, first := tableRows(raw, val)
+
+	var columns []string
+	if flag.formatArg != "" {
+		columns = strings.Split(flag.formatArg, ",")
+	} else if first != nil {
+		columns = tableColumns(first)
+	}
+
+	if len(columns) == 0 {
+		if len(rows) != 0 {
+			if _, ok := rows[0].(map[string]any); ok {
+				return errors.New("no columns to display, specify columns with -o table=COLUMN,...")
+			}
+		}
+		columns = []string{"$"}
+	}
+
+	records := [][]string{columns}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, c := range columns {
+			if c == "$" {
+				record[i] = formatCell(row)
+				continue
+			}
+			vals, err := jsonPath(row, c)
+			if err != nil {
+				return err
+			}
+			cells := make([]string, len(vals))
+			for j := range vals {
+				cells[j] = formatCell(vals[j])
+			}
+			record[i] = strings.Join(cells, ",")
+		}
+		records = append(records, record)
+	}
+
+	if flag.format == formatCSV {
+		w := csv.NewWriter(flag.Out)
+		if err := w.WriteAll(records); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+
+	tw := tabwriter.NewWriter(flag.Out, 2, 0, 2, ' ', 0)
+	for i, record := range records {
+		if i == 0 {
+			for j := range record {
+				record[j] = strings.ToUpper(record[j])
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(record, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatCell formats a scalar as-is and encodes any other value as JSON.
+func formatCell(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// jsonPath evaluates a JSONPath expression against val, supporting the subset:
+// '$' (optional), '.field', '["field"]', '[index]', '[*]', '.*' and '..field' (recursive descent).
+// A kubectl-style '{expr}' wrapper is also accepted.
+func jsonPath(val any, expr string) ([]any, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = expr[1 : len(expr)-1]
+	}
+	expr = strings.TrimPrefix(expr, "$")
+	if expr != "" && expr[0] != '.' && expr[0] != '[' {
+		expr = "." + expr // allow "a.b" as shorthand for "$.a.b"
+	}
+
+	vals := []any{val}
+
+	for expr != "" {
+		var (
+			next      []any
+			recursive bool
+		)
+
+		switch {
+		case strings.HasPrefix(expr, ".."):
+			recursive = true
+			expr = expr[2:]
+		case expr[0] == '.':
+			expr = expr[1:]
+		}
+
+		if recursive {
+			var all []any
+			for _, v := range vals {
+				all = append(all, descendants(v)...)
+			}
+			vals = all
+		}
+
+		var sel string
+		switch {
+		case expr == "":
+			return nil, errors.New("jsonpath: unexpected end of expression")
+		case expr[0] == '[':
+			end := strings.IndexByte(expr, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: missing ']' in %q", expr)
+			}
+			sel, expr = expr[1:end], expr[end+1:]
+			sel = strings.TrimSpace(sel)
+			if s, err := strconv.Unquote(sel); err == nil {
+				sel = s // ["field"]
+			} else if strings.HasPrefix(sel, "'") && strings.HasSuffix(sel, "'") && len(sel) > 1 {
+				sel = sel[1 : len(sel)-1] // ['field']
+			} else if sel != "*" {
+				i, err := strconv.Atoi(sel)
+				if err != nil {
+					return nil, fmt.Errorf("jsonpath: invalid index %q", sel)
+				}
+				for _, v := range vals {
+					if a, ok := v.([]any); ok {
+						j := i
+						if j < 0 {
+							j += len(a)
+						}
+						if j >= 0 && j < len(a) {
+							next = append(next, a[j])
+						}
+					}
+				}
+				vals = next
+				continue
+			}
+		default:
+			end := strings.IndexAny(expr, ".[")
+			if end < 0 {
+				end = len(expr)
+			}
+			sel, expr = expr[:end], expr[end:]
+		}
+
+		for _, v := range vals {
+			switch x := v.(type) {
+			case map[string]any:
+				if sel == "*" {
+					keys := make([]string, 0, len(x))
+					for k := range x {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						next = append(next, x[k])
+					}
+				} else if f, ok := x[sel]; ok {
+					next = append(next, f)
+				}
+			case []any:
+				if sel == "*" {
+					next = append(next, x...)
+				}
+			}
+		}
+
+		vals = next
+	}
+
+	return vals, nil
+}
+
+// descendants returns val and all values nested within it.
+func descendants(val any) []any {
+	all := []any{val}
+
+	switch x := val.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			all = append(all, descendants(x[k])...)
+		}
+	case []any:
+		for _, v := range x {
+			all = append(all, descendants(v)...)
+		}
+	}
+
+	return all
+}
@@ -0,0 +1,134 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package flags
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+type formatVM struct {
+	Name   string   `json:"name"`
+	CPU    int      `json:"cpu"`
+	On     bool     `json:"on"`
+	Tags   []string `json:"tags,omitempty"`
+	Config struct {
+		UUID string `json:"uuid"`
+	} `json:"config"`
+}
+
+type formatResult struct {
+	VirtualMachines []formatVM `json:"virtualMachines"`
+}
+
+func (*formatResult) Write(io.Writer) error {
+	return nil
+}
+
+func testFormatResult() *formatResult {
+	r := &formatResult{
+		VirtualMachines: []formatVM{
+			{Name: "vm-a", CPU: 2, On: true, Tags: []string{"x", "y"}},
+			{Name: "vm, b", CPU: 1},
+		},
+	}
+	r.VirtualMachines[0].Config.UUID = "1234"
+	return r
+}
+
+func TestOutputFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", "NAME   CPU  ON\nvm-a   2    true\nvm, b  1    false\n"},
+		{"table=name,config.uuid", "NAME   CONFIG.UUID\nvm-a   1234\nvm, b  \n"},
+		{"csv", "name,cpu,on\nvm-a,2,true\n\"vm, b\",1,false\n"},
+		{"csv=name,tags", "name,tags\nvm-a,\"[\"\"x\"\",\"\"y\"\"]\"\n\"vm, b\",\n"},
+		{"yaml", "virtualMachines:\n  - name: vm-a\n    cpu: 2\n    on: true\n    tags:\n      - x\n      - y\n    config:\n      uuid: \"1234\"\n  - name: vm, b\n    cpu: 1\n    on: false\n    config:\n      uuid: \"\"\n"},
+		{"template={{range .virtualMachines}}{{.name}}={{.cpu}};{{end}}", "vm-a=2;vm, b=1;\n"},
+		{"jsonpath=$.virtualMachines[*].name", "vm-a\nvm, b\n"},
+		{"jsonpath={.virtualMachines[0].tags[-1]}", "y\n"},
+		{"jsonpath=$..uuid", "1234\n\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			flag := &OutputFlag{Out: &buf, Format: test.format}
+
+			if err := flag.parseFormat(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := flag.WriteResult(testFormatResult()); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != test.want {
+				t.Errorf("got:\n%q\nwant:\n%q", buf.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestOutputFormatInvalid(t *testing.T) {
+	for _, format := range []string{"html", "template", "jsonpath="} {
+		flag := &OutputFlag{Format: format}
+		if err := flag.parseFormat(); err == nil {
+			t.Errorf("%s: expected error", format)
+		}
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	var val any = map[string]any{
+		"a": []any{
+			map[string]any{"b": "one", "x": []any{"p", "q", "r"}},
+			map[string]any{"b": "two", "c": map[string]any{"b": "three"}, "x": []any{"s"}},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"$.a[0].b", []string{"one"}},
+		{"a[1].b", []string{"two"}},
+		{`$["a"][*].b`, []string{"one", "two"}},
+		{"$..b", []string{"one", "two", "three"}},
+		{"$.a[5].b", nil},
+		{"$.a[*].x[0]", []string{"p", "s"}},
+		{"$.a[*].x[-1]", []string{"r", "s"}},
+		{"$.a[*].x[-3]", []string{"p"}},
+	}
+
+	for _, test := range tests {
+		vals, err := jsonPath(val, test.expr)
+		if err != nil {
+			t.Fatalf("%s: %s", test.expr, err)
+		}
+
+		var got []string
+		for _, v := range vals {
+			got = append(got, formatCell(v))
+		}
+
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.expr, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: got %v, want %v", test.expr, got, test.want)
+			}
+		}
+	}
+
+	if _, err := jsonPath(val, "$.a[x]"); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -9,21 +9,46 @@ import (
 	"flag"
 
 	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/ovf"
 	"github.com/vmware/govmomi/ovf/importer"
 )
 
 type ova struct {
 	*ovfx
+
+	stream  bool
+	retries int
 }
 
 func init() {
-	cli.Register("import.ova", &ova{&ovfx{}})
+	cli.Register("import.ova", &ova{ovfx: &ovfx{}})
+}
+
+func (cmd *ova) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ovfx.Register(ctx, f)
+
+	f.BoolVar(&cmd.stream, "stream", false, "Read the OVA in a single pass, resuming interrupted remote downloads")
+	f.IntVar(&cmd.retries, "retries", 5, "Number of times to resume an interrupted remote download with '-stream'")
 }
 
 func (cmd *ova) Usage() string {
 	return "PATH_TO_OVA"
 }
 
+func (cmd *ova) Description() string {
+	return `Import OVA from PATH_TO_OVA.
+
+PATH_TO_OVA can be a local file or an http(s) URL.
+By default, the archive is opened once per file it contains.
+With the '-stream' flag, the archive is read sequentially only once and each disk
+is uploaded as it is encountered, without staging remote files on local disk.
+
+Examples:
+  govc import.ova -ds datastore1 ./ttylinux.ova
+  govc import.ova -stream https://example.com/appliance.ova`
+}
+
 func (cmd *ova) Run(ctx context.Context, f *flag.FlagSet) error {
 	fpath, err := cmd.Prepare(f)
 	if err != nil {
@@ -34,7 +59,33 @@ func (cmd *ova) Run(ctx context.Context, f *flag.FlagSet) error {
 	archive.Client = cmd.Importer.Client
 
 	cmd.Importer.Archive = archive
-	fpath = "*.ovf"
 
-	return cmd.Import(ctx, fpath)
+	if cmd.stream && !cmd.lease {
+		return cmd.Stream(ctx, fpath)
+	}
+
+	return cmd.Import(ctx, "*.ovf")
+}
+
+func (cmd *ova) Stream(ctx context.Context, fpath string) error {
+	if cmd.net != "" {
+		// map networks using the descriptor read by ImportStream, rather than opening the archive twice
+		cmd.Importer.Descriptor = func(e *ovf.Envelope, opts *importer.Options) error {
+			if len(opts.NetworkMapping) == 0 {
+				opts.NetworkMapping = importer.NetworkMapping(e)
+			}
+			cmd.setNetwork(opts.NetworkMapping)
+			return nil
+		}
+	}
+
+	cmd.Importer.Retries = cmd.retries
+
+	moref, err := cmd.Importer.ImportStream(ctx, fpath, cmd.Options)
+	if err != nil {
+		return err
+	}
+
+	vm := object.NewVirtualMachine(cmd.Importer.Client, *moref)
+	return cmd.Deploy(vm, cmd.OutputFlag)
 }
@@ -111,17 +111,8 @@ func (cmd *ovfx) Run(ctx context.Context, f *flag.FlagSet) error {
 
 func (cmd *ovfx) Import(ctx context.Context, fpath string) error {
 	if cmd.net != "" {
-		if len(cmd.Options.NetworkMapping) == 0 {
-			env, err := importer.Spec(fpath, cmd.Importer.Archive, false, false)
-			if err != nil {
-				return err
-			}
-
-			cmd.Options.NetworkMapping = env.NetworkMapping
-		}
-
-		for i := range cmd.Options.NetworkMapping {
-			cmd.Options.NetworkMapping[i].Network = cmd.net
+		if err := cmd.mapNetwork(fpath); err != nil {
+			return err
 		}
 	}
 
@@ -148,6 +139,28 @@ func (cmd *ovfx) Import(ctx context.Context, fpath string) error {
 	return cmd.Deploy(vm, cmd.OutputFlag)
 }
 
+func (cmd *ovfx) mapNetwork(fpath string) error {
+	if len(cmd.Options.NetworkMapping) == 0 {
+		env, err := importer.Spec(fpath, cmd.Importer.Archive, false, false)
+		if err != nil {
+			return err
+		}
+
+		cmd.Options.NetworkMapping = env.NetworkMapping
+	}
+
+	cmd.setNetwork(cmd.Options.NetworkMapping)
+
+	return nil
+}
+
+// setNetwork maps each of the given OVF networks to the '-net' flag value
+func (cmd *ovfx) setNetwork(mapping []importer.Network) {
+	for i := range mapping {
+		mapping[i].Network = cmd.net
+	}
+}
+
 func (cmd *ovfx) Prepare(f *flag.FlagSet) (string, error) {
 	var err error
 
@@ -52,12 +52,14 @@ func init() {
 }
 
 func (cmd *collect) Register(ctx context.Context, f *flag.FlagSet) {
+	// Registered before DatacenterFlag, which would otherwise define '-o' as the output format
+	f.BoolVar(&cmd.object, "o", false, "Output the structure of a single Managed Object")
+
 	cmd.DatacenterFlag, ctx = flags.NewDatacenterFlag(ctx)
 	cmd.DatacenterFlag.Register(ctx, f)
 
 	f.BoolVar(&cmd.simple, "s", false, "Output property value only")
 	f.StringVar(&cmd.delim, "d", ",", "Delimiter for array values")
-	f.BoolVar(&cmd.object, "o", false, "Output the structure of a single Managed Object")
 	f.BoolVar(&cmd.dump, "O", false, "Output the CreateFilter request itself")
 	f.StringVar(&cmd.raw, "R", "", "Raw XML encoded CreateFilter request")
 	f.IntVar(&cmd.n, "n", 0, "Wait for N property updates")
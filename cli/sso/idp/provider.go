@@ -0,0 +1,156 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package idp
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+)
+
+// claimFlag parses repeated CLAIM:VALUE=GROUP[,GROUP...] mappings
+type claimFlag identity.ClaimMap
+
+func (c *claimFlag) String() string {
+	return ""
+}
+
+func (c *claimFlag) Set(val string) error {
+	claim, mapping, ok := strings.Cut(val, ":")
+	value, groups, ok2 := strings.Cut(mapping, "=")
+	if !ok || !ok2 || claim == "" || value == "" || groups == "" {
+		return fmt.Errorf("invalid claim mapping %q, expected CLAIM:VALUE=GROUP[,GROUP]", val)
+	}
+	if *c == nil {
+		*c = make(claimFlag)
+	}
+	if (*c)[claim] == nil {
+		(*c)[claim] = make(map[string][]string)
+	}
+	(*c)[claim][value] = append((*c)[claim][value], strings.Split(groups, ",")...)
+	return nil
+}
+
+// providerFlag contains the identity provider settings common to create and update
+type providerFlag struct {
+	name        string
+	clientID    string
+	secret      string
+	discovery   string
+	logout      string
+	auth        string
+	token       string
+	keys        string
+	issuer      string
+	method      string
+	claims      claimFlag
+	domains     []string
+	upnClaim    string
+	groupsClaim string
+	protocol    string
+	endpoints   []string
+	isDefault   bool
+}
+
+func (p *providerFlag) Register(f *flag.FlagSet) {
+	f.StringVar(&p.name, "name", "", "Provider name")
+	f.StringVar(&p.clientID, "client-id", "", "Client ID registered with the provider")
+	f.StringVar(&p.secret, "client-secret", "", "Client secret registered with the provider")
+	f.StringVar(&p.discovery, "discovery", "", "OIDC discovery endpoint URL")
+	f.StringVar(&p.logout, "logout", "", "OIDC logout endpoint URL")
+	f.StringVar(&p.auth, "auth-endpoint", "", "Oauth2 authentication endpoint URL")
+	f.StringVar(&p.token, "token-endpoint", "", "Oauth2 token endpoint URL")
+	f.StringVar(&p.keys, "public-key-uri", "", "Oauth2 public key (JWKS) URL")
+	f.StringVar(&p.issuer, "issuer", "", "Oauth2 token issuer")
+	f.StringVar(&p.method, "auth-method", "", "Oauth2 client authentication method")
+	f.Var(&p.claims, "claim", "Map token CLAIM:VALUE=GROUP[,GROUP]")
+	f.Var((*flags.StringList)(&p.domains), "domain", "Domain name of users authenticated by the provider")
+	f.StringVar(&p.upnClaim, "upn-claim", "", "Token claim used as the user principal name")
+	f.StringVar(&p.groupsClaim, "groups-claim", "", "Token claim containing group membership")
+	f.StringVar(&p.protocol, "idm-protocol", "", "Protocol used to query users and groups (REST|SCIM|SCIM2_0|LDAP)")
+	f.Var((*flags.StringList)(&p.endpoints), "idm-endpoint", "Endpoint used to query users and groups")
+	f.BoolVar(&p.isDefault, "default", false, "Make this the default provider")
+}
+
+func (p *providerFlag) createSpec(tag string) identity.CreateSpec {
+	spec := identity.CreateSpec{
+		ConfigTag:    tag,
+		Name:         p.name,
+		IsDefault:    p.isDefault,
+		DomainNames:  p.domains,
+		IdmProtocol:  p.protocol,
+		IdmEndpoints: p.endpoints,
+		UpnClaim:     p.upnClaim,
+		GroupsClaim:  p.groupsClaim,
+	}
+
+	switch tag {
+	case identity.ConfigOidc:
+		spec.Oidc = &identity.OidcCreateSpec{
+			DiscoveryEndpoint: p.discovery,
+			LogoutEndpoint:    p.logout,
+			ClientID:          p.clientID,
+			ClientSecret:      p.secret,
+			ClaimMap:          identity.ClaimMap(p.claims),
+		}
+	case identity.ConfigOauth2:
+		method := p.method
+		if method == "" {
+			method = identity.ClientSecretBasic
+		}
+		spec.Oauth2 = &identity.Oauth2CreateSpec{
+			AuthEndpoint:         p.auth,
+			TokenEndpoint:        p.token,
+			PublicKeyURI:         p.keys,
+			ClientID:             p.clientID,
+			ClientSecret:         p.secret,
+			ClaimMap:             identity.ClaimMap(p.claims),
+			Issuer:               p.issuer,
+			AuthenticationMethod: method,
+		}
+	}
+
+	return spec
+}
+
+func (p *providerFlag) updateSpec(tag string) identity.UpdateSpec {
+	spec := identity.UpdateSpec{
+		ConfigTag:    tag,
+		Name:         p.name,
+		MakeDefault:  p.isDefault,
+		DomainNames:  p.domains,
+		IdmProtocol:  p.protocol,
+		IdmEndpoints: p.endpoints,
+		UpnClaim:     p.upnClaim,
+		GroupsClaim:  p.groupsClaim,
+	}
+
+	switch tag {
+	case identity.ConfigOidc:
+		spec.Oidc = &identity.OidcUpdateSpec{
+			DiscoveryEndpoint: p.discovery,
+			LogoutEndpoint:    p.logout,
+			ClientID:          p.clientID,
+			ClientSecret:      p.secret,
+			ClaimMap:          identity.ClaimMap(p.claims),
+		}
+	case identity.ConfigOauth2:
+		spec.Oauth2 = &identity.Oauth2UpdateSpec{
+			AuthEndpoint:         p.auth,
+			TokenEndpoint:        p.token,
+			PublicKeyURI:         p.keys,
+			ClientID:             p.clientID,
+			ClientSecret:         p.secret,
+			ClaimMap:             identity.ClaimMap(p.claims),
+			Issuer:               p.issuer,
+			AuthenticationMethod: p.method,
+		}
+	}
+
+	return spec
+}
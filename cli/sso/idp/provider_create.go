@@ -0,0 +1,76 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package idp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+)
+
+type providerCreate struct {
+	*flags.ClientFlag
+
+	provider providerFlag
+	oauth2   bool
+}
+
+func init() {
+	cli.Register("sso.idp.provider.create", &providerCreate{})
+}
+
+func (cmd *providerCreate) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.provider.Register(f)
+
+	f.BoolVar(&cmd.oauth2, "oauth2", false, "Create an Oauth2 provider (defaults to OIDC)")
+}
+
+func (cmd *providerCreate) Description() string {
+	return `Create a federated identity provider.
+
+An OIDC provider is configured using its '-discovery' endpoint.
+An Oauth2 provider, such as ADFS, requires the '-oauth2' flag along with its endpoints.
+Token claims are mapped to vCenter groups using the '-claim' flag.
+The provider ID is written to stdout.
+
+Examples:
+  govc sso.idp.provider.create -name okta -client-id vcenter -client-secret secret \
+    -discovery https://example.okta.com/.well-known/openid-configuration \
+    -domain example.com -upn-claim email -groups-claim groups \
+    -claim groups:vc-admins=Administrators@vsphere.local -default
+  govc sso.idp.provider.create -oauth2 -name adfs -client-id vcenter -client-secret secret \
+    -auth-endpoint https://adfs.example.com/adfs/oauth2/authorize \
+    -token-endpoint https://adfs.example.com/adfs/oauth2/token \
+    -public-key-uri https://adfs.example.com/adfs/discovery/keys \
+    -issuer https://adfs.example.com/adfs`
+}
+
+func (cmd *providerCreate) Run(ctx context.Context, f *flag.FlagSet) error {
+	tag := identity.ConfigOidc
+	if cmd.oauth2 {
+		tag = identity.ConfigOauth2
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := identity.NewManager(c).CreateProvider(ctx, cmd.provider.createSpec(tag))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	return nil
+}
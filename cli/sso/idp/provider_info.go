@@ -0,0 +1,124 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package idp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+)
+
+type providerInfo struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("sso.idp.provider.info", &providerInfo{})
+}
+
+func (cmd *providerInfo) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *providerInfo) Usage() string {
+	return "ID"
+}
+
+func (cmd *providerInfo) Description() string {
+	return `Display federated identity provider ID configuration.
+
+Examples:
+  govc sso.idp.provider.info $id
+  govc sso.idp.provider.info -json $id | jq .oidc.claim_map`
+}
+
+func (cmd *providerInfo) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+type providerInfoResult identity.Info
+
+func (r *providerInfoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Name:\t%s\n", r.Name)
+	fmt.Fprintf(tw, "Type:\t%s\n", r.ConfigTag)
+	fmt.Fprintf(tw, "Default:\t%t\n", r.IsDefault)
+	fmt.Fprintf(tw, "Domains:\t%s\n", strings.Join(r.DomainNames, ","))
+	fmt.Fprintf(tw, "UPN Claim:\t%s\n", r.UpnClaim)
+	fmt.Fprintf(tw, "Groups Claim:\t%s\n", r.GroupsClaim)
+	if r.IdmProtocol != "" {
+		fmt.Fprintf(tw, "IDM Protocol:\t%s\n", r.IdmProtocol)
+		fmt.Fprintf(tw, "IDM Endpoints:\t%s\n", strings.Join(r.IdmEndpoints, ","))
+	}
+
+	var claims identity.ClaimMap
+
+	switch {
+	case r.Oidc != nil:
+		fmt.Fprintf(tw, "Discovery Endpoint:\t%s\n", r.Oidc.DiscoveryEndpoint)
+		fmt.Fprintf(tw, "Issuer:\t%s\n", r.Oidc.Issuer)
+		fmt.Fprintf(tw, "Client ID:\t%s\n", r.Oidc.ClientID)
+		fmt.Fprintf(tw, "Auth Endpoint:\t%s\n", r.Oidc.AuthEndpoint)
+		fmt.Fprintf(tw, "Token Endpoint:\t%s\n", r.Oidc.TokenEndpoint)
+		fmt.Fprintf(tw, "Public Key URI:\t%s\n", r.Oidc.PublicKeyURI)
+		claims = r.Oidc.ClaimMap
+	case r.Oauth2 != nil:
+		fmt.Fprintf(tw, "Issuer:\t%s\n", r.Oauth2.Issuer)
+		fmt.Fprintf(tw, "Client ID:\t%s\n", r.Oauth2.ClientID)
+		fmt.Fprintf(tw, "Auth Method:\t%s\n", r.Oauth2.AuthenticationMethod)
+		fmt.Fprintf(tw, "Auth Endpoint:\t%s\n", r.Oauth2.AuthEndpoint)
+		fmt.Fprintf(tw, "Token Endpoint:\t%s\n", r.Oauth2.TokenEndpoint)
+		fmt.Fprintf(tw, "Public Key URI:\t%s\n", r.Oauth2.PublicKeyURI)
+		claims = r.Oauth2.ClaimMap
+	}
+
+	var mappings []string
+	for claim, values := range claims {
+		for value, groups := range values {
+			mappings = append(mappings, fmt.Sprintf("%s:%s=%s", claim, value, strings.Join(groups, ",")))
+		}
+	}
+	slices.Sort(mappings)
+	for _, m := range mappings {
+		fmt.Fprintf(tw, "Claim:\t%s\n", m)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *providerInfo) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := identity.NewManager(c).GetProvider(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult((*providerInfoResult)(res))
+}
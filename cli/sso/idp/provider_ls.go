@@ -0,0 +1,85 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package idp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+)
+
+type providerLs struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("sso.idp.provider.ls", &providerLs{})
+}
+
+func (cmd *providerLs) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *providerLs) Description() string {
+	return `List federated (OIDC and Oauth2) identity providers.
+
+Examples:
+  govc sso.idp.provider.ls
+  govc sso.idp.provider.ls -json | jq -r '.[] | select(.is_default) | .provider'`
+}
+
+func (cmd *providerLs) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+type providerLsResult []identity.Summary
+
+func (r providerLsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tName\tType\tDefault\tDomains\tClient ID\tToken Endpoint")
+	for _, p := range r {
+		var client, token string
+		switch {
+		case p.Oidc != nil:
+			client, token = p.Oidc.ClientID, p.Oidc.TokenEndpoint
+		case p.Oauth2 != nil:
+			client, token = p.Oauth2.ClientID, p.Oauth2.TokenEndpoint
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\t%s\t%s\n",
+			p.Provider, p.Name, p.ConfigTag, p.IsDefault, strings.Join(p.DomainNames, ","), client, token)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *providerLs) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := identity.NewManager(c).ListProviders(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(providerLsResult(res))
+}
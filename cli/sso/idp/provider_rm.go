@@ -0,0 +1,59 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package idp
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+)
+
+type providerRm struct {
+	*flags.ClientFlag
+}
+
+func init() {
+	cli.Register("sso.idp.provider.rm", &providerRm{})
+}
+
+func (cmd *providerRm) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+}
+
+func (cmd *providerRm) Usage() string {
+	return "ID..."
+}
+
+func (cmd *providerRm) Description() string {
+	return `Delete federated identity providers ID.
+
+Examples:
+  govc sso.idp.provider.rm $id`
+}
+
+func (cmd *providerRm) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := identity.NewManager(c)
+
+	for _, id := range f.Args() {
+		if err = m.DeleteProvider(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
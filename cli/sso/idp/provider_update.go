@@ -0,0 +1,77 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package idp
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+)
+
+type providerUpdate struct {
+	*flags.ClientFlag
+
+	provider    providerFlag
+	resetUpn    bool
+	resetGroups bool
+}
+
+func init() {
+	cli.Register("sso.idp.provider.update", &providerUpdate{})
+}
+
+func (cmd *providerUpdate) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.provider.Register(f)
+
+	f.BoolVar(&cmd.resetUpn, "reset-upn-claim", false, "Reset the UPN claim to the default")
+	f.BoolVar(&cmd.resetGroups, "reset-groups-claim", false, "Reset the groups claim to the default")
+}
+
+func (cmd *providerUpdate) Usage() string {
+	return "ID"
+}
+
+func (cmd *providerUpdate) Description() string {
+	return `Update federated identity provider ID.
+
+Only the settings specified by flags are changed.
+When '-claim' is specified, the claim mappings are replaced.
+
+Examples:
+  govc sso.idp.provider.update -default $id
+  govc sso.idp.provider.update -client-secret new-secret $id
+  govc sso.idp.provider.update -claim groups:vc-admins=Administrators@vsphere.local -claim groups:vc-users=Users@vsphere.local $id`
+}
+
+func (cmd *providerUpdate) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+	id := f.Arg(0)
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := identity.NewManager(c)
+
+	info, err := m.GetProvider(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	spec := cmd.provider.updateSpec(info.ConfigTag)
+	spec.ResetUpnClaim = cmd.resetUpn
+	spec.ResetGroupsClaim = cmd.resetGroups
+
+	return m.UpdateProvider(ctx, id, spec)
+}
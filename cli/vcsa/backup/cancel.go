@@ -0,0 +1,51 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type cancel struct {
+	*flags.ClientFlag
+}
+
+func init() {
+	cli.Register("vcsa.backup.cancel", &cancel{})
+}
+
+func (cmd *cancel) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+}
+
+func (cmd *cancel) Usage() string {
+	return "ID"
+}
+
+func (cmd *cancel) Description() string {
+	return `Cancel VC Appliance backup job ID.
+
+Examples:
+  govc vcsa.backup.cancel $id`
+}
+
+func (cmd *cancel) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return recovery.NewManager(c).CancelJob(ctx, f.Arg(0))
+}
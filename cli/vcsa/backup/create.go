@@ -0,0 +1,79 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type create struct {
+	*flags.ClientFlag
+
+	location locationFlag
+	req      recovery.BackupRequest
+}
+
+func init() {
+	cli.Register("vcsa.backup.create", &create{})
+}
+
+func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.location.Register(f)
+
+	f.Var((*flags.StringList)(&cmd.req.Parts), "part", "Optional backup part ID")
+	f.StringVar(&cmd.req.BackupPassword, "backup-password", "", "Password used to encrypt the backup")
+	f.StringVar(&cmd.req.Comment, "comment", "", "Backup comment")
+	f.BoolVar(&cmd.req.FastBackup, "fast", false, "Use fast backup")
+}
+
+func (cmd *create) Usage() string {
+	return "LOCATION"
+}
+
+func (cmd *create) Description() string {
+	return `Start a VC Appliance backup job to LOCATION.
+
+The job ID is written to stdout, use vcsa.backup.ls to monitor the job.
+Optional backup parts are listed by vcsa.backup.parts.
+
+Examples:
+  id=$(govc vcsa.backup.create -user backup -password pass sftp://backup.example.com/vc01)
+  govc vcsa.backup.create -part seat -backup-password secret -comment nightly nfs://nas.example.com/backups/vc01
+  govc vcsa.backup.ls $id`
+}
+
+func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	if err := cmd.location.Set(f.Arg(0)); err != nil {
+		return err
+	}
+	cmd.req.Location = cmd.location.Location
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := recovery.NewManager(c).CreateJob(ctx, cmd.req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	return nil
+}
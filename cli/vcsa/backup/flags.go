@@ -0,0 +1,52 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+type locationFlag struct {
+	recovery.Location
+}
+
+func (l *locationFlag) Register(f *flag.FlagSet) {
+	f.StringVar(&l.LocationType, "type", "", fmt.Sprintf("Location type %s (defaults to LOCATION scheme)", recovery.LocationTypes))
+	f.StringVar(&l.LocationUser, "user", "", "Location user name")
+	f.StringVar(&l.LocationPassword, "password", "", "Location password")
+}
+
+// Set sets the location URL, deriving the location type from its scheme if not specified
+func (l *locationFlag) Set(location string) error {
+	l.Location.Location = location
+
+	if l.LocationType == "" {
+		u, err := url.Parse(location)
+		if err != nil {
+			return err
+		}
+		l.LocationType = strings.ToUpper(u.Scheme)
+	}
+
+	if !slices.Contains(recovery.LocationTypes, l.LocationType) {
+		return fmt.Errorf("invalid location type %q", l.LocationType)
+	}
+
+	return nil
+}
+
+func writeMessages(w io.Writer, messages []rest.LocalizableMessage) {
+	for _, m := range messages {
+		fmt.Fprintf(w, "  %s\n", m.DefaultMessage)
+	}
+}
@@ -0,0 +1,125 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/units"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type ls struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	long bool
+}
+
+func init() {
+	cli.Register("vcsa.backup.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.long, "l", false, "Long listing format")
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *ls) Usage() string {
+	return "[ID]..."
+}
+
+func (cmd *ls) Description() string {
+	return `List VC Appliance backup jobs.
+
+If ID is specified, only those jobs are listed.
+
+Examples:
+  govc vcsa.backup.ls
+  govc vcsa.backup.ls -l
+  govc vcsa.backup.ls -json $id | jq -r .[].state`
+}
+
+type lsResult struct {
+	jobs []recovery.JobInfo
+	long bool
+}
+
+func (r *lsResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.jobs)
+}
+
+func (r *lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tType\tState\tProgress\tSize\tStart Time\tLocation")
+	for _, job := range r.jobs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d%%\t%s\t%s\t%s\n",
+			job.ID, job.Type, job.State, job.Progress, units.ByteSize(job.Size),
+			job.StartTime.Format(time.RFC3339), job.Location)
+		if r.long {
+			if job.Comment != "" {
+				fmt.Fprintf(tw, "  Comment: %s\n", job.Comment)
+			}
+			for _, m := range job.Messages {
+				fmt.Fprintf(tw, "  %s\n", m.DefaultMessage)
+			}
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	details, err := recovery.NewManager(c).ListJobDetails(ctx, f.Args()...)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range f.Args() {
+		if _, ok := details[id]; !ok {
+			return fmt.Errorf("backup job %q not found", id)
+		}
+	}
+
+	res := &lsResult{long: cmd.long}
+	for _, job := range details {
+		res.jobs = append(res.jobs, job)
+	}
+	slices.SortFunc(res.jobs, func(a, b recovery.JobInfo) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+
+	return cmd.WriteResult(res)
+}
@@ -0,0 +1,97 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type parts struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.backup.parts", &parts{})
+}
+
+func (cmd *parts) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *parts) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *parts) Description() string {
+	return `List VC Appliance backup parts.
+
+Parts that are not optional are always included in a backup.
+
+Examples:
+  govc vcsa.backup.parts
+  govc vcsa.backup.parts -json | jq -r '.[] | select(.optional) | .id'`
+}
+
+type part struct {
+	recovery.Part
+	Size int64 `json:"size"`
+}
+
+type partsResult []part
+
+func (r partsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tName\tOptional\tDefault\tSize (MB)")
+	for _, p := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%t\t%d\n",
+			p.ID, p.Name.DefaultMessage, p.Optional, p.SelectedByDefault, p.Size)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *parts) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := recovery.NewManager(c)
+
+	list, err := m.ListParts(ctx)
+	if err != nil {
+		return err
+	}
+
+	res := make(partsResult, len(list))
+	for i := range list {
+		res[i].Part = list[i]
+		if res[i].Size, err = m.GetPartSize(ctx, list[i].ID); err != nil {
+			return err
+		}
+	}
+
+	return cmd.WriteResult(res)
+}
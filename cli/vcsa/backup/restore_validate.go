@@ -0,0 +1,122 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type restoreValidate struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	location locationFlag
+	req      recovery.RestoreRequest
+}
+
+func init() {
+	cli.Register("vcsa.backup.restore.validate", &restoreValidate{})
+}
+
+func (cmd *restoreValidate) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	cmd.location.Register(f)
+
+	f.StringVar(&cmd.req.BackupPassword, "backup-password", "", "Password used to encrypt the backup")
+	f.StringVar(&cmd.req.SSOAdminUserName, "sso-user", "", "SSO administrator user name")
+	f.StringVar(&cmd.req.SSOAdminUserPassword, "sso-password", "", "SSO administrator password")
+	f.BoolVar(&cmd.req.IgnoreWarnings, "ignore-warnings", false, "Ignore validation warnings")
+}
+
+func (cmd *restoreValidate) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *restoreValidate) Usage() string {
+	return "LOCATION"
+}
+
+func (cmd *restoreValidate) Description() string {
+	return `Validate the VC Appliance backup at LOCATION can be restored.
+
+The backup metadata is written to stdout.
+
+Examples:
+  govc vcsa.backup.restore.validate -backup-password secret nfs://nas.example.com/backups/vc01`
+}
+
+type restoreResult recovery.Metadata
+
+func (r *restoreResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Box Name:\t%s\n", r.BoxName)
+	fmt.Fprintf(tw, "Version:\t%s\n", r.Version)
+	fmt.Fprintf(tw, "Time:\t%s\n", r.TimeStamp.Format(time.RFC3339))
+	fmt.Fprintf(tw, "Parts:\t%s\n", strings.Join(r.Parts, ","))
+	fmt.Fprintf(tw, "Comment:\t%s\n", r.Comment)
+	fmt.Fprintf(tw, "Applicable:\t%t\n", r.Applicable)
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	writeMessages(w, r.Messages)
+
+	return nil
+}
+
+func (cmd *restoreValidate) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	if err := cmd.location.Set(f.Arg(0)); err != nil {
+		return err
+	}
+	cmd.req.Location = cmd.location.Location
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := recovery.NewManager(c).ValidateRestore(ctx, cmd.req)
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.WriteResult((*restoreResult)(res)); err != nil {
+		return err
+	}
+
+	if !res.Applicable {
+		return errors.New("backup cannot be restored")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type scheduleCreate struct {
+	*flags.ClientFlag
+
+	spec       recovery.Schedule
+	recurrence recovery.RecurrenceInfo
+	retain     int
+	disable    bool
+}
+
+func init() {
+	cli.Register("vcsa.backup.schedule.create", &scheduleCreate{})
+}
+
+func (cmd *scheduleCreate) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.spec.LocationUser, "user", "", "Location user name")
+	f.StringVar(&cmd.spec.LocationPassword, "password", "", "Location password")
+	f.Var((*flags.StringList)(&cmd.spec.Parts), "part", "Optional backup part ID")
+	f.StringVar(&cmd.spec.BackupPassword, "backup-password", "", "Password used to encrypt the backup")
+	f.BoolVar(&cmd.spec.FastBackup, "fast", false, "Use fast backup")
+	f.IntVar(&cmd.recurrence.Hour, "hour", 23, "Hour of the day to run the backup")
+	f.IntVar(&cmd.recurrence.Minute, "minute", 0, "Minute of the hour to run the backup")
+	f.Var((*flags.StringList)(&cmd.recurrence.Days), "day", "Day of the week to run the backup (defaults to daily)")
+	f.IntVar(&cmd.retain, "retain", 0, "Number of backups to retain (0 retains all)")
+	f.BoolVar(&cmd.disable, "disable", false, "Create the schedule disabled")
+}
+
+func (cmd *scheduleCreate) Usage() string {
+	return "ID LOCATION"
+}
+
+func (cmd *scheduleCreate) Description() string {
+	return `Create VC Appliance backup schedule ID, backing up to LOCATION.
+
+Each scheduled backup is written to a new directory within LOCATION.
+
+Examples:
+  govc vcsa.backup.schedule.create -hour 1 -minute 30 -retain 7 default nfs://nas.example.com/backups
+  govc vcsa.backup.schedule.create -day saturday -day sunday -part seat default sftp://backup.example.com/vc01`
+}
+
+func (cmd *scheduleCreate) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	for i := range cmd.recurrence.Days {
+		cmd.recurrence.Days[i] = strings.ToUpper(cmd.recurrence.Days[i])
+	}
+
+	enable := !cmd.disable
+	cmd.spec.Enable = &enable
+	cmd.spec.Location = f.Arg(1)
+	cmd.spec.RecurrenceInfo = &cmd.recurrence
+	if cmd.retain > 0 {
+		cmd.spec.RetentionInfo = &recovery.RetentionInfo{MaxCount: cmd.retain}
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return recovery.NewManager(c).CreateSchedule(ctx, f.Arg(0), cmd.spec)
+}
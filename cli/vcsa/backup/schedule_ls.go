@@ -0,0 +1,100 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type scheduleLs struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.backup.schedule.ls", &scheduleLs{})
+}
+
+func (cmd *scheduleLs) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *scheduleLs) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *scheduleLs) Description() string {
+	return `List VC Appliance backup schedules.
+
+Examples:
+  govc vcsa.backup.schedule.ls
+  govc vcsa.backup.schedule.ls -json | jq .default.retention_info`
+}
+
+type scheduleLsResult map[string]recovery.Schedule
+
+func (r scheduleLsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	ids := make([]string, 0, len(r))
+	for id := range r {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	fmt.Fprintln(tw, "ID\tEnabled\tRecurrence\tRetain\tParts\tLocation")
+	for _, id := range ids {
+		s := r[id]
+		recurrence, retain := "-", "-"
+		if s.RecurrenceInfo != nil {
+			days := "daily"
+			if len(s.RecurrenceInfo.Days) != 0 {
+				days = strings.Join(s.RecurrenceInfo.Days, ",")
+			}
+			recurrence = fmt.Sprintf("%02d:%02d %s", s.RecurrenceInfo.Hour, s.RecurrenceInfo.Minute, days)
+		}
+		if s.RetentionInfo != nil {
+			retain = fmt.Sprint(s.RetentionInfo.MaxCount)
+		}
+		fmt.Fprintf(tw, "%s\t%t\t%s\t%s\t%s\t%s\n",
+			id, s.Enable != nil && *s.Enable, recurrence, retain, strings.Join(s.Parts, ","), s.Location)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *scheduleLs) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := recovery.NewManager(c).ListSchedules(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(scheduleLsResult(res))
+}
@@ -0,0 +1,65 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type scheduleRun struct {
+	*flags.ClientFlag
+
+	comment string
+}
+
+func init() {
+	cli.Register("vcsa.backup.schedule.run", &scheduleRun{})
+}
+
+func (cmd *scheduleRun) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.comment, "comment", "", "Backup comment")
+}
+
+func (cmd *scheduleRun) Usage() string {
+	return "ID"
+}
+
+func (cmd *scheduleRun) Description() string {
+	return `Start a VC Appliance backup job using schedule ID.
+
+The job ID is written to stdout, use vcsa.backup.ls to monitor the job.
+
+Examples:
+  govc vcsa.backup.schedule.run -comment "before upgrade" default`
+}
+
+func (cmd *scheduleRun) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	job, err := recovery.NewManager(c).RunSchedule(ctx, f.Arg(0), cmd.comment)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(job.ID)
+
+	return nil
+}
@@ -0,0 +1,101 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+)
+
+type validate struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	location locationFlag
+	req      recovery.BackupRequest
+}
+
+func init() {
+	cli.Register("vcsa.backup.validate", &validate{})
+}
+
+func (cmd *validate) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	cmd.location.Register(f)
+
+	f.Var((*flags.StringList)(&cmd.req.Parts), "part", "Optional backup part ID")
+}
+
+func (cmd *validate) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *validate) Usage() string {
+	return "LOCATION"
+}
+
+func (cmd *validate) Description() string {
+	return `Validate a VC Appliance backup to LOCATION can be created.
+
+Examples:
+  govc vcsa.backup.validate -user backup -password pass sftp://backup.example.com/vc01`
+}
+
+type validateResult recovery.ReturnResult
+
+func (r *validateResult) Write(w io.Writer) error {
+	fmt.Fprintf(w, "Status: %s\n", r.Status)
+	writeMessages(w, r.Messages)
+	return nil
+}
+
+func (cmd *validate) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	if err := cmd.location.Set(f.Arg(0)); err != nil {
+		return err
+	}
+	cmd.req.Location = cmd.location.Location
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := recovery.NewManager(c).Validate(ctx, cmd.req)
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.WriteResult((*validateResult)(res)); err != nil {
+		return err
+	}
+
+	if res.Status == recovery.StatusError {
+		return errors.New("backup validation failed")
+	}
+
+	return nil
+}
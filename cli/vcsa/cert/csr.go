@@ -0,0 +1,84 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+// subjectFlag registers the certificate subject flags shared by vcsa.cert.csr and vcsa.cert.vmca.replace
+type subjectFlag struct {
+	certificates.VMCARootCreateSpec
+}
+
+func (s *subjectFlag) Register(f *flag.FlagSet) {
+	f.IntVar(&s.KeySize, "key-size", 0, "Key size in bits (default is server defined)")
+	f.StringVar(&s.CommonName, "cn", "", "Common name")
+	f.StringVar(&s.Organization, "o", "", "Organization")
+	f.StringVar(&s.OrganizationUnit, "ou", "", "Organization unit")
+	f.StringVar(&s.Locality, "l", "", "Locality")
+	f.StringVar(&s.StateOrProvince, "st", "", "State or province")
+	f.StringVar(&s.Country, "c", "", "Country code")
+	f.StringVar(&s.EmailAddress, "email", "", "Email address")
+	f.Var((*flags.StringList)(&s.SubjectAltName), "san", "Subject alternative name (DNS name or IP address)")
+}
+
+type csr struct {
+	*flags.ClientFlag
+
+	subject subjectFlag
+}
+
+func init() {
+	cli.Register("vcsa.cert.csr", &csr{})
+}
+
+func (cmd *csr) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.subject.Register(f)
+}
+
+func (cmd *csr) Description() string {
+	return `Generate a certificate signing request for the vCenter machine SSL certificate.
+
+The private key is retained by vCenter, the signed certificate can be installed with vcsa.cert.replace.
+
+Examples:
+  govc vcsa.cert.csr -cn vcenter.example.com -o Example -c US -san vcenter.example.com -san 10.0.0.10 > vcenter.csr`
+}
+
+func (cmd *csr) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	s := cmd.subject.VMCARootCreateSpec
+	res, err := certificates.NewManager(c).CreateTLSCSR(ctx, certificates.TLSCSRSpec{
+		KeySize:          s.KeySize,
+		CommonName:       s.CommonName,
+		Organization:     s.Organization,
+		OrganizationUnit: s.OrganizationUnit,
+		Locality:         s.Locality,
+		StateOrProvince:  s.StateOrProvince,
+		Country:          s.Country,
+		EmailAddress:     s.EmailAddress,
+		SubjectAltName:   s.SubjectAltName,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Print(res.CSR)
+	return err
+}
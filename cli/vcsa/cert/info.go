@@ -0,0 +1,116 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	show bool
+}
+
+func init() {
+	cli.Register("vcsa.cert.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.show, "show", false, "Show PEM encoded certificate only")
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *info) Description() string {
+	return `Display the vCenter machine SSL certificate.
+
+Examples:
+  govc vcsa.cert.info
+  govc vcsa.cert.info -json | jq -r .thumbprint
+  govc vcsa.cert.info -show > vcenter.pem`
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := certificates.NewManager(c).GetTLS(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cmd.show {
+		_, err = fmt.Fprint(cmd.Out, res.Cert)
+		return err
+	}
+
+	return cmd.WriteResult(&infoResult{res})
+}
+
+type infoResult struct {
+	*certificates.TLSInfo
+}
+
+func (r *infoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Subject:\t%s\n", r.SubjectDN)
+	fmt.Fprintf(tw, "Issuer:\t%s\n", r.IssuerDN)
+	fmt.Fprintf(tw, "Serial Number:\t%s\n", r.SerialNumber)
+	fmt.Fprintf(tw, "Signature Algorithm:\t%s\n", r.SignatureAlgorithm)
+	fmt.Fprintf(tw, "Valid From:\t%s\n", r.ValidFrom.Format(time.ANSIC))
+	fmt.Fprintf(tw, "Valid To:\t%s\n", r.ValidTo.Format(time.ANSIC))
+	fmt.Fprintf(tw, "Thumbprint:\t%s\n", r.Thumbprint)
+	fmt.Fprintf(tw, "Alternative Names:\t%s\n", strings.Join(r.SubjectAlternativeName, ", "))
+	fmt.Fprintf(tw, "Key Usage:\t%s\n", strings.Join(r.KeyUsage, ", "))
+	fmt.Fprintf(tw, "Extended Key Usage:\t%s\n", strings.Join(r.ExtendedKeyUsage, ", "))
+
+	return tw.Flush()
+}
+
+// readFile returns the contents of the named file, or stdin if name is "-"
+func readFile(name string) (string, error) {
+	if name == "-" {
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, os.Stdin); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	b, err := os.ReadFile(filepath.Clean(name))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
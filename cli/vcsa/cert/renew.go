@@ -0,0 +1,50 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type renew struct {
+	*flags.ClientFlag
+
+	duration int
+}
+
+func init() {
+	cli.Register("vcsa.cert.renew", &renew{})
+}
+
+func (cmd *renew) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.IntVar(&cmd.duration, "duration", 0, "Validity period in days (default is server defined)")
+}
+
+func (cmd *renew) Description() string {
+	return `Renew the vCenter machine SSL certificate, issued by VMCA.
+
+The subject and alternative names of the current certificate are retained.
+
+Examples:
+  govc vcsa.cert.renew
+  govc vcsa.cert.renew -duration 365`
+}
+
+func (cmd *renew) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return certificates.NewManager(c).RenewTLS(ctx, cmd.duration)
+}
@@ -0,0 +1,82 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type replace struct {
+	*flags.ClientFlag
+
+	key  string
+	root string
+}
+
+func init() {
+	cli.Register("vcsa.cert.replace", &replace{})
+}
+
+func (cmd *replace) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.key, "private-key", "", "PEM encoded private key FILE")
+	f.StringVar(&cmd.root, "root", "", "PEM encoded root certificate FILE of the issuing CA")
+}
+
+func (cmd *replace) Usage() string {
+	return "FILE"
+}
+
+func (cmd *replace) Description() string {
+	return `Replace the vCenter machine SSL certificate with the PEM encoded certificate in FILE.
+
+If FILE name is "-", read certificate from stdin.
+The '-private-key' flag can be omitted if the certificate was issued for a CSR generated by vcsa.cert.csr.
+The '-root' certificate is added to the trusted root chains.
+
+Examples:
+  govc vcsa.cert.replace -private-key vcenter.key -root ca.pem vcenter.pem
+  govc vcsa.cert.csr -cn vcenter.example.com > vcenter.csr # sign with an external CA
+  govc vcsa.cert.replace -root ca.pem vcenter.pem`
+}
+
+func (cmd *replace) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	var (
+		spec certificates.TLSSpec
+		err  error
+	)
+
+	if spec.Cert, err = readFile(f.Arg(0)); err != nil {
+		return err
+	}
+	if cmd.key != "" {
+		if spec.Key, err = readFile(cmd.key); err != nil {
+			return err
+		}
+	}
+	if cmd.root != "" {
+		if spec.RootCert, err = readFile(cmd.root); err != nil {
+			return err
+		}
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return certificates.NewManager(c).ReplaceTLS(ctx, spec)
+}
@@ -0,0 +1,75 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type rootAdd struct {
+	*flags.ClientFlag
+
+	id string
+}
+
+func init() {
+	cli.Register("vcsa.cert.root.add", &rootAdd{})
+}
+
+func (cmd *rootAdd) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.id, "id", "", "Chain ID (default is server generated)")
+}
+
+func (cmd *rootAdd) Usage() string {
+	return "FILE"
+}
+
+func (cmd *rootAdd) Description() string {
+	return `Add the PEM encoded certificate chain in FILE to the vCenter trusted root chains.
+
+If FILE name is "-", read certificate chain from stdin.
+The chain ID is printed on success.
+
+Examples:
+  govc vcsa.cert.root.add ca.pem
+  govc about.cert -show -u ldaps.example.com:636 | govc vcsa.cert.root.add -id ldaps -`
+}
+
+func (cmd *rootAdd) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	chain, err := readFile(f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := certificates.NewManager(c).CreateTrustedRootChain(ctx, certificates.TrustedRootChainCreateSpec{
+		CertChain: certificates.X509CertChain{CertChain: []string{chain}},
+		Chain:     cmd.id,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(id)
+
+	return nil
+}
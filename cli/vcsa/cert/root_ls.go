@@ -0,0 +1,103 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type rootLs struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.cert.root.ls", &rootLs{})
+}
+
+func (cmd *rootLs) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *rootLs) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *rootLs) Description() string {
+	return `List vCenter trusted root certificate chains.
+
+Examples:
+  govc vcsa.cert.root.ls
+  govc vcsa.cert.root.ls -json | jq -r .[].chain`
+}
+
+// rootChain is a trusted root chain along with its identifier
+type rootChain struct {
+	Chain string `json:"chain"`
+	certificates.TrustedRootChainInfo
+}
+
+func (cmd *rootLs) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := certificates.NewManager(c)
+
+	chains, err := m.ListTrustedRootChains(ctx)
+	if err != nil {
+		return err
+	}
+
+	var res rootLsResult
+	for _, chain := range chains {
+		info, err := m.GetTrustedRootChain(ctx, chain.Chain)
+		if err != nil {
+			return err
+		}
+		res = append(res, rootChain{chain.Chain, *info})
+	}
+
+	return cmd.WriteResult(res)
+}
+
+type rootLsResult []rootChain
+
+func (r rootLsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tSubject\tValid To")
+	for _, chain := range r {
+		var info object.HostCertificateInfo
+		subject, expires := "-", "-"
+		if len(chain.CertChain.CertChain) != 0 {
+			if _, err := info.FromPEM([]byte(chain.CertChain.CertChain[0])); err == nil {
+				subject = info.Subject
+				expires = info.NotAfter.Format(time.ANSIC)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", chain.Chain, subject, expires)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,59 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type rootRm struct {
+	*flags.ClientFlag
+}
+
+func init() {
+	cli.Register("vcsa.cert.root.rm", &rootRm{})
+}
+
+func (cmd *rootRm) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+}
+
+func (cmd *rootRm) Usage() string {
+	return "ID..."
+}
+
+func (cmd *rootRm) Description() string {
+	return `Remove vCenter trusted root certificate chains.
+
+Examples:
+  govc vcsa.cert.root.rm ldaps`
+}
+
+func (cmd *rootRm) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := certificates.NewManager(c)
+
+	for _, id := range f.Args() {
+		if err := m.DeleteTrustedRootChain(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
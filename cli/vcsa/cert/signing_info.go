@@ -0,0 +1,89 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type signingInfo struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.cert.signing.info", &signingInfo{})
+}
+
+func (cmd *signingInfo) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *signingInfo) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *signingInfo) Description() string {
+	return `Display the vCenter token signing certificates.
+
+The active certificate is listed first.
+
+Examples:
+  govc vcsa.cert.signing.info
+  govc vcsa.cert.signing.info -json | jq -r .active_cert_chain.cert_chain[0]`
+}
+
+func (cmd *signingInfo) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := certificates.NewManager(c).GetSigningCertificate(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&signingInfoResult{res})
+}
+
+type signingInfoResult struct {
+	*certificates.SigningCertificateInfo
+}
+
+func (r *signingInfoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Subject\tIssuer\tValid To\tThumbprint")
+	for _, chain := range r.SigningCertChains {
+		if len(chain.CertChain) == 0 {
+			continue
+		}
+		var info object.HostCertificateInfo
+		if _, err := info.FromPEM([]byte(chain.CertChain[0])); err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Subject, info.Issuer, info.NotAfter.Format(time.ANSIC), info.ThumbprintSHA1)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,48 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type signingRefresh struct {
+	*flags.ClientFlag
+
+	force bool
+}
+
+func init() {
+	cli.Register("vcsa.cert.signing.refresh", &signingRefresh{})
+}
+
+func (cmd *signingRefresh) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.force, "force", false, "Refresh even if the current certificate is not VMCA issued")
+}
+
+func (cmd *signingRefresh) Description() string {
+	return `Replace the vCenter token signing certificate with a new certificate issued by VMCA.
+
+Examples:
+  govc vcsa.cert.signing.refresh`
+}
+
+func (cmd *signingRefresh) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = certificates.NewManager(c).RefreshSigningCertificate(ctx, cmd.force)
+	return err
+}
@@ -0,0 +1,49 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package cert
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+)
+
+type vmcaReplace struct {
+	*flags.ClientFlag
+
+	subject subjectFlag
+}
+
+func init() {
+	cli.Register("vcsa.cert.vmca.replace", &vmcaReplace{})
+}
+
+func (cmd *vmcaReplace) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.subject.Register(f)
+}
+
+func (cmd *vmcaReplace) Description() string {
+	return `Replace the VMCA root certificate with a new self-signed certificate.
+
+The machine SSL certificate is reissued by the new root.
+
+Examples:
+  govc vcsa.cert.vmca.replace -cn "vCenter CA" -o Example -c US`
+}
+
+func (cmd *vmcaReplace) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return certificates.NewManager(c).ReplaceVMCARoot(ctx, cmd.subject.VMCARootCreateSpec)
+}
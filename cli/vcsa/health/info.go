@@ -0,0 +1,110 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.health.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *info) Usage() string {
+	return "[COMPONENT]..."
+}
+
+func (cmd *info) Description() string {
+	return `Display the VC Appliance health status.
+
+Health level is one of: green, yellow, orange, red, gray
+COMPONENT defaults to all of: applmgmt, database-storage, load, mem, software-packages, storage, swap, system
+
+Examples:
+  govc vcsa.health.info
+  govc vcsa.health.info system
+  govc vcsa.health.info -json | jq -r .status.system`
+}
+
+type infoResult struct {
+	Status    health.Status `json:"status"`
+	LastCheck time.Time     `json:"last_check"`
+
+	components []string
+}
+
+func (r *infoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, name := range r.components {
+		fmt.Fprintf(tw, "%s:\t%s\n", name, r.Status[name])
+	}
+	fmt.Fprintf(tw, "Last check:\t%s\n", r.LastCheck.Format(time.ANSIC))
+
+	return tw.Flush()
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := health.NewManager(c)
+
+	res := infoResult{
+		Status:     make(health.Status),
+		components: f.Args(),
+	}
+	if len(res.components) == 0 {
+		res.components = health.Components
+	}
+
+	for _, name := range res.components {
+		level, err := m.Get(ctx, name)
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+		res.Status[name] = level
+	}
+
+	if res.LastCheck, err = m.LastCheck(ctx); err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&res)
+}
@@ -0,0 +1,72 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ntp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/ntp"
+	"github.com/vmware/govmomi/vapi/appliance/timesync"
+)
+
+type change struct {
+	*flags.ClientFlag
+
+	mode string
+}
+
+func init() {
+	cli.Register("vcsa.ntp.change", &change{})
+}
+
+func (cmd *change) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	modes := []string{timesync.Disabled, timesync.NTP, timesync.Host}
+	f.StringVar(&cmd.mode, "mode", "", fmt.Sprintf("Time synchronization mode %s", modes))
+}
+
+func (cmd *change) Usage() string {
+	return "[SERVER]..."
+}
+
+func (cmd *change) Description() string {
+	return `Change the VC Appliance time synchronization mode and NTP servers.
+
+If SERVER arguments are given, the NTP servers are replaced.
+
+Examples:
+  govc vcsa.ntp.change -mode NTP time1.example.com time2.example.com
+  govc vcsa.ntp.change -mode HOST`
+}
+
+func (cmd *change) Run(ctx context.Context, f *flag.FlagSet) error {
+	if cmd.mode == "" && f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	if f.NArg() != 0 {
+		err = ntp.NewManager(c).Set(ctx, ntp.Servers{Servers: f.Args()})
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.mode != "" {
+		return timesync.NewManager(c).Set(ctx, timesync.Mode{Mode: cmd.mode})
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ntp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/ntp"
+	"github.com/vmware/govmomi/vapi/appliance/timesync"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.ntp.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *info) Description() string {
+	return `Display the VC Appliance time synchronization mode and NTP servers.
+
+Examples:
+  govc vcsa.ntp.info
+  govc vcsa.ntp.info -json | jq -r .servers[]`
+}
+
+type infoResult struct {
+	Mode    string   `json:"mode"`
+	Servers []string `json:"servers"`
+}
+
+func (r *infoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Mode:\t%s\n", r.Mode)
+	fmt.Fprintf(tw, "Servers:\t%s\n", strings.Join(r.Servers, ", "))
+
+	return tw.Flush()
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	var res infoResult
+
+	if res.Mode, err = timesync.NewManager(c).Get(ctx); err != nil {
+		return err
+	}
+
+	if res.Servers, err = ntp.NewManager(c).Get(ctx); err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&res)
+}
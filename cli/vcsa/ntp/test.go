@@ -0,0 +1,93 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ntp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/ntp"
+)
+
+type test struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.ntp.test", &test{})
+}
+
+func (cmd *test) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *test) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *test) Usage() string {
+	return "[SERVER]..."
+}
+
+func (cmd *test) Description() string {
+	return `Test connection to NTP servers.
+
+SERVER defaults to the configured NTP servers.
+
+Examples:
+  govc vcsa.ntp.test
+  govc vcsa.ntp.test time.example.com`
+}
+
+type testResult []ntp.TestResult
+
+func (r testResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, s := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Server, s.Status, s.Message.DefaultMessage)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *test) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := ntp.NewManager(c)
+
+	servers := f.Args()
+	if len(servers) == 0 {
+		if servers, err = m.Get(ctx); err != nil {
+			return err
+		}
+	}
+
+	res, err := m.Test(ctx, ntp.Servers{Servers: servers})
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(testResult(res))
+}
@@ -0,0 +1,75 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+)
+
+type service struct {
+	*flags.ClientFlag
+}
+
+func init() {
+	cli.Register("vcsa.service", &service{})
+}
+
+func (cmd *service) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+}
+
+func (cmd *service) Usage() string {
+	return "ACTION NAME..."
+}
+
+func (cmd *service) Description() string {
+	return `Apply VC Appliance service ACTION to services NAME.
+
+Where ACTION is one of: start, stop, restart
+
+Examples:
+  govc vcsa.service restart sshd
+  govc vcsa.service stop ntpd`
+}
+
+func (cmd *service) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() < 2 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := services.NewManager(c)
+
+	var action func(context.Context, string) error
+
+	switch f.Arg(0) {
+	case services.Start:
+		action = m.Start
+	case services.Stop:
+		action = m.Stop
+	case services.Restart:
+		action = m.Restart
+	default:
+		return flag.ErrHelp
+	}
+
+	for _, name := range f.Args()[1:] {
+		if err := action(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
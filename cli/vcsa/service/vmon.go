@@ -0,0 +1,89 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+)
+
+type vmon struct {
+	*flags.ClientFlag
+}
+
+func init() {
+	cli.Register("vcsa.vmon", &vmon{})
+}
+
+func (cmd *vmon) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+}
+
+func (cmd *vmon) Usage() string {
+	return "ACTION NAME..."
+}
+
+func (cmd *vmon) Description() string {
+	return `Apply vMon service ACTION to services NAME.
+
+Where ACTION is one of: start, stop, restart, automatic, manual, disable
+The automatic, manual and disable actions change the service startup type.
+
+Examples:
+  govc vcsa.vmon restart vsphere-ui
+  govc vcsa.vmon automatic imagebuilder
+  govc vcsa.vmon start imagebuilder`
+}
+
+func (cmd *vmon) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() < 2 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := services.NewManager(c)
+
+	startup := func(kind string) func(context.Context, string) error {
+		return func(ctx context.Context, name string) error {
+			return m.UpdateVMon(ctx, name, services.VMonUpdateSpec{StartupType: kind})
+		}
+	}
+
+	var action func(context.Context, string) error
+
+	switch f.Arg(0) {
+	case services.Start:
+		action = m.StartVMon
+	case services.Stop:
+		action = m.StopVMon
+	case services.Restart:
+		action = m.RestartVMon
+	case "automatic":
+		action = startup(services.StartupTypeAutomatic)
+	case "manual":
+		action = startup(services.StartupTypeManual)
+	case "disable":
+		action = startup(services.StartupTypeDisabled)
+	default:
+		return flag.ErrHelp
+	}
+
+	for _, name := range f.Args()[1:] {
+		if err := action(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
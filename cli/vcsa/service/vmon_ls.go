@@ -0,0 +1,84 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+)
+
+type vmonLs struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.vmon.ls", &vmonLs{})
+}
+
+func (cmd *vmonLs) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *vmonLs) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *vmonLs) Description() string {
+	return `List vCenter services managed by vMon.
+
+Examples:
+  govc vcsa.vmon.ls
+  govc vcsa.vmon.ls -json | jq -r .vpxd.health`
+}
+
+type vmonLsResult map[string]services.VMonInfo
+
+func (r vmonLsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Name\tStartup Type\tState\tHealth")
+	for _, name := range sortedKeys(r) {
+		s := r[name]
+		health := s.Health
+		if health == "" {
+			health = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, s.StartupType, s.State, health)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *vmonLs) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := services.NewManager(c).ListVMon(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(vmonLsResult(res))
+}
@@ -0,0 +1,107 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.update.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *info) Description() string {
+	return `Display the VC Appliance update state, last update task and staged update.
+
+Examples:
+  govc vcsa.update.info
+  govc vcsa.update.info -json | jq -r .info.state`
+}
+
+type infoResult struct {
+	Info   *update.Info       `json:"info"`
+	Staged *update.StagedInfo `json:"staged,omitempty"`
+}
+
+func (r *infoResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Version:\t%s\n", r.Info.Version)
+	fmt.Fprintf(tw, "State:\t%s\n", r.Info.State)
+	if r.Info.LatestQueryTime != nil {
+		fmt.Fprintf(tw, "Last Query:\t%s\n", r.Info.LatestQueryTime.Format(time.ANSIC))
+	}
+	if t := r.Info.Task; t != nil {
+		fmt.Fprintf(tw, "Task:\t%s\n", t.Description.DefaultMessage)
+		fmt.Fprintf(tw, "  Status:\t%s\n", t.Status)
+		if t.Progress != nil {
+			fmt.Fprintf(tw, "  Progress:\t%d/%d\n", t.Progress.Completed, t.Progress.Total)
+		}
+	}
+	if s := r.Staged; s != nil {
+		fmt.Fprintf(tw, "Staged:\t%s\n", s.Version)
+		fmt.Fprintf(tw, "  Complete:\t%t\n", s.StagingComplete)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := update.NewManager(c)
+
+	var res infoResult
+
+	if res.Info, err = m.Get(ctx); err != nil {
+		return err
+	}
+
+	if res.Staged, err = m.GetStaged(ctx); err != nil {
+		if !rest.IsStatusError(err, http.StatusNotFound) {
+			return err
+		}
+		res.Staged = nil
+	}
+
+	return cmd.WriteResult(&res)
+}
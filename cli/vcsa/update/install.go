@@ -0,0 +1,87 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+)
+
+type install struct {
+	*flags.ClientFlag
+
+	stage    bool
+	userData []string
+}
+
+func init() {
+	cli.Register("vcsa.update.install", &install{})
+}
+
+func (cmd *install) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.stage, "stage", false, "Stage the update before installing")
+	f.Var((*flags.StringList)(&cmd.userData), "e", "Answer to update question (KEY=VALUE)")
+}
+
+func (cmd *install) Usage() string {
+	return "VERSION"
+}
+
+func (cmd *install) Description() string {
+	return `Install VC Appliance update VERSION.
+
+The update must be staged using vcsa.update.stage, unless the '-stage' flag is specified.
+User data is validated before installing.
+
+Examples:
+  govc vcsa.update.install -stage 8.0.3.00100
+  govc vcsa.update.install -e vmdir.password=secret 8.0.3.00100`
+}
+
+func (cmd *install) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+	version := f.Arg(0)
+
+	spec := update.InstallSpec{UserData: make(map[string]string)}
+	for _, kv := range cmd.userData {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return fmt.Errorf("invalid user data %q, expected KEY=VALUE", kv)
+		}
+		spec.UserData[k] = v
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := update.NewManager(c)
+
+	issues, err := m.Validate(ctx, version, spec)
+	if err != nil {
+		return err
+	}
+	if len(issues.Errors) != 0 {
+		return fmt.Errorf("%s", issues.Errors[0].Message.DefaultMessage)
+	}
+
+	if cmd.stage {
+		return m.StageAndInstall(ctx, version, spec)
+	}
+
+	return m.Install(ctx, version, spec)
+}
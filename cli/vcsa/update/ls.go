@@ -0,0 +1,85 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+)
+
+type ls struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	source string
+}
+
+func init() {
+	cli.Register("vcsa.update.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	sources := []string{update.SourceLastCheck, update.SourceLocal, update.SourceLocalAndOnline}
+	f.StringVar(&cmd.source, "source", update.SourceLocalAndOnline, fmt.Sprintf("Update source %s", sources))
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *ls) Description() string {
+	return `List available VC Appliance updates.
+
+Examples:
+  govc vcsa.update.ls
+  govc vcsa.update.ls -source LAST_CHECK -json | jq -r .[].version`
+}
+
+type lsResult []update.Summary
+
+func (r lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Version\tType\tSeverity\tRelease Date\tReboot\tName")
+	for _, s := range r {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\t%s\n",
+			s.Version, s.UpdateType, s.Severity, s.ReleaseDate.Format("2006-01-02"), s.RebootRequired, s.Name)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	res, err := update.NewManager(c).ListPending(ctx, cmd.source)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(lsResult(res))
+}
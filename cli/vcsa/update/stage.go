@@ -0,0 +1,66 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vapi/appliance/update"
+)
+
+type stage struct {
+	*flags.ClientFlag
+
+	remove bool
+}
+
+func init() {
+	cli.Register("vcsa.update.stage", &stage{})
+}
+
+func (cmd *stage) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.remove, "rm", false, "Remove the staged update")
+}
+
+func (cmd *stage) Usage() string {
+	return "VERSION"
+}
+
+func (cmd *stage) Description() string {
+	return `Stage VC Appliance update VERSION.
+
+Examples:
+  govc vcsa.update.stage 8.0.3.00100
+  govc vcsa.update.stage -rm`
+}
+
+func (cmd *stage) Run(ctx context.Context, f *flag.FlagSet) error {
+	if cmd.remove {
+		if f.NArg() != 0 {
+			return flag.ErrHelp
+		}
+	} else if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := update.NewManager(c)
+
+	if cmd.remove {
+		return m.DeleteStaged(ctx)
+	}
+
+	return m.Stage(ctx, f.Arg(0))
+}
@@ -0,0 +1,91 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type aliasAdd struct {
+	*GuestFlag
+	AliasFlag
+
+	mapCert bool
+	comment string
+}
+
+func init() {
+	cli.Register("guest.alias.add", &aliasAdd{})
+}
+
+func (cmd *aliasAdd) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+	cmd.AliasFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.mapCert, "map", false, "Map the certificate to USERNAME, such that SAML token auth does not require a username")
+	f.StringVar(&cmd.comment, "comment", "", "Alias comment")
+}
+
+func (cmd *aliasAdd) Process(ctx context.Context) error {
+	return processAlias(ctx, &cmd.AliasFlag, cmd.GuestFlag)
+}
+
+func (cmd *aliasAdd) Usage() string {
+	return "USERNAME"
+}
+
+func (cmd *aliasAdd) Description() string {
+	return `Add guest alias for USERNAME.
+
+A guest alias maps an SSO signing certificate and SAML token subject to a guest account,
+such that guest operations can use the '-guest-token' flag rather than a guest password.
+The subject defaults to that of the '-token' flag, use '-any' to match any subject.
+
+Examples:
+  govc session.login -issue > token.xml
+  govc guest.alias.add -vm $name -l root:pass -token token.xml -map root
+  govc guest.run -vm $name -guest-token token.xml uname -a
+  govc session.login -issue | govc guest.run -vm $name -guest-token - uname -a
+  GOVC_GUEST_TOKEN="$(govc session.login -issue)" govc guest.run -vm $name uname -a
+  govc guest.alias.add -vm $name -l root:pass -sso-cert sso.pem -subject Administrator@VSPHERE.LOCAL root
+  govc guest.alias.add -vm $name -l root:pass -sso-cert sso.pem -any -comment automation root`
+}
+
+func (cmd *aliasAdd) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	cert, err := cmd.Cert()
+	if err != nil {
+		return err
+	}
+
+	subject, err := cmd.Subject()
+	if err != nil {
+		return err
+	}
+	if subject == nil {
+		return errors.New("-subject, -token or -any flag is required")
+	}
+
+	m, err := cmd.AliasManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	info := types.GuestAuthAliasInfo{
+		Subject: subject,
+		Comment: cmd.comment,
+	}
+
+	return m.AddAlias(ctx, cmd.Auth(), f.Arg(0), cmd.mapCert, cert, info)
+}
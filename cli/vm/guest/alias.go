@@ -0,0 +1,111 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// AliasFlag specifies the certificate and subject of a guest alias
+type AliasFlag struct {
+	cert      string
+	token     string
+	tokenFile string
+	subject   string
+	any       bool
+}
+
+func (flag *AliasFlag) Register(ctx context.Context, f *flag.FlagSet) {
+	f.StringVar(&flag.cert, "sso-cert", "", "SSO signing certificate file (PEM or base64 encoded DER)")
+	f.StringVar(&flag.tokenFile, "token", "", "Use the signing certificate and subject of SAML token FILE (\"-\" for stdin)")
+	f.StringVar(&flag.subject, "subject", "", "Subject name")
+	f.BoolVar(&flag.any, "any", false, "Any subject")
+}
+
+func (flag *AliasFlag) Process(ctx context.Context) error {
+	if flag.tokenFile != "" {
+		token, err := readToken(flag.tokenFile)
+		if err != nil {
+			return fmt.Errorf("token: %w", err)
+		}
+
+		flag.token = token
+	}
+
+	return nil
+}
+
+// processAlias processes the alias and guest flags, the alias token is read first as both may read from stdin
+func processAlias(ctx context.Context, alias *AliasFlag, flag *GuestFlag) error {
+	if alias.tokenFile == "-" {
+		if err := flag.stdinConflict("-token -"); err != nil {
+			return err
+		}
+	}
+
+	if err := alias.Process(ctx); err != nil {
+		return err
+	}
+
+	return flag.Process(ctx)
+}
+
+// Cert returns the base64 encoded DER certificate
+func (flag *AliasFlag) Cert() (string, error) {
+	if flag.token != "" {
+		info, err := guest.ParseToken(flag.token)
+		if err != nil {
+			return "", err
+		}
+		return info.Base64Cert, nil
+	}
+
+	if flag.cert == "" {
+		return "", errors.New("-sso-cert or -token flag is required")
+	}
+
+	data, err := os.ReadFile(flag.cert)
+	if err != nil {
+		return "", err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		return base64.StdEncoding.EncodeToString(block.Bytes), nil
+	}
+
+	return strings.Join(strings.Fields(string(data)), ""), nil
+}
+
+// Subject returns the alias subject, nil if not specified
+func (flag *AliasFlag) Subject() (types.BaseGuestAuthSubject, error) {
+	if flag.any {
+		return new(types.GuestAuthAnySubject), nil
+	}
+
+	name := flag.subject
+	if name == "" && flag.token != "" {
+		info, err := guest.ParseToken(flag.token)
+		if err != nil {
+			return nil, err
+		}
+		name = info.Subject
+	}
+
+	if name == "" {
+		return nil, nil
+	}
+
+	return &types.GuestAuthNamedSubject{Name: name}, nil
+}
@@ -0,0 +1,144 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type aliasList struct {
+	*flags.OutputFlag
+	*GuestFlag
+
+	mapped bool
+}
+
+func init() {
+	cli.Register("guest.alias.ls", &aliasList{})
+}
+
+func (cmd *aliasList) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.mapped, "mapped", false, "List mapped aliases of all users")
+}
+
+func (cmd *aliasList) Process(ctx context.Context) error {
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *aliasList) Usage() string {
+	return "[USERNAME]"
+}
+
+func (cmd *aliasList) Description() string {
+	return `List guest aliases for USERNAME.
+
+USERNAME defaults to the '-l' flag user.
+
+Examples:
+  govc guest.alias.ls -vm $name -l root:pass
+  govc guest.alias.ls -vm $name -l root:pass -json admin
+  govc guest.alias.ls -vm $name -l root:pass -mapped`
+}
+
+func subjectName(s types.BaseGuestAuthSubject) string {
+	if named, ok := s.(*types.GuestAuthNamedSubject); ok {
+		return named.Name
+	}
+	return "*"
+}
+
+func certID(cert string) string {
+	if len(cert) > 16 {
+		return cert[len(cert)-16:]
+	}
+	return cert
+}
+
+type aliasResult struct {
+	Aliases []types.GuestAliases `json:"aliases"`
+}
+
+func (r *aliasResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "CERT\tSUBJECT\tCOMMENT")
+
+	for _, a := range r.Aliases {
+		for _, info := range a.Aliases {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", certID(a.Base64Cert), subjectName(info.Subject), info.Comment)
+		}
+	}
+
+	return tw.Flush()
+}
+
+type mappedAliasResult struct {
+	Aliases []types.GuestMappedAliases `json:"aliases"`
+}
+
+func (r *mappedAliasResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "USER\tCERT\tSUBJECT")
+
+	for _, a := range r.Aliases {
+		for _, s := range a.Subjects {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", a.Username, certID(a.Base64Cert), subjectName(s))
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *aliasList) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() > 1 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.AliasManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cmd.mapped {
+		aliases, err := m.ListMappedAliases(ctx, cmd.Auth())
+		if err != nil {
+			return err
+		}
+		return cmd.WriteResult(&mappedAliasResult{aliases})
+	}
+
+	username := f.Arg(0)
+	if username == "" {
+		username = cmd.auth.Username
+	}
+
+	aliases, err := m.ListAliases(ctx, cmd.Auth(), username)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&aliasResult{aliases})
+}
@@ -0,0 +1,73 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+)
+
+type aliasRemove struct {
+	*GuestFlag
+	AliasFlag
+}
+
+func init() {
+	cli.Register("guest.alias.rm", &aliasRemove{})
+}
+
+func (cmd *aliasRemove) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+	cmd.AliasFlag.Register(ctx, f)
+}
+
+func (cmd *aliasRemove) Process(ctx context.Context) error {
+	return processAlias(ctx, &cmd.AliasFlag, cmd.GuestFlag)
+}
+
+func (cmd *aliasRemove) Usage() string {
+	return "USERNAME"
+}
+
+func (cmd *aliasRemove) Description() string {
+	return `Remove guest alias for USERNAME.
+
+If no subject is specified, all aliases of USERNAME with the given certificate are removed.
+
+Examples:
+  govc guest.alias.rm -vm $name -l root:pass -token token.xml root
+  govc guest.alias.rm -vm $name -l root:pass -sso-cert sso.pem -any root
+  govc guest.alias.rm -vm $name -l root:pass -sso-cert sso.pem root`
+}
+
+func (cmd *aliasRemove) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	cert, err := cmd.Cert()
+	if err != nil {
+		return err
+	}
+
+	subject, err := cmd.Subject()
+	if err != nil {
+		return err
+	}
+
+	m, err := cmd.AliasManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	if subject == nil {
+		return m.RemoveAliasByCert(ctx, cmd.Auth(), f.Arg(0), cert)
+	}
+
+	return m.RemoveAlias(ctx, cmd.Auth(), f.Arg(0), cert, subject)
+}
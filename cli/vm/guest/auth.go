@@ -8,15 +8,19 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/vmware/govmomi/vim25/types"
 )
 
 type AuthFlag struct {
-	auth types.NamePasswordAuthentication
-	proc bool
+	auth      types.NamePasswordAuthentication
+	token     string
+	tokenFile string
+	proc      bool
 }
 
 func newAuthFlag(ctx context.Context) (*AuthFlag, context.Context) {
@@ -48,12 +52,54 @@ func (flag *AuthFlag) Register(ctx context.Context, f *flag.FlagSet) {
 	}
 	usage := fmt.Sprintf("Guest VM credentials (<user>:<password>) [%s]", env)
 	f.Var(flag, "l", usage)
+	env = "GOVC_GUEST_TOKEN"
+	flag.token = os.Getenv(env)
+	usage = fmt.Sprintf("Guest VM SAML token FILE (\"-\" for stdin), used instead of the -l password (%s may contain the token)", env)
+	f.StringVar(&flag.tokenFile, "guest-token", "", usage)
 	if flag.proc {
 		f.BoolVar(&flag.auth.GuestAuthentication.InteractiveSession, "i", false, "Interactive session")
 	}
 }
 
+// readToken returns the SAML token read from the given file, or from stdin if name is "-"
+func readToken(name string) (string, error) {
+	var data []byte
+	var err error
+
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filepath.Clean(name))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// stdinConflict returns an error if the guest token is read from stdin, which the given flag also reads from
+func (flag *AuthFlag) stdinConflict(name string) error {
+	if flag.tokenFile == "-" {
+		return fmt.Errorf("-guest-token - cannot be used with %s, both read from stdin", name)
+	}
+	return nil
+}
+
 func (flag *AuthFlag) Process(ctx context.Context) error {
+	if flag.tokenFile != "" {
+		token, err := readToken(flag.tokenFile)
+		if err != nil {
+			return fmt.Errorf("guest token: %w", err)
+		}
+
+		flag.token = token
+	}
+
+	if flag.token != "" {
+		return nil // username is optional when the token subject is mapped via guest alias
+	}
+
 	if flag.auth.Username == "" {
 		return fmt.Errorf("guest login username must not be empty")
 	}
@@ -62,5 +108,13 @@ func (flag *AuthFlag) Process(ctx context.Context) error {
 }
 
 func (flag *AuthFlag) Auth() types.BaseGuestAuthentication {
+	if flag.token != "" {
+		return &types.SAMLTokenAuthentication{
+			GuestAuthentication: flag.auth.GuestAuthentication,
+			Token:               flag.token,
+			Username:            flag.auth.Username,
+		}
+	}
+
 	return &flag.auth
 }
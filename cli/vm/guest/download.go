@@ -11,6 +11,7 @@ import (
 	"os"
 
 	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/guest"
 	"github.com/vmware/govmomi/vim25/progress"
 )
 
@@ -18,6 +19,8 @@ type download struct {
 	*GuestFlag
 
 	overwrite bool
+	recursive bool
+	transfer  guest.TransferOptions
 }
 
 func init() {
@@ -29,6 +32,9 @@ func (cmd *download) Register(ctx context.Context, f *flag.FlagSet) {
 	cmd.GuestFlag.Register(ctx, f)
 
 	f.BoolVar(&cmd.overwrite, "f", false, "If set, the local destination file is clobbered")
+	f.BoolVar(&cmd.recursive, "r", false, "Recursively download SOURCE directory")
+	f.IntVar(&cmd.transfer.Concurrency, "c", guest.DefaultTransferConcurrency, "Number of concurrent file transfers with -r")
+	f.BoolVar(&cmd.transfer.Resume, "resume", false, "Skip files where DEST has the same size and modification time with -r")
 }
 
 func (cmd *download) Usage() string {
@@ -40,9 +46,18 @@ func (cmd *download) Description() string {
 
 If DEST name is "-", source is written to stdout.
 
+With the '-r' flag, SOURCE directory in the guest is copied to DEST directory, preserving file permissions
+and modification times. Symlinks are skipped.
+The directory is transferred as a single gzip'd tar if supported by guest tools, otherwise one file transfer
+is used per file.
+If the download fails, it can be run again with the '-resume' flag to skip files that were already copied,
+which always uses one file transfer per file.
+
 Examples:
   govc guest.download -l user:pass -vm=my-vm /var/log/my.log ./local.log
   govc guest.download -l user:pass -vm=my-vm /etc/motd -
+  govc guest.download -l user:pass -vm=my-vm -r /var/log ./log
+  govc guest.download -l user:pass -vm=my-vm -r -resume /var/log ./log
   tar -cf- foo/ | govc guest.run -d - tar -C /tmp -xf-
   govc guest.run tar -C /tmp -cf- foo/ | tar -C /tmp -xf- # download directory`
 }
@@ -62,6 +77,16 @@ func (cmd *download) Run(ctx context.Context, f *flag.FlagSet) error {
 	src := f.Arg(0)
 	dst := f.Arg(1)
 
+	if cmd.recursive {
+		c, err := cmd.Toolbox(ctx)
+		if err != nil {
+			return err
+		}
+
+		cmd.transfer.Overwrite = cmd.overwrite
+		return c.DownloadDirectory(ctx, src, dst, &cmd.transfer)
+	}
+
 	_, err := os.Stat(dst)
 	if err == nil && !cmd.overwrite {
 		return os.ErrExist
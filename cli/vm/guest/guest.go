@@ -102,6 +102,36 @@ func (flag *GuestFlag) ProcessManager() (*guest.ProcessManager, error) {
 	return o.ProcessManager(ctx)
 }
 
+func (flag *GuestFlag) AliasManager(ctx context.Context) (*guest.AliasManager, error) {
+	c, err := flag.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := flag.VirtualMachine()
+	if err != nil {
+		return nil, err
+	}
+
+	o := guest.NewOperationsManager(c, vm.Reference())
+	return o.AliasManager(ctx)
+}
+
+func (flag *GuestFlag) RegistryManager(ctx context.Context) (*guest.RegistryManager, error) {
+	c, err := flag.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := flag.VirtualMachine()
+	if err != nil {
+		return nil, err
+	}
+
+	o := guest.NewOperationsManager(c, vm.Reference())
+	return o.RegistryManager(ctx)
+}
+
 func (flag *GuestFlag) ParseURL(urlStr string) (*url.URL, error) {
 	c, err := flag.Client()
 	if err != nil {
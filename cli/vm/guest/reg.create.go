@@ -0,0 +1,67 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+)
+
+type regCreate struct {
+	*GuestFlag
+	RegistryFlag
+
+	volatile bool
+	class    string
+}
+
+func init() {
+	cli.Register("guest.reg.create", &regCreate{})
+}
+
+func (cmd *regCreate) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+	cmd.RegistryFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.volatile, "volatile", false, "Key is not preserved when the guest is restarted")
+	f.StringVar(&cmd.class, "class", "", "Key class type")
+}
+
+func (cmd *regCreate) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.RegistryFlag.Process(ctx)
+}
+
+func (cmd *regCreate) Usage() string {
+	return "KEY"
+}
+
+func (cmd *regCreate) Description() string {
+	return `Create registry KEY in Windows guest.
+
+Any missing parent keys are also created.
+
+Examples:
+  govc guest.reg.create -vm $name 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp\Settings'
+  govc guest.reg.create -vm $name -volatile 'HKLM\SOFTWARE\MyApp\Session'`
+}
+
+func (cmd *regCreate) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.RegistryManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	return m.CreateKey(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), cmd.volatile, cmd.class)
+}
@@ -0,0 +1,125 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type regGet struct {
+	*flags.OutputFlag
+	*GuestFlag
+	RegistryFlag
+
+	expand bool
+}
+
+func init() {
+	cli.Register("guest.reg.get", &regGet{})
+}
+
+func (cmd *regGet) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+	cmd.RegistryFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.expand, "x", false, "Expand environment variables in REG_EXPAND_SZ values")
+}
+
+func (cmd *regGet) Process(ctx context.Context) error {
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.RegistryFlag.Process(ctx)
+}
+
+func (cmd *regGet) Usage() string {
+	return "KEY [NAME]..."
+}
+
+func (cmd *regGet) Description() string {
+	return `Display values of registry KEY in Windows guest.
+
+If NAME is specified, only those values are displayed.
+
+Examples:
+  govc guest.reg.get -vm $name 'HKLM\SOFTWARE\MyApp'
+  govc guest.reg.get -vm $name -x 'HKLM\SOFTWARE\MyApp' Home
+  govc guest.reg.get -vm $name -json 'HKLM\SOFTWARE\MyApp' Version Enabled`
+}
+
+type regGetResult struct {
+	Values []types.GuestRegValueSpec `json:"values"`
+}
+
+func regValueString(data types.BaseGuestRegValueDataSpec) string {
+	switch v := guest.RegistryValueData(data).(type) {
+	case []byte:
+		return hex.EncodeToString(v)
+	case []string:
+		return strings.Join(v, " ")
+	case uint32:
+		return fmt.Sprintf("%#x (%d)", v, v)
+	case uint64:
+		return fmt.Sprintf("%#x (%d)", v, v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (r *regGetResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, v := range r.Values {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Name.Name, guest.RegistryValueType(v.Data), regValueString(v.Data))
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *regGet) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	pattern := ""
+	if f.NArg() > 1 {
+		names := make([]string, f.NArg()-1)
+		for i, name := range f.Args()[1:] {
+			names[i] = regexp.QuoteMeta(name)
+		}
+		pattern = "(?i)^(" + strings.Join(names, "|") + ")$"
+	}
+
+	m, err := cmd.RegistryManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	values, err := m.ListValues(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), cmd.expand, pattern)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&regGetResult{values})
+}
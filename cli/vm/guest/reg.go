@@ -0,0 +1,39 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// RegistryFlag specifies the registry view of guest.reg commands
+type RegistryFlag struct {
+	wow string
+}
+
+func (flag *RegistryFlag) Register(ctx context.Context, f *flag.FlagSet) {
+	f.StringVar(&flag.wow, "wow", string(types.GuestRegKeyWowSpecWOWNative),
+		fmt.Sprintf("Registry view %s", types.GuestRegKeyWowSpec("").Strings()))
+}
+
+func (flag *RegistryFlag) Process(ctx context.Context) error {
+	if !slices.Contains(types.GuestRegKeyWowSpec("").Strings(), flag.wow) {
+		return fmt.Errorf("invalid -wow=%s", flag.wow)
+	}
+	return nil
+}
+
+// Key returns the key name spec for the given registry path
+func (flag *RegistryFlag) Key(path string) types.GuestRegKeyNameSpec {
+	key := guest.RegistryKey(path)
+	key.WowBitness = flag.wow
+	return key
+}
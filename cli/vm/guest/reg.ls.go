@@ -0,0 +1,101 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type regList struct {
+	*flags.OutputFlag
+	*GuestFlag
+	RegistryFlag
+
+	recursive bool
+	pattern   string
+}
+
+func init() {
+	cli.Register("guest.reg.ls", &regList{})
+}
+
+func (cmd *regList) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+	cmd.RegistryFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.recursive, "r", false, "List subkeys recursively")
+	f.StringVar(&cmd.pattern, "p", "", "Filter key names by regular expression")
+}
+
+func (cmd *regList) Process(ctx context.Context) error {
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.RegistryFlag.Process(ctx)
+}
+
+func (cmd *regList) Usage() string {
+	return "KEY"
+}
+
+func (cmd *regList) Description() string {
+	return `List subkeys of registry KEY in Windows guest.
+
+Examples:
+  govc guest.reg.ls -vm $name 'HKLM\SOFTWARE'
+  govc guest.reg.ls -vm $name -r -p '^MyApp' 'HKLM\SOFTWARE'`
+}
+
+type regListResult struct {
+	Keys []types.GuestRegKeyRecordSpec `json:"keys"`
+}
+
+func (r *regListResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, rec := range r.Keys {
+		if rec.Fault != nil {
+			fmt.Fprintf(tw, "%s\t%s\n", rec.Key.KeyName.RegistryPath, rec.Fault.LocalizedMessage)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", rec.Key.KeyName.RegistryPath, rec.Key.LastWritten.Format(time.Stamp))
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *regList) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.RegistryManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := m.ListKeys(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), cmd.recursive, cmd.pattern)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&regListResult{keys})
+}
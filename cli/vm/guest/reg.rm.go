@@ -0,0 +1,63 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+)
+
+type regRemove struct {
+	*GuestFlag
+	RegistryFlag
+
+	recursive bool
+}
+
+func init() {
+	cli.Register("guest.reg.rm", &regRemove{})
+}
+
+func (cmd *regRemove) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+	cmd.RegistryFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.recursive, "r", false, "Remove KEY subkeys")
+}
+
+func (cmd *regRemove) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.RegistryFlag.Process(ctx)
+}
+
+func (cmd *regRemove) Usage() string {
+	return "KEY"
+}
+
+func (cmd *regRemove) Description() string {
+	return `Remove registry KEY in Windows guest.
+
+Examples:
+  govc guest.reg.rm -vm $name 'HKLM\SOFTWARE\MyApp\Settings'
+  govc guest.reg.rm -vm $name -r 'HKLM\SOFTWARE\MyApp'`
+}
+
+func (cmd *regRemove) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.RegistryManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	return m.DeleteKey(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), cmd.recursive)
+}
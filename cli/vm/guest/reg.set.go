@@ -0,0 +1,109 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/guest"
+)
+
+type regSet struct {
+	*GuestFlag
+	RegistryFlag
+
+	kind string
+}
+
+var regTypes = []string{"REG_SZ", "REG_EXPAND_SZ", "REG_DWORD", "REG_QWORD", "REG_BINARY", "REG_MULTI_SZ"}
+
+func init() {
+	cli.Register("guest.reg.set", &regSet{})
+}
+
+func (cmd *regSet) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+	cmd.RegistryFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.kind, "t", regTypes[0], fmt.Sprintf("Value type %s", regTypes))
+}
+
+func (cmd *regSet) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.RegistryFlag.Process(ctx)
+}
+
+func (cmd *regSet) Usage() string {
+	return "KEY NAME VALUE..."
+}
+
+func (cmd *regSet) Description() string {
+	return `Set value NAME of registry KEY in Windows guest.
+
+REG_DWORD and REG_QWORD values can be decimal or prefixed with 0x for hex.
+REG_BINARY values are hex encoded.
+REG_MULTI_SZ values are specified as multiple VALUE arguments.
+
+Examples:
+  govc guest.reg.set -vm $name 'HKLM\SOFTWARE\MyApp' Version 1.0
+  govc guest.reg.set -vm $name -t REG_EXPAND_SZ 'HKLM\SOFTWARE\MyApp' Home '%ProgramFiles%\MyApp'
+  govc guest.reg.set -vm $name -t REG_DWORD 'HKLM\SOFTWARE\MyApp' Enabled 1
+  govc guest.reg.set -vm $name -t REG_BINARY 'HKLM\SOFTWARE\MyApp' Key cafebabe
+  govc guest.reg.set -vm $name -t REG_MULTI_SZ 'HKLM\SOFTWARE\MyApp' Hosts a.example.com b.example.com`
+}
+
+func regValue(kind string, args []string) (any, error) {
+	if kind == "REG_MULTI_SZ" {
+		return args, nil
+	}
+
+	if len(args) != 1 {
+		return nil, flag.ErrHelp
+	}
+	arg := args[0]
+
+	switch kind {
+	case "REG_SZ":
+		return arg, nil
+	case "REG_EXPAND_SZ":
+		return guest.ExpandString(arg), nil
+	case "REG_DWORD":
+		v, err := strconv.ParseUint(arg, 0, 32)
+		return uint32(v), err
+	case "REG_QWORD":
+		v, err := strconv.ParseUint(arg, 0, 64)
+		return v, err
+	case "REG_BINARY":
+		return hex.DecodeString(arg)
+	default:
+		return nil, fmt.Errorf("invalid -t=%s", kind)
+	}
+}
+
+func (cmd *regSet) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() < 3 {
+		return flag.ErrHelp
+	}
+
+	data, err := regValue(cmd.kind, f.Args()[2:])
+	if err != nil {
+		return err
+	}
+
+	m, err := cmd.RegistryManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	return m.Set(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), f.Arg(1), data)
+}
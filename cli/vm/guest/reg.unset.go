@@ -0,0 +1,64 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type regUnset struct {
+	*GuestFlag
+	RegistryFlag
+}
+
+func init() {
+	cli.Register("guest.reg.unset", &regUnset{})
+}
+
+func (cmd *regUnset) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+	cmd.RegistryFlag.Register(ctx, f)
+}
+
+func (cmd *regUnset) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.RegistryFlag.Process(ctx)
+}
+
+func (cmd *regUnset) Usage() string {
+	return "KEY NAME"
+}
+
+func (cmd *regUnset) Description() string {
+	return `Remove value NAME of registry KEY in Windows guest.
+
+Examples:
+  govc guest.reg.unset -vm $name 'HKLM\SOFTWARE\MyApp' Version`
+}
+
+func (cmd *regUnset) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.RegistryManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := types.GuestRegValueNameSpec{
+		KeyName: cmd.Key(f.Arg(0)),
+		Name:    f.Arg(1),
+	}
+
+	return m.DeleteValue(ctx, cmd.Auth(), name)
+}
@@ -10,6 +10,8 @@ import (
 	"flag"
 	"os"
 	"os/exec"
+	"os/signal"
+	"time"
 
 	"github.com/vmware/govmomi/cli"
 )
@@ -17,9 +19,11 @@ import (
 type run struct {
 	*GuestFlag
 
-	data string
-	dir  string
-	vars env
+	data    string
+	dir     string
+	vars    env
+	stream  bool
+	timeout time.Duration
 }
 
 func init() {
@@ -33,6 +37,17 @@ func (cmd *run) Register(ctx context.Context, f *flag.FlagSet) {
 	f.StringVar(&cmd.data, "d", "", "Input data string. A value of '-' reads from OS stdin")
 	f.StringVar(&cmd.dir, "C", "", "The absolute path of the working directory for the program to start")
 	f.Var(&cmd.vars, "e", "Set environment variables")
+	f.BoolVar(&cmd.stream, "stream", false, "Stream stdout and stderr while the program is running")
+	f.DurationVar(&cmd.timeout, "timeout", 0, "Terminate the program if it does not exit within the given duration")
+}
+
+func (cmd *run) Process(ctx context.Context) error {
+	if cmd.data == "-" {
+		if err := cmd.stdinConflict("-d -"); err != nil {
+			return err
+		}
+	}
+	return cmd.GuestFlag.Process(ctx)
 }
 
 func (cmd *run) Usage() string {
@@ -46,6 +61,12 @@ The guest.run command starts a program in the VM with i/o redirected, waits for
 propagates the exit code to the govc process exit code.  Note that stdout and stderr are redirected by default,
 stdin is only redirected when the '-d' flag is specified.
 
+By default, output is displayed once the program exits.  With the '-stream' flag, output is displayed while the
+program is running, by polling the redirected output files for growth, and input data is forwarded to the program
+as it is read.  Otherwise, or if the guest family is Windows, input data is read until EOF and copied to the guest
+before the program is started.  The program is terminated if the '-timeout' duration is exceeded or, when
+streaming, if govc is interrupted.
+
 Note that vmware-tools requires program PATH to be absolute.
 If PATH is not absolute and vm guest family is Windows,
 guest.run changes the command to: 'c:\\Windows\\System32\\cmd.exe /c "PATH [ARG]..."'
@@ -59,7 +80,8 @@ Examples:
   govc guest.run -vm $name curl -s :invalid: || echo $? # exit code 6
   govc guest.run -vm $name -e FOO=bar -e BIZ=baz -C /tmp env
   govc guest.run -vm $name -l root:mypassword ntpdate -u pool.ntp.org
-  govc guest.run -vm $name powershell C:\\network_refresh.ps1`
+  govc guest.run -vm $name powershell C:\\network_refresh.ps1
+  govc guest.run -vm $name -stream -timeout 10m /opt/app/install.sh`
 }
 
 func (cmd *run) Run(ctx context.Context, f *flag.FlagSet) error {
@@ -90,5 +112,19 @@ func (cmd *run) Run(ctx context.Context, f *flag.FlagSet) error {
 		ecmd.Stdin = bytes.NewBuffer([]byte(cmd.data))
 	}
 
+	if cmd.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cmd.timeout)
+		defer cancel()
+	}
+
+	if cmd.stream {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+
+		return c.Stream(ctx, ecmd, 0)
+	}
+
 	return c.Run(ctx, ecmd)
 }
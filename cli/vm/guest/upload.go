@@ -12,7 +12,9 @@ import (
 	"path/filepath"
 
 	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/guest"
 	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 type upload struct {
@@ -20,6 +22,8 @@ type upload struct {
 	*FileAttrFlag
 
 	overwrite bool
+	recursive bool
+	transfer  guest.TransferOptions
 }
 
 func init() {
@@ -33,6 +37,9 @@ func (cmd *upload) Register(ctx context.Context, f *flag.FlagSet) {
 	cmd.FileAttrFlag.Register(ctx, f)
 
 	f.BoolVar(&cmd.overwrite, "f", false, "If set, the guest destination file is clobbered")
+	f.BoolVar(&cmd.recursive, "r", false, "Recursively upload SOURCE directory")
+	f.IntVar(&cmd.transfer.Concurrency, "c", guest.DefaultTransferConcurrency, "Number of concurrent file transfers with -r")
+	f.BoolVar(&cmd.transfer.Resume, "resume", false, "Skip files where DEST has the same size and modification time with -r")
 }
 
 func (cmd *upload) Usage() string {
@@ -44,9 +51,18 @@ func (cmd *upload) Description() string {
 
 If SOURCE name is "-", read source from stdin.
 
+With the '-r' flag, SOURCE directory is copied to DEST directory in the guest, preserving file permissions
+and modification times. Symlinks and other special files are skipped.
+The directory is transferred as a single gzip'd tar if supported by guest tools, otherwise or if DEST exists
+without the '-f' flag, one file transfer is used per regular file.
+If the upload fails, it can be run again with the '-resume' flag to skip files that were already copied,
+which always uses one file transfer per regular file.
+
 Examples:
   govc guest.upload -l user:pass -vm=my-vm ~/.ssh/id_rsa.pub /home/$USER/.ssh/authorized_keys
   cowsay "have a great day" | govc guest.upload -l user:pass -vm=my-vm - /etc/motd
+  govc guest.upload -l user:pass -vm=my-vm -r ./foo /tmp/foo
+  govc guest.upload -l user:pass -vm=my-vm -r -resume ./foo /tmp/foo
   tar -cf- foo/ | govc guest.run -d - tar -C /tmp -xf- # upload a directory`
 }
 
@@ -65,13 +81,33 @@ func (cmd *upload) Run(ctx context.Context, f *flag.FlagSet) error {
 		return flag.ErrHelp
 	}
 
+	src := f.Arg(0)
+	dst := f.Arg(1)
+
+	if src == "-" {
+		if err := cmd.stdinConflict("SOURCE \"-\""); err != nil {
+			return err
+		}
+	}
+
 	c, err := cmd.Toolbox(ctx)
 	if err != nil {
 		return err
 	}
 
-	src := f.Arg(0)
-	dst := f.Arg(1)
+	if cmd.recursive {
+		cmd.transfer.Overwrite = cmd.overwrite
+		if c.GuestFamily == types.VirtualMachineGuestOsFamilyWindowsGuest {
+			cmd.transfer.Attributes = func(info os.FileInfo) types.BaseGuestFileAttributes {
+				return &types.GuestWindowsFileAttributes{
+					GuestFileAttributes: types.GuestFileAttributes{
+						ModificationTime: types.NewTime(info.ModTime()),
+					},
+				}
+			}
+		}
+		return c.UploadDirectory(ctx, src, dst, &cmd.transfer)
+	}
 
 	p := soap.DefaultUpload
 
@@ -0,0 +1,79 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileshare
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+)
+
+type change struct {
+	*flags.ClusterFlag
+
+	configFlag
+
+	rmLabel []string
+	force   bool
+}
+
+func init() {
+	cli.Register("vsan.fileshare.change", &change{})
+}
+
+func (cmd *change) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClusterFlag, ctx = flags.NewClusterFlag(ctx)
+	cmd.ClusterFlag.Register(ctx, f)
+
+	cmd.configFlag.Register(ctx, f)
+
+	f.Var((*flags.StringList)(&cmd.rmLabel), "rm-label", "Remove label KEY")
+	f.BoolVar(&cmd.force, "force", false, "Change a file share managed by another solution, such as CNS")
+}
+
+func (cmd *change) Usage() string {
+	return "NAME"
+}
+
+func (cmd *change) Description() string {
+	return `Change vSAN file share.
+
+Permissions specified with -permission replace the existing permissions.
+
+Examples:
+  govc vsan.fileshare.change -cluster ClusterA -quota 20GB share1
+  govc vsan.fileshare.change -cluster ClusterA -permission "*=READ_ONLY" share1
+  govc vsan.fileshare.change -cluster ClusterA -label env=prod -rm-label owner share1`
+}
+
+func (cmd *change) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, cluster, err := client(ctx, cmd.ClusterFlag)
+	if err != nil {
+		return err
+	}
+
+	shares, err := find(ctx, c, cluster, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	task, err := c.ReconfigureFileShare(ctx, cluster, shares[0].Uuid, cmd.VsanFileShareConfig, cmd.force, cmd.rmLabel...)
+	if err != nil {
+		return err
+	}
+
+	logger := cmd.ProgressLogger(fmt.Sprintf("Reconfiguring file share %s...", f.Arg(0)))
+	defer logger.Wait()
+
+	_, err = task.WaitForResult(ctx, logger)
+	return err
+}
@@ -0,0 +1,127 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileshare
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/govmomi/cli/flags"
+	vim "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vsan"
+	"github.com/vmware/govmomi/vsan/types"
+)
+
+type permission []types.VsanFileShareNetPermission
+
+func (p *permission) String() string {
+	return fmt.Sprintf("%v", *p)
+}
+
+// Set parses IPS[=ACCESS], where ACCESS defaults to READ_WRITE.
+func (p *permission) Set(v string) error {
+	ips, access, ok := strings.Cut(v, "=")
+	if !ok {
+		access = "READ_WRITE"
+	}
+	if ips == "" {
+		return fmt.Errorf("failed to parse: %s", v)
+	}
+	*p = append(*p, types.VsanFileShareNetPermission{
+		Ips:         ips,
+		Permissions: strings.ToUpper(access),
+		AllowRoot:   vim.NewBool(true),
+	})
+	return nil
+}
+
+type label []vim.KeyValue
+
+func (l *label) String() string {
+	return fmt.Sprintf("%v", *l)
+}
+
+func (l *label) Set(v string) error {
+	key, val, ok := strings.Cut(v, "=")
+	if !ok {
+		return fmt.Errorf("failed to parse: %s", v)
+	}
+	*l = append(*l, vim.KeyValue{Key: key, Value: val})
+	return nil
+}
+
+// configFlag registers the file share config flags shared by create and change.
+type configFlag struct {
+	types.VsanFileShareConfig
+}
+
+func (f *configFlag) Register(ctx context.Context, fs *flag.FlagSet) {
+	fs.StringVar(&f.Quota, "quota", "", "Hard quota (e.g. 10GB)")
+	fs.StringVar(&f.SoftQuota, "soft-quota", "", "Soft quota (e.g. 8GB)")
+	fs.Var((*flags.StringList)(&f.Protocols), "protocol", "Access protocols (NFSv3,NFSv4,SMB)")
+	fs.Var((*permission)(&f.Permission), "permission", "NFS permission IPS[=READ_ONLY|READ_WRITE|NO_ACCESS]")
+	fs.Var((*label)(&f.Labels), "label", "Label KEY=VALUE")
+}
+
+// find returns the file shares on the cluster with the given names.
+func find(ctx context.Context, c *vsan.Client, cluster vim.ManagedObjectReference, names ...string) ([]types.VsanFileShare, error) {
+	var shares []types.VsanFileShare
+
+	spec := types.VsanFileShareQuerySpec{Names: names}
+
+	for {
+		page, err := c.QueryFileShares(ctx, cluster, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, page.FileShares...)
+
+		if page.NextOffset == "" {
+			break
+		}
+		spec.Offset = page.NextOffset
+	}
+
+	for _, name := range names {
+		found := false
+		for _, share := range shares {
+			if share.Config.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("file share %q not found", name)
+		}
+	}
+
+	return shares, nil
+}
+
+func client(ctx context.Context, cmd *flags.ClusterFlag) (*vsan.Client, vim.ManagedObjectReference, error) {
+	var ref vim.ManagedObjectReference
+
+	vc, err := cmd.Client()
+	if err != nil {
+		return nil, ref, err
+	}
+
+	cluster, err := cmd.Cluster()
+	if err != nil {
+		return nil, ref, err
+	}
+
+	c, err := vsan.NewClient(ctx, vc)
+	if err != nil {
+		return nil, ref, err
+	}
+
+	c.RoundTripper = cmd.RoundTripper(c.Client)
+
+	return c, cluster.Reference(), nil
+}
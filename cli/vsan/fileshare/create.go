@@ -0,0 +1,71 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileshare
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+)
+
+type create struct {
+	*flags.ClusterFlag
+
+	configFlag
+}
+
+func init() {
+	cli.Register("vsan.fileshare.create", &create{})
+}
+
+func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClusterFlag, ctx = flags.NewClusterFlag(ctx)
+	cmd.ClusterFlag.Register(ctx, f)
+
+	cmd.configFlag.Register(ctx, f)
+}
+
+func (cmd *create) Usage() string {
+	return "NAME"
+}
+
+func (cmd *create) Description() string {
+	return `Create vSAN file share.
+
+The vSAN file service must be enabled on the cluster, see 'govc vsan.change -file-service-enabled'.
+
+Examples:
+  govc vsan.fileshare.create -cluster ClusterA -quota 10GB share1
+  govc vsan.fileshare.create -cluster ClusterA -protocol NFSv4 -permission "10.0.0.0/24=READ_ONLY" share2
+  govc vsan.fileshare.create -cluster ClusterA -protocol SMB -label env=dev share3`
+}
+
+func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, cluster, err := client(ctx, cmd.ClusterFlag)
+	if err != nil {
+		return err
+	}
+
+	config := cmd.VsanFileShareConfig
+	config.Name = f.Arg(0)
+
+	task, err := c.CreateFileShare(ctx, cluster, config)
+	if err != nil {
+		return err
+	}
+
+	logger := cmd.ProgressLogger(fmt.Sprintf("Creating file share %s...", config.Name))
+	defer logger.Wait()
+
+	_, err = task.WaitForResult(ctx, logger)
+	return err
+}
@@ -0,0 +1,120 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileshare
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vsan/types"
+)
+
+type ls struct {
+	*flags.ClusterFlag
+	*flags.OutputFlag
+
+	long      bool
+	managedBy string
+}
+
+func init() {
+	cli.Register("vsan.fileshare.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClusterFlag, ctx = flags.NewClusterFlag(ctx)
+	cmd.ClusterFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.long, "l", false, "Long listing format")
+	f.StringVar(&cmd.managedBy, "managed-by", "", "List file shares managed by (user,cns)")
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClusterFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *ls) Usage() string {
+	return "[NAME...]"
+}
+
+func (cmd *ls) Description() string {
+	return `List vSAN file shares.
+
+Examples:
+  govc vsan.fileshare.ls -cluster ClusterA
+  govc vsan.fileshare.ls -cluster ClusterA -l share1
+  govc vsan.fileshare.ls -cluster ClusterA -managed-by cns
+  govc vsan.fileshare.ls -cluster ClusterA -json | jq -r '.fileShares[].Runtime.AccessPoints'`
+}
+
+type lsResult struct {
+	FileShares []types.VsanFileShare `json:"fileShares"`
+	cmd        *ls
+}
+
+func (r *lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(r.cmd.Out, 2, 0, 2, ' ', 0)
+
+	for _, share := range r.FileShares {
+		fmt.Fprintf(tw, "%s\t%s", share.Config.Name, share.Uuid)
+		if r.cmd.long {
+			var points []string
+			for _, p := range share.Runtime.AccessPoints {
+				points = append(points, p.Value)
+			}
+			fmt.Fprintf(tw, "\t%s\t%s\t%s\t%s",
+				share.Config.Quota, strings.Join(share.Config.Protocols, ","), share.Runtime.ManagedBy, strings.Join(points, ","))
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, cluster, err := client(ctx, cmd.ClusterFlag)
+	if err != nil {
+		return err
+	}
+
+	spec := types.VsanFileShareQuerySpec{
+		Names: f.Args(),
+	}
+	if cmd.managedBy != "" {
+		spec.ManagedBy = []string{cmd.managedBy}
+	}
+
+	var res lsResult
+
+	for {
+		page, err := c.QueryFileShares(ctx, cluster, spec)
+		if err != nil {
+			return err
+		}
+
+		res.FileShares = append(res.FileShares, page.FileShares...)
+
+		if page.NextOffset == "" {
+			break
+		}
+		spec.Offset = page.NextOffset
+	}
+
+	res.cmd = cmd
+
+	return cmd.WriteResult(&res)
+}
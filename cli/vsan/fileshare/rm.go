@@ -0,0 +1,74 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package fileshare
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+)
+
+type rm struct {
+	*flags.ClusterFlag
+
+	force bool
+}
+
+func init() {
+	cli.Register("vsan.fileshare.rm", &rm{})
+}
+
+func (cmd *rm) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClusterFlag, ctx = flags.NewClusterFlag(ctx)
+	cmd.ClusterFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.force, "force", false, "Remove a file share managed by another solution, such as CNS")
+}
+
+func (cmd *rm) Usage() string {
+	return "NAME..."
+}
+
+func (cmd *rm) Description() string {
+	return `Remove vSAN file shares.
+
+Examples:
+  govc vsan.fileshare.rm -cluster ClusterA share1 share2`
+}
+
+func (cmd *rm) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, cluster, err := client(ctx, cmd.ClusterFlag)
+	if err != nil {
+		return err
+	}
+
+	shares, err := find(ctx, c, cluster, f.Args()...)
+	if err != nil {
+		return err
+	}
+
+	for _, share := range shares {
+		task, err := c.RemoveFileShare(ctx, cluster, share.Uuid, cmd.force)
+		if err != nil {
+			return err
+		}
+
+		logger := cmd.ProgressLogger(fmt.Sprintf("Removing file share %s...", share.Config.Name))
+		_, err = task.WaitForResult(ctx, logger)
+		logger.Wait()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
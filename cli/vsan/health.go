@@ -0,0 +1,110 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsan
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vsan"
+	"github.com/vmware/govmomi/vsan/types"
+)
+
+type health struct {
+	*flags.DatacenterFlag
+
+	long  bool
+	cache bool
+}
+
+func init() {
+	cli.Register("vsan.health", &health{})
+}
+
+func (cmd *health) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.DatacenterFlag, ctx = flags.NewDatacenterFlag(ctx)
+	cmd.DatacenterFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.long, "l", false, "Long listing format, including each health test")
+	f.BoolVar(&cmd.cache, "cache", false, "Return cached results instead of running the health tests")
+}
+
+func (cmd *health) Usage() string {
+	return "CLUSTER"
+}
+
+func (cmd *health) Description() string {
+	return `Display vSAN health summary.
+
+Examples:
+  govc vsan.health ClusterA
+  govc vsan.health -l ClusterA
+  govc vsan.health -json ClusterA | jq -r .ObjectHealth`
+}
+
+func (cmd *health) Run(ctx context.Context, f *flag.FlagSet) error {
+	vc, err := cmd.Client()
+	if err != nil {
+		return err
+	}
+
+	finder, err := cmd.Finder()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := finder.ClusterComputeResourceOrDefault(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c, err := vsan.NewClient(ctx, vc)
+	if err != nil {
+		return err
+	}
+
+	c.RoundTripper = cmd.RoundTripper(c.Client)
+
+	summary, err := c.VsanQueryVcClusterHealthSummary(ctx, cluster.Reference(), nil, cmd.cache)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&healthResult{summary, cmd})
+}
+
+type healthResult struct {
+	*types.VsanClusterHealthSummary
+	cmd *health
+}
+
+func (r *healthResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Overall health:\t%s\n", r.OverallHealth)
+
+	for _, group := range r.Groups {
+		fmt.Fprintf(tw, "%s:\t%s\n", group.GroupName, group.GroupHealth)
+		if !r.cmd.long {
+			continue
+		}
+		for _, test := range group.GroupTests {
+			fmt.Fprintf(tw, "  %s:\t%s\n", test.TestName, test.TestHealth)
+		}
+	}
+
+	if oh := r.ObjectHealth; oh != nil {
+		for _, detail := range oh.ObjectHealthDetail {
+			fmt.Fprintf(tw, "Objects %s:\t%d\n", detail.Health, detail.NumObjects)
+		}
+	}
+
+	return tw.Flush()
+}
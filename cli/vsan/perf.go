@@ -0,0 +1,128 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vsan
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/cli"
+	"github.com/vmware/govmomi/cli/flags"
+	"github.com/vmware/govmomi/vsan"
+	"github.com/vmware/govmomi/vsan/types"
+)
+
+type perf struct {
+	*flags.DatacenterFlag
+
+	entity   string
+	labels   string
+	samples  int
+	interval int
+}
+
+func init() {
+	cli.Register("vsan.perf", &perf{})
+}
+
+func (cmd *perf) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.DatacenterFlag, ctx = flags.NewDatacenterFlag(ctx)
+	cmd.DatacenterFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.entity, "entity", "cluster-domclient", "Entity TYPE[:UUID], where UUID defaults to all entities of TYPE")
+	f.StringVar(&cmd.labels, "label", "", "Comma separated metric labels (default all)")
+	f.IntVar(&cmd.samples, "n", 6, "Max number of samples")
+	f.IntVar(&cmd.interval, "i", 300, "Sample interval in seconds")
+}
+
+func (cmd *perf) Usage() string {
+	return "CLUSTER"
+}
+
+func (cmd *perf) Description() string {
+	return `Query vSAN performance stats.
+
+Entity types include cluster-domclient, cluster-domcompmgr, host-domclient, host-domcompmgr and disk-group.
+
+Examples:
+  govc vsan.perf ClusterA
+  govc vsan.perf -entity host-domclient -label iopsRead,iopsWrite ClusterA
+  govc vsan.perf -entity disk-group -n 12 -json ClusterA`
+}
+
+func (cmd *perf) Run(ctx context.Context, f *flag.FlagSet) error {
+	vc, err := cmd.Client()
+	if err != nil {
+		return err
+	}
+
+	finder, err := cmd.Finder()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := finder.ClusterComputeResourceOrDefault(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c, err := vsan.NewClient(ctx, vc)
+	if err != nil {
+		return err
+	}
+
+	c.RoundTripper = cmd.RoundTripper(c.Client)
+
+	entity := cmd.entity
+	if !strings.Contains(entity, ":") {
+		entity += ":*"
+	}
+
+	interval := time.Duration(cmd.interval) * time.Second
+	end := time.Now().Truncate(interval)
+	start := end.Add(-time.Duration(cmd.samples-1) * interval)
+
+	var labels []string
+	if cmd.labels != "" {
+		labels = strings.Split(cmd.labels, ",")
+	}
+
+	spec := types.VsanPerfQuerySpec{
+		EntityRefId: entity,
+		StartTime:   &start,
+		EndTime:     &end,
+		Labels:      labels,
+		Interval:    int32(cmd.interval),
+	}
+
+	ref := cluster.Reference()
+	res, err := c.VsanPerfQueryPerf(ctx, &ref, []types.VsanPerfQuerySpec{spec})
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&perfResult{res})
+}
+
+type perfResult struct {
+	Entities []types.VsanPerfEntityMetricCSV `json:"entities"`
+}
+
+func (r *perfResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, entity := range r.Entities {
+		for _, metric := range entity.Value {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", entity.EntityRefId, metric.MetricId.Label, metric.Values)
+		}
+	}
+
+	return tw.Flush()
+}
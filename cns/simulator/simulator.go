@@ -6,6 +6,7 @@ package simulator
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"time"
 
@@ -21,6 +22,10 @@ import (
 	vim25methods "github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/soap"
 	vim25types "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vsan"
+	vsansim "github.com/vmware/govmomi/vsan/simulator"
+	vsantypes "github.com/vmware/govmomi/vsan/types"
+	vsanfstypes "github.com/vmware/govmomi/vsan/vsanfs/types"
 )
 
 func init() {
@@ -36,11 +41,17 @@ func New() *simulator.Registry {
 	r.Namespace = cns.Namespace
 	r.Path = cns.Path
 
-	r.Put(&CnsVolumeManager{
+	m := &CnsVolumeManager{
 		ManagedObjectReference: cns.CnsVolumeManagerInstance,
 		volumes:                make(map[vim25types.ManagedObjectReference]map[cnstypes.CnsVolumeId]*cnstypes.CnsVolume),
 		attachments:            make(map[cnstypes.CnsVolumeId]vim25types.ManagedObjectReference),
 		snapshots:              make(map[cnstypes.CnsVolumeId]map[cnstypes.CnsSnapshotId]*cnstypes.CnsSnapshot),
+	}
+
+	r.Put(m)
+	r.Put(&CnsDebugManager{
+		ManagedObjectReference: cns.CnsDebugManagerInstance,
+		volumeManager:          m,
 	})
 
 	return r
@@ -53,6 +64,166 @@ type CnsVolumeManager struct {
 	snapshots   map[cnstypes.CnsVolumeId]map[cnstypes.CnsSnapshotId]*cnstypes.CnsSnapshot
 }
 
+// volume returns the volume with the given ID and the Datastore where it is placed, if any.
+func (m *CnsVolumeManager) volume(id cnstypes.CnsVolumeId) (*cnstypes.CnsVolume, vim25types.ManagedObjectReference) {
+	for ds, volumes := range m.volumes {
+		if volume, ok := volumes[id]; ok {
+			return volume, ds
+		}
+	}
+	return nil, vim25types.ManagedObjectReference{}
+}
+
+// fileService returns the vSAN file service, which backs file volumes.
+func fileService(ctx *simulator.Context) *vsansim.FileServiceSystem {
+	return ctx.For(vsan.Path).Map.Get(vsan.VsanFileServiceSystemInstance).(*vsansim.FileServiceSystem)
+}
+
+// fileShareID returns the vSAN file share uuid of a file volume.
+func fileShareID(volume *cnstypes.CnsVolume) string {
+	return volume.BackingObjectDetails.(*cnstypes.CnsVsanFileShareBackingDetails).BackingFileId
+}
+
+// datastoreCluster returns the cluster of a host that mounts the given Datastore, if any.
+func datastoreCluster(vctx *simulator.Context, ds *simulator.Datastore) *vim25types.ManagedObjectReference {
+	for _, mount := range ds.Host {
+		if host, ok := vctx.Map.Get(mount.Key).(*simulator.HostSystem); ok {
+			if host.Parent != nil && host.Parent.Type == "ClusterComputeResource" {
+				return host.Parent
+			}
+		}
+	}
+	return nil
+}
+
+// netPermissions converts CNS file volume permissions to vSAN file share permissions.
+func netPermissions(acl []vsanfstypes.VsanFileShareNetPermission) []vsantypes.VsanFileShareNetPermission {
+	perms := make([]vsantypes.VsanFileShareNetPermission, len(acl))
+	for i, p := range acl {
+		perms[i] = vsantypes.VsanFileShareNetPermission{
+			Ips:         p.Ips,
+			Permissions: string(p.Permissions),
+			AllowRoot:   vim25types.NewBool(p.AllowRoot),
+		}
+	}
+	return perms
+}
+
+// filePermissions converts vSAN file share permissions to CNS file volume permissions.
+func filePermissions(acl []vsantypes.VsanFileShareNetPermission) []vsanfstypes.VsanFileShareNetPermission {
+	var perms []vsanfstypes.VsanFileShareNetPermission
+	for _, p := range acl {
+		perms = append(perms, vsanfstypes.VsanFileShareNetPermission{
+			Ips:         p.Ips,
+			Permissions: vsanfstypes.VsanFileShareAccessType(p.Permissions),
+			AllowRoot:   p.AllowRoot != nil && *p.AllowRoot,
+		})
+	}
+	return perms
+}
+
+// createFileShare creates the vSAN file share backing a new file volume, on the cluster of the given Datastore.
+// The vSAN file service must be enabled on the cluster.
+func (m *CnsVolumeManager) createFileShare(vctx *simulator.Context, datastore *simulator.Datastore, createSpec cnstypes.CnsVolumeCreateSpec) (*cnstypes.CnsVsanFileShareBackingDetails, vim25types.BaseMethodFault) {
+	details := createSpec.BackingObjectDetails.(*cnstypes.CnsVsanFileShareBackingDetails)
+	cc := createSpec.Metadata.ContainerCluster
+
+	config := vsantypes.VsanFileShareConfig{
+		Name:  createSpec.Name,
+		Quota: fmt.Sprintf("%dMB", details.CapacityInMb),
+		Labels: []vim25types.KeyValue{
+			{Key: "cns.containerCluster.clusterId", Value: cc.ClusterId},
+			{Key: "cns.containerCluster.clusterFlavor", Value: cc.ClusterFlavor},
+		},
+	}
+
+	if spec, ok := createSpec.CreateSpec.(*cnstypes.CnsVSANFileCreateSpec); ok {
+		config.Permission = netPermissions(spec.Permission)
+		if spec.SoftQuotaInMb != 0 {
+			config.SoftQuota = fmt.Sprintf("%dMB", spec.SoftQuotaInMb)
+		}
+	}
+
+	fs := fileService(vctx)
+
+	var share *vsantypes.VsanFileShare
+	var fault vim25types.BaseMethodFault
+
+	vctx.WithLock(fs, func() {
+		share, fault = fs.CreateShare(vctx, datastoreCluster(vctx, datastore), config, vsansim.ManagedByCNS)
+	})
+
+	if fault != nil {
+		return nil, fault
+	}
+
+	return &cnstypes.CnsVsanFileShareBackingDetails{
+		CnsFileBackingDetails: cnstypes.CnsFileBackingDetails{
+			CnsBackingObjectDetails: details.CnsBackingObjectDetails,
+			BackingFileId:           share.Uuid,
+		},
+		Name:         share.Config.Name,
+		AccessPoints: share.Runtime.AccessPoints,
+	}, nil
+}
+
+// removeFileShare removes the vSAN file share backing a file volume.
+func (m *CnsVolumeManager) removeFileShare(vctx *simulator.Context, volume *cnstypes.CnsVolume) vim25types.BaseMethodFault {
+	var fault vim25types.BaseMethodFault
+
+	fs := fileService(vctx)
+	vctx.WithLock(fs, func() {
+		fault = fs.RemoveShare(nil, fileShareID(volume), true)
+	})
+
+	return fault
+}
+
+// fileVolumeACL returns the NFS access control list of a file volume, from its vSAN file share.
+func (m *CnsVolumeManager) fileVolumeACL(vctx *simulator.Context, volume *cnstypes.CnsVolume) ([]vsanfstypes.VsanFileShareNetPermission, vim25types.BaseMethodFault) {
+	var share *vsantypes.VsanFileShare
+
+	fs := fileService(vctx)
+	vctx.WithLock(fs, func() {
+		share = fs.Share(fileShareID(volume))
+	})
+
+	if share == nil {
+		return nil, &cnstypes.CnsVolumeNotFoundFault{VolumeId: volume.VolumeId}
+	}
+
+	return filePermissions(share.Config.Permission), nil
+}
+
+// setFileVolumeACL replaces the NFS access control list of a file volume, on its vSAN file share.
+func (m *CnsVolumeManager) setFileVolumeACL(vctx *simulator.Context, volume *cnstypes.CnsVolume, acl []vsanfstypes.VsanFileShareNetPermission) vim25types.BaseMethodFault {
+	var fault vim25types.BaseMethodFault
+
+	config := vsantypes.VsanFileShareConfig{Permission: netPermissions(acl)}
+
+	fs := fileService(vctx)
+	vctx.WithLock(fs, func() {
+		fault = fs.ReconfigureShare(nil, fileShareID(volume), config, nil, true)
+	})
+
+	return fault
+}
+
+func volumeNotFound(id cnstypes.CnsVolumeId) *vim25types.LocalizedMethodFault {
+	return &vim25types.LocalizedMethodFault{
+		Fault: &cnstypes.CnsVolumeNotFoundFault{VolumeId: id},
+	}
+}
+
+func profileID(profile []vim25types.BaseVirtualMachineProfileSpec) string {
+	if len(profile) != 0 {
+		if spec, ok := profile[0].(*vim25types.VirtualMachineDefinedProfileSpec); ok {
+			return spec.ProfileId
+		}
+	}
+	return ""
+}
+
 const simulatorDiskUUID = "6000c298595bf4575739e9105b2c0c2d"
 
 func (m *CnsVolumeManager) findDisk(vctx *simulator.Context, createSpec cnstypes.CnsVolumeCreateSpec) (*vim25types.VStorageObject, vim25types.BaseMethodFault) {
@@ -62,6 +233,9 @@ func (m *CnsVolumeManager) findDisk(vctx *simulator.Context, createSpec cnstypes
 	for _, objs := range vsom.Catalog() {
 		for id, val := range objs {
 			if id.Id == details.BackingDiskId {
+				if policy := profileID(createSpec.Profile); policy != "" {
+					val.StorageProfile = policy
+				}
 				return &val.VStorageObject, nil
 			}
 		}
@@ -106,6 +280,58 @@ func (m *CnsVolumeManager) createDisk(vctx *simulator.Context, createSpec cnstyp
 	return task.Info.Result.(*vim25types.VStorageObject), nil
 }
 
+// createFileVolume creates a volume backed by a file share, without a backing first class disk.
+func (m *CnsVolumeManager) createFileVolume(vctx *simulator.Context, createSpec cnstypes.CnsVolumeCreateSpec) (vim25types.AnyType, vim25types.BaseMethodFault) {
+	if len(createSpec.Datastores) == 0 {
+		return nil, &vim25types.InvalidArgument{InvalidProperty: "createSpecs.datastores"}
+	}
+
+	datastore, ok := vctx.Map.Get(createSpec.Datastores[0]).(*simulator.Datastore)
+	if !ok {
+		return nil, &vim25types.ManagedObjectNotFound{Obj: createSpec.Datastores[0]}
+	}
+
+	backing, fault := m.createFileShare(vctx, datastore, createSpec)
+	if fault != nil {
+		return nil, fault
+	}
+
+	volumes, ok := m.volumes[datastore.Self]
+	if !ok {
+		volumes = make(map[cnstypes.CnsVolumeId]*cnstypes.CnsVolume)
+		m.volumes[datastore.Self] = volumes
+	}
+
+	volume := &cnstypes.CnsVolume{
+		VolumeId:                     cnstypes.CnsVolumeId{Id: "file:" + backing.BackingFileId},
+		Name:                         createSpec.Name,
+		VolumeType:                   string(cnstypes.CnsVolumeTypeFile),
+		DatastoreUrl:                 datastore.Info.GetDatastoreInfo().Url,
+		Metadata:                     createSpec.Metadata,
+		BackingObjectDetails:         backing,
+		ComplianceStatus:             string(pbmtypes.PbmComplianceStatusCompliant),
+		DatastoreAccessibilityStatus: string(pbmtypes.PbmHealthStatusForEntityGreen),
+		HealthStatus:                 string(pbmtypes.PbmHealthStatusForEntityGreen),
+		StoragePolicyId:              profileID(createSpec.Profile),
+	}
+
+	volumes[volume.VolumeId] = volume
+
+	return &cnstypes.CnsVolumeOperationBatchResult{
+		VolumeResults: []cnstypes.BaseCnsVolumeOperationResult{
+			&cnstypes.CnsVolumeCreateResult{
+				CnsVolumeOperationResult: cnstypes.CnsVolumeOperationResult{
+					VolumeId: volume.VolumeId,
+				},
+				Name: volume.Name,
+				PlacementResults: []cnstypes.CnsPlacementResult{{
+					Datastore: datastore.Reference(),
+				}},
+			},
+		},
+	}, nil
+}
+
 func (m *CnsVolumeManager) CnsCreateVolume(ctx *simulator.Context, req *cnstypes.CnsCreateVolume) soap.HasFault {
 	vctx := ctx.For(vim25.Path)
 	vsom := vctx.Map.VStorageObjectManager()
@@ -130,6 +356,8 @@ func (m *CnsVolumeManager) CnsCreateVolume(ctx *simulator.Context, req *cnstypes
 			if fault != nil {
 				return nil, fault
 			}
+		case *cnstypes.CnsVsanFileShareBackingDetails:
+			return m.createFileVolume(vctx, createSpec)
 		default:
 			return nil, &vim25types.InvalidArgument{InvalidProperty: "createSpecs.backingObjectDetails"}
 		}
@@ -143,13 +371,6 @@ func (m *CnsVolumeManager) CnsCreateVolume(ctx *simulator.Context, req *cnstypes
 			m.volumes[datastore.Self] = volumes
 		}
 
-		policyId := ""
-		if len(createSpec.Profile) != 0 {
-			if profileSpec, ok := createSpec.Profile[0].(*vim25types.VirtualMachineDefinedProfileSpec); ok {
-				policyId = profileSpec.ProfileId
-			}
-		}
-
 		volume := &cnstypes.CnsVolume{
 			VolumeId:     cnstypes.CnsVolumeId(obj.Config.Id),
 			Name:         createSpec.Name,
@@ -166,7 +387,7 @@ func (m *CnsVolumeManager) CnsCreateVolume(ctx *simulator.Context, req *cnstypes
 			ComplianceStatus:             string(pbmtypes.PbmComplianceStatusCompliant),
 			DatastoreAccessibilityStatus: string(pbmtypes.PbmHealthStatusForEntityGreen),
 			HealthStatus:                 string(pbmtypes.PbmHealthStatusForEntityGreen),
-			StoragePolicyId:              policyId,
+			StoragePolicyId:              profileID(createSpec.Profile),
 		}
 
 		volumes[volume.VolumeId] = volume
@@ -342,11 +563,15 @@ func (m *CnsVolumeManager) CnsDeleteVolume(ctx *simulator.Context, req *cnstypes
 		}
 
 		for ds, volumes := range m.volumes {
-			if _, ok := volumes[volumeId]; ok {
+			if volume, ok := volumes[volumeId]; ok {
 				found = true
 				delete(m.volumes[ds], volumeId)
 
-				if req.DeleteDisk {
+				if volume.VolumeType == string(cnstypes.CnsVolumeTypeFile) {
+					if err := m.removeFileShare(vctx, volume); err != nil {
+						res.Fault = &vim25types.LocalizedMethodFault{Fault: err}
+					}
+				} else if req.DeleteDisk {
 					val := vsom.DeleteVStorageObjectTask(vctx, &vim25types.DeleteVStorageObject_Task{
 						This:      vsom.Self,
 						Id:        vim25types.ID(volumeId),
@@ -763,3 +988,396 @@ func (m *CnsVolumeManager) CnsQuerySnapshots(ctx *simulator.Context, req *cnstyp
 		},
 	}
 }
+
+// CnsRelocateVolume simulates RelocateVolume call for simulated vc.
+// The backing first class disk is moved to the target Datastore, along with any profile change.
+func (m *CnsVolumeManager) CnsRelocateVolume(ctx *simulator.Context, req *cnstypes.CnsRelocateVolume) soap.HasFault {
+	vctx := ctx.For(vim25.Path)
+	vsom := vctx.Map.VStorageObjectManager()
+
+	task := simulator.CreateTask(m, "CnsRelocateVolume", func(*simulator.Task) (vim25types.AnyType, vim25types.BaseMethodFault) {
+		if len(req.RelocateSpecs) == 0 {
+			return nil, &vim25types.InvalidArgument{InvalidProperty: "CnsVolumeRelocateSpec"}
+		}
+
+		var operationResult []cnstypes.BaseCnsVolumeOperationResult
+
+		for _, s := range req.RelocateSpecs {
+			spec := s.GetCnsVolumeRelocateSpec()
+			res := &cnstypes.CnsVolumeOperationResult{
+				VolumeId: spec.VolumeId,
+			}
+			operationResult = append(operationResult, res)
+
+			volume, src := m.volume(spec.VolumeId)
+			if volume == nil {
+				res.Fault = volumeNotFound(spec.VolumeId)
+				continue
+			}
+
+			details, ok := volume.BackingObjectDetails.(*cnstypes.CnsBlockBackingDetails)
+			if !ok {
+				res.Fault = &vim25types.LocalizedMethodFault{
+					Fault: &vim25types.InvalidArgument{InvalidProperty: "volumeId"},
+				}
+				continue
+			}
+
+			datastore, ok := vctx.Map.Get(spec.Datastore).(*simulator.Datastore)
+			if !ok {
+				res.Fault = &vim25types.LocalizedMethodFault{
+					Fault: &vim25types.ManagedObjectNotFound{Obj: spec.Datastore},
+				}
+				continue
+			}
+
+			val := vsom.RelocateVStorageObjectTask(vctx, &vim25types.RelocateVStorageObject_Task{
+				This:      vsom.Self,
+				Id:        vim25types.ID(spec.VolumeId),
+				Datastore: src,
+				Spec: vim25types.VslmRelocateSpec{
+					VslmMigrateSpec: vim25types.VslmMigrateSpec{
+						BackingSpec: &vim25types.VslmCreateSpecDiskFileBackingSpec{
+							VslmCreateSpecBackingSpec: vim25types.VslmCreateSpecBackingSpec{
+								Datastore: datastore.Self,
+							},
+						},
+						Profile: spec.Profile,
+					},
+				},
+			})
+
+			ref := val.(*vim25methods.RelocateVStorageObject_TaskBody).Res.Returnval
+			task := vctx.Map.Get(ref).(*simulator.Task)
+			task.Wait()
+			if task.Info.Error != nil {
+				res.Fault = task.Info.Error
+				continue
+			}
+
+			obj := task.Info.Result.(*vim25types.VStorageObject)
+			details.BackingDiskPath = obj.Config.Backing.(*vim25types.BaseConfigInfoDiskFileBackingInfo).FilePath
+			volume.DatastoreUrl = datastore.Info.GetDatastoreInfo().Url
+			if len(spec.Profile) != 0 {
+				volume.StoragePolicyId = profileID(spec.Profile)
+			}
+
+			if src != datastore.Self {
+				delete(m.volumes[src], spec.VolumeId)
+				volumes, ok := m.volumes[datastore.Self]
+				if !ok {
+					volumes = make(map[cnstypes.CnsVolumeId]*cnstypes.CnsVolume)
+					m.volumes[datastore.Self] = volumes
+				}
+				volumes[spec.VolumeId] = volume
+			}
+		}
+
+		return &cnstypes.CnsVolumeOperationBatchResult{
+			VolumeResults: operationResult,
+		}, nil
+	})
+
+	return &methods.CnsRelocateVolumeBody{
+		Res: &cnstypes.CnsRelocateVolumeResponse{
+			Returnval: task.Run(vctx),
+		},
+	}
+}
+
+// CnsConfigureVolumeACLs simulates ConfigureVolumeACLs call for simulated vc.
+// Permissions are keyed by the Ips field, a permission with the same Ips replaces the existing one.
+func (m *CnsVolumeManager) CnsConfigureVolumeACLs(ctx *simulator.Context, req *cnstypes.CnsConfigureVolumeACLs) soap.HasFault {
+	vctx := ctx.For(vim25.Path)
+
+	task := simulator.CreateTask(m, "CnsConfigureVolumeACLs", func(*simulator.Task) (vim25types.AnyType, vim25types.BaseMethodFault) {
+		if len(req.ACLConfigSpecs) == 0 {
+			return nil, &vim25types.InvalidArgument{InvalidProperty: "CnsVolumeACLConfigureSpec"}
+		}
+
+		var operationResult []cnstypes.BaseCnsVolumeOperationResult
+
+		for _, spec := range req.ACLConfigSpecs {
+			res := &cnstypes.CnsVolumeOperationResult{
+				VolumeId: spec.VolumeId,
+			}
+			operationResult = append(operationResult, res)
+
+			volume, _ := m.volume(spec.VolumeId)
+			if volume == nil {
+				res.Fault = volumeNotFound(spec.VolumeId)
+				continue
+			}
+
+			if volume.VolumeType != string(cnstypes.CnsVolumeTypeFile) {
+				res.Fault = &vim25types.LocalizedMethodFault{
+					Fault:            &vim25types.InvalidArgument{InvalidProperty: "volumeId"},
+					LocalizedMessage: "ACLs can only be configured for file volumes",
+				}
+				continue
+			}
+
+			acl, err := m.fileVolumeACL(vctx, volume)
+			if err != nil {
+				res.Fault = &vim25types.LocalizedMethodFault{Fault: err}
+				continue
+			}
+
+			for _, ac := range spec.AccessControlSpecList {
+				for _, p := range ac.Permission {
+					acl = slices.DeleteFunc(acl, func(e vsanfstypes.VsanFileShareNetPermission) bool {
+						return e.Ips == p.Ips
+					})
+					if !ac.Delete {
+						acl = append(acl, p)
+					}
+				}
+			}
+
+			if err = m.setFileVolumeACL(vctx, volume, acl); err != nil {
+				res.Fault = &vim25types.LocalizedMethodFault{Fault: err}
+			}
+		}
+
+		return &cnstypes.CnsVolumeOperationBatchResult{
+			VolumeResults: operationResult,
+		}, nil
+	})
+
+	return &methods.CnsConfigureVolumeACLsBody{
+		Res: &cnstypes.CnsConfigureVolumeACLsResponse{
+			Returnval: task.Run(vctx),
+		},
+	}
+}
+
+// CnsReconfigVolumePolicy simulates ReconfigVolumePolicy call for simulated vc.
+// The policy of a block volume is applied to the backing first class disk, updating its PBM association.
+func (m *CnsVolumeManager) CnsReconfigVolumePolicy(ctx *simulator.Context, req *cnstypes.CnsReconfigVolumePolicy) soap.HasFault {
+	vctx := ctx.For(vim25.Path)
+	vsom := vctx.Map.VStorageObjectManager()
+
+	task := simulator.CreateTask(m, "CnsReconfigVolumePolicy", func(*simulator.Task) (vim25types.AnyType, vim25types.BaseMethodFault) {
+		if len(req.VolumePolicyReconfigSpecs) == 0 {
+			return nil, &vim25types.InvalidArgument{InvalidProperty: "CnsVolumePolicyReconfigSpec"}
+		}
+
+		var operationResult []cnstypes.BaseCnsVolumeOperationResult
+
+		for _, spec := range req.VolumePolicyReconfigSpecs {
+			res := &cnstypes.CnsVolumeOperationResult{
+				VolumeId: spec.VolumeId,
+			}
+			operationResult = append(operationResult, res)
+
+			volume, ds := m.volume(spec.VolumeId)
+			if volume == nil {
+				res.Fault = volumeNotFound(spec.VolumeId)
+				continue
+			}
+
+			if volume.VolumeType != string(cnstypes.CnsVolumeTypeFile) {
+				val := vsom.UpdateVStorageObjectPolicyTask(vctx, &vim25types.UpdateVStorageObjectPolicy_Task{
+					This:      vsom.Self,
+					Id:        vim25types.ID(spec.VolumeId),
+					Datastore: ds,
+					Profile:   spec.Profile,
+				})
+
+				ref := val.(*vim25methods.UpdateVStorageObjectPolicy_TaskBody).Res.Returnval
+				task := vctx.Map.Get(ref).(*simulator.Task)
+				task.Wait()
+				if task.Info.Error != nil {
+					res.Fault = task.Info.Error
+					continue
+				}
+			}
+
+			volume.StoragePolicyId = profileID(spec.Profile)
+		}
+
+		return &cnstypes.CnsVolumeOperationBatchResult{
+			VolumeResults: operationResult,
+		}, nil
+	})
+
+	return &methods.CnsReconfigVolumePolicyBody{
+		Res: &cnstypes.CnsReconfigVolumePolicyResponse{
+			Returnval: task.Run(vctx),
+		},
+	}
+}
+
+// sync updates a block volume from its backing first class disk.
+// Volumes whose disk no longer exists are removed, as CNS does when reconciling with the FCD catalog.
+func (m *CnsVolumeManager) sync(vctx *simulator.Context, ds vim25types.ManagedObjectReference, volume *cnstypes.CnsVolume) {
+	details, ok := volume.BackingObjectDetails.(*cnstypes.CnsBlockBackingDetails)
+	if !ok {
+		return
+	}
+
+	vsom := vctx.Map.VStorageObjectManager()
+
+	vctx.WithLock(vsom, func() {
+		obj := vsom.Catalog()[ds][vim25types.ID(volume.VolumeId)]
+		if obj == nil {
+			delete(m.volumes[ds], volume.VolumeId)
+			delete(m.snapshots, volume.VolumeId)
+			return
+		}
+
+		details.CapacityInMb = obj.Config.CapacityInMB
+		details.BackingDiskPath = obj.Config.Backing.(*vim25types.BaseConfigInfoDiskFileBackingInfo).FilePath
+		volume.StoragePolicyId = obj.StorageProfile
+	})
+}
+
+// CnsSyncVolume simulates SyncVolume call for simulated vc
+func (m *CnsVolumeManager) CnsSyncVolume(ctx *simulator.Context, req *cnstypes.CnsSyncVolume) soap.HasFault {
+	vctx := ctx.For(vim25.Path)
+
+	task := simulator.CreateTask(m, "CnsSyncVolume", func(*simulator.Task) (vim25types.AnyType, vim25types.BaseMethodFault) {
+		if len(req.SyncSpecs) == 0 {
+			return nil, &vim25types.InvalidArgument{InvalidProperty: "CnsSyncVolumeSpec"}
+		}
+
+		var operationResult []cnstypes.BaseCnsVolumeOperationResult
+
+		for _, spec := range req.SyncSpecs {
+			res := &cnstypes.CnsVolumeOperationResult{
+				VolumeId: spec.VolumeId,
+			}
+			operationResult = append(operationResult, res)
+
+			volume, ds := m.volume(spec.VolumeId)
+			if volume == nil || (spec.Datastore != nil && *spec.Datastore != ds) {
+				res.Fault = volumeNotFound(spec.VolumeId)
+				continue
+			}
+
+			m.sync(vctx, ds, volume)
+		}
+
+		return &cnstypes.CnsVolumeOperationBatchResult{
+			VolumeResults: operationResult,
+		}, nil
+	})
+
+	return &methods.CnsSyncVolumeBody{
+		Res: &cnstypes.CnsSyncVolumeResponse{
+			Returnval: task.Run(vctx),
+		},
+	}
+}
+
+// CnsUnregisterVolume simulates UnregisterVolume call for simulated vc.
+// The volume is removed from CNS, the backing disk is kept as an FCD or,
+// with the LEGACY_DISK target type, as a virtual disk that is no longer in the FCD catalog.
+func (m *CnsVolumeManager) CnsUnregisterVolume(ctx *simulator.Context, req *cnstypes.CnsUnregisterVolume) soap.HasFault {
+	vctx := ctx.For(vim25.Path)
+	vsom := vctx.Map.VStorageObjectManager()
+
+	task := simulator.CreateTask(m, "CnsUnregisterVolume", func(*simulator.Task) (vim25types.AnyType, vim25types.BaseMethodFault) {
+		if len(req.UnregisterSpec) == 0 {
+			return nil, &vim25types.InvalidArgument{InvalidProperty: "CnsUnregisterVolumeSpec"}
+		}
+
+		var operationResult []cnstypes.BaseCnsVolumeOperationResult
+
+		for _, spec := range req.UnregisterSpec {
+			res := &cnstypes.CnsVolumeOperationResult{
+				VolumeId: spec.VolumeId,
+			}
+			operationResult = append(operationResult, res)
+
+			volume, ds := m.volume(spec.VolumeId)
+			if volume == nil {
+				res.Fault = volumeNotFound(spec.VolumeId)
+				continue
+			}
+
+			if volume.VolumeType == string(cnstypes.CnsVolumeTypeFile) {
+				res.Fault = &vim25types.LocalizedMethodFault{
+					Fault: &vim25types.InvalidArgument{InvalidProperty: "volumeId"},
+				}
+				continue
+			}
+
+			switch cnstypes.CnsUnregisterTargetVolumeType(spec.TargetVolumeType) {
+			case "", cnstypes.CnsUnregisterTargetVolumeTypeFCD, cnstypes.CnsUnregisterTargetVolumeTypeLEGACY_DISK:
+			default:
+				res.Fault = &vim25types.LocalizedMethodFault{
+					Fault: &vim25types.InvalidArgument{InvalidProperty: "targetVolumeType"},
+				}
+				continue
+			}
+
+			if vm, ok := m.attachments[spec.VolumeId]; ok {
+				res.Fault = &vim25types.LocalizedMethodFault{
+					Fault: &vim25types.ResourceInUse{Type: vm.Type, Name: spec.VolumeId.Id},
+				}
+				continue
+			}
+
+			delete(m.volumes[ds], spec.VolumeId)
+			delete(m.snapshots, spec.VolumeId)
+
+			if spec.TargetVolumeType == string(cnstypes.CnsUnregisterTargetVolumeTypeLEGACY_DISK) {
+				vctx.WithLock(vsom, func() {
+					delete(vsom.Catalog()[ds], vim25types.ID(spec.VolumeId))
+				})
+			}
+		}
+
+		return &cnstypes.CnsVolumeOperationBatchResult{
+			VolumeResults: operationResult,
+		}, nil
+	})
+
+	return &methods.CnsUnregisterVolumeBody{
+		Res: &cnstypes.CnsUnregisterVolumeResponse{
+			Returnval: task.Run(vctx),
+		},
+	}
+}
+
+type CnsDebugManager struct {
+	vim25types.ManagedObjectReference
+
+	volumeManager *CnsVolumeManager
+}
+
+// CnsSyncDatastore simulates SyncDatastore call for simulated vc.
+// Volumes on the Datastore are reconciled with the FCD catalog, regardless of FullSync.
+func (m *CnsDebugManager) CnsSyncDatastore(ctx *simulator.Context, req *cnstypes.CnsSyncDatastore) soap.HasFault {
+	vctx := ctx.For(vim25.Path)
+
+	// The task entity is the volume manager, as the sync operates on its volumes
+	task := simulator.CreateTask(m.volumeManager, "CnsSyncDatastore", func(*simulator.Task) (vim25types.AnyType, vim25types.BaseMethodFault) {
+		var ref *vim25types.ManagedObjectReference
+
+		for _, obj := range vctx.Map.All("Datastore") {
+			ds := obj.(*simulator.Datastore)
+			if ds.Info.GetDatastoreInfo().Url == req.DatastoreUrl {
+				ref = &ds.Self
+				break
+			}
+		}
+
+		if ref == nil {
+			return nil, &vim25types.InvalidArgument{InvalidProperty: "datastoreUrl"}
+		}
+
+		for _, volume := range m.volumeManager.volumes[*ref] {
+			m.volumeManager.sync(vctx, *ref, volume)
+		}
+
+		return nil, nil
+	})
+
+	return &methods.CnsSyncDatastoreBody{
+		Res: &cnstypes.CnsSyncDatastoreResponse{
+			Returnval: task.Run(vctx),
+		},
+	}
+}
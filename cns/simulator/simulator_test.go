@@ -6,15 +6,26 @@ package simulator
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/cns"
 	cnstypes "github.com/vmware/govmomi/cns/types"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/pbm"
+	_ "github.com/vmware/govmomi/pbm/simulator"
+	pbmtypes "github.com/vmware/govmomi/pbm/types"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
 	vim25types "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vsan"
+	vsantypes "github.com/vmware/govmomi/vsan/types"
+	vsanfstypes "github.com/vmware/govmomi/vsan/vsanfs/types"
 	"github.com/vmware/govmomi/vslm"
 )
 
@@ -594,3 +605,261 @@ func TestSimulator(t *testing.T) {
 	}
 
 }
+
+func TestVolumeLifecycle(t *testing.T) {
+	model := simulator.VPX()
+	model.Datastore = 2
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		r := New()
+		model.Service.RegisterSDK(r)
+		m := r.Get(cns.CnsVolumeManagerInstance).(*CnsVolumeManager)
+
+		cnsClient, err := cns.NewClient(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		pc, err := pbm.NewClient(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		policy, err := pc.ProfileIDByName(ctx, "vSAN Default Storage Policy")
+		if err != nil {
+			return err
+		}
+
+		finder := find.NewFinder(c)
+		datastores, err := finder.DatastoreList(ctx, "*")
+		if err != nil {
+			return err
+		}
+		src, dst := datastores[0].Reference(), datastores[1].Reference()
+
+		run := func(task *object.Task, err error) cnstypes.BaseCnsVolumeOperationResult {
+			t.Helper()
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := cns.GetTaskInfo(ctx, task)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := cns.GetTaskResult(ctx, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res
+		}
+
+		query := func(id cnstypes.CnsVolumeId) []cnstypes.CnsVolume {
+			t.Helper()
+			res, err := cnsClient.QueryVolume(ctx, &cnstypes.CnsQueryFilter{VolumeIds: []cnstypes.CnsVolumeId{id}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return res.Volumes
+		}
+
+		associated := func(id cnstypes.CnsVolumeId) string {
+			t.Helper()
+			ids, err := pc.QueryAssociatedProfile(ctx, pbmtypes.PbmServerObjectRef{
+				ObjectType: string(pbmtypes.PbmObjectTypeVirtualDiskUUID),
+				Key:        id.Id,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ids) == 0 {
+				return ""
+			}
+			return ids[0].UniqueId
+		}
+
+		// block volume
+		res := run(cnsClient.CreateVolume(ctx, []cnstypes.CnsVolumeCreateSpec{{
+			Name:       "pvc-block",
+			VolumeType: string(cnstypes.CnsVolumeTypeBlock),
+			Datastores: []vim25types.ManagedObjectReference{src},
+			BackingObjectDetails: &cnstypes.CnsBlockBackingDetails{
+				CnsBackingObjectDetails: cnstypes.CnsBackingObjectDetails{CapacityInMb: 1024},
+			},
+		}}))
+		block := res.GetCnsVolumeOperationResult().VolumeId
+		assert.Equal(t, "", associated(block))
+
+		// policy reconfigure updates the PBM association
+		res = run(cnsClient.ReconfigVolumePolicy(ctx, []cnstypes.CnsVolumePolicyReconfigSpec{{
+			VolumeId: block,
+			Profile:  []vim25types.BaseVirtualMachineProfileSpec{&vim25types.VirtualMachineDefinedProfileSpec{ProfileId: policy}},
+		}}))
+		assert.Nil(t, res.GetCnsVolumeOperationResult().Fault)
+		assert.Equal(t, policy, query(block)[0].StoragePolicyId)
+		assert.Equal(t, policy, associated(block))
+
+		entities, err := pc.QueryAssociatedEntity(ctx, pbmtypes.PbmProfileId{UniqueId: policy}, string(pbmtypes.PbmObjectTypeVirtualDiskUUID))
+		if err != nil {
+			return err
+		}
+		assert.Len(t, entities, 1)
+
+		// relocate moves the FCD and volume to the target datastore
+		res = run(cnsClient.RelocateVolume(ctx, cnstypes.NewCnsBlockVolumeRelocateSpec(block.Id, dst)))
+		assert.Nil(t, res.GetCnsVolumeOperationResult().Fault)
+
+		volumes, err := cnsClient.QueryVolume(ctx, &cnstypes.CnsQueryFilter{Datastores: []vim25types.ManagedObjectReference{dst}})
+		if err != nil {
+			return err
+		}
+		assert.Len(t, volumes.Volumes, 1)
+		volume, ds := m.volume(block)
+		assert.Equal(t, dst, ds)
+		assert.Contains(t, volume.BackingObjectDetails.(*cnstypes.CnsBlockBackingDetails).BackingDiskPath, "["+datastores[1].Name()+"]")
+		assert.Equal(t, policy, associated(block))
+
+		disks, err := vslm.NewObjectManager(c).List(ctx, dst)
+		if err != nil {
+			return err
+		}
+		assert.Len(t, disks, 1)
+
+		res = run(cnsClient.RelocateVolume(ctx, cnstypes.NewCnsBlockVolumeRelocateSpec("enoent", dst)))
+		assert.IsType(t, new(cnstypes.CnsVolumeNotFoundFault), res.GetCnsVolumeOperationResult().Fault.Fault)
+
+		// sync
+		res = run(cnsClient.SyncVolume(ctx, []cnstypes.CnsSyncVolumeSpec{{VolumeId: block}}))
+		assert.Nil(t, res.GetCnsVolumeOperationResult().Fault)
+
+		task, err := cnsClient.SyncDatastore(ctx, volumes.Volumes[0].DatastoreUrl, true)
+		if err != nil {
+			return err
+		}
+		if err = task.Wait(ctx); err != nil {
+			return err
+		}
+
+		task, err = cnsClient.SyncDatastore(ctx, "ds:///enoent", false)
+		if err != nil {
+			return err
+		}
+		assert.Error(t, task.Wait(ctx))
+
+		// file volume ACLs
+		fileSpec := []cnstypes.CnsVolumeCreateSpec{{
+			Name:       "pvc-file",
+			VolumeType: string(cnstypes.CnsVolumeTypeFile),
+			Datastores: []vim25types.ManagedObjectReference{src},
+			BackingObjectDetails: &cnstypes.CnsVsanFileShareBackingDetails{
+				CnsFileBackingDetails: cnstypes.CnsFileBackingDetails{
+					CnsBackingObjectDetails: cnstypes.CnsBackingObjectDetails{CapacityInMb: 1024},
+				},
+			},
+			CreateSpec: &cnstypes.CnsVSANFileCreateSpec{
+				Permission: []vsanfstypes.VsanFileShareNetPermission{{Ips: "*", Permissions: "READ_WRITE"}},
+			},
+		}}
+
+		task, err = cnsClient.CreateVolume(ctx, fileSpec)
+		if err != nil {
+			return err
+		}
+		_, err = cns.GetTaskInfo(ctx, task)
+		assert.Error(t, err) // vSAN file service is not enabled
+
+		cluster, err := finder.ClusterComputeResource(ctx, "DC0_C0")
+		if err != nil {
+			return err
+		}
+
+		vsanClient, err := vsan.NewClient(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		task, err = vsanClient.EnableFileService(ctx, cluster.Reference(), vsantypes.VsanFileServiceConfig{})
+		if err != nil {
+			return err
+		}
+		if err = task.Wait(ctx); err != nil {
+			return err
+		}
+
+		res = run(cnsClient.CreateVolume(ctx, fileSpec))
+		file := res.GetCnsVolumeOperationResult().VolumeId
+		assert.Equal(t, string(cnstypes.CnsVolumeTypeFile), query(file)[0].VolumeType)
+
+		acl := func(id cnstypes.CnsVolumeId, remove bool, ips string) *vim25types.LocalizedMethodFault {
+			res := run(cnsClient.ConfigureVolumeACLs(ctx, cnstypes.CnsVolumeACLConfigureSpec{
+				VolumeId: id,
+				AccessControlSpecList: []cnstypes.CnsNFSAccessControlSpec{{
+					Permission: []vsanfstypes.VsanFileShareNetPermission{{Ips: ips, Permissions: "READ_ONLY"}},
+					Delete:     remove,
+				}},
+			}))
+			return res.GetCnsVolumeOperationResult().Fault
+		}
+
+		permissions := func() []vsanfstypes.VsanFileShareNetPermission {
+			t.Helper()
+			res, err := vsanClient.QueryFileShares(ctx, cluster.Reference(), vsantypes.VsanFileShareQuerySpec{
+				Uuids: []string{strings.TrimPrefix(file.Id, "file:")},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.FileShares) != 1 {
+				t.Fatalf("file share for %s not found", file.Id)
+			}
+			return filePermissions(res.FileShares[0].Config.Permission)
+		}
+
+		assert.Len(t, permissions(), 1)
+		assert.Nil(t, acl(file, false, "192.168.124.2"))
+		assert.Len(t, permissions(), 2)
+		assert.Nil(t, acl(file, false, "*")) // replaces the existing entry
+		assert.Len(t, permissions(), 2)
+		assert.EqualValues(t, "READ_ONLY", permissions()[1].Permissions)
+		assert.Nil(t, acl(file, true, "*"))
+		assert.Len(t, permissions(), 1)
+		assert.NotNil(t, acl(block, false, "*")) // not a file volume
+
+		// deleting the volume removes the CNS managed file share
+		res = run(cnsClient.DeleteVolume(ctx, []cnstypes.CnsVolumeId{file}, true))
+		assert.Nil(t, res.GetCnsVolumeOperationResult().Fault)
+
+		shares, err := vsanClient.QueryFileShares(ctx, cluster.Reference(), vsantypes.VsanFileShareQuerySpec{})
+		if err != nil {
+			return err
+		}
+		assert.Empty(t, shares.FileShares)
+
+		// unregister keeps the FCD, unless attached
+		vm := model.Map().Any("VirtualMachine").Reference()
+		run(cnsClient.AttachVolume(ctx, []cnstypes.CnsVolumeAttachDetachSpec{{VolumeId: block, Vm: vm}}))
+
+		spec := []cnstypes.CnsUnregisterVolumeSpec{{
+			VolumeId:         block,
+			TargetVolumeType: string(cnstypes.CnsUnregisterTargetVolumeTypeFCD),
+		}}
+		res = run(cnsClient.UnregisterVolume(ctx, spec))
+		assert.IsType(t, new(vim25types.ResourceInUse), res.GetCnsVolumeOperationResult().Fault.Fault)
+
+		run(cnsClient.DetachVolume(ctx, []cnstypes.CnsVolumeAttachDetachSpec{{VolumeId: block, Vm: vm}}))
+
+		res = run(cnsClient.UnregisterVolume(ctx, spec))
+		assert.Nil(t, res.GetCnsVolumeOperationResult().Fault)
+		assert.Empty(t, query(block))
+
+		disks, err = vslm.NewObjectManager(c).List(ctx, dst)
+		if err != nil {
+			return err
+		}
+		assert.Len(t, disks, 1)
+
+		res = run(cnsClient.UnregisterVolume(ctx, spec))
+		assert.IsType(t, new(cnstypes.CnsVolumeNotFoundFault), res.GetCnsVolumeOperationResult().Fault.Fault)
+
+		return nil
+	}, model)
+}
@@ -19,6 +19,8 @@ import (
 	_ "github.com/vmware/govmomi/cli/cluster/override"
 	_ "github.com/vmware/govmomi/cli/cluster/rule"
 	_ "github.com/vmware/govmomi/cli/cluster/vlcm"
+	_ "github.com/vmware/govmomi/cli/context"
+	_ "github.com/vmware/govmomi/cli/customization"
 	_ "github.com/vmware/govmomi/cli/datacenter"
 	_ "github.com/vmware/govmomi/cli/datastore"
 	_ "github.com/vmware/govmomi/cli/datastore/cluster"
@@ -99,9 +101,15 @@ import (
 	_ "github.com/vmware/govmomi/cli/vcsa/access/dcui"
 	_ "github.com/vmware/govmomi/cli/vcsa/access/shell"
 	_ "github.com/vmware/govmomi/cli/vcsa/access/ssh"
+	_ "github.com/vmware/govmomi/cli/vcsa/backup"
+	_ "github.com/vmware/govmomi/cli/vcsa/cert"
+	_ "github.com/vmware/govmomi/cli/vcsa/health"
 	_ "github.com/vmware/govmomi/cli/vcsa/log"
+	_ "github.com/vmware/govmomi/cli/vcsa/ntp"
 	_ "github.com/vmware/govmomi/cli/vcsa/proxy"
+	_ "github.com/vmware/govmomi/cli/vcsa/service"
 	_ "github.com/vmware/govmomi/cli/vcsa/shutdown"
+	_ "github.com/vmware/govmomi/cli/vcsa/update"
 	_ "github.com/vmware/govmomi/cli/version"
 	_ "github.com/vmware/govmomi/cli/vlcm/depot/content/baseimages"
 	_ "github.com/vmware/govmomi/cli/vlcm/depot/offline"
@@ -120,6 +128,7 @@ import (
 	_ "github.com/vmware/govmomi/cli/volume"
 	_ "github.com/vmware/govmomi/cli/volume/snapshot"
 	_ "github.com/vmware/govmomi/cli/vsan"
+	_ "github.com/vmware/govmomi/cli/vsan/fileshare"
 )
 
 func main() {
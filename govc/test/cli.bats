@@ -200,6 +200,122 @@ load test_helper
   assert_matches "requires 2 more usable fault domains"
 }
 
+@test "govc output formats" {
+  vcsim_env
+
+  run govc vm.info -o table=name,runtime.powerState DC0_H0_VM0
+  assert_success
+  assert_matches "NAME *RUNTIME.POWERSTATE"
+  assert_matches "DC0_H0_VM0 *poweredOn"
+
+  run govc datastore.info -o csv=name LocalDS_0
+  assert_success "name
+LocalDS_0"
+
+  run govc vm.info -o yaml DC0_H0_VM0
+  assert_success
+  assert_matches "name: DC0_H0_VM0"
+
+  run govc vm.info -o 'template={{range .virtualMachines}}{{.config.guestId}}{{end}}' DC0_H0_VM0
+  assert_success "otherGuest"
+
+  run govc vm.info -o 'jsonpath=$.virtualMachines[*].config.hardware.numCPU' DC0_H0_VM0
+  assert_success 1
+
+  run govc vm.info -o json DC0_H0_VM0
+  assert_success
+  jq . <<<"$output"
+
+  run govc vm.info -o html DC0_H0_VM0
+  assert_failure
+
+  run govc vm.power -o table -on DC0_H0_VM0
+  assert_failure
+  assert_matches "InvalidPowerState"
+
+  run govc collect -o -s vm/DC0_H0_VM0 name
+  assert_success
+}
+
+@test "govc context" {
+  vcsim_start
+
+  export GOVMOMI_HOME="$BATS_TMPDIR/$(new_id)"
+  url="$GOVC_URL"
+  unset GOVC_URL
+
+  run govc context.ls
+  assert_success
+
+  run govc context.use vc1
+  assert_failure
+
+  run govc context.set -u "$url" -dc DC0 -o table=name -thumbprint invalid vc1
+  assert_failure
+
+  run govc context.set -u "$url" -dc DC0 vc1
+  assert_success
+
+  run govc context.set -u "$url" -dc DC1 vc2
+  assert_success
+
+  run govc context.ls
+  assert_success
+  assert_matches "\* *vc1"
+
+  run govc ls vm
+  assert_success
+  assert_matches "/DC0/vm/DC0_H0_VM0"
+
+  run govc context.set -o table=name vc1
+  assert_success
+
+  run govc datastore.info
+  assert_success "NAME
+LocalDS_0"
+
+  run govc datastore.info -o csv=summary.type
+  assert_success "summary.type
+OTHER"
+
+  run env GOVC_CONTEXT=vc2 govc ls vm
+  assert_failure # DC1 does not exist
+
+  run env GOVC_CONTEXT=vc3 govc ls vm
+  assert_failure
+
+  run env GOVC_CONTEXT=vc3 govc context.ls
+  assert_success # context.* commands do not apply the context
+
+  run env GOVC_DATACENTER=DC0 GOVC_CONTEXT=vc2 govc ls vm
+  assert_success
+
+  run govc context.use vc2
+  assert_success
+
+  run govc context.set -dc "" vc2
+  assert_success
+
+  run govc ls vm
+  assert_success
+
+  run govc context.ls -json
+  assert_success
+  assert_equal vc2 "$(jq -r '.contexts[] | select(.current) | .name' <<<"$output")"
+
+  run govc context.rm vc2
+  assert_success
+
+  run govc context.ls -json
+  assert_success
+  assert_equal vc1 "$(jq -r '.contexts[].name' <<<"$output")"
+
+  run govc ls vm
+  assert_failure # no current context
+
+  rm -rf "$GOVMOMI_HOME"
+}
+
 @test "insecure cookies" {
   vcsim_start -tls=false
 
@@ -0,0 +1,88 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "customization.ls" {
+  vcsim_env
+
+  run govc customization.ls
+  assert_success
+  assert_matches vcsim-linux
+
+  run govc customization.ls -json -os WINDOWS
+  assert_success
+  [ "$(jq -r '.[].os_type' <<<"$output" | sort -u)" = "WINDOWS" ]
+
+  run govc customization.ls -json vcsim-linux
+  assert_success
+  [ "$(jq -r '.[].name' <<<"$output")" = "vcsim-linux" ]
+}
+
+@test "customization.export" {
+  vcsim_env
+
+  run govc customization.export enoent
+  assert_failure
+
+  run govc customization.export vcsim-linux
+  assert_success
+  [ "$(jq -r .spec.configuration_spec.linux_config.domain <<<"$output")" = "eng.vmware.com" ]
+
+  run govc customization.export -format xml vcsim-linux
+  assert_success
+  assert_matches "<?xml"
+
+  file=$BATS_TMPDIR/vcsim-linux.json
+  run govc customization.export vcsim-linux "$file"
+  assert_success
+  [ -s "$file" ]
+  rm -f "$file"
+}
+
+@test "customization.import" {
+  vcsim_env
+
+  dir=$(mktemp -d "$BATS_TMPDIR/customization-XXXXXX")
+
+  run govc customization.export vcsim-linux "$dir/spec.json"
+  assert_success
+
+  run govc customization.import "$dir/spec.json"
+  assert_failure # already exists
+
+  run govc customization.import -name my-linux "$dir/spec.json"
+  assert_success
+
+  run govc customization.ls my-linux
+  assert_success
+  assert_matches LINUX
+
+  jq '.spec.configuration_spec.linux_config.domain = "example.com"' "$dir/spec.json" > "$dir/update.json"
+
+  run govc customization.import -name my-linux -overwrite "$dir/update.json"
+  assert_success
+
+  run govc customization.export my-linux
+  assert_success
+  [ "$(jq -r .spec.configuration_spec.linux_config.domain <<<"$output")" = "example.com" ]
+
+  run govc customization.export -format xml vcsim-windows-static "$dir/spec.xml"
+  assert_success
+
+  run govc customization.import -name my-windows -description "from xml" "$dir/spec.xml"
+  assert_success
+
+  run govc customization.ls -json my-windows
+  assert_success
+  [ "$(jq -r '.[].description' <<<"$output")" = "from xml" ]
+
+  govc customization.export vcsim-linux-static | govc customization.import -name my-linux-static -
+  run govc customization.ls my-linux-static
+  assert_success
+  assert_matches my-linux-static
+
+  run govc customization.import "$dir/enoent.json"
+  assert_failure
+
+  rm -rf "$dir"
+}
@@ -37,3 +37,31 @@ load test_helper
 
   rm -rf "$dir"
 }
+
+@test "export.ovf -config" {
+  vcsim_env
+
+  vm=DC0_H0_VM0
+
+  run govc export.ovf -vm $vm -config
+  assert_success
+  assert_matches "<VirtualSystem id=\"$vm\">"
+
+  run govc export.ovf -vm $vm -config -json
+  assert_success
+
+  cpus=$(jq -r '.virtualSystem.virtualHardwareSection[0].item[] | select(.resourceType == 3) | .virtualQuantity' <<<"$output")
+  assert_equal "$(govc object.collect -s vm/$vm config.hardware.numCPU)" "$cpus"
+
+  dir=$BATS_TMPDIR/$vm-config
+  run govc export.ovf -vm $vm -config -name $vm-copy "$dir"
+  assert_success
+
+  run govc import.ovf -name $vm-copy "$dir/$vm-copy/$vm-copy.ovf"
+  assert_success
+
+  run govc vm.info $vm-copy
+  assert_success
+
+  rm -rf "$dir"
+}
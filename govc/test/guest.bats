@@ -155,6 +155,134 @@ vcsim_guest() {
   assert_success
   assert_matches FOO=bar
   assert_matches PWD=/tmp
+
+  run govc guest.run -stream uname -a
+  assert_success
+  assert_matches Linux
+
+  run govc guest.run -stream -timeout 1s sleep 30
+  assert_failure # deadline exceeded
+}
+
+@test "guest alias manager" {
+  vcsim_guest
+
+  token="$(govc session.login -issue)"
+  echo "$token" > "$BATS_TMPDIR/token.xml"
+
+  run govc guest.alias.ls
+  assert_success
+  refute_line --partial VSPHERE.LOCAL
+
+  run govc guest.getenv -guest-token "$BATS_TMPDIR/token.xml" -l user HOME
+  assert_failure # no alias
+
+  run govc guest.alias.add -token "$BATS_TMPDIR/token.xml" -map user
+  assert_success
+
+  run govc guest.alias.add -token - -map user <<<"$token"
+  assert_failure # exists
+
+  run govc guest.alias.add -token - -guest-token - -map user <<<"$token"
+  assert_failure # both read from stdin
+
+  run govc guest.alias.ls -json
+  assert_success
+  [ "$(jq -r '.aliases[].aliases[].subject.name' <<<"$output")" = "Administrator@VSPHERE.LOCAL" ]
+
+  run govc guest.alias.ls -mapped
+  assert_success
+  assert_matches Administrator@VSPHERE.LOCAL
+
+  run govc guest.getenv -guest-token "$BATS_TMPDIR/token.xml" -l user HOME
+  assert_success
+
+  run govc guest.getenv -guest-token - -l user HOME <<<"$token"
+  assert_success
+
+  run govc guest.run -guest-token - -l user -d - cat <<<"$token"
+  assert_failure # both read from stdin
+
+  run govc guest.upload -guest-token - -l user - /tmp/token.xml <<<"$token"
+  assert_failure # both read from stdin
+
+  GOVC_GUEST_LOGIN= GOVC_GUEST_TOKEN="$token" run govc guest.getenv HOME
+  assert_success # mapped
+
+  run govc guest.alias.rm -token - user <<<"$token"
+  assert_success
+
+  run govc guest.getenv -guest-token "$BATS_TMPDIR/token.xml" -l user HOME
+  assert_failure
+}
+
+@test "guest registry" {
+  vcsim_env
+
+  export GOVC_VM=DC0_H0_VM0 GOVC_GUEST_LOGIN=user:pass
+  key='HKLM\SOFTWARE\MyApp'
+
+  run govc guest.reg.ls 'HKLM\SOFTWARE'
+  assert_failure # not a windows guest
+
+  run govc vm.change -g windows9_64Guest
+  assert_success
+
+  run govc guest.reg.create "$key\\Settings"
+  assert_success
+
+  run govc guest.reg.create "$key"
+  assert_failure # exists
+
+  run govc guest.reg.ls -r 'HKLM\SOFTWARE'
+  assert_success
+  assert_matches 'MyApp\\Settings'
+
+  run govc guest.reg.set "$key" Version 1.0
+  assert_success
+
+  run govc guest.reg.set -t REG_DWORD "$key" Enabled 0x10
+  assert_success
+
+  run govc guest.reg.set -t REG_DWORD "$key" Enabled 0x100000000
+  assert_failure # out of range
+
+  run govc guest.reg.set -t REG_EXPAND_SZ "$key" Home '%ProgramFiles%\MyApp'
+  assert_success
+
+  run govc guest.reg.set -t REG_BINARY "$key" Key cafebabe
+  assert_success
+
+  run govc guest.reg.set -t REG_MULTI_SZ "$key" Hosts a.example.com b.example.com
+  assert_success
+
+  run govc guest.reg.get -json "$key" Enabled
+  assert_success
+  [ "$(jq -r '.values[].data.value' <<<"$output")" = "16" ]
+
+  run govc guest.reg.get -x "$key" Home
+  assert_success
+  assert_matches 'C:\\Program Files\\MyApp'
+
+  run govc guest.reg.get "$key"
+  assert_success
+  assert_matches cafebabe
+  assert_matches "a.example.com b.example.com"
+
+  run govc guest.reg.unset "$key" Version
+  assert_success
+
+  run govc guest.reg.unset "$key" Version
+  assert_failure # not found
+
+  run govc guest.reg.rm "$key"
+  assert_failure # has subkeys
+
+  run govc guest.reg.rm -r "$key"
+  assert_success
+
+  run govc guest.reg.get "$key"
+  assert_failure
 }
 
 @test "guest tools status" {
@@ -58,6 +58,31 @@ load test_helper
   assert_success
 }
 
+@test "import.ova -stream" {
+  vcsim_env
+
+  run govc import.ova -stream -name stream-local "$GOVC_IMAGES/$TTYLINUX_NAME.ova"
+  assert_success
+
+  run govc device.ls -vm stream-local
+  assert_success
+  assert_matches "disk-"
+
+  # link ova to datastore so we can test with an http source
+  dir=$(govc datastore.info -json | jq -r .datastores[].info.url)
+  ln -s "$GOVC_IMAGES/$TTYLINUX_NAME.ova" "$dir"
+
+  run govc import.ova -stream -m -name stream-remote "https://$(govc env GOVC_URL)/folder/$TTYLINUX_NAME.ova"
+  assert_success
+
+  run govc device.ls -vm stream-remote
+  assert_success
+  assert_matches "disk-"
+
+  run govc import.ova -stream -m -name=bad-checksum-vm "$GOVC_IMAGES/$TTYLINUX_NAME-bad-checksum.ova"
+  assert_failure # vmdk checksum mismatch
+}
+
 @test "import.ova with iso" {
   vcsim_env
 
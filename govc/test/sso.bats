@@ -138,3 +138,58 @@ load test_helper
   run govc sso.group.rm govc
   assert_failure # does not exist
 }
+
+@test "sso.idp.provider" {
+  vcsim_env
+
+  run govc sso.idp.provider.ls -json
+  assert_success
+  [ "$(jq length <<<"$output")" = 0 ]
+
+  run govc sso.idp.provider.create -name okta -client-id vcenter -discovery enoent
+  assert_failure
+
+  run govc sso.idp.provider.create -name okta -client-id vcenter -client-secret secret \
+      -discovery https://idp.example.com/.well-known/openid-configuration \
+      -domain example.com -groups-claim groups -claim groups:vc-admins=Administrators@vsphere.local -default
+  assert_success
+  okta=$output
+
+  run govc sso.idp.provider.create -oauth2 -name adfs -client-id vcenter \
+      -auth-endpoint https://adfs.example.com/adfs/oauth2/authorize \
+      -token-endpoint https://adfs.example.com/adfs/oauth2/token \
+      -public-key-uri https://adfs.example.com/adfs/discovery/keys \
+      -issuer https://adfs.example.com/adfs
+  assert_success
+  adfs=$output
+
+  run govc sso.idp.provider.ls
+  assert_success
+  assert_matches "$okta *okta *Oidc *true"
+  assert_matches "$adfs *adfs *Oauth2 *false"
+
+  run govc sso.idp.provider.info "$okta"
+  assert_success
+  assert_matches "Claim: *groups:vc-admins=Administrators@vsphere.local"
+
+  run govc sso.idp.provider.update -claim groups:vc-users=Users@vsphere.local "$okta"
+  assert_success
+
+  run govc sso.idp.provider.info -json "$okta"
+  assert_success
+  [ "$(jq -r '.oidc.claim_map.groups | keys | join(",")' <<<"$output")" = "vc-users" ]
+  [ "$(jq -r .oidc.issuer <<<"$output")" = "https://idp.example.com" ]
+
+  run govc sso.idp.provider.update -default "$adfs"
+  assert_success
+  [ "$(govc sso.idp.provider.info -json "$okta" | jq -r .is_default)" = "false" ]
+
+  run govc sso.idp.provider.update -auth-method INVALID "$adfs"
+  assert_failure
+
+  run govc sso.idp.provider.rm "$okta" "$adfs"
+  assert_success
+
+  run govc sso.idp.provider.info "$okta"
+  assert_failure
+}
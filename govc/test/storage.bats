@@ -31,6 +31,17 @@ load test_helper
 
     kind="$(jq -r .policies[].filterMap[].iofilters[].filterType <<<"$output")"
     assert_equal "ENCRYPTION" "$kind"
+
+    run govc storage.policy.info -s -json "vSAN Default Storage Policy"
+    assert_success
+    assert_equal null "$(jq -r .policies[].compatibleDatastores <<<"$output")"
+
+    run govc cluster.change -vsan-enabled DC0_C0
+    assert_success
+
+    run govc storage.policy.info -s -json "vSAN Default Storage Policy"
+    assert_success
+    assert_equal vsanDatastore "$(jq -r .policies[].compatibleDatastores[] <<<"$output")"
 }
 
 @test "storage.policy.create" {
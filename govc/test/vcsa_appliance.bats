@@ -0,0 +1,260 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "vcsa.health.info" {
+  vcsim_env
+
+  run govc vcsa.health.info
+  assert_success
+  assert_matches "system: *green"
+
+  run govc vcsa.health.info -json mem swap
+  assert_success
+  [ "$(jq -r .status.mem <<<"$output")" = "green" ]
+  [ "$(jq -r '.status | length' <<<"$output")" = 2 ]
+
+  run govc vcsa.health.info enoent
+  assert_failure
+}
+
+@test "vcsa.service" {
+  vcsim_env
+
+  run govc vcsa.service.ls
+  assert_success
+  assert_matches sshd
+
+  run govc vcsa.service stop sshd
+  assert_success
+
+  run govc vcsa.service.ls -json
+  assert_success
+  [ "$(jq -r .sshd.state <<<"$output")" = "STOPPED" ]
+
+  run govc vcsa.service start sshd
+  assert_success
+  [ "$(govc vcsa.service.ls -json | jq -r .sshd.state)" = "STARTED" ]
+
+  run govc vcsa.service start enoent
+  assert_failure
+
+  run govc vcsa.service enable sshd
+  assert_failure
+}
+
+@test "vcsa.vmon" {
+  vcsim_env
+
+  run govc vcsa.vmon.ls
+  assert_success
+  assert_matches vpxd
+
+  [ "$(govc vcsa.vmon.ls -json | jq -r .vpxd.health)" = "HEALTHY" ]
+  [ "$(govc vcsa.vmon.ls -json | jq -r .imagebuilder.state)" = "STOPPED" ]
+
+  run govc vcsa.vmon disable imagebuilder
+  assert_success
+
+  run govc vcsa.vmon start imagebuilder
+  assert_failure # disabled
+
+  run govc vcsa.vmon automatic imagebuilder
+  assert_success
+
+  run govc vcsa.vmon start imagebuilder
+  assert_success
+
+  run govc vcsa.vmon.ls -json
+  assert_success
+  [ "$(jq -r .imagebuilder.startup_type <<<"$output")" = "AUTOMATIC" ]
+  [ "$(jq -r .imagebuilder.state <<<"$output")" = "STARTED" ]
+
+  run govc vcsa.vmon stop vsphere-ui vpxd
+  assert_success
+  [ "$(govc vcsa.vmon.ls -json | jq -r .vpxd.state)" = "STOPPED" ]
+}
+
+@test "vcsa.update" {
+  vcsim_env
+
+  run govc vcsa.update.info -json
+  assert_success
+  [ "$(jq -r .info.state <<<"$output")" = "UPDATES_PENDING" ]
+  current=$(jq -r .info.version <<<"$output")
+
+  run govc vcsa.update.ls -source INVALID
+  assert_failure
+
+  run govc vcsa.update.ls -json
+  assert_success
+  version=$(jq -r .[0].version <<<"$output")
+
+  run govc vcsa.update.precheck "$version"
+  assert_success
+  assert_matches "Reboot Required: *true"
+
+  run govc vcsa.update.precheck enoent
+  assert_failure
+
+  run govc vcsa.update.install "$version"
+  assert_failure # not staged
+
+  run govc vcsa.update.stage "$version"
+  assert_success
+
+  run govc vcsa.update.info -json
+  assert_success
+  [ "$(jq -r .staged.version <<<"$output")" = "$version" ]
+
+  run govc vcsa.update.stage -rm
+  assert_success
+
+  run govc vcsa.update.stage -rm
+  assert_failure
+
+  run govc vcsa.update.install -stage "$version"
+  assert_success
+
+  run govc vcsa.update.info -json
+  assert_success
+  [ "$(jq -r .info.state <<<"$output")" = "UP_TO_DATE" ]
+  [ "$(jq -r .info.version <<<"$output")" = "$version" ]
+  [ "$(jq -r .info.task.status <<<"$output")" = "SUCCEEDED" ]
+  [ "$version" != "$current" ]
+
+  run govc vcsa.update.ls -json
+  assert_success
+  [ "$(jq length <<<"$output")" = 0 ]
+}
+
+@test "vcsa.ntp" {
+  vcsim_env
+
+  run govc vcsa.ntp.info -json
+  assert_success
+  [ "$(jq -r .mode <<<"$output")" = "HOST" ]
+  [ "$(jq -r '.servers | length' <<<"$output")" = 0 ]
+
+  run govc vcsa.ntp.change
+  assert_failure
+
+  run govc vcsa.ntp.change -mode INVALID
+  assert_failure
+
+  run govc vcsa.ntp.change -mode NTP time1.example.com time2.example.com
+  assert_success
+
+  run govc vcsa.ntp.info -json
+  assert_success
+  [ "$(jq -r .mode <<<"$output")" = "NTP" ]
+  [ "$(jq -r '.servers | join(",")' <<<"$output")" = "time1.example.com,time2.example.com" ]
+
+  [ "$(govc vcsa.service.ls -json | jq -r .ntpd.state)" = "STARTED" ]
+
+  run govc vcsa.ntp.test
+  assert_success
+  assert_matches "time2.example.com *SERVER_REACHABLE"
+
+  run govc vcsa.ntp.test -json time.invalid
+  assert_success
+  [ "$(jq -r .[0].status <<<"$output")" = "SERVER_UNREACHABLE" ]
+}
+
+@test "vcsa.backup" {
+  vcsim_env
+
+  dir=$BATS_TMPDIR/vcsa-backup
+  rm -rf "$dir"
+
+  run govc vcsa.backup.parts
+  assert_success
+  assert_matches seat
+
+  run govc vcsa.backup.validate "sftp://backup.example.com$dir/vc01"
+  assert_success
+  assert_matches "Status: *OK"
+
+  run govc vcsa.backup.validate -part enoent "sftp://backup.example.com$dir/vc01"
+  assert_failure
+
+  touch "$dir.file"
+  run govc vcsa.backup.create "ftp://backup.example.com$dir.file/vc01"
+  assert_success # job fails, not a directory
+  [ "$(govc vcsa.backup.ls -json "$output" | jq -r .[].state)" = "FAILED" ]
+
+  run govc vcsa.backup.create -type INVALID "$dir/vc01"
+  assert_failure
+
+  run govc vcsa.backup.create -part seat -backup-password secret -comment test "sftp://backup.example.com$dir/vc01"
+  assert_success
+  id=$output
+
+  run govc vcsa.backup.ls -json "$id"
+  assert_success
+  [ "$(jq -r .[].state <<<"$output")" = "SUCCEEDED" ]
+  [ "$(jq -r '.[].parts | join(",")' <<<"$output")" = "common,seat" ]
+  [ -e "$dir/vc01/seat.data" ]
+
+  run govc vcsa.backup.cancel "$id"
+  assert_failure # completed
+
+  run govc vcsa.backup.validate "sftp://backup.example.com$dir/vc01"
+  assert_success
+  assert_matches "Status: *WARNING"
+
+  run govc vcsa.backup.restore.validate "sftp://backup.example.com$dir/vc01"
+  assert_failure # password required
+  assert_matches "Applicable: *false"
+
+  run govc vcsa.backup.restore.validate -json -backup-password secret "sftp://backup.example.com$dir/vc01"
+  assert_success
+  [ "$(jq -r .comment <<<"$output")" = "test" ]
+
+  run govc vcsa.backup.restore.validate "sftp://backup.example.com$dir/enoent"
+  assert_failure
+
+  rm -rf "$dir" "$dir.file"
+}
+
+@test "vcsa.backup.schedule" {
+  vcsim_env
+
+  dir=$BATS_TMPDIR/vcsa-backup-schedule
+  rm -rf "$dir"
+
+  run govc vcsa.backup.schedule.create -hour 24 default "nfs://nas.example.com$dir"
+  assert_failure
+
+  run govc vcsa.backup.schedule.create -day saturday -retain 2 default "nfs://nas.example.com$dir"
+  assert_success
+
+  run govc vcsa.backup.schedule.create default "nfs://nas.example.com$dir"
+  assert_failure # exists
+
+  run govc vcsa.backup.schedule.ls
+  assert_success
+  assert_matches "23:00 SATURDAY"
+
+  [ "$(govc vcsa.backup.schedule.ls -json | jq -r .default.retention_info.max_count)" = 2 ]
+
+  for i in 1 2 3; do
+    run govc vcsa.backup.schedule.run -comment "run $i" default
+    assert_success
+  done
+
+  run govc vcsa.backup.ls -json "$output"
+  assert_success
+  [ "$(jq -r .[].type <<<"$output")" = "SCHEDULED" ]
+  [ "$(jq -r .[].location_type <<<"$output")" = "NFS" ]
+
+  [ "$(find "$dir" -name backup-metadata.json | wc -l)" -eq 2 ]
+
+  run govc vcsa.backup.schedule.rm default
+  assert_success
+
+  run govc vcsa.backup.schedule.run default
+  assert_failure
+
+  rm -rf "$dir"
+}
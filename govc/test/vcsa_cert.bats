@@ -0,0 +1,124 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "vcsa.cert.info" {
+  vcsim_env
+
+  run govc vcsa.cert.info
+  assert_success
+  assert_matches Thumbprint
+
+  run govc vcsa.cert.info -show
+  assert_success
+  assert_matches "BEGIN CERTIFICATE"
+
+  thumbprint=$(govc about.cert -json | jq -r .thumbprintSHA1)
+  run govc vcsa.cert.info -json
+  assert_success
+  [ "$(jq -r .thumbprint <<<"$output")" = "$thumbprint" ]
+}
+
+@test "vcsa.cert.renew" {
+  vcsim_env
+
+  before=$(govc vcsa.cert.info -json | jq -r .thumbprint)
+
+  run govc vcsa.cert.renew -duration 30
+  assert_success
+
+  run govc vcsa.cert.info -json
+  assert_success
+  [ "$(jq -r .thumbprint <<<"$output")" != "$before" ]
+  [ "$(jq -r .issuer_dn <<<"$output")" = "CN=CA" ]
+}
+
+@test "vcsa.cert.replace" {
+  vcsim_env
+
+  dir=$BATS_TMPDIR/vcsa-cert
+  mkdir -p "$dir"
+
+  openssl req -x509 -newkey rsa:2048 -nodes -days 1 -subj "/CN=Test CA" \
+          -addext basicConstraints=critical,CA:TRUE -addext keyUsage=keyCertSign \
+          -keyout "$dir/ca.key" -out "$dir/ca.pem" 2>/dev/null
+
+  run govc vcsa.cert.csr -cn vcenter.example.com -o VMware -c US -san vcenter.example.com
+  assert_success
+  echo "$output" > "$dir/vcenter.csr"
+
+  openssl x509 -req -days 1 -in "$dir/vcenter.csr" -CA "$dir/ca.pem" -CAkey "$dir/ca.key" \
+          -CAcreateserial -out "$dir/vcenter.pem" 2>/dev/null
+
+  run govc vcsa.cert.replace "$dir/vcenter.pem"
+  assert_failure # untrusted CA
+
+  run govc vcsa.cert.replace -root "$dir/ca.pem" "$dir/vcenter.pem"
+  assert_success # using the key retained by vcsa.cert.csr
+
+  run govc vcsa.cert.info -json
+  assert_success
+  [ "$(jq -r .subject_dn <<<"$output")" = "CN=vcenter.example.com,O=VMware,C=US" ]
+  [ "$(jq -r .issuer_dn <<<"$output")" = "CN=Test CA" ]
+
+  run govc vcsa.cert.replace -private-key "$dir/ca.key" -root "$dir/ca.pem" "$dir/vcenter.pem"
+  assert_failure # key mismatch
+
+  rm -rf "$dir"
+}
+
+@test "vcsa.cert.root" {
+  vcsim_env
+
+  run govc vcsa.cert.root.ls
+  assert_success
+  assert_matches "CN=CA"
+
+  n=$(govc vcsa.cert.root.ls -json | jq length)
+
+  run govc vcsa.cert.root.add -id test - <<<"$(govc about.cert -show)"
+  assert_success test
+
+  run govc vcsa.cert.root.add -id test - <<<"$(govc about.cert -show)"
+  assert_failure
+
+  run govc vcsa.cert.root.ls -json
+  assert_success
+  [ "$(jq length <<<"$output")" = $((n+1)) ]
+
+  run govc vcsa.cert.root.rm test
+  assert_success
+
+  run govc vcsa.cert.root.rm test
+  assert_failure
+}
+
+@test "vcsa.cert.signing" {
+  vcsim_env
+
+  run govc vcsa.cert.signing.info
+  assert_success
+  assert_matches ssoserver-sign
+
+  run govc vcsa.cert.signing.refresh
+  assert_success
+
+  run govc vcsa.cert.signing.info -json
+  assert_success
+  [ "$(jq '.signing_cert_chains | length' <<<"$output")" = 2 ]
+}
+
+@test "vcsa.cert.vmca.replace" {
+  vcsim_env
+
+  run govc vcsa.cert.vmca.replace -cn "vcsim CA" -o VMware
+  assert_success
+
+  run govc vcsa.cert.info -json
+  assert_success
+  [ "$(jq -r .issuer_dn <<<"$output")" = "CN=vcsim CA,O=VMware" ]
+
+  run govc vcsa.cert.root.ls
+  assert_success
+  assert_matches "vcsim CA"
+}
@@ -36,3 +36,100 @@ load test_helper
   config=$(jq .clusters[].info.FileServiceConfig.Enabled <<<"$output")
   assert_equal true "$config"
 }
+
+@test "vsan.fileshare" {
+  vcsim_env -cluster 2
+
+  export GOVC_CLUSTER=DC0_C0
+
+  run govc vsan.fileshare.create share1
+  assert_failure # file service not enabled
+
+  run govc vsan.change -file-service-enabled DC0_C0
+  assert_success
+
+  run govc vsan.fileshare.ls
+  assert_success ""
+
+  run govc vsan.fileshare.create -quota 10GB -permission "10.0.0.0/24=READ_ONLY" share1
+  assert_success
+
+  run govc vsan.fileshare.create share1
+  assert_failure # duplicate name
+
+  run govc vsan.fileshare.create -protocol SMB share2
+  assert_success
+
+  run govc vsan.fileshare.ls
+  assert_success
+  assert_equal 2 "${#lines[@]}"
+
+  run govc vsan.fileshare.ls -json share1
+  assert_success
+  assert_equal 10GB "$(jq -r .fileShares[].Config.Quota <<<"$output")"
+  assert_equal READ_ONLY "$(jq -r .fileShares[].Config.Permission[].Permissions <<<"$output")"
+
+  run govc vsan.fileshare.change -quota 20GB -label env=dev share1
+  assert_success
+
+  run govc vsan.fileshare.ls -json share1
+  assert_success
+  assert_equal 20GB "$(jq -r .fileShares[].Config.Quota <<<"$output")"
+  assert_equal dev "$(jq -r '.fileShares[].Config.Labels[] | select(.key == "env") | .value' <<<"$output")"
+
+  run govc vsan.fileshare.change -rm-label env share1
+  assert_success
+
+  run govc vsan.fileshare.ls -json share1
+  assert_success
+  assert_equal null "$(jq -r .fileShares[].Config.Labels <<<"$output")"
+
+  run govc vsan.fileshare.rm enoent
+  assert_failure
+
+  run govc vsan.fileshare.rm share1 share2
+  assert_success
+
+  run govc vsan.fileshare.ls
+  assert_success ""
+}
+
+@test "vsan.health" {
+  vcsim_env -cluster 2
+
+  run govc vsan.health DC0_C0
+  assert_success
+  assert_matches "Overall health: *green"
+
+  run govc vsan.health -l DC0_C0
+  assert_success
+  assert_matches "Hosts disconnected from VC: *green"
+
+  run govc vsan.health -json DC0_C0
+  assert_success
+  assert_equal green "$(jq -r .OverallHealth <<<"$output")"
+  assert_equal 3 "$(jq -r '.ClusterStatus.TrackedHostsStatus | length' <<<"$output")"
+
+  run govc vsan.health enoent
+  assert_failure
+}
+
+@test "vsan.perf" {
+  vcsim_env -cluster 2
+
+  run govc vsan.perf DC0_C0
+  assert_success
+  assert_equal 8 "${#lines[@]}"
+
+  run govc vsan.perf -entity host-domclient -label iopsRead,iopsWrite DC0_C0
+  assert_success
+  assert_equal 6 "${#lines[@]}"
+
+  run govc vsan.perf -entity disk-group -n 12 -json DC0_C0
+  assert_success
+  assert_equal 3 "$(jq -r '.entities | length' <<<"$output")"
+  assert_equal 12 "$(jq -r '.entities[0].Value[0].Values | split(",") | length' <<<"$output")"
+
+  run govc vsan.perf -entity enoent DC0_C0
+  assert_failure
+}
@@ -0,0 +1,140 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// AliasManager wraps GuestAliasManager, which maps SSO certificates and SAML token subjects to guest accounts,
+// such that guest operations can use SAMLTokenAuthentication rather than guest passwords.
+type AliasManager struct {
+	types.ManagedObjectReference
+
+	vm types.ManagedObjectReference
+
+	c *vim25.Client
+}
+
+func (m AliasManager) Reference() types.ManagedObjectReference {
+	return m.ManagedObjectReference
+}
+
+// AddAlias adds an alias for the guest account username, where base64Cert is the SSO signing certificate.
+// If mapCert is true, the username can be omitted from the SAMLTokenAuthentication of guest operation requests.
+func (m AliasManager) AddAlias(ctx context.Context, auth types.BaseGuestAuthentication, username string, mapCert bool, base64Cert string, info types.GuestAuthAliasInfo) error {
+	req := types.AddGuestAlias{
+		This:       m.Reference(),
+		Vm:         m.vm,
+		Auth:       auth,
+		Username:   username,
+		MapCert:    mapCert,
+		Base64Cert: base64Cert,
+		AliasInfo:  info,
+	}
+
+	_, err := methods.AddGuestAlias(ctx, m.c, &req)
+
+	return err
+}
+
+// RemoveAlias removes the alias with the given certificate and subject from the guest account username
+func (m AliasManager) RemoveAlias(ctx context.Context, auth types.BaseGuestAuthentication, username string, base64Cert string, subject types.BaseGuestAuthSubject) error {
+	req := types.RemoveGuestAlias{
+		This:       m.Reference(),
+		Vm:         m.vm,
+		Auth:       auth,
+		Username:   username,
+		Base64Cert: base64Cert,
+		Subject:    subject,
+	}
+
+	_, err := methods.RemoveGuestAlias(ctx, m.c, &req)
+
+	return err
+}
+
+// RemoveAliasByCert removes all aliases with the given certificate from the guest account username
+func (m AliasManager) RemoveAliasByCert(ctx context.Context, auth types.BaseGuestAuthentication, username string, base64Cert string) error {
+	req := types.RemoveGuestAliasByCert{
+		This:       m.Reference(),
+		Vm:         m.vm,
+		Auth:       auth,
+		Username:   username,
+		Base64Cert: base64Cert,
+	}
+
+	_, err := methods.RemoveGuestAliasByCert(ctx, m.c, &req)
+
+	return err
+}
+
+// ListAliases returns the aliases of the guest account username
+func (m AliasManager) ListAliases(ctx context.Context, auth types.BaseGuestAuthentication, username string) ([]types.GuestAliases, error) {
+	req := types.ListGuestAliases{
+		This:     m.Reference(),
+		Vm:       m.vm,
+		Auth:     auth,
+		Username: username,
+	}
+
+	res, err := methods.ListGuestAliases(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
+// ListMappedAliases returns the aliases with a mapped certificate, for all guest accounts
+func (m AliasManager) ListMappedAliases(ctx context.Context, auth types.BaseGuestAuthentication) ([]types.GuestMappedAliases, error) {
+	req := types.ListGuestMappedAliases{
+		This: m.Reference(),
+		Vm:   m.vm,
+		Auth: auth,
+	}
+
+	res, err := methods.ListGuestMappedAliases(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
+// TokenInfo is the SAML token subject and signing certificate, as used by GuestAliasManager
+type TokenInfo struct {
+	Subject    string
+	Base64Cert string
+}
+
+// ParseToken returns the subject and base64 encoded signing certificate of the given SAML token,
+// such as one issued by sts.Client, for use with AddAlias.
+func ParseToken(token string) (*TokenInfo, error) {
+	var assertion struct {
+		Subject string   `xml:"Subject>NameID"`
+		Certs   []string `xml:"Signature>KeyInfo>X509Data>X509Certificate"`
+	}
+
+	if err := xml.Unmarshal([]byte(token), &assertion); err != nil {
+		return nil, err
+	}
+
+	if assertion.Subject == "" || len(assertion.Certs) == 0 {
+		return nil, errors.New("SAML token subject or signing certificate not found")
+	}
+
+	return &TokenInfo{
+		Subject:    strings.TrimSpace(assertion.Subject),
+		Base64Cert: strings.Join(strings.Fields(assertion.Certs[0]), ""),
+	}, nil
+}
@@ -0,0 +1,380 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/internal"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// DefaultTransferConcurrency is the default number of concurrent file transfers for directory transfers
+const DefaultTransferConcurrency = 4
+
+// TransferOptions configures UploadDirectory and DownloadDirectory
+type TransferOptions struct {
+	// Concurrency is the maximum number of concurrent file transfers, defaults to DefaultTransferConcurrency
+	Concurrency int
+
+	// Overwrite existing destination files
+	Overwrite bool
+
+	// Resume skips files where the destination has the same size and modification time as the source,
+	// such that a failed transfer can be resumed. Other existing destination files are overwritten.
+	Resume bool
+
+	// Attributes returns the guest file attributes for an uploaded file,
+	// defaults to GuestPosixFileAttributes with the file permissions and modification time.
+	Attributes func(os.FileInfo) types.BaseGuestFileAttributes
+}
+
+func (o *TransferOptions) concurrency() int {
+	if o.Concurrency <= 0 {
+		return DefaultTransferConcurrency
+	}
+	return o.Concurrency
+}
+
+func (o *TransferOptions) attributes(info os.FileInfo) types.BaseGuestFileAttributes {
+	if o.Attributes != nil {
+		return o.Attributes(info)
+	}
+
+	return &types.GuestPosixFileAttributes{
+		GuestFileAttributes: types.GuestFileAttributes{
+			ModificationTime: types.NewTime(info.ModTime()),
+		},
+		Permissions: int64(info.Mode().Perm()),
+	}
+}
+
+// unchanged returns true if the destination file has the same size and modification time as the source
+func unchanged(size int64, mtime *time.Time, dstSize int64, dstTime time.Time) bool {
+	return mtime != nil && size == dstSize && mtime.Unix() == dstTime.Unix()
+}
+
+// within returns true if the given name is dir or is under dir
+func within(dir, name string) bool {
+	rel, err := filepath.Rel(dir, name)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// transfer runs the given jobs with at most n running concurrently, stopping at the first error
+func transfer(ctx context.Context, n int, jobs []func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		err error
+	)
+
+	ch := make(chan func(context.Context) error)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range ch {
+				if jerr := job(ctx); jerr != nil {
+					mu.Lock()
+					if err == nil {
+						err = jerr
+						cancel()
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+queue:
+	for _, job := range jobs {
+		select {
+		case ch <- job:
+		case <-ctx.Done():
+			break queue
+		}
+	}
+
+	close(ch)
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return err
+}
+
+// ListAllFiles returns all entries of the given guest directory, paging through ListFiles results.
+// The "." and ".." entries are not included.
+func (m FileManager) ListAllFiles(ctx context.Context, auth types.BaseGuestAuthentication, dir string) ([]types.GuestFileInfo, error) {
+	var files []types.GuestFileInfo
+	var index int32
+
+	for {
+		info, err := m.ListFiles(ctx, auth, dir, index, 0, "")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range info.Files {
+			if file.Path != "." && file.Path != ".." {
+				files = append(files, file)
+			}
+		}
+
+		index += int32(len(info.Files))
+		if info.Remaining == 0 || len(info.Files) == 0 {
+			return files, nil
+		}
+	}
+}
+
+func (m FileManager) uploadFile(ctx context.Context, auth types.BaseGuestAuthentication, src, dst string, attr types.BaseGuestFileAttributes, overwrite bool) error {
+	f, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	turl, err := m.InitiateFileTransferToGuest(ctx, auth, dst, attr, info.Size(), overwrite)
+	if err != nil {
+		return fmt.Errorf("%s: %w", dst, err)
+	}
+
+	u, err := m.TransferURL(ctx, turl)
+	if err != nil {
+		return err
+	}
+
+	vc := m.c
+	if internal.UsingEnvoySidecar(vc) {
+		vc = internal.ClientWithEnvoyHostGateway(vc)
+	}
+
+	p := soap.DefaultUpload
+	p.ContentLength = info.Size()
+	p.Close = true // disable Keep-Alive connection to ESX
+
+	return vc.Client.Upload(ctx, f, u, &p)
+}
+
+func (m FileManager) downloadFile(ctx context.Context, auth types.BaseGuestAuthentication, src, dst string) error {
+	info, err := m.InitiateFileTransferFromGuest(ctx, auth, src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+
+	u, err := m.TransferURL(ctx, info.Url)
+	if err != nil {
+		return err
+	}
+
+	vc := m.c
+	if internal.UsingEnvoySidecar(vc) {
+		vc = internal.ClientWithEnvoyHostGateway(vc)
+	}
+
+	p := soap.DefaultDownload
+	p.Close = true // disable Keep-Alive connection to ESX
+
+	if err = vc.Client.DownloadFile(ctx, dst, u, &p); err != nil {
+		return err
+	}
+
+	if attr, ok := info.Attributes.(*types.GuestPosixFileAttributes); ok && attr.Permissions != 0 {
+		if err = os.Chmod(dst, fs.FileMode(attr.Permissions).Perm()); err != nil {
+			return err
+		}
+	}
+
+	return setFileTimes(dst, info.Attributes)
+}
+
+func setFileTimes(name string, attr types.BaseGuestFileAttributes) error {
+	if attr == nil {
+		return nil
+	}
+
+	a := attr.GetGuestFileAttributes()
+	if a.ModificationTime == nil {
+		return nil
+	}
+
+	atime := *a.ModificationTime
+	if a.AccessTime != nil {
+		atime = *a.AccessTime
+	}
+
+	return os.Chtimes(name, atime, *a.ModificationTime)
+}
+
+// UploadDirectory recursively copies the local src directory to the guest dst directory,
+// using InitiateFileTransferToGuest for each regular file. Symlinks and other special files are skipped.
+// The guest directories are created first, then files are transferred concurrently.
+func (m FileManager) UploadDirectory(ctx context.Context, auth types.BaseGuestAuthentication, src, dst string, opts *TransferOptions) error {
+	if opts == nil {
+		opts = new(TransferOptions)
+	}
+
+	var jobs []func(context.Context) error
+	existing := make(map[string]types.GuestFileInfo)
+
+	err := filepath.WalkDir(src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, name)
+		if err != nil {
+			return err
+		}
+		target := path.Join(dst, filepath.ToSlash(rel))
+
+		if d.IsDir() {
+			err = m.MakeDirectory(ctx, auth, target, true)
+			if err != nil && !fault.Is(err, &types.FileAlreadyExists{}) {
+				return fmt.Errorf("%s: %w", target, err)
+			}
+
+			if opts.Resume {
+				files, err := m.ListAllFiles(ctx, auth, target)
+				if err != nil {
+					return err
+				}
+				for _, file := range files {
+					existing[path.Join(target, file.Path)] = file
+				}
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		attr := opts.attributes(info)
+		overwrite := opts.Overwrite
+
+		if file, ok := existing[target]; ok && opts.Resume {
+			var mtime *time.Time
+			if file.Attributes != nil {
+				mtime = file.Attributes.GetGuestFileAttributes().ModificationTime
+			}
+			if unchanged(file.Size, mtime, info.Size(), info.ModTime()) {
+				return nil
+			}
+			overwrite = true
+		}
+
+		jobs = append(jobs, func(ctx context.Context) error {
+			return m.uploadFile(ctx, auth, name, target, attr, overwrite)
+		})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return transfer(ctx, opts.concurrency(), jobs)
+}
+
+// DownloadDirectory recursively copies the guest src directory to the local dst directory,
+// using InitiateFileTransferFromGuest for each file. Symlinks are skipped.
+// The local directories are created first, then files are transferred concurrently.
+func (m FileManager) DownloadDirectory(ctx context.Context, auth types.BaseGuestAuthentication, src, dst string, opts *TransferOptions) error {
+	if opts == nil {
+		opts = new(TransferOptions)
+	}
+
+	var jobs []func(context.Context) error
+
+	dirs := []string{""}
+
+	for len(dirs) != 0 {
+		rel := dirs[0]
+		dirs = dirs[1:]
+
+		dir := filepath.Join(dst, filepath.FromSlash(rel))
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return err
+		}
+
+		files, err := m.ListAllFiles(ctx, auth, path.Join(src, rel))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path.Join(src, rel), err)
+		}
+
+		for _, file := range files {
+			if strings.ContainsAny(file.Path, `/\`) || file.Path == "." || file.Path == ".." {
+				return fmt.Errorf("%s: invalid file name %q", path.Join(src, rel), file.Path)
+			}
+
+			name := path.Join(rel, file.Path)
+
+			switch types.GuestFileType(file.Type) {
+			case types.GuestFileTypeDirectory:
+				dirs = append(dirs, name)
+				continue
+			case types.GuestFileTypeSymlink:
+				continue
+			}
+
+			source := path.Join(src, name)
+			target := filepath.Join(dst, filepath.FromSlash(name))
+			if !within(dst, target) {
+				return fmt.Errorf("%s: outside of %s", target, dst)
+			}
+
+			if info, err := os.Stat(target); err == nil {
+				var mtime *time.Time
+				if file.Attributes != nil {
+					mtime = file.Attributes.GetGuestFileAttributes().ModificationTime
+				}
+				switch {
+				case opts.Resume && unchanged(file.Size, mtime, info.Size(), info.ModTime()):
+					continue
+				case !opts.Resume && !opts.Overwrite:
+					return fmt.Errorf("%s: %w", target, os.ErrExist)
+				}
+			}
+
+			jobs = append(jobs, func(ctx context.Context) error {
+				return m.downloadFile(ctx, auth, source, target)
+			})
+		}
+	}
+
+	return transfer(ctx, opts.concurrency(), jobs)
+}
@@ -0,0 +1,156 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestTransferCancel(t *testing.T) {
+	fail := errors.New("fail")
+	var started int32
+
+	jobs := make([]func(context.Context) error, 100)
+	jobs[0] = func(context.Context) error {
+		atomic.AddInt32(&started, 1)
+		return fail
+	}
+	for i := 1; i < len(jobs); i++ {
+		jobs[i] = func(ctx context.Context) error {
+			atomic.AddInt32(&started, 1)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+	}
+
+	err := transfer(context.Background(), 2, jobs)
+	if !errors.Is(err, fail) {
+		t.Errorf("err=%v", err)
+	}
+	if n := atomic.LoadInt32(&started); n == int32(len(jobs)) {
+		t.Errorf("started=%d", n)
+	}
+}
+
+func TestUnchanged(t *testing.T) {
+	now := time.Now()
+	sub := now.Truncate(time.Second).Add(time.Millisecond)
+
+	tests := []struct {
+		size   int64
+		mtime  *time.Time
+		expect bool
+	}{
+		{10, &now, true},
+		{10, &sub, true}, // guest times have second precision
+		{11, &now, false},
+		{10, nil, false},
+	}
+
+	for i, test := range tests {
+		if unchanged(test.size, test.mtime, 10, now.Truncate(time.Second)) != test.expect {
+			t.Errorf("%d: expected %t", i, test.expect)
+		}
+	}
+}
+
+func TestDownloadDirectory(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("GOOS=%s", runtime.GOOS) // toolbox ListFiles is only implemented for linux
+	}
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, simulator.Map(ctx).Any("VirtualMachine").Reference())
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: simulator.ToolboxBackingOptionKey, Value: "TRUE"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+
+		fm, err := NewOperationsManager(c, vm.Reference()).FileManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		src := t.TempDir()
+		mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		for _, name := range []string{"a.txt", "b.txt", "c..txt"} {
+			path := filepath.Join(src, name)
+			if err = os.WriteFile(path, []byte(name), 0600); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		dst := t.TempDir()
+		if err = fm.DownloadDirectory(ctx, auth, src, dst, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = os.Stat(filepath.Join(dst, "c..txt")); err != nil {
+			t.Error(err)
+		}
+
+		// existing files are not overwritten by default
+		err = fm.DownloadDirectory(ctx, auth, src, dst, nil)
+		if !errors.Is(err, os.ErrExist) {
+			t.Errorf("err=%v", err)
+		}
+
+		// Resume skips files with the same size and mtime, other files are overwritten
+		a := filepath.Join(dst, "a.txt")
+		if err = os.WriteFile(a, []byte("A.TXT"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.Chtimes(a, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		b := filepath.Join(dst, "b.txt")
+		if err = os.WriteFile(b, []byte("modified"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = fm.DownloadDirectory(ctx, auth, src, dst, &TransferOptions{Resume: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		for name, expect := range map[string]string{a: "A.TXT", b: "b.txt"} {
+			data, _ := os.ReadFile(name)
+			if string(data) != expect {
+				t.Errorf("%s: data=%q", name, data)
+			}
+		}
+
+		// guest file names are not allowed to escape dst
+		if err = os.WriteFile(filepath.Join(src, `..\x`), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err = fm.DownloadDirectory(ctx, auth, src, t.TempDir(), nil); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -39,6 +39,17 @@ func (m OperationsManager) AuthManager(ctx context.Context) (*AuthManager, error
 	return &AuthManager{*g.AuthManager, m.vm, m.c}, nil
 }
 
+func (m OperationsManager) AliasManager(ctx context.Context) (*AliasManager, error) {
+	var g mo.GuestOperationsManager
+
+	err := m.retrieveOne(ctx, "aliasManager", &g)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AliasManager{*g.AliasManager, m.vm, m.c}, nil
+}
+
 func (m OperationsManager) FileManager(ctx context.Context) (*FileManager, error) {
 	var g mo.GuestOperationsManager
 
@@ -66,3 +77,14 @@ func (m OperationsManager) ProcessManager(ctx context.Context) (*ProcessManager,
 
 	return &ProcessManager{*g.ProcessManager, m.vm, m.c}, nil
 }
+
+func (m OperationsManager) RegistryManager(ctx context.Context) (*RegistryManager, error) {
+	var g mo.GuestOperationsManager
+
+	err := m.retrieveOne(ctx, "guestWindowsRegistryManager", &g)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegistryManager{*g.GuestWindowsRegistryManager, m.vm, m.c}, nil
+}
@@ -0,0 +1,220 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// RegistryManager wraps GuestWindowsRegistryManager
+type RegistryManager struct {
+	types.ManagedObjectReference
+
+	vm types.ManagedObjectReference
+
+	c *vim25.Client
+}
+
+func (m RegistryManager) Reference() types.ManagedObjectReference {
+	return m.ManagedObjectReference
+}
+
+// RegistryKey returns a key name spec for the given path, such as `HKEY_LOCAL_MACHINE\SOFTWARE\VMware, Inc.`,
+// using the native registry view.
+func RegistryKey(path string) types.GuestRegKeyNameSpec {
+	return types.GuestRegKeyNameSpec{
+		RegistryPath: path,
+		WowBitness:   string(types.GuestRegKeyWowSpecWOWNative),
+	}
+}
+
+// ExpandString is a REG_EXPAND_SZ registry value, for use with NewRegistryValue
+type ExpandString string
+
+// NewRegistryValue returns a value spec, where the registry data type is determined by the type of data:
+// string (REG_SZ), ExpandString (REG_EXPAND_SZ), int32 or uint32 (REG_DWORD), int64 or uint64 (REG_QWORD),
+// []byte (REG_BINARY) and []string (REG_MULTI_SZ).
+// REG_BINARY data is base64 encoded, as the xsd:base64Binary field is otherwise encoded as raw bytes.
+func NewRegistryValue(key types.GuestRegKeyNameSpec, name string, data any) (*types.GuestRegValueSpec, error) {
+	var spec types.BaseGuestRegValueDataSpec
+
+	switch v := data.(type) {
+	case string:
+		spec = &types.GuestRegValueStringSpec{Value: v}
+	case ExpandString:
+		spec = &types.GuestRegValueExpandStringSpec{Value: string(v)}
+	case int32:
+		spec = &types.GuestRegValueDwordSpec{Value: v}
+	case uint32:
+		spec = &types.GuestRegValueDwordSpec{Value: int32(v)}
+	case int64:
+		spec = &types.GuestRegValueQwordSpec{Value: v}
+	case uint64:
+		spec = &types.GuestRegValueQwordSpec{Value: int64(v)}
+	case []byte:
+		spec = &types.GuestRegValueBinarySpec{Value: []byte(base64.StdEncoding.EncodeToString(v))}
+	case []string:
+		spec = &types.GuestRegValueMultiStringSpec{Value: v}
+	default:
+		return nil, fmt.Errorf("unsupported registry value type: %T", data)
+	}
+
+	return &types.GuestRegValueSpec{
+		Name: types.GuestRegValueNameSpec{KeyName: key, Name: name},
+		Data: spec,
+	}, nil
+}
+
+// RegistryValueData returns the Go value of the given registry data, the inverse of NewRegistryValue,
+// where REG_DWORD is returned as uint32 and REG_QWORD as uint64.
+func RegistryValueData(data types.BaseGuestRegValueDataSpec) any {
+	switch v := data.(type) {
+	case *types.GuestRegValueStringSpec:
+		return v.Value
+	case *types.GuestRegValueExpandStringSpec:
+		return ExpandString(v.Value)
+	case *types.GuestRegValueDwordSpec:
+		return uint32(v.Value)
+	case *types.GuestRegValueQwordSpec:
+		return uint64(v.Value)
+	case *types.GuestRegValueBinarySpec:
+		b, err := base64.StdEncoding.DecodeString(string(v.Value))
+		if err != nil {
+			return v.Value
+		}
+		return b
+	case *types.GuestRegValueMultiStringSpec:
+		return v.Value
+	default:
+		return nil
+	}
+}
+
+// RegistryValueType returns the Windows name of the registry data type, such as REG_SZ
+func RegistryValueType(data types.BaseGuestRegValueDataSpec) string {
+	switch data.(type) {
+	case *types.GuestRegValueStringSpec:
+		return "REG_SZ"
+	case *types.GuestRegValueExpandStringSpec:
+		return "REG_EXPAND_SZ"
+	case *types.GuestRegValueDwordSpec:
+		return "REG_DWORD"
+	case *types.GuestRegValueQwordSpec:
+		return "REG_QWORD"
+	case *types.GuestRegValueBinarySpec:
+		return "REG_BINARY"
+	case *types.GuestRegValueMultiStringSpec:
+		return "REG_MULTI_SZ"
+	default:
+		return "REG_NONE"
+	}
+}
+
+func (m RegistryManager) CreateKey(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, volatile bool, classType string) error {
+	req := types.CreateRegistryKeyInGuest{
+		This:       m.Reference(),
+		Vm:         m.vm,
+		Auth:       auth,
+		KeyName:    key,
+		IsVolatile: volatile,
+		ClassType:  classType,
+	}
+
+	_, err := methods.CreateRegistryKeyInGuest(ctx, m.c, &req)
+
+	return err
+}
+
+func (m RegistryManager) ListKeys(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, recursive bool, pattern string) ([]types.GuestRegKeyRecordSpec, error) {
+	req := types.ListRegistryKeysInGuest{
+		This:         m.Reference(),
+		Vm:           m.vm,
+		Auth:         auth,
+		KeyName:      key,
+		Recursive:    recursive,
+		MatchPattern: pattern,
+	}
+
+	res, err := methods.ListRegistryKeysInGuest(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
+func (m RegistryManager) DeleteKey(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, recursive bool) error {
+	req := types.DeleteRegistryKeyInGuest{
+		This:      m.Reference(),
+		Vm:        m.vm,
+		Auth:      auth,
+		KeyName:   key,
+		Recursive: recursive,
+	}
+
+	_, err := methods.DeleteRegistryKeyInGuest(ctx, m.c, &req)
+
+	return err
+}
+
+func (m RegistryManager) SetValue(ctx context.Context, auth types.BaseGuestAuthentication, value types.GuestRegValueSpec) error {
+	req := types.SetRegistryValueInGuest{
+		This:  m.Reference(),
+		Vm:    m.vm,
+		Auth:  auth,
+		Value: value,
+	}
+
+	_, err := methods.SetRegistryValueInGuest(ctx, m.c, &req)
+
+	return err
+}
+
+// Set creates or replaces the named value of the given key, see NewRegistryValue for the supported data types
+func (m RegistryManager) Set(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, name string, data any) error {
+	value, err := NewRegistryValue(key, name, data)
+	if err != nil {
+		return err
+	}
+
+	return m.SetValue(ctx, auth, *value)
+}
+
+func (m RegistryManager) ListValues(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, expand bool, pattern string) ([]types.GuestRegValueSpec, error) {
+	req := types.ListRegistryValuesInGuest{
+		This:          m.Reference(),
+		Vm:            m.vm,
+		Auth:          auth,
+		KeyName:       key,
+		ExpandStrings: expand,
+		MatchPattern:  pattern,
+	}
+
+	res, err := methods.ListRegistryValuesInGuest(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
+func (m RegistryManager) DeleteValue(ctx context.Context, auth types.BaseGuestAuthentication, name types.GuestRegValueNameSpec) error {
+	req := types.DeleteRegistryValueInGuest{
+		This:      m.Reference(),
+		Vm:        m.vm,
+		Auth:      auth,
+		ValueName: name,
+	}
+
+	_, err := methods.DeleteRegistryValueInGuest(ctx, m.c, &req)
+
+	return err
+}
@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -73,13 +75,28 @@ func NewClient(ctx context.Context, c *vim25.Client, vm mo.Reference, auth types
 	}, nil
 }
 
-func (c *Client) rm(ctx context.Context, path string) {
+// rm removes the guest file, using a new context as the caller's context may be done
+func (c *Client) rm(path string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
 	err := c.FileManager.DeleteFile(ctx, c.Authentication, path)
 	if err != nil {
 		log.Printf("rm %q: %s", path, err)
 	}
 }
 
+// rmdir removes the guest directory and its contents, using a new context as the caller's context may be done
+func (c *Client) rmdir(path string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := c.FileManager.DeleteDirectory(ctx, c.Authentication, path, true)
+	if err != nil {
+		log.Printf("rmdir %q: %s", path, err)
+	}
+}
+
 func (c *Client) mktemp(ctx context.Context) (string, error) {
 	return c.FileManager.CreateTemporaryFile(ctx, c.Authentication, "govmomi-", "", "")
 }
@@ -93,15 +110,166 @@ func (e *exitError) ExitCode() int {
 	return e.exitCode
 }
 
+// DefaultStreamInterval is the default interval used by Stream to poll the guest process output
+const DefaultStreamInterval = time.Second
+
+// output is a guest process stdout or stderr redirected to a guest temp file
+type output struct {
+	io.Writer
+	fd     string
+	path   string
+	offset int64
+}
+
+// inputScript is run by the guest shell to copy the numbered files uploaded to directory $1 to stdout in order,
+// polling every $2 seconds until the "eof" file is created or the directory is removed.
+const inputScript = `n=0; while :; do ` +
+	`if [ -f "$1/$n" ]; then cat "$1/$n" || exit 1; rm -f "$1/$n"; n=$((n+1)); ` +
+	`elif [ -e "$1/eof" ] || [ ! -d "$1" ]; then exit 0; ` +
+	`else sleep $2; fi; done`
+
+// maxInputChunk is the maximum size of a file uploaded by input.send
+const maxInputChunk = 1024 * 1024
+
+// input forwards stdin to a guest process while it is running
+type input struct {
+	dir  string
+	n    int
+	data chan []byte
+	done chan struct{}
+	err  error
+	eof  bool
+}
+
+func newInput(r io.Reader, dir string) *input {
+	in := &input{
+		dir:  dir,
+		data: make(chan []byte, 16),
+		done: make(chan struct{}),
+	}
+
+	go in.read(r)
+
+	return in
+}
+
+// read copies r to the data channel, which is closed once r returns an error
+func (in *input) read(r io.Reader) {
+	defer close(in.data)
+
+	for {
+		buf := make([]byte, 32*1024)
+		n, err := r.Read(buf)
+		if n != 0 {
+			select {
+			case in.data <- buf[:n]:
+			case <-in.done:
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				in.err = err
+			}
+			return
+		}
+	}
+}
+
+// send uploads the data read since the last call to the next numbered files in the guest directory.
+// Files are uploaded with a temporary name and then renamed, so the guest never reads a partial file.
+// Once stdin is at EOF, the "eof" directory is created.
+func (c *Client) send(ctx context.Context, in *input) error {
+	for !in.eof {
+		var buf bytes.Buffer
+
+	read:
+		for buf.Len() < maxInputChunk {
+			select {
+			case data, ok := <-in.data:
+				if !ok {
+					in.eof = true
+					break read
+				}
+				buf.Write(data)
+			default:
+				break read
+			}
+		}
+
+		size := buf.Len()
+
+		if size != 0 {
+			next := in.dir + "/next"
+
+			p := soap.DefaultUpload
+			p.ContentLength = int64(size)
+			attr := new(types.GuestPosixFileAttributes)
+
+			if err := c.Upload(ctx, &buf, next, p, attr, true); err != nil {
+				return err
+			}
+
+			if err := c.FileManager.MoveFile(ctx, c.Authentication, next, fmt.Sprintf("%s/%d", in.dir, in.n), false); err != nil {
+				return err
+			}
+
+			in.n++
+		}
+
+		if in.eof {
+			if in.err != nil {
+				return in.err
+			}
+			return c.FileManager.MakeDirectory(ctx, c.Authentication, in.dir+"/eof", false)
+		}
+
+		if size < maxInputChunk {
+			return nil // no more data available until the next interval
+		}
+	}
+
+	return nil
+}
+
 // Run implements exec.Cmd.Run over vmx guest RPC against standard vmware-tools or toolbox.
 func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
-	if cmd.Stdin != nil {
+	return c.run(ctx, cmd, time.Second/2, false)
+}
+
+// Stream is like Run, but copies process output to cmd.Stdout and cmd.Stderr while the process is running,
+// polling the guest temp files for growth at the given interval.
+// Stdin, if any, is also forwarded while the process is running: data read from stdin is uploaded at each interval
+// and piped to the process by a guest shell loop. With Windows guests, stdin is read until EOF and uploaded
+// before the process is started.
+// If ctx is canceled or its deadline is exceeded, the guest process is terminated.
+func (c *Client) Stream(ctx context.Context, cmd *exec.Cmd, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+	return c.run(ctx, cmd, interval, true)
+}
+
+func (c *Client) run(ctx context.Context, cmd *exec.Cmd, interval time.Duration, stream bool) error {
+	var in *input
+
+	if cmd.Stdin != nil && stream && c.GuestFamily != types.VirtualMachineGuestOsFamilyWindowsGuest {
+		dir, err := c.FileManager.CreateTemporaryDirectory(ctx, c.Authentication, "govmomi-", "", "")
+		if err != nil {
+			return err
+		}
+
+		defer c.rmdir(dir)
+
+		in = newInput(cmd.Stdin, dir)
+		defer close(in.done)
+	} else if cmd.Stdin != nil {
 		dst, err := c.mktemp(ctx)
 		if err != nil {
 			return err
 		}
 
-		defer c.rm(ctx, dst)
+		defer c.rm(dst)
 
 		var buf bytes.Buffer
 		size, err := io.Copy(&buf, cmd.Stdin)
@@ -121,16 +289,12 @@ func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
 		cmd.Args = append(cmd.Args, "<", dst)
 	}
 
-	output := []struct {
-		io.Writer
-		fd   string
-		path string
-	}{
-		{cmd.Stdout, "1", ""},
-		{cmd.Stderr, "2", ""},
+	output := []*output{
+		{Writer: cmd.Stdout, fd: "1"},
+		{Writer: cmd.Stderr, fd: "2"},
 	}
 
-	for i, out := range output {
+	for _, out := range output {
 		if out.Writer == nil {
 			continue
 		}
@@ -140,10 +304,10 @@ func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
 			return err
 		}
 
-		defer c.rm(ctx, dst)
+		defer c.rm(dst)
 
 		cmd.Args = append(cmd.Args, out.fd+">", dst)
-		output[i].path = dst
+		out.path = dst
 	}
 
 	path := cmd.Path
@@ -164,6 +328,13 @@ func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
 		}
 	}
 
+	if in != nil {
+		// Pipe the input loop to the program, whose exit code is that of the pipeline
+		secs := strconv.FormatFloat(interval.Seconds(), 'f', -1, 64)
+		args = append([]string{"-c", "'" + inputScript + "'", "stdin", in.dir, secs, "|", path}, args...)
+		path = "/bin/sh"
+	}
+
 	spec := types.GuestProgramSpec{
 		ProgramPath:      path,
 		Arguments:        strings.Join(args, " "),
@@ -176,46 +347,140 @@ func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
 		return err
 	}
 
-	rc := 0
+	rc, err := c.wait(ctx, pid, interval, func() error {
+		if in != nil {
+			if err := c.send(ctx, in); err != nil {
+				return err
+			}
+		}
+		if stream {
+			return c.tail(ctx, output)
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			c.kill(pid)
+		}
+		return err
+	}
+
+	if err = c.tail(ctx, output); err != nil {
+		return err
+	}
+
+	if rc != 0 {
+		return &exitError{fmt.Errorf("%s: exit %d", cmd.Path, rc), rc}
+	}
+
+	return nil
+}
+
+// wait polls the guest process until it exits, calling poll at each interval while the process is running
+func (c *Client) wait(ctx context.Context, pid int64, interval time.Duration, poll func() error) (int, error) {
 	for {
 		procs, err := c.ProcessManager.ListProcesses(ctx, c.Authentication, []int64{pid})
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		p := procs[0]
-		if p.EndTime == nil {
-			<-time.After(time.Second / 2)
-			continue
+		if p.EndTime != nil {
+			return int(p.ExitCode), nil
 		}
 
-		rc = int(p.ExitCode)
+		if err = poll(); err != nil {
+			return 0, err
+		}
 
-		break
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
 	}
+}
+
+// kill terminates the guest process, using a new context as the caller's context is done
+func (c *Client) kill(pid int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := c.ProcessManager.TerminateProcess(ctx, c.Authentication, pid)
+	if err != nil {
+		log.Printf("kill %d: %s", pid, err)
+	}
+}
 
+// tail copies data appended to the output files since the last call to their Writer
+func (c *Client) tail(ctx context.Context, output []*output) error {
 	for _, out := range output {
 		if out.Writer == nil {
 			continue
 		}
 
-		f, _, err := c.Download(ctx, out.path)
+		n, err := c.readFrom(ctx, out.path, out.offset, out.Writer)
+		out.offset += n
 		if err != nil {
 			return err
 		}
+	}
 
-		_, err = io.Copy(out.Writer, f)
-		_ = f.Close()
-		if err != nil {
-			return err
+	return nil
+}
+
+// readFrom copies the contents of the guest file starting at offset to w.
+// A Range request is used to skip the first offset bytes, falling back to discarding them
+// if the transfer URL does not support ranges.
+func (c *Client) readFrom(ctx context.Context, src string, offset int64, w io.Writer) (int64, error) {
+	info, err := c.FileManager.InitiateFileTransferFromGuest(ctx, c.Authentication, src)
+	if err != nil {
+		return 0, err
+	}
+
+	if info.Size <= offset {
+		return 0, nil
+	}
+
+	u, err := c.FileManager.TransferURL(ctx, info.Url)
+	if err != nil {
+		return 0, err
+	}
+
+	vc := c.ProcessManager.Client()
+	if internal.UsingEnvoySidecar(vc) {
+		vc = internal.ClientWithEnvoyHostGateway(vc)
+	}
+
+	p := soap.DefaultDownload
+	p.Close = true // disable Keep-Alive connection to ESX
+	if offset != 0 {
+		p.Headers = map[string]string{"Range": fmt.Sprintf("bytes=%d-", offset)}
+	}
+
+	res, err := vc.DownloadRequest(ctx, u, &p)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		if _, err = io.CopyN(io.Discard, res.Body, offset); err != nil {
+			return 0, err
 		}
+	case http.StatusPartialContent:
+	default:
+		return 0, fmt.Errorf("download(%s): %s", u, res.Status)
 	}
 
-	if rc != 0 {
-		return &exitError{fmt.Errorf("%s: exit %d", cmd.Path, rc), rc}
+	// Limit to the size reported by InitiateFileTransferFromGuest, the file may have grown since
+	n, err := io.CopyN(w, res.Body, info.Size-offset)
+	if err == io.EOF {
+		err = nil
 	}
 
-	return nil
+	return n, err
 }
 
 // archiveReader wraps an io.ReadCloser to support streaming download
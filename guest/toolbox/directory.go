@@ -0,0 +1,255 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// archivePath returns the path used to transfer the given guest directory as a gzip'd tar,
+// via the hgfs archive scheme implemented by the Go toolbox.
+func archivePath(dir string) string {
+	u := url.URL{Path: dir, RawQuery: "format=tgz"}
+	return "/archive:" + u.String()
+}
+
+// archiveUnsupported returns true if the given error is a fault from InitiateFileTransfer{To,From}Guest.
+// Guest tools other than the Go toolbox do not support the archive scheme and fail such requests.
+func archiveUnsupported(err error) bool {
+	return soap.IsSoapFault(err) || soap.IsVimFault(err)
+}
+
+// UploadDirectory recursively copies the local src directory to the guest dst directory.
+// The directory is transferred as a single gzip'd tar if guest tools supports the archive scheme,
+// otherwise guest.FileManager.UploadDirectory is used to transfer one file at a time.
+// The per-file transfer is also used if opts.Resume is set, or if dst exists and opts.Overwrite is not set.
+func (c *Client) UploadDirectory(ctx context.Context, src, dst string, opts *guest.TransferOptions) error {
+	if opts == nil {
+		opts = new(guest.TransferOptions)
+	}
+
+	if !opts.Resume {
+		_, err := c.FileManager.ListFiles(ctx, c.Authentication, dst, 0, 1, "")
+		switch {
+		case err == nil && !opts.Overwrite:
+			// the archive would replace existing files, the per-file transfer reports them
+		case err == nil || fault.Is(err, &types.FileNotFound{}):
+			err = c.uploadArchive(ctx, src, dst)
+			if !archiveUnsupported(err) {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: %w", dst, err)
+		}
+	}
+
+	return c.FileManager.UploadDirectory(ctx, c.Authentication, src, dst, opts)
+}
+
+// uploadArchive writes the src directory to a local gzip'd tar file and uploads it to the guest dst directory.
+// Content-Length is required by the upload, so the archive is not streamed.
+func (c *Client) uploadArchive(ctx context.Context, src, dst string) error {
+	err := c.FileManager.MakeDirectory(ctx, c.Authentication, dst, true)
+	if err != nil && !fault.Is(err, &types.FileAlreadyExists{}) {
+		return err
+	}
+
+	f, err := os.CreateTemp("", "govmomi-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err = writeArchive(f, src); err != nil {
+		return err
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return c.Upload(ctx, f, archivePath(dst), soap.DefaultUpload, new(types.GuestFileAttributes), true)
+}
+
+// writeArchive writes the contents of the src directory to w as a gzip'd tar.
+// Symlinks and other special files are skipped.
+func writeArchive(w io.Writer, src string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.WalkDir(src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, name)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			header.Name += "/"
+		}
+
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(filepath.Clean(name))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// DownloadDirectory recursively copies the guest src directory to the local dst directory.
+// The directory is transferred as a single gzip'd tar if guest tools supports the archive scheme,
+// otherwise guest.FileManager.DownloadDirectory is used to transfer one file at a time.
+// The per-file transfer is also used if opts.Resume is set.
+func (c *Client) DownloadDirectory(ctx context.Context, src, dst string, opts *guest.TransferOptions) error {
+	if opts == nil {
+		opts = new(guest.TransferOptions)
+	}
+
+	if !opts.Resume {
+		// errors reading the directory are not propagated by the archive stream
+		if _, err := c.FileManager.ListFiles(ctx, c.Authentication, src, 0, 1, ""); err != nil {
+			return fmt.Errorf("%s: %w", src, err)
+		}
+
+		f, _, err := c.Download(ctx, archivePath(src))
+		if err == nil {
+			defer f.Close()
+			return readArchive(f, dst, opts.Overwrite)
+		}
+		if !archiveUnsupported(err) {
+			return err
+		}
+	}
+
+	return c.FileManager.DownloadDirectory(ctx, c.Authentication, src, dst, opts)
+}
+
+// readArchive writes the contents of the given gzip'd tar to the dst directory,
+// preserving file permissions and modification times. Symlinks and other special files are skipped.
+// Existing files are only replaced if overwrite is true.
+func readArchive(r io.Reader, dst string, overwrite bool) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(dst, 0750); err != nil {
+		return err
+	}
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if !overwrite {
+		flag |= os.O_EXCL
+	}
+
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		rel := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(rel) {
+			return fmt.Errorf("%s: invalid file name %q", dst, header.Name)
+		}
+
+		name := filepath.Join(dst, rel)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(name, 0750)
+		case tar.TypeReg:
+			err = readArchiveFile(tr, name, flag, header)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func readArchiveFile(r io.Reader, name string, flag int, header *tar.Header) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0750); err != nil {
+		return err
+	}
+
+	mode := header.FileInfo().Mode().Perm()
+
+	f, err := os.OpenFile(name, flag, mode)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = os.Chmod(name, mode); err != nil {
+		return err
+	}
+
+	return os.Chtimes(name, header.ModTime, header.ModTime)
+}
@@ -0,0 +1,147 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestDirectoryTransfer(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("GOOS=%s", runtime.GOOS) // toolbox ListFiles is only implemented for linux
+	}
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, simulator.Map(ctx).Any("VirtualMachine").Reference())
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: simulator.ToolboxBackingOptionKey, Value: "TRUE"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		tools, err := NewClient(ctx, c, vm, &types.NamePasswordAuthentication{Username: "user", Password: "pass"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		src := t.TempDir()
+		mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		files := []string{"a.txt", "c..txt", filepath.Join("sub", "b.txt")}
+		for _, name := range files {
+			path := filepath.Join(src, name)
+			if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(path, []byte(name), 0640); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		verify := func(dir string) {
+			t.Helper()
+			for _, name := range files {
+				path := filepath.Join(dir, name)
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != name {
+					t.Errorf("%s: data=%q", path, data)
+				}
+				info, _ := os.Stat(path)
+				if !info.ModTime().Equal(mtime) {
+					t.Errorf("%s: mtime=%s", path, info.ModTime())
+				}
+				if info.Mode().Perm() != 0640 {
+					t.Errorf("%s: mode=%s", path, info.Mode())
+				}
+			}
+		}
+
+		// the simulator toolbox runs on this host, so the guest directory is also local
+		guestDir := filepath.Join(t.TempDir(), "guest")
+
+		if err = tools.UploadDirectory(ctx, src, guestDir, nil); err != nil {
+			t.Fatal(err)
+		}
+		verify(guestDir)
+
+		// existing files are not overwritten by default
+		if err = tools.UploadDirectory(ctx, src, guestDir, nil); err == nil {
+			t.Error("expected error")
+		}
+
+		if err = tools.UploadDirectory(ctx, src, guestDir, &guest.TransferOptions{Overwrite: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		dst := t.TempDir()
+		if err = tools.DownloadDirectory(ctx, guestDir, dst, nil); err != nil {
+			t.Fatal(err)
+		}
+		verify(dst)
+
+		err = tools.DownloadDirectory(ctx, guestDir, dst, nil)
+		if !errors.Is(err, os.ErrExist) {
+			t.Errorf("err=%v", err)
+		}
+
+		if err = tools.DownloadDirectory(ctx, guestDir, dst, &guest.TransferOptions{Overwrite: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = tools.DownloadDirectory(ctx, filepath.Join(guestDir, "enoent"), t.TempDir(), nil); err == nil {
+			t.Error("expected error")
+		}
+
+		// Resume uses the per-file transfer
+		if err = tools.DownloadDirectory(ctx, guestDir, dst, &guest.TransferOptions{Resume: true}); err != nil {
+			t.Fatal(err)
+		}
+		verify(dst)
+	})
+}
+
+func TestReadArchive(t *testing.T) {
+	for _, name := range []string{"../x", "/x", "a/../../x"} {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0600}); err != nil {
+			t.Fatal(err)
+		}
+		_ = tw.Close()
+		_ = gz.Close()
+
+		if err := readArchive(&buf, t.TempDir(), false); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
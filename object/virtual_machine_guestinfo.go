@@ -0,0 +1,134 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// GuestInfoPrefix is the ExtraConfig key prefix of variables shared with the guest
+const GuestInfoPrefix = "guestinfo."
+
+// ErrGuestInfoNotFound is returned by VirtualMachine.GuestInfo when the variable is not set
+var ErrGuestInfoNotFound = errors.New("guestinfo variable not found")
+
+// GuestInfoKey returns the given key with the GuestInfoPrefix, if not already prefixed.
+func GuestInfoKey(key string) string {
+	if strings.HasPrefix(strings.ToLower(key), GuestInfoPrefix) {
+		return key
+	}
+	return GuestInfoPrefix + key
+}
+
+func guestInfoValue(options []types.BaseOptionValue, key string) (string, bool) {
+	for _, o := range options {
+		opt := o.GetOptionValue()
+		if strings.EqualFold(opt.Key, key) {
+			s, ok := opt.Value.(string)
+			return s, ok
+		}
+	}
+	return "", false
+}
+
+// GuestInfo returns the value of the given guestinfo variable from the VirtualMachine's config.extraConfig property.
+// The GuestInfoPrefix is added to key if needed.  Note that vCenter does not include variables set by the guest
+// via the info-set RPC in config.extraConfig, vcsim does.  See toolbox.GuestVariables for the guest side of this keyspace.
+func (v VirtualMachine) GuestInfo(ctx context.Context, key string) (string, error) {
+	var o mo.VirtualMachine
+
+	err := v.Properties(ctx, v.Reference(), []string{"config.extraConfig"}, &o)
+	if err != nil {
+		return "", err
+	}
+
+	if o.Config != nil {
+		if val, ok := guestInfoValue(o.Config.ExtraConfig, GuestInfoKey(key)); ok {
+			return val, nil
+		}
+	}
+
+	return "", ErrGuestInfoNotFound
+}
+
+// SetGuestInfo reconfigures the VirtualMachine ExtraConfig with the given guestinfo variable.
+// The GuestInfoPrefix is added to key if needed.  An empty value removes the variable.
+func (v VirtualMachine) SetGuestInfo(ctx context.Context, key, val string) error {
+	spec := types.VirtualMachineConfigSpec{
+		ExtraConfig: []types.BaseOptionValue{
+			&types.OptionValue{Key: GuestInfoKey(key), Value: val},
+		},
+	}
+
+	task, err := v.Reconfigure(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	return task.Wait(ctx)
+}
+
+// GuestInfoJSON decodes the JSON value of the given guestinfo variable into val.
+func (v VirtualMachine) GuestInfoJSON(ctx context.Context, key string, val any) error {
+	s, err := v.GuestInfo(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(s), val)
+}
+
+// SetGuestInfoJSON sets the given guestinfo variable to the JSON encoding of val.
+func (v VirtualMachine) SetGuestInfoJSON(ctx context.Context, key string, val any) error {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	return v.SetGuestInfo(ctx, key, string(b))
+}
+
+// WaitForGuestInfo waits for the given guestinfo variable to be set in the VirtualMachine's config.extraConfig property
+// to a non-empty value other than prev, such that a value read before sending a request with SetGuestInfo is not
+// mistaken for the reply.  As with GuestInfo, replies set by the guest via the info-set RPC are only visible with vcsim.
+func (v VirtualMachine) WaitForGuestInfo(ctx context.Context, key, prev string) (string, error) {
+	key = GuestInfoKey(key)
+
+	var val string
+
+	p := property.DefaultCollector(v.c)
+	err := property.Wait(ctx, p, v.Reference(), []string{"config.extraConfig"}, func(pc []types.PropertyChange) bool {
+		for _, c := range pc {
+			if c.Op != types.PropertyChangeOpAssign {
+				continue
+			}
+
+			options, ok := c.Val.(types.ArrayOfOptionValue)
+			if !ok {
+				continue
+			}
+
+			if s, ok := guestInfoValue(options.OptionValue, key); ok && s != "" && s != prev {
+				val = s
+				return true
+			}
+		}
+
+		return false
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return val, nil
+}
@@ -7,6 +7,7 @@ package ovf
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	"github.com/vmware/govmomi/vim25/xml"
 )
@@ -72,12 +73,17 @@ func (e Env) MarshalManual() string {
 	var buffer bytes.Buffer
 
 	buffer.WriteString(xml.Header)
-	buffer.WriteString(fmt.Sprintf(ovfEnvHeader, e.EsxID))
-	buffer.WriteString(fmt.Sprintf(ovfEnvPlatformSection, e.Platform.Kind, e.Platform.Version, e.Platform.Vendor, e.Platform.Locale))
+	buffer.WriteString(fmt.Sprintf(ovfEnvHeader, escapeEnv(e.EsxID)))
+	if p := e.Platform; p != nil {
+		buffer.WriteString(fmt.Sprintf(ovfEnvPlatformSection,
+			escapeEnv(p.Kind), escapeEnv(p.Version), escapeEnv(p.Vendor), escapeEnv(p.Locale)))
+	}
 
 	buffer.WriteString(fmt.Sprint(ovfEnvPropertyHeader))
-	for _, p := range e.Property.Properties {
-		buffer.WriteString(fmt.Sprintf(ovfEnvPropertyEntry, p.Key, p.Value))
+	if e.Property != nil {
+		for _, p := range e.Property.Properties {
+			buffer.WriteString(fmt.Sprintf(ovfEnvPropertyEntry, escapeEnv(p.Key), escapeEnv(p.Value)))
+		}
 	}
 	buffer.WriteString(fmt.Sprint(ovfEnvPropertyFooter))
 
@@ -85,3 +91,34 @@ func (e Env) MarshalManual() string {
 
 	return buffer.String()
 }
+
+func escapeEnv(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// UnmarshalEnv parses an OVF environment document, as generated by Marshal or MarshalManual.
+func UnmarshalEnv(r io.Reader) (*Env, error) {
+	var e Env
+
+	dec := xml.NewDecoder(r)
+	if err := dec.Decode(&e); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// Properties returns the environment's PropertySection as a map of key to value.
+func (e Env) Properties() map[string]string {
+	props := make(map[string]string)
+
+	if e.Property != nil {
+		for _, p := range e.Property.Properties {
+			props[p.Key] = p.Value
+		}
+	}
+
+	return props
+}
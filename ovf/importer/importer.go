@@ -31,6 +31,7 @@ type Importer struct {
 	Name           string
 	VerifyManifest bool
 	Hidden         bool
+	Retries        int // Number of times ImportStream resumes an interrupted remote download
 
 	Client *vim25.Client
 	Finder *find.Finder
@@ -44,6 +45,10 @@ type Importer struct {
 
 	Archive  Archive
 	Manifest map[string]*library.Checksum
+
+	// Descriptor, if set, is called by ImportStream with the OVF envelope read from the archive,
+	// such that Options can be derived from it without opening the archive again.
+	Descriptor func(e *ovf.Envelope, opts *Options) error
 }
 
 func (imp *Importer) manifestPath(fpath string) string {
@@ -69,9 +74,24 @@ func (imp *Importer) ImportVApp(ctx context.Context, fpath string, opts Options)
 		return nil, nil, err
 	}
 
+	spec, err := imp.createImportSpec(ctx, o, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if imp.VerifyManifest {
+		if err := imp.ReadManifest(fpath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return imp.importVApp(ctx, spec)
+}
+
+func (imp *Importer) createImportSpec(ctx context.Context, o []byte, opts Options) (*types.OvfCreateImportSpecResult, error) {
 	e, err := ReadEnvelope(o)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse ovf: %s", err)
+		return nil, fmt.Errorf("failed to parse ovf: %s", err)
 	}
 
 	if e.VirtualSystem != nil {
@@ -98,7 +118,7 @@ func (imp *Importer) ImportVApp(ctx context.Context, fpath string, opts Options)
 
 	nmap, err := imp.NetworkMap(ctx, e, opts.NetworkMapping)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	cisp := types.OvfCreateImportSpecParams{
@@ -116,10 +136,10 @@ func (imp *Importer) ImportVApp(ctx context.Context, fpath string, opts Options)
 	m := ovf.NewManager(imp.Client)
 	spec, err := m.CreateImportSpec(ctx, string(o), imp.ResourcePool, imp.Datastore, &cisp)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if spec.Error != nil {
-		return nil, nil, &task.Error{LocalizedMethodFault: &spec.Error[0]}
+		return nil, &task.Error{LocalizedMethodFault: &spec.Error[0]}
 	}
 	if spec.Warning != nil {
 		for _, w := range spec.Warning {
@@ -136,12 +156,10 @@ func (imp *Importer) ImportVApp(ctx context.Context, fpath string, opts Options)
 		}
 	}
 
-	if imp.VerifyManifest {
-		if err := imp.ReadManifest(fpath); err != nil {
-			return nil, nil, err
-		}
-	}
+	return spec, nil
+}
 
+func (imp *Importer) importVApp(ctx context.Context, spec *types.OvfCreateImportSpecResult) (*nfc.LeaseInfo, *nfc.Lease, error) {
 	lease, err := imp.ResourcePool.ImportVApp(ctx, spec.ImportSpec, imp.Folder, imp.Host)
 	if err != nil {
 		return nil, nil, err
@@ -153,6 +171,12 @@ func (imp *Importer) ImportVApp(ctx context.Context, fpath string, opts Options)
 		return nil, nil, err
 	}
 
+	if len(spec.FileItem) == 0 {
+		// Nothing to upload, such as an OVF with empty disks only.
+		// Lease.Wait treats an empty list of items as an export.
+		info.Items = nil
+	}
+
 	return info, lease, nil
 }
 
@@ -307,23 +331,29 @@ func (imp *Importer) Upload(ctx context.Context, lease *nfc.Lease, item nfc.File
 	}
 
 	if imp.VerifyManifest {
-		mapImportKeyToKey := func(urls []types.HttpNfcLeaseDeviceUrl, importKey string) string {
-			for _, url := range urls {
-				if url.ImportKey == importKey {
-					return url.Key
-				}
+		return imp.verifyUpload(ctx, lease, item)
+	}
+	return nil
+}
+
+func (imp *Importer) verifyUpload(ctx context.Context, lease *nfc.Lease, item nfc.FileItem) error {
+	file := item.Path
+
+	mapImportKeyToKey := func(urls []types.HttpNfcLeaseDeviceUrl, importKey string) string {
+		for _, url := range urls {
+			if url.ImportKey == importKey {
+				return url.Key
 			}
-			return ""
-		}
-		leaseInfo, err := lease.Wait(ctx, nil)
-		if err != nil {
-			return err
-		}
-		sum, ok := imp.Manifest[file]
-		if !ok {
-			return fmt.Errorf("missing checksum for %v in manifest file", file)
 		}
-		return ValidateChecksum(ctx, lease, sum, file, mapImportKeyToKey(leaseInfo.DeviceUrl, item.DeviceId))
+		return ""
 	}
-	return nil
+	leaseInfo, err := lease.Wait(ctx, nil)
+	if err != nil {
+		return err
+	}
+	sum, ok := imp.Manifest[file]
+	if !ok {
+		return fmt.Errorf("missing checksum for %v in manifest file", file)
+	}
+	return ValidateChecksum(ctx, lease, sum, file, mapImportKeyToKey(leaseInfo.DeviceUrl, item.DeviceId))
 }
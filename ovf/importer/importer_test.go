@@ -5,8 +5,20 @@
 package importer
 
 import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 func TestImporter_manifestPath(t *testing.T) {
@@ -48,3 +60,85 @@ func TestImporter_manifestPath(t *testing.T) {
 		}
 	}
 }
+
+func TestImportFromVirtualMachine(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		dc, err := finder.DefaultDatacenter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.SetDatacenter(dc)
+
+		ds, err := finder.DefaultDatastore(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pool, err := finder.ResourcePool(ctx, "DC0_H0/Resources")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		folders, err := dc.Folders(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var props mo.VirtualMachine
+		if err = vm.Properties(ctx, vm.Reference(), []string{"config"}, &props); err != nil {
+			t.Fatal(err)
+		}
+
+		// The generated descriptor has a CD-ROM on the SCSI controller and
+		// an empty disk without a file reference, so there is nothing to upload.
+		e, err := ovf.FromVirtualMachine(props)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		desc, err := xml.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fpath := filepath.Join(t.TempDir(), "vm.ovf")
+		if err = os.WriteFile(fpath, desc, 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		imp := Importer{
+			Client:       c,
+			Finder:       finder,
+			Datacenter:   dc,
+			Datastore:    ds,
+			ResourcePool: pool,
+			Folder:       folders.VmFolder,
+			Archive:      &FileArchive{Path: fpath},
+		}
+
+		name := "vm-copy"
+		ref, err := imp.Import(ctx, fpath, Options{Name: &name})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		devices, err := object.NewVirtualMachine(c, *ref).Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n := len(devices.SelectByType((*types.VirtualCdrom)(nil))); n != 1 {
+			t.Errorf("cdroms=%d", n)
+		}
+		if n := len(devices.SelectByType((*types.VirtualDisk)(nil))); n != 1 {
+			t.Errorf("disks=%d", n)
+		}
+	})
+}
@@ -57,6 +57,19 @@ func SpecMap(e *ovf.Envelope, hidden, verbose bool) (res []Property) {
 	return
 }
 
+// NetworkMapping returns a mapping for each network of the given envelope, with an empty Network target.
+func NetworkMapping(e *ovf.Envelope) []Network {
+	var res []Network
+
+	if e.Network != nil {
+		for _, net := range e.Network.Networks {
+			res = append(res, Network{net.Name, ""})
+		}
+	}
+
+	return res
+}
+
 func Spec(fpath string, a Archive, hidden, verbose bool) (*Options, error) {
 	e := &ovf.Envelope{}
 	if fpath != "" {
@@ -105,11 +118,7 @@ func Spec(fpath string, a Archive, hidden, verbose bool) (*Options, error) {
 		o.Annotation = e.VirtualSystem.Annotation.Annotation
 	}
 
-	if e.Network != nil {
-		for _, net := range e.Network.Networks {
-			o.NetworkMapping = append(o.NetworkMapping, Network{net.Name, ""})
-		}
-	}
+	o.NetworkMapping = NetworkMapping(e)
 
 	if verbose {
 		if deploymentOptions != nil {
@@ -0,0 +1,275 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package importer
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/vmware/govmomi/nfc"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/progress"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// ImportStream imports the OVA at fpath, reading the archive sequentially in a single pass.
+// Unlike Import with a TapeArchive, the archive is opened only once, which avoids
+// downloading a remote OVA multiple times or staging it on local disk.
+// The OVF descriptor must be the first entry of the archive and the manifest, if any,
+// must directly follow it, as required by the OVF specification.
+// Each file referenced by the import lease is uploaded as it is encountered in the archive.
+// When fpath is a remote URL, an interrupted download is resumed using an HTTP range
+// request, up to imp.Retries times.
+func (imp *Importer) ImportStream(ctx context.Context, fpath string, opts Options) (*types.ManagedObjectReference, error) {
+	o := Opener{imp.Client}
+
+	var (
+		f   io.ReadCloser
+		err error
+	)
+
+	if IsRemotePath(fpath) {
+		f, _, err = o.OpenRemoteStream(ctx, fpath, imp.Retries)
+	} else {
+		f, _, err = o.OpenLocal(fpath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := tar.NewReader(f)
+
+	h, err := r.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", fpath, err)
+	}
+	if path.Ext(h.Name) != ".ovf" {
+		return nil, fmt.Errorf("first entry of %s must be an OVF descriptor, found %q", fpath, h.Name)
+	}
+
+	desc, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if imp.Descriptor != nil {
+		e, err := ReadEnvelope(desc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ovf: %s", err)
+		}
+		if err = imp.Descriptor(e, &opts); err != nil {
+			return nil, err
+		}
+	}
+
+	spec, err := imp.createImportSpec(ctx, desc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	imp.Manifest = nil
+
+	h, err = r.Next()
+	if err == nil && path.Ext(h.Name) == ".mf" {
+		imp.Manifest, err = library.ReadManifest(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest %q: %s", h.Name, err)
+		}
+		h, err = r.Next()
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if imp.VerifyManifest && imp.Manifest == nil {
+		return nil, fmt.Errorf("manifest (.mf) must follow the OVF descriptor in %s", fpath)
+	}
+
+	info, lease, err := imp.importVApp(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	u := lease.StartUpdater(ctx, info)
+	defer u.Done()
+
+	items := make(map[string]nfc.FileItem, len(info.Items))
+	for _, item := range info.Items {
+		items[path.Base(item.Path)] = item
+	}
+
+	abort := func(file string, err error) (*types.ManagedObjectReference, error) {
+		_ = lease.Abort(ctx, &types.LocalizedMethodFault{
+			Fault: &types.FileFault{
+				File: file,
+			},
+		})
+		return nil, err
+	}
+
+	for ; err == nil; h, err = r.Next() {
+		name := path.Base(h.Name)
+		item, ok := items[name]
+		if !ok {
+			continue // not part of this import, such as a disk excluded by the deployment option
+		}
+		delete(items, name)
+
+		if err := imp.uploadStream(ctx, lease, item, r, h.Size); err != nil {
+			return abort(item.Path, err)
+		}
+	}
+
+	if err != io.EOF {
+		return abort(fpath, err)
+	}
+
+	for _, item := range items {
+		return abort(item.Path, fmt.Errorf("file %q not found in %s", item.Path, fpath))
+	}
+
+	return &info.Entity, lease.Complete(ctx)
+}
+
+func (imp *Importer) uploadStream(ctx context.Context, lease *nfc.Lease, item nfc.FileItem, r io.Reader, size int64) error {
+	var sink progress.Sinker = item // feeds the lease updater
+
+	if imp.Log != nil {
+		logger := progress.NewProgressLogger(imp.Log, fmt.Sprintf("Uploading %s... ", path.Base(item.Path)))
+		defer logger.Wait()
+		sink = progress.Tee(sink, logger)
+	}
+
+	if imp.Sinker != nil {
+		sink = progress.Tee(sink, imp.Sinker)
+	}
+
+	opts := soap.Upload{
+		ContentLength: size,
+		Progress:      sink,
+	}
+
+	if err := lease.Upload(ctx, item, r, opts); err != nil {
+		return err
+	}
+
+	if imp.VerifyManifest {
+		return imp.verifyUpload(ctx, lease, item)
+	}
+
+	return nil
+}
+
+// OpenRemoteStream is like OpenRemote, but the returned reader resumes the download
+// using an HTTP range request from the current offset when a read fails,
+// up to the given number of retries.
+func (o Opener) OpenRemoteStream(ctx context.Context, link string, retries int) (io.ReadCloser, int64, error) {
+	if o.Client == nil {
+		return nil, 0, errors.New("remote path not supported")
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r := &rangeReader{
+		ctx:     ctx,
+		c:       o.Client,
+		u:       u,
+		retries: retries,
+	}
+
+	if err := r.open(); err != nil {
+		return nil, 0, err
+	}
+
+	return r, r.size, nil
+}
+
+// rangeReader reads a remote file, reopening it at the current offset on failure.
+type rangeReader struct {
+	ctx context.Context
+	c   *vim25.Client
+	u   *url.URL
+
+	rc      io.ReadCloser
+	pos     int64
+	size    int64
+	retries int
+}
+
+func (r *rangeReader) open() error {
+	param := soap.DefaultDownload
+	if r.pos != 0 {
+		param.Headers = map[string]string{
+			"Range": fmt.Sprintf("bytes=%d-", r.pos),
+		}
+	}
+
+	res, err := r.c.DownloadRequest(r.ctx, r.u, &param)
+	if err != nil {
+		return err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		if r.pos != 0 {
+			_ = res.Body.Close()
+			return fmt.Errorf("download(%s): server does not support range requests", r.u)
+		}
+		r.size = res.ContentLength
+	case http.StatusPartialContent:
+		var start int64
+		_, err = fmt.Sscanf(res.Header.Get("Content-Range"), "bytes %d-", &start)
+		if err != nil || start != r.pos {
+			_ = res.Body.Close()
+			return fmt.Errorf("download(%s): invalid Content-Range %q for offset %d",
+				r.u, res.Header.Get("Content-Range"), r.pos)
+		}
+	default:
+		_ = res.Body.Close()
+		return fmt.Errorf("download(%s): %s", r.u, res.Status)
+	}
+
+	r.rc = res.Body
+
+	return nil
+}
+
+func (r *rangeReader) Read(b []byte) (int, error) {
+	for {
+		n, err := r.rc.Read(b)
+		r.pos += int64(n)
+
+		if err == nil || err == io.EOF || r.retries == 0 || r.ctx.Err() != nil {
+			return n, err
+		}
+
+		r.retries--
+		_ = r.rc.Close()
+
+		if oerr := r.open(); oerr != nil {
+			return n, fmt.Errorf("%s (resume at offset %d: %s)", err, r.pos, oerr)
+		}
+
+		if n != 0 {
+			return n, nil
+		}
+	}
+}
+
+func (r *rangeReader) Close() error {
+	return r.rc.Close()
+}
@@ -0,0 +1,206 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+)
+
+const testOVA = "../../vapi/library/testdata/ttylinux-pc_i486-16.1.ova"
+
+// flakyServer serves data, dropping the connection of the first request half way through.
+func flakyServer(t *testing.T, data []byte) (*httptest.Server, *int32) {
+	var requests int32
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			_, _ = w.Write(data[:len(data)/2])
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_ = conn.Close()
+			return
+		}
+		http.ServeContent(w, r, "test.ova", time.Now(), bytes.NewReader(data))
+	}))
+
+	return s, &requests
+}
+
+func TestOpenRemoteStream(t *testing.T) {
+	data := bytes.Repeat([]byte("govmomi"), 64*1024)
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		for _, retries := range []int{0, 1} {
+			s, requests := flakyServer(t, data)
+
+			o := Opener{c}
+			f, size, err := o.OpenRemoteStream(ctx, s.URL+"/test.ova", retries)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != int64(len(data)) {
+				t.Errorf("size=%d", size)
+			}
+
+			b, err := io.ReadAll(f)
+			_ = f.Close()
+			s.Close()
+
+			if retries == 0 {
+				if err == nil {
+					t.Error("expected error")
+				}
+				continue
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(b, data) {
+				t.Errorf("resumed data mismatch (%d bytes)", len(b))
+			}
+			if n := atomic.LoadInt32(requests); n != 2 {
+				t.Errorf("requests=%d", n)
+			}
+		}
+	})
+}
+
+func TestOpenRemoteStreamInvalidRange(t *testing.T) {
+	data := bytes.Repeat([]byte("govmomi"), 64*1024)
+	var requests int32
+
+	// server drops the first connection and ignores the requested range offset when resuming
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if atomic.AddInt32(&requests, 1) == 1 {
+			_, _ = w.Write(data[:len(data)/2])
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(data)-1, len(data)))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(data)
+	}))
+	defer s.Close()
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		o := Opener{c}
+		f, _, err := o.OpenRemoteStream(ctx, s.URL+"/test.ova", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		_, err = io.ReadAll(f)
+		if err == nil || !strings.Contains(err.Error(), "invalid Content-Range") {
+			t.Errorf("expected Content-Range error, got %v", err)
+		}
+	})
+}
+
+func TestImportStream(t *testing.T) {
+	data, err := os.ReadFile(testOVA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		dc, err := finder.DefaultDatacenter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.SetDatacenter(dc)
+
+		ds, err := finder.DefaultDatastore(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pool, err := finder.DefaultResourcePool(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		folders, err := dc.Folders(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, requests := flakyServer(t, data)
+		defer s.Close()
+
+		tests := []struct {
+			name string
+			path string
+		}{
+			{"local", testOVA},
+			{"remote", s.URL + "/ttylinux.ova"},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var networks []Network
+
+				imp := Importer{
+					Client:       c,
+					Finder:       finder,
+					Datacenter:   dc,
+					Datastore:    ds,
+					ResourcePool: pool,
+					Folder:       folders.VmFolder,
+					Retries:      1,
+					Descriptor: func(e *ovf.Envelope, opts *Options) error {
+						networks = NetworkMapping(e)
+						return nil
+					},
+				}
+
+				name := "stream-" + test.name
+				ref, err := imp.ImportStream(ctx, test.path, Options{Name: &name})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(networks) != 1 || networks[0].Name != "nat" {
+					t.Errorf("networks=%#v", networks)
+				}
+
+				vm := object.NewVirtualMachine(c, *ref)
+				if vm.Reference().Type != "VirtualMachine" {
+					t.Errorf("unexpected entity: %s", vm.Reference())
+				}
+			})
+		}
+
+		if n := atomic.LoadInt32(requests); n != 2 {
+			t.Errorf("requests=%d", n)
+		}
+	}, simulator.ESX())
+}
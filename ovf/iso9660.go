@@ -0,0 +1,215 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Minimal ISO 9660 support, enough to write and read the single file volume used by the
+// OVF environment ISO transport. See ECMA-119 for the on-disk format.
+
+const (
+	isoSectorSize  = 2048
+	isoPVDSector   = 16  // Primary Volume Descriptor, after the 32KiB system area
+	isoPadSectors  = 150 // trailing padding, as with mkisofs -pad, avoids read-ahead errors on some drivers
+	isoRootRecord  = 156
+	isoMaxDirSize  = 16 * isoSectorSize // bound on the root directory size read from the volume
+	isoDirectory   = 0x02
+	isoVolumeID    = "OVF ENV"
+	isoEnvFileName = "OVF-ENV.XML;1"
+)
+
+var isoIdentifier = []byte("CD001")
+
+// isoBoth32 encodes v in both-endian format, as used by most numeric ISO 9660 fields.
+func isoBoth32(b []byte, v uint32) {
+	binary.LittleEndian.PutUint32(b[0:4], v)
+	binary.BigEndian.PutUint32(b[4:8], v)
+}
+
+func isoBoth16(b []byte, v uint16) {
+	binary.LittleEndian.PutUint16(b[0:2], v)
+	binary.BigEndian.PutUint16(b[2:4], v)
+}
+
+func isoPad(b []byte, s string) {
+	n := copy(b, s)
+	for i := n; i < len(b); i++ {
+		b[i] = ' '
+	}
+}
+
+func isoRecordTime(t time.Time) []byte {
+	return []byte{
+		byte(t.Year() - 1900), byte(t.Month()), byte(t.Day()),
+		byte(t.Hour()), byte(t.Minute()), byte(t.Second()),
+		0, // GMT offset
+	}
+}
+
+func isoVolumeTime(t time.Time) []byte {
+	if t.IsZero() {
+		return append([]byte("0000000000000000"), 0)
+	}
+	return append([]byte(t.Format("20060102150405")+"00"), 0)
+}
+
+// isoRecord encodes a directory record
+func isoRecord(id []byte, sector, size uint32, flags byte, t time.Time) []byte {
+	n := 33 + len(id)
+	if n%2 != 0 {
+		n++ // pad to even length
+	}
+
+	r := make([]byte, n)
+	r[0] = byte(n)
+	isoBoth32(r[2:10], sector)
+	isoBoth32(r[10:18], size)
+	copy(r[18:25], isoRecordTime(t))
+	r[25] = flags
+	isoBoth16(r[28:32], 1)
+	r[32] = byte(len(id))
+	copy(r[33:], id)
+
+	return r
+}
+
+// writeISO writes an ISO 9660 volume containing a single file in the root directory.
+func writeISO(w io.Writer, volume, name string, data []byte, t time.Time) error {
+	const (
+		pathL = isoPVDSector + 2 // after the PVD and set terminator
+		pathM = pathL + 1
+		root  = pathM + 1
+		file  = root + 1
+	)
+
+	t = t.UTC()
+	sectors := uint32(file + (len(data)+isoSectorSize-1)/isoSectorSize + isoPadSectors)
+	iso := make([]byte, int(sectors)*isoSectorSize)
+
+	sector := func(n int) []byte {
+		return iso[n*isoSectorSize : (n+1)*isoSectorSize]
+	}
+
+	rootRecord := isoRecord([]byte{0}, root, isoSectorSize, isoDirectory, t)
+
+	pvd := sector(isoPVDSector)
+	pvd[0] = 1 // Primary Volume Descriptor
+	copy(pvd[1:6], isoIdentifier)
+	pvd[6] = 1
+	isoPad(pvd[8:40], "")
+	isoPad(pvd[40:72], volume)
+	isoBoth32(pvd[80:88], sectors)
+	isoBoth16(pvd[120:124], 1) // volume set size
+	isoBoth16(pvd[124:128], 1) // volume sequence number
+	isoBoth16(pvd[128:132], isoSectorSize)
+	isoBoth32(pvd[132:140], 10) // path table size
+	binary.LittleEndian.PutUint32(pvd[140:144], pathL)
+	binary.BigEndian.PutUint32(pvd[148:152], pathM)
+	copy(pvd[isoRootRecord:], rootRecord)
+	isoPad(pvd[190:813], "")
+	copy(pvd[813:830], isoVolumeTime(t))
+	copy(pvd[830:847], isoVolumeTime(t))
+	copy(pvd[847:864], isoVolumeTime(time.Time{}))
+	copy(pvd[864:881], isoVolumeTime(t))
+	pvd[881] = 1 // file structure version
+
+	term := sector(isoPVDSector + 1)
+	term[0] = 255 // Volume Descriptor Set Terminator
+	copy(term[1:6], isoIdentifier)
+	term[6] = 1
+
+	// Path tables with the root directory entry only
+	l := sector(pathL)
+	l[0] = 1
+	binary.LittleEndian.PutUint32(l[2:6], root)
+	binary.LittleEndian.PutUint16(l[6:8], 1)
+
+	m := sector(pathM)
+	m[0] = 1
+	binary.BigEndian.PutUint32(m[2:6], root)
+	binary.BigEndian.PutUint16(m[6:8], 1)
+
+	records := [][]byte{
+		rootRecord, // "."
+		isoRecord([]byte{1}, root, isoSectorSize, isoDirectory, t), // ".."
+		isoRecord([]byte(name), file, uint32(len(data)), 0, t),
+	}
+
+	dir := sector(root)
+	for _, r := range records {
+		dir = dir[copy(dir, r):]
+	}
+
+	copy(iso[file*isoSectorSize:], data)
+
+	_, err := w.Write(iso)
+	return err
+}
+
+// readISO returns a reader for the file with the given name in the root directory of an ISO 9660 volume.
+// The name is matched case-insensitively, ignoring any ";version" suffix.
+func readISO(r io.ReaderAt, name string) (*io.SectionReader, error) {
+	pvd := make([]byte, isoSectorSize)
+	if _, err := r.ReadAt(pvd, isoPVDSector*isoSectorSize); err != nil {
+		return nil, fmt.Errorf("reading volume descriptor: %s", err)
+	}
+
+	if pvd[0] != 1 || !bytes.Equal(pvd[1:6], isoIdentifier) {
+		return nil, errors.New("not an ISO 9660 volume")
+	}
+
+	root := pvd[isoRootRecord:]
+	sector := int64(binary.LittleEndian.Uint32(root[2:6]))
+	size := int64(binary.LittleEndian.Uint32(root[10:14]))
+	if size > isoMaxDirSize {
+		return nil, errors.New("invalid directory record")
+	}
+
+	dir := make([]byte, size)
+	if _, err := r.ReadAt(dir, sector*isoSectorSize); err != nil {
+		return nil, fmt.Errorf("reading root directory: %s", err)
+	}
+
+	for i := 0; i < len(dir); {
+		n := int(dir[i])
+		if n == 0 {
+			// records do not span sectors, skip to the next one
+			i = (i/isoSectorSize + 1) * isoSectorSize
+			continue
+		}
+		if i+n > len(dir) || n < 34 || 33+int(dir[i+32]) > n {
+			return nil, errors.New("invalid directory record")
+		}
+
+		record := dir[i : i+n]
+		i += n
+
+		if record[25]&isoDirectory != 0 {
+			continue
+		}
+
+		id := string(record[33 : 33+int(record[32])])
+		if k := strings.IndexByte(id, ';'); k >= 0 {
+			id = id[:k]
+		}
+		id = strings.TrimSuffix(id, ".")
+
+		if strings.EqualFold(id, name) {
+			offset := int64(binary.LittleEndian.Uint32(record[2:6])) * isoSectorSize
+			length := int64(binary.LittleEndian.Uint32(record[10:14]))
+			return io.NewSectionReader(r, offset, length), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s not found", name)
+}
@@ -0,0 +1,128 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// OVF environment transports, as listed in VmConfigInfo.OvfEnvironmentTransport
+const (
+	EnvTransportGuestInfo = "com.vmware.guestInfo"
+	EnvTransportISO       = "iso"
+)
+
+const (
+	// EnvGuestInfoKey is the guestinfo variable set by the com.vmware.guestInfo transport.
+	EnvGuestInfoKey = "guestinfo.ovfEnv"
+	// EnvFileName is the name of the file in the root directory of the iso transport's media.
+	EnvFileName = "ovf-env.xml"
+)
+
+// GuestInfo reads guestinfo variables from within a guest,
+// such as the rpcvmx.Config type in github.com/vmware/vmw-guestinfo.
+type GuestInfo interface {
+	String(key string, defaultValue string) (string, error)
+}
+
+// ReadEnvGuestInfo reads the OVF environment provided by the com.vmware.guestInfo transport.
+func ReadEnvGuestInfo(c GuestInfo) (*Env, error) {
+	val, err := c.String(EnvGuestInfoKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if val == "" {
+		return nil, errors.New(EnvGuestInfoKey + " is not set")
+	}
+
+	return UnmarshalEnv(strings.NewReader(val))
+}
+
+// UnmarshalEnvISO reads the OVF environment from the media provided by the iso transport,
+// such as the CD-ROM device (/dev/sr0 for example) or an ISO image file.
+func UnmarshalEnvISO(r io.ReaderAt) (*Env, error) {
+	f, err := readISO(r, EnvFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnmarshalEnv(f)
+}
+
+// MarshalISO encodes the environment as an ISO 9660 image containing the ovf-env.xml file,
+// as expected by guests using the iso transport.
+func (e Env) MarshalISO() ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := writeISO(&buf, isoVolumeID, isoEnvFileName, []byte(e.MarshalManual()), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// AttachEnvISO uploads the environment as an ISO image to the given datastore path
+// and inserts the image in the VM's first CD-ROM device, adding one if needed.
+func AttachEnvISO(ctx context.Context, vm *object.VirtualMachine, ds *object.Datastore, path string, e Env) error {
+	iso, err := e.MarshalISO()
+	if err != nil {
+		return err
+	}
+
+	p := soap.DefaultUpload
+	p.ContentLength = int64(len(iso))
+
+	if err = ds.Upload(ctx, bytes.NewReader(iso), path, &p); err != nil {
+		return err
+	}
+
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return err
+	}
+
+	cdrom, err := devices.FindCdrom("")
+	if err != nil {
+		ide, err := devices.FindIDEController("")
+		if err != nil {
+			return err
+		}
+
+		cdrom, err = devices.CreateCdrom(ide)
+		if err != nil {
+			return err
+		}
+
+		connect(cdrom)
+
+		return vm.AddDevice(ctx, devices.InsertIso(cdrom, ds.Path(path)))
+	}
+
+	connect(cdrom)
+
+	return vm.EditDevice(ctx, devices.InsertIso(cdrom, ds.Path(path)))
+}
+
+// connect sets the cdrom connected now, such that the ISO is visible to a powered on guest, and at power on.
+func connect(cdrom *types.VirtualCdrom) {
+	if cdrom.Connectable == nil {
+		cdrom.Connectable = new(types.VirtualDeviceConnectInfo)
+	}
+
+	cdrom.Connectable.Connected = true
+	cdrom.Connectable.StartConnected = true
+	cdrom.Connectable.AllowGuestControl = true
+}
@@ -0,0 +1,193 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/ovf"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func testEnv() ovf.Env {
+	return ovf.Env{
+		EsxID: "vm-42",
+		Platform: &ovf.PlatformSection{
+			Kind:    "VMware ESXi",
+			Version: "8.0.3",
+			Vendor:  "VMware, Inc.",
+			Locale:  "en",
+		},
+		Property: &ovf.PropertySection{
+			Properties: []ovf.EnvProperty{
+				{Key: "hostname", Value: "photon"},
+				{Key: "motd", Value: `"quoted" <value> & more`},
+			},
+		},
+	}
+}
+
+type guestInfo map[string]string
+
+func (g guestInfo) String(key string, defaultValue string) (string, error) {
+	if val, ok := g[key]; ok {
+		return val, nil
+	}
+	return defaultValue, nil
+}
+
+func TestReadEnvGuestInfo(t *testing.T) {
+	env := testEnv()
+
+	g := guestInfo{ovf.EnvGuestInfoKey: env.MarshalManual()}
+
+	e, err := ovf.ReadEnvGuestInfo(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, env.EsxID, e.EsxID)
+	assert.Equal(t, env.Platform, e.Platform)
+	assert.Equal(t, env.Property, e.Property)
+
+	_, err = ovf.ReadEnvGuestInfo(guestInfo{})
+	assert.Error(t, err)
+}
+
+func TestEnvISO(t *testing.T) {
+	env := testEnv()
+
+	iso, err := env.MarshalISO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(iso)%2048 != 0 {
+		t.Errorf("iso size=%d", len(iso))
+	}
+
+	e, err := ovf.UnmarshalEnvISO(bytes.NewReader(iso))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, env.Properties(), e.Properties())
+
+	_, err = ovf.UnmarshalEnvISO(bytes.NewReader(make([]byte, len(iso))))
+	assert.Error(t, err)
+
+	// root directory size is bounded rather than trusted
+	root := 16*2048 + 156
+	binary.LittleEndian.PutUint32(iso[root+10:], 0xffffffff)
+	_, err = ovf.UnmarshalEnvISO(bytes.NewReader(iso))
+	assert.ErrorContains(t, err, "invalid directory record")
+}
+
+func TestAttachEnvISO(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ds, err := finder.Datastore(ctx, "LocalDS_0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state, err := vm.PowerState(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, types.VirtualMachinePowerStatePoweredOn, state)
+
+		devices, err := vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cdrom, err := devices.FindCdrom("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = devices.Disconnect(cdrom); err != nil {
+			t.Fatal(err)
+		}
+		if err = vm.EditDevice(ctx, cdrom); err != nil {
+			t.Fatal(err)
+		}
+
+		name := path.Join(vm.Name(), "ovf-env.iso")
+
+		attach := func() *types.VirtualCdrom {
+			err := ovf.AttachEnvISO(ctx, vm, ds, name, testEnv())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			devices, err := vm.Device(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cdrom, err := devices.FindCdrom("")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// connected to the powered on VM
+			if assert.NotNil(t, cdrom.Connectable) {
+				assert.True(t, cdrom.Connectable.Connected)
+				assert.True(t, cdrom.Connectable.StartConnected)
+			}
+
+			return cdrom
+		}
+
+		// a CD-ROM is added if needed
+		cdrom = attach()
+		if err = vm.RemoveDevice(ctx, false, cdrom); err != nil {
+			t.Fatal(err)
+		}
+		cdrom = attach()
+
+		backing, ok := cdrom.Backing.(*types.VirtualCdromIsoBackingInfo)
+		if !ok {
+			t.Fatalf("backing=%T", cdrom.Backing)
+		}
+		assert.Equal(t, ds.Path(name), backing.FileName)
+
+		var p object.DatastorePath
+		p.FromString(backing.FileName)
+
+		f, _, err := ds.Download(ctx, p.Path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iso, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e, err := ovf.UnmarshalEnvISO(bytes.NewReader(iso))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, testEnv().Properties(), e.Properties())
+	})
+}
@@ -0,0 +1,572 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const (
+	diskFormatStreamOptimized = "http://www.vmware.com/interfaces/specifications/vmdk.html#streamOptimized"
+	virtualSystemTypePrefix   = "vmx-"
+)
+
+// FromVirtualMachineOptions influence the behavior of the
+// FromVirtualMachineWithOptions function.
+type FromVirtualMachineOptions struct {
+
+	// Networks maps a network backing identifier to the name used in the
+	// NetworkSection. The identifier is the portgroup key for distributed
+	// port backings and the network ID for opaque network backings. Standard
+	// network backings use the backing's device name and need no mapping.
+	Networks map[string]string
+
+	// ExtraConfig, if non-nil, is called for each ExtraConfig key. Only keys
+	// for which it returns true are included in the envelope.
+	ExtraConfig func(key string) bool
+}
+
+// FromVirtualMachine calls FromVirtualMachineWithOptions with an empty
+// FromVirtualMachineOptions object.
+func FromVirtualMachine(vm mo.VirtualMachine) (*Envelope, error) {
+	return FromVirtualMachineWithOptions(vm, FromVirtualMachineOptions{})
+}
+
+// FromVirtualMachineWithOptions builds an envelope from the config of a
+// virtual machine, without using the NFC export lease or the server side
+// OvfManager.CreateDescriptor method. It is the inverse of
+// Envelope.ToConfigSpec.
+// Please note, at this time:
+//   - Disk content is not exported, disks are described by their capacity
+//     only and do not reference a file.
+//   - Devices that ToConfigSpec does not support are omitted.
+func FromVirtualMachineWithOptions(
+	vm mo.VirtualMachine,
+	opts FromVirtualMachineOptions) (*Envelope, error) {
+
+	if vm.Config == nil {
+		return nil, errors.New("no VirtualMachine config")
+	}
+
+	src := vm.Config
+	name := src.Name
+
+	vs := &VirtualSystem{
+		Content: Content{
+			ID:   name,
+			Info: "A virtual machine",
+			Name: &name,
+		},
+		OperatingSystem: &OperatingSystemSection{
+			Section: Section{
+				Info: "The kind of installed guest operating system",
+			},
+			ID:     int16(GuestIDToCIMOSType(src.GuestId)),
+			OSType: types.New(src.GuestId),
+		},
+	}
+
+	if src.GuestFullName != "" {
+		vs.OperatingSystem.Description = types.New(src.GuestFullName)
+	}
+
+	if src.Annotation != "" {
+		vs.Annotation = &AnnotationSection{
+			Section: Section{
+				Info: "A human-readable annotation",
+			},
+			Annotation: src.Annotation,
+		}
+	}
+
+	e := &Envelope{
+		VirtualSystem: vs,
+	}
+
+	b := fromVirtualMachine{
+		Envelope: e,
+		opts:     opts,
+		devices:  object.VirtualDeviceList(src.Hardware.Device),
+		ids:      map[int32]string{},
+		networks: map[string]bool{},
+	}
+
+	hw, err := b.toVirtualHardware(src)
+	if err != nil {
+		return nil, err
+	}
+
+	vs.VirtualHardware = []VirtualHardwareSection{hw}
+	vs.Product = b.toProduct(src.VAppConfig)
+
+	return e, nil
+}
+
+type fromVirtualMachine struct {
+	*Envelope
+
+	opts     FromVirtualMachineOptions
+	devices  object.VirtualDeviceList
+	ids      map[int32]string // device key -> Item InstanceID
+	networks map[string]bool
+	items    []ResourceAllocationSettingData
+}
+
+func (b *fromVirtualMachine) addItem(
+	name string,
+	kind CIMResourceType,
+	item ResourceAllocationSettingData) string {
+
+	id := strconv.Itoa(len(b.items) + 1)
+
+	item.ElementName = name
+	item.InstanceID = id
+	item.ResourceType = &kind
+
+	b.items = append(b.items, item)
+
+	return id
+}
+
+func (b *fromVirtualMachine) toVirtualHardware(
+	src *types.VirtualMachineConfigInfo) (VirtualHardwareSection, error) {
+
+	hw := VirtualHardwareSection{
+		Section: Section{
+			Info: "Virtual hardware requirements",
+		},
+		System: &VirtualSystemSettingData{
+			CIMVirtualSystemSettingData: CIMVirtualSystemSettingData{
+				ElementName:             "Virtual Hardware Family",
+				InstanceID:              "0",
+				VirtualSystemIdentifier: types.New(src.Name),
+			},
+		},
+	}
+
+	if src.Version != "" {
+		version := src.Version
+		if !strings.HasPrefix(version, virtualSystemTypePrefix) {
+			version = virtualSystemTypePrefix + version
+		}
+		hw.System.VirtualSystemType = &version
+	}
+
+	if vapp, ok := src.VAppConfig.(*types.VmConfigInfo); ok {
+		if len(vapp.OvfEnvironmentTransport) != 0 {
+			transport := strings.Join(vapp.OvfEnvironmentTransport, " ")
+			hw.Transport = &transport
+		}
+	}
+
+	cpus := uint(src.Hardware.NumCPU)
+	cpu := ResourceAllocationSettingData{
+		CIMResourceAllocationSettingData: CIMResourceAllocationSettingData{
+			AllocationUnits: types.New("hertz * 10^6"),
+			Description:     types.New("Number of Virtual CPUs"),
+			VirtualQuantity: &cpus,
+		},
+	}
+	if n := src.Hardware.NumCoresPerSocket; n != 0 {
+		cpu.CoresPerSocket = &CoresPerSocket{Value: n}
+	}
+	b.addItem(fmt.Sprintf("%d virtual CPU(s)", cpus), Processor, cpu)
+
+	memory := uint(src.Hardware.MemoryMB)
+	b.addItem(fmt.Sprintf("%dMB of memory", memory), Memory, ResourceAllocationSettingData{
+		CIMResourceAllocationSettingData: CIMResourceAllocationSettingData{
+			AllocationUnits: types.New("byte * 2^20"),
+			Description:     types.New("Memory Size"),
+			VirtualQuantity: &memory,
+		},
+	})
+
+	// Controllers must precede the devices that refer to them as Parent.
+	for _, d := range b.devices {
+		if _, ok := d.(types.BaseVirtualController); ok {
+			if err := b.toItem(d); err != nil {
+				return hw, err
+			}
+		}
+	}
+
+	for _, d := range b.devices {
+		if _, ok := d.(types.BaseVirtualController); !ok {
+			if err := b.toItem(d); err != nil {
+				return hw, err
+			}
+		}
+	}
+
+	hw.Item = b.items
+	hw.Config = fromConfig(src)
+
+	for _, opt := range src.ExtraConfig {
+		ov := opt.GetOptionValue()
+		if b.opts.ExtraConfig != nil && !b.opts.ExtraConfig(ov.Key) {
+			continue
+		}
+		hw.ExtraConfig = append(hw.ExtraConfig, Config{
+			Required: types.NewBool(false),
+			Key:      ov.Key,
+			Value:    fmt.Sprint(ov.Value),
+		})
+	}
+
+	return hw, nil
+}
+
+func (b *fromVirtualMachine) toItem(d types.BaseVirtualDevice) error {
+	var (
+		item ResourceAllocationSettingData
+		kind CIMResourceType
+	)
+
+	vd := d.GetVirtualDevice()
+	label := b.devices.Name(d)
+	if vd.DeviceInfo != nil {
+		if info := vd.DeviceInfo.GetDescription(); info != nil && info.Label != "" {
+			label = info.Label
+		}
+	}
+
+	switch c := d.(type) {
+	case *types.VirtualIDEController:
+		kind = IdeController
+		item.Address = fromBusNumber(c.BusNumber)
+
+	case types.BaseVirtualSCSIController:
+		kind = ParallelScsiHba
+		item.Address = fromBusNumber(c.GetVirtualSCSIController().BusNumber)
+		item.ResourceSubType = types.New(fromSCSIControllerType(d))
+
+	case *types.VirtualAHCIController:
+		kind = OtherStorage
+		item.Address = fromBusNumber(c.BusNumber)
+		item.ResourceSubType = types.New(ResourceSubTypeSATAAHCI)
+
+	case *types.VirtualNVMEController:
+		kind = OtherStorage
+		item.Address = fromBusNumber(c.BusNumber)
+		item.ResourceSubType = types.New(ResourceSubTypeNVMEController)
+
+	case *types.VirtualUSBController:
+		kind = UsbController
+		item.Address = fromBusNumber(c.BusNumber)
+		item.ResourceSubType = types.New(ResourceSubTypeUSBEHCI)
+		item.Config = appendBoolConfig(item.Config, "autoConnectDevices", c.AutoConnectDevices)
+		item.Config = appendBoolConfig(item.Config, "ehciEnabled", c.EhciEnabled)
+
+	case *types.VirtualUSBXHCIController:
+		kind = UsbController
+		item.Address = fromBusNumber(c.BusNumber)
+		item.ResourceSubType = types.New(ResourceSubTypeUSBXHCI)
+		item.Config = appendBoolConfig(item.Config, "autoConnectDevices", c.AutoConnectDevices)
+
+	case types.BaseVirtualEthernetCard:
+		kind = EthernetAdapter
+		nic := c.GetVirtualEthernetCard()
+		item.ResourceSubType = types.New(fromEthernetCardType(d))
+		if network := b.toNetwork(nic.Backing); network != "" {
+			item.Connection = []string{network}
+		}
+		item.Config = appendBoolConfig(item.Config, "wakeOnLanEnabled", nic.WakeOnLanEnabled)
+		item.Config = appendBoolConfig(item.Config, "uptCompatibilityEnabled", nic.UptCompatibilityEnabled)
+
+	case *types.VirtualFloppy:
+		kind = FloppyDrive
+
+	case *types.VirtualCdrom:
+		kind = CdDrive
+
+	case *types.VirtualDisk:
+		kind = DiskDrive
+		item.HostResource = []string{b.toDisk(c)}
+
+	case *types.VirtualMachineVideoCard:
+		kind = Graphics
+		item.Config = appendBoolConfig(item.Config, "enable3DSupport", c.Enable3DSupport)
+		if c.GraphicsMemorySizeInKB != 0 {
+			item.Config = appendConfig(item.Config, "graphicsMemorySizeInKB", strconv.FormatInt(c.GraphicsMemorySizeInKB, 10))
+		}
+		item.Config = appendBoolConfig(item.Config, "useAutoDetect", c.UseAutoDetect)
+		if c.VideoRamSizeInKB != 0 {
+			item.Config = appendConfig(item.Config, "videoRamSizeInKB", strconv.FormatInt(c.VideoRamSizeInKB, 10))
+		}
+		if c.NumDisplays != 0 {
+			item.Config = appendConfig(item.Config, "numDisplays", strconv.Itoa(int(c.NumDisplays)))
+		}
+		if c.Use3dRenderer != "" {
+			item.Config = appendConfig(item.Config, "use3dRenderer", c.Use3dRenderer)
+		}
+
+	case *types.VirtualMachineVMCIDevice:
+		kind = Other
+		item.ResourceSubType = types.New(ResourceSubTypeVMCI)
+		item.Config = appendBoolConfig(item.Config, "allowUnrestrictedCommunication", c.AllowUnrestrictedCommunication)
+
+	default:
+		return nil // Not supported by ToConfigSpec
+	}
+
+	switch kind {
+	case CdDrive, DiskDrive:
+		parent, ok := b.ids[vd.ControllerKey]
+		if !ok {
+			return nil // Parent is unsupported
+		}
+		item.Parent = &parent
+	}
+
+	if vd.UnitNumber != nil {
+		item.AddressOnParent = types.New(strconv.Itoa(int(*vd.UnitNumber)))
+	}
+
+	if c := vd.Connectable; c != nil {
+		item.AutomaticAllocation = types.NewBool(c.StartConnected)
+		item.Config = appendConfig(item.Config, "connectable.allowGuestControl", strconv.FormatBool(c.AllowGuestControl))
+	}
+
+	if si, ok := vd.SlotInfo.(*types.VirtualDevicePciBusSlotInfo); ok && si.PciSlotNumber >= 0 {
+		item.Config = appendConfig(item.Config, "slotInfo.pciSlotNumber", strconv.Itoa(int(si.PciSlotNumber)))
+	}
+
+	b.ids[vd.Key] = b.addItem(label, kind, item)
+
+	return nil
+}
+
+func (b *fromVirtualMachine) toNetwork(backing types.BaseVirtualDeviceBackingInfo) string {
+	var name string
+
+	switch n := backing.(type) {
+	case *types.VirtualEthernetCardNetworkBackingInfo:
+		name = n.DeviceName
+	case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+		name = n.Port.PortgroupKey
+	case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+		name = n.OpaqueNetworkId
+	}
+
+	if mapped, ok := b.opts.Networks[name]; ok {
+		name = mapped
+	}
+
+	if name == "" || b.networks[name] {
+		return name
+	}
+
+	b.networks[name] = true
+
+	if b.Network == nil {
+		b.Network = &NetworkSection{
+			Section: Section{
+				Info: "The list of logical networks",
+			},
+		}
+	}
+
+	b.Network.Networks = append(b.Network.Networks, Network{
+		Name:        name,
+		Description: fmt.Sprintf("The %s network", name),
+	})
+
+	return name
+}
+
+func (b *fromVirtualMachine) toDisk(d *types.VirtualDisk) string {
+	if b.Disk == nil {
+		b.Disk = &DiskSection{
+			Section: Section{
+				Info: "Virtual disk information",
+			},
+		}
+	}
+
+	id := fmt.Sprintf("vmdisk%d", len(b.Disk.Disks)+1)
+
+	capacity := strconv.FormatInt(d.CapacityInBytes, 10)
+	units := "byte"
+	if d.CapacityInBytes%(1024*1024) == 0 {
+		capacity = strconv.FormatInt(d.CapacityInBytes/(1024*1024), 10)
+		units = "byte * 2^20"
+	}
+
+	b.Disk.Disks = append(b.Disk.Disks, VirtualDiskDesc{
+		DiskID:                  id,
+		Capacity:                capacity,
+		CapacityAllocationUnits: &units,
+		Format:                  types.New(diskFormatStreamOptimized),
+	})
+
+	return "ovf:/disk/" + id
+}
+
+func (b *fromVirtualMachine) toProduct(info types.BaseVmConfigInfo) []ProductSection {
+	vapp, ok := info.(*types.VmConfigInfo)
+	if !ok || (len(vapp.Product) == 0 && len(vapp.Property) == 0) {
+		return nil
+	}
+
+	var products []ProductSection
+
+	for _, p := range vapp.Product {
+		products = append(products, ProductSection{
+			Section: Section{
+				Info: "Information about the installed software",
+			},
+			Class:       nilIfEmpty(p.ClassId),
+			Instance:    nilIfEmpty(p.InstanceId),
+			Product:     p.Name,
+			Vendor:      p.Vendor,
+			Version:     p.Version,
+			FullVersion: p.FullVersion,
+			ProductURL:  p.ProductUrl,
+			VendorURL:   p.VendorUrl,
+			AppURL:      p.AppUrl,
+		})
+	}
+
+	for _, p := range vapp.Property {
+		index := -1
+		for i := range products {
+			if deref(products[i].Class) == p.ClassId && deref(products[i].Instance) == p.InstanceId {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			products = append(products, ProductSection{
+				Section: Section{
+					Info: "Information about the installed software",
+				},
+				Class:    nilIfEmpty(p.ClassId),
+				Instance: nilIfEmpty(p.InstanceId),
+			})
+			index = len(products) - 1
+		}
+
+		product := &products[index]
+		if product.Category == "" {
+			product.Category = p.Category
+		}
+
+		product.Property = append(product.Property, Property{
+			Key:              p.Id,
+			Type:             p.Type,
+			UserConfigurable: p.UserConfigurable,
+			Default:          nilIfEmpty(p.DefaultValue),
+			Value:            nilIfEmpty(p.Value),
+			Label:            nilIfEmpty(p.Label),
+			Description:      nilIfEmpty(p.Description),
+		})
+	}
+
+	return products
+}
+
+// fromConfig is the inverse of Envelope.toConfig.
+func fromConfig(src *types.VirtualMachineConfigInfo) []Config {
+	var c []Config
+
+	c = appendBoolConfig(c, "cpuHotAddEnabled", src.CpuHotAddEnabled)
+	c = appendBoolConfig(c, "cpuHotRemoveEnabled", src.CpuHotRemoveEnabled)
+	if bo := src.BootOptions; bo != nil {
+		c = appendBoolConfig(c, "bootOptions.efiSecureBootEnabled", bo.EfiSecureBootEnabled)
+	}
+	if src.Firmware != "" {
+		c = appendConfig(c, "firmware", src.Firmware)
+	}
+	c = appendBoolConfig(c, "flags.vbsEnabled", src.Flags.VbsEnabled)
+	c = appendBoolConfig(c, "flags.vvtdEnabled", src.Flags.VvtdEnabled)
+	c = appendBoolConfig(c, "memoryHotAddEnabled", src.MemoryHotAddEnabled)
+	c = appendBoolConfig(c, "nestedHVEnabled", src.NestedHVEnabled)
+	c = appendBoolConfig(c, "virtualICH7MPresent", src.Hardware.VirtualICH7MPresent)
+	c = appendBoolConfig(c, "virtualSMCPresent", src.Hardware.VirtualSMCPresent)
+	if a := src.CpuAllocation; a != nil && a.Shares != nil {
+		c = appendConfig(c, "cpuAllocation.shares.shares", strconv.Itoa(int(a.Shares.Shares)))
+		c = appendConfig(c, "cpuAllocation.shares.level", string(a.Shares.Level))
+	}
+	if n := src.Hardware.SimultaneousThreads; n != 0 {
+		c = appendConfig(c, "simultaneousThreads", strconv.Itoa(int(n)))
+	}
+	if t := src.Tools; t != nil {
+		c = appendBoolConfig(c, "tools.syncTimeWithHost", t.SyncTimeWithHost)
+		c = appendBoolConfig(c, "tools.syncTimeWithHostAllowed", t.SyncTimeWithHostAllowed)
+		c = appendBoolConfig(c, "tools.afterPowerOn", t.AfterPowerOn)
+		c = appendBoolConfig(c, "tools.afterResume", t.AfterResume)
+		c = appendBoolConfig(c, "tools.beforeGuestShutdown", t.BeforeGuestShutdown)
+		c = appendBoolConfig(c, "tools.beforeGuestStandby", t.BeforeGuestStandby)
+		if t.ToolsUpgradePolicy != "" {
+			c = appendConfig(c, "tools.toolsUpgradePolicy", t.ToolsUpgradePolicy)
+		}
+	}
+	if p := src.DefaultPowerOps; p.PowerOffType != "" {
+		c = appendConfig(c, "powerOpInfo.powerOffType", p.PowerOffType)
+		c = appendConfig(c, "powerOpInfo.resetType", p.ResetType)
+		c = appendConfig(c, "powerOpInfo.suspendType", p.SuspendType)
+		c = appendConfig(c, "powerOpInfo.standbyAction", p.StandbyAction)
+	}
+	c = appendBoolConfig(c, "vPMCEnabled", src.VPMCEnabled)
+
+	return c
+}
+
+func appendConfig(c []Config, key, value string) []Config {
+	return append(c, Config{
+		Required: types.NewBool(false),
+		Key:      key,
+		Value:    value,
+	})
+}
+
+func appendBoolConfig(c []Config, key string, value *bool) []Config {
+	if value == nil {
+		return c
+	}
+	return appendConfig(c, key, strconv.FormatBool(*value))
+}
+
+func fromBusNumber(n int32) *string {
+	return types.New(strconv.Itoa(int(n)))
+}
+
+func fromSCSIControllerType(d types.BaseVirtualDevice) string {
+	switch d.(type) {
+	case *types.ParaVirtualSCSIController:
+		return "VirtualSCSI"
+	case *types.VirtualLsiLogicSASController:
+		return "lsilogicsas"
+	case *types.VirtualBusLogicController:
+		return "buslogic"
+	default:
+		return "lsilogic"
+	}
+}
+
+func fromEthernetCardType(d types.BaseVirtualDevice) string {
+	switch d.(type) {
+	case *types.VirtualSriovEthernetCard:
+		return "sriov"
+	default:
+		name := object.VirtualDeviceList{}.TypeName(d)
+		return strings.ToLower(strings.TrimPrefix(name, "Virtual"))
+	}
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,225 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ovf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// testVirtualMachine converts the given ConfigSpec to a VirtualMachine config,
+// as if a VM had been created with the spec.
+func testVirtualMachine(t *testing.T, cs types.VirtualMachineConfigSpec) mo.VirtualMachine {
+	info := &types.VirtualMachineConfigInfo{
+		Name:                cs.Name,
+		GuestId:             cs.GuestId,
+		Version:             cs.Version,
+		Firmware:            cs.Firmware,
+		CpuHotAddEnabled:    cs.CpuHotAddEnabled,
+		CpuHotRemoveEnabled: cs.CpuHotRemoveEnabled,
+		MemoryHotAddEnabled: cs.MemoryHotAddEnabled,
+		NestedHVEnabled:     cs.NestedHVEnabled,
+		VPMCEnabled:         cs.VPMCEnabled,
+		BootOptions:         cs.BootOptions,
+		Tools:               cs.Tools,
+		CpuAllocation:       cs.CpuAllocation,
+		ExtraConfig:         cs.ExtraConfig,
+		Hardware: types.VirtualHardware{
+			NumCPU:              cs.NumCPUs,
+			NumCoresPerSocket:   cs.NumCoresPerSocket,
+			MemoryMB:            int32(cs.MemoryMB),
+			SimultaneousThreads: cs.SimultaneousThreads,
+			VirtualICH7MPresent: cs.VirtualICH7MPresent,
+			VirtualSMCPresent:   cs.VirtualSMCPresent,
+		},
+	}
+
+	if cs.Flags != nil {
+		info.Flags = *cs.Flags
+	}
+
+	if cs.PowerOpInfo != nil {
+		info.DefaultPowerOps = *cs.PowerOpInfo
+	}
+
+	for _, dc := range cs.DeviceChange {
+		info.Hardware.Device = append(info.Hardware.Device, dc.GetVirtualDeviceConfigSpec().Device)
+	}
+
+	if spec, ok := cs.VAppConfig.(*types.VAppConfigSpec); ok {
+		vapp := &types.VmConfigInfo{
+			OvfEnvironmentTransport: spec.OvfEnvironmentTransport,
+		}
+		for _, p := range spec.Product {
+			vapp.Product = append(vapp.Product, *p.Info)
+		}
+		for _, p := range spec.Property {
+			vapp.Property = append(vapp.Property, *p.Info)
+		}
+		info.VAppConfig = vapp
+	}
+
+	return mo.VirtualMachine{Config: info}
+}
+
+// testDevices summarizes a device list, ignoring device keys.
+func testDevices(cs types.VirtualMachineConfigSpec) []string {
+	var devices object.VirtualDeviceList
+	for _, dc := range cs.DeviceChange {
+		devices = append(devices, dc.GetVirtualDeviceConfigSpec().Device)
+	}
+
+	var s []string
+	for _, d := range devices {
+		vd := d.GetVirtualDevice()
+		desc := devices.TypeName(d)
+		if vd.UnitNumber != nil {
+			desc += fmt.Sprintf(" unit=%d", *vd.UnitNumber)
+		}
+		if c := devices.FindByKey(vd.ControllerKey); c != nil {
+			desc += " parent=" + devices.TypeName(c)
+		}
+		if disk, ok := d.(*types.VirtualDisk); ok {
+			desc += fmt.Sprintf(" capacity=%d", disk.CapacityInBytes)
+		}
+		s = append(s, desc)
+	}
+
+	return s
+}
+
+func TestFromVirtualMachine(t *testing.T) {
+	fixtures := []string{
+		"fixtures/configspec.ovf",
+		"fixtures/properties.ovf",
+		"fixtures/ttylinux.ovf",
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(fixture, func(t *testing.T) {
+			cs1, err := testEnvelope(t, fixture).ToConfigSpec()
+			assert.NoError(t, err)
+
+			e, err := FromVirtualMachine(testVirtualMachine(t, cs1))
+			assert.NoError(t, err)
+
+			// Round trip through XML, as when the descriptor is stored in a file.
+			var buf bytes.Buffer
+			assert.NoError(t, e.Write(&buf))
+			e, err = Unmarshal(&buf)
+			assert.NoError(t, err)
+
+			cs2, err := e.ToConfigSpec()
+			assert.NoError(t, err)
+
+			assert.Equal(t, cs1.Name, cs2.Name)
+			assert.Equal(t, cs1.GuestId, cs2.GuestId)
+			assert.Equal(t, cs1.Version, cs2.Version)
+			assert.Equal(t, cs1.NumCPUs, cs2.NumCPUs)
+			assert.Equal(t, cs1.NumCoresPerSocket, cs2.NumCoresPerSocket)
+			assert.Equal(t, cs1.MemoryMB, cs2.MemoryMB)
+			assert.Equal(t, cs1.Firmware, cs2.Firmware)
+			assert.Equal(t, cs1.Flags, cs2.Flags)
+			assert.Equal(t, cs1.Tools, cs2.Tools)
+			assert.Equal(t, cs1.ExtraConfig, cs2.ExtraConfig)
+			assert.Equal(t, cs1.VAppConfig, cs2.VAppConfig)
+			assert.ElementsMatch(t, testDevices(cs1), testDevices(cs2))
+		})
+	}
+
+	t.Run("ExtraConfig filter", func(t *testing.T) {
+		vm := mo.VirtualMachine{
+			Config: &types.VirtualMachineConfigInfo{
+				Name: "filter",
+				ExtraConfig: []types.BaseOptionValue{
+					&types.OptionValue{Key: "guestinfo.password", Value: "secret"},
+					&types.OptionValue{Key: "disk.enableUUID", Value: "TRUE"},
+				},
+			},
+		}
+
+		e, err := FromVirtualMachineWithOptions(vm, FromVirtualMachineOptions{
+			ExtraConfig: func(key string) bool {
+				return !strings.HasPrefix(key, "guestinfo.")
+			},
+		})
+		assert.NoError(t, err)
+
+		ec := e.VirtualSystem.VirtualHardware[0].ExtraConfig
+		if assert.Len(t, ec, 1) {
+			assert.Equal(t, "disk.enableUUID", ec[0].Key)
+			assert.Equal(t, "TRUE", ec[0].Value)
+		}
+	})
+
+	t.Run("Networks", func(t *testing.T) {
+		vm := mo.VirtualMachine{
+			Config: &types.VirtualMachineConfigInfo{
+				Name: "networks",
+				Hardware: types.VirtualHardware{
+					Device: []types.BaseVirtualDevice{
+						&types.VirtualVmxnet3{
+							VirtualVmxnet: types.VirtualVmxnet{
+								VirtualEthernetCard: types.VirtualEthernetCard{
+									VirtualDevice: types.VirtualDevice{
+										Key: 4000,
+										Backing: &types.VirtualEthernetCardNetworkBackingInfo{
+											VirtualDeviceDeviceBackingInfo: types.VirtualDeviceDeviceBackingInfo{
+												DeviceName: "VM Network",
+											},
+										},
+									},
+								},
+							},
+						},
+						&types.VirtualE1000{
+							VirtualEthernetCard: types.VirtualEthernetCard{
+								VirtualDevice: types.VirtualDevice{
+									Key: 4001,
+									Backing: &types.VirtualEthernetCardDistributedVirtualPortBackingInfo{
+										Port: types.DistributedVirtualSwitchPortConnection{
+											PortgroupKey: "dvportgroup-1",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		e, err := FromVirtualMachineWithOptions(vm, FromVirtualMachineOptions{
+			Networks: map[string]string{"dvportgroup-1": "DVPG0"},
+		})
+		assert.NoError(t, err)
+
+		if assert.NotNil(t, e.Network) && assert.Len(t, e.Network.Networks, 2) {
+			assert.Equal(t, "VM Network", e.Network.Networks[0].Name)
+			assert.Equal(t, "DVPG0", e.Network.Networks[1].Name)
+		}
+
+		items := e.VirtualSystem.VirtualHardware[0].Item
+		if assert.Len(t, items, 4) {
+			assert.Equal(t, "vmxnet3", *items[2].ResourceSubType)
+			assert.Equal(t, []string{"VM Network"}, items[2].Connection)
+			assert.Equal(t, "e1000", *items[3].ResourceSubType)
+			assert.Equal(t, []string{"DVPG0"}, items[3].Connection)
+		}
+	})
+
+	t.Run("No config", func(t *testing.T) {
+		_, err := FromVirtualMachine(mo.VirtualMachine{})
+		assert.Error(t, err)
+	})
+}
@@ -117,6 +117,22 @@ func (c *Client) CheckRequirements(ctx context.Context, hubs []types.PbmPlacemen
 	return res.Returnval, nil
 }
 
+// CheckCompatibility checks the compatibility of the given profile with the given hubs, or all hubs if none are given.
+func (c *Client) CheckCompatibility(ctx context.Context, hubs []types.PbmPlacementHub, profile types.PbmProfileId) (PlacementCompatibilityResult, error) {
+	req := types.PbmCheckCompatibility{
+		This:         c.ServiceContent.PlacementSolver,
+		HubsToSearch: hubs,
+		Profile:      profile,
+	}
+
+	res, err := methods.PbmCheckCompatibility(ctx, c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
 func (l PlacementCompatibilityResult) CompatibleDatastores() []types.PbmPlacementHub {
 	var compatibleDatastores []types.PbmPlacementHub
 
@@ -264,6 +280,23 @@ func (c *Client) FetchComplianceResult(ctx context.Context, entities []types.Pbm
 	return res.Returnval, nil
 }
 
+// CheckCompliance checks the compliance of the given entities with their associated profile,
+// or with the given profile if not nil.
+func (c *Client) CheckCompliance(ctx context.Context, entities []types.PbmServerObjectRef, profile *types.PbmProfileId) ([]types.PbmComplianceResult, error) {
+	req := types.PbmCheckCompliance{
+		This:     c.ServiceContent.ComplianceManager,
+		Entities: entities,
+		Profile:  profile,
+	}
+
+	res, err := methods.PbmCheckCompliance(ctx, c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
 // GetProfileNameByID gets storage profile name by ID
 func (c *Client) GetProfileNameByID(ctx context.Context, profileID string) (string, error) {
 	resourceType := types.PbmProfileResourceType{
@@ -0,0 +1,276 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/vmware/govmomi/internal"
+	"github.com/vmware/govmomi/pbm/types"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	vim "github.com/vmware/govmomi/vim25/types"
+)
+
+// Capability namespaces evaluated against simulated datastores.
+// Capabilities in other namespaces are assumed to be supported by all datastores.
+const (
+	namespaceVSAN        = "VSAN"
+	namespaceTag         = "http://www.vmware.com/storage/tag"
+	namespaceDataService = "com.vmware.storageprofile.dataservice"
+	namespacePMem        = "PMem"
+
+	encryptionCapabilityID = "ad5a249d-cbc2-43af-9366-694d7664fa52"
+
+	vsanMaxStripeWidth = 12
+)
+
+// hub is a placement hub (Datastore) along with the capabilities it exposes.
+type hub struct {
+	ref  vim.ManagedObjectReference
+	ds   *simulator.Datastore
+	tags []vim.VslmTagEntry
+	kms  bool // a key provider is configured, required by the encryption data service
+
+	hosts int // number of hosts that mount the datastore, which is the number of vSAN fault domains
+	vsan  bool
+	pmem  bool
+}
+
+// newHub returns the hub for the given Datastore, or nil if the Datastore does not exist.
+func newHub(ctx *simulator.Context, ref vim.ManagedObjectReference) *hub {
+	vctx := ctx.For(vim25.Path)
+
+	ds, ok := vctx.Map.Get(ref).(*simulator.Datastore)
+	if !ok {
+		return nil
+	}
+
+	return &hub{
+		ref:   ref,
+		ds:    ds,
+		tags:  vctx.Map.AttachedTags(ref),
+		kms:   len(vctx.Map.CryptoManager().KmipServers) != 0,
+		hosts: len(ds.Host),
+		vsan:  internal.IsDatastoreVSAN(ds.Datastore),
+		pmem:  ds.Summary.Type == string(vim.HostFileSystemVolumeFileSystemTypePMEM),
+	}
+}
+
+func (h *hub) placementHub() types.PbmPlacementHub {
+	return types.PbmPlacementHub{
+		HubType: h.ref.Type,
+		HubId:   h.ref.Value,
+	}
+}
+
+// check evaluates the constraints of a profile, returning the reasons the hub is not compatible, if any.
+// Rule sets (sub profiles) with datastore specific capabilities are alternatives, only one of which must be satisfied.
+// Rule sets with data service capabilities only, such as encryption, must always be satisfied.
+func (h *hub) check(constraints types.BasePbmCapabilityConstraints) []vim.LocalizedMethodFault {
+	sub, ok := constraints.(*types.PbmCapabilitySubProfileConstraints)
+	if !ok || len(sub.SubProfiles) == 0 {
+		return nil
+	}
+
+	var (
+		required []vim.LocalizedMethodFault
+		rulesets []vim.LocalizedMethodFault
+		matched  bool
+		storage  bool
+	)
+
+	for _, profile := range sub.SubProfiles {
+		var faults []vim.LocalizedMethodFault
+
+		for _, c := range profile.Capability {
+			faults = append(faults, h.checkCapability(profile, c)...)
+		}
+
+		if isDataService(profile) {
+			required = append(required, faults...)
+			continue
+		}
+
+		storage = true
+		if len(faults) == 0 {
+			matched = true
+		}
+		rulesets = append(rulesets, faults...)
+	}
+
+	if storage && !matched {
+		required = append(required, rulesets...)
+	}
+
+	return required
+}
+
+func isDataService(profile types.PbmCapabilitySubProfile) bool {
+	for _, c := range profile.Capability {
+		if c.Id.Namespace != namespaceDataService {
+			return false
+		}
+	}
+	return len(profile.Capability) != 0
+}
+
+// checkCapability evaluates the constraint alternatives of a capability instance, one of which must be satisfied.
+func (h *hub) checkCapability(profile types.PbmCapabilitySubProfile, c types.PbmCapabilityInstance) []vim.LocalizedMethodFault {
+	var faults []vim.LocalizedMethodFault
+
+	for _, constraint := range c.Constraint {
+		var mismatch []vim.LocalizedMethodFault
+
+		for _, p := range constraint.PropertyInstance {
+			if msg := h.checkProperty(profile, c.Id, p); msg != "" {
+				mismatch = append(mismatch, h.mismatch(c.Id, p, msg))
+			}
+		}
+
+		if len(mismatch) == 0 {
+			return nil
+		}
+		faults = append(faults, mismatch...)
+	}
+
+	return faults
+}
+
+// checkProperty returns a message describing why the property is not satisfied, or an empty string if it is.
+func (h *hub) checkProperty(profile types.PbmCapabilitySubProfile, id types.PbmCapabilityMetadataUniqueId, p types.PbmCapabilityPropertyInstance) string {
+	switch id.Namespace {
+	case namespaceVSAN:
+		if !h.vsan {
+			return fmt.Sprintf("Datastore %s does not support capability %s.%s", h.ds.Name, id.Namespace, id.Id)
+		}
+		return h.checkVSAN(profile, p)
+	case namespaceTag:
+		values := propertyValues(p.Value)
+		for _, tag := range h.tags {
+			if tag.ParentCategoryName == id.Id && slices.Contains(values, tag.TagName) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("Datastore %s does not have a tag in category %s matching %v", h.ds.Name, id.Id, values)
+	case namespaceDataService:
+		if id.Id == encryptionCapabilityID && !h.kms {
+			return "Encryption requires a key provider, none is configured"
+		}
+	case namespacePMem:
+		if !h.pmem {
+			return fmt.Sprintf("Datastore %s is not a PMem datastore", h.ds.Name)
+		}
+	}
+
+	return ""
+}
+
+func (h *hub) checkVSAN(profile types.PbmCapabilitySubProfile, p types.PbmCapabilityPropertyInstance) string {
+	val, ok := propertyInt(p.Value)
+
+	switch p.Id {
+	case "hostFailuresToTolerate":
+		if !ok || val < 0 || val > 3 {
+			return fmt.Sprintf("Invalid value for hostFailuresToTolerate: %v", p.Value)
+		}
+		need := int(2*val + 1)
+		if h.hosts < need && !forceProvisioning(profile) {
+			return fmt.Sprintf("There are currently %d usable fault domains. The operation requires %d more usable fault domains.", h.hosts, need-h.hosts)
+		}
+	case "stripeWidth":
+		if !ok || val < 1 || val > vsanMaxStripeWidth {
+			return fmt.Sprintf("Invalid value for stripeWidth: %v, must be between 1 and %d", p.Value, vsanMaxStripeWidth)
+		}
+	case "proportionalCapacity":
+		if !ok || val < 0 || val > 100 {
+			return fmt.Sprintf("Invalid value for proportionalCapacity: %v, must be between 0 and 100", p.Value)
+		}
+	case "cacheReservation":
+		if !ok || val < 0 || val > 1000000 {
+			return fmt.Sprintf("Invalid value for cacheReservation: %v, must be between 0 and 1000000", p.Value)
+		}
+	}
+
+	return ""
+}
+
+// forceProvisioning returns true if the vSAN rule set allows provisioning when the policy cannot be satisfied.
+func forceProvisioning(profile types.PbmCapabilitySubProfile) bool {
+	if profile.ForceProvision != nil && *profile.ForceProvision {
+		return true
+	}
+
+	for _, c := range profile.Capability {
+		if c.Id.Namespace != namespaceVSAN || c.Id.Id != "forceProvisioning" {
+			continue
+		}
+		for _, constraint := range c.Constraint {
+			for _, p := range constraint.PropertyInstance {
+				if b, ok := p.Value.(bool); ok && b {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func (h *hub) mismatch(id types.PbmCapabilityMetadataUniqueId, p types.PbmCapabilityPropertyInstance, msg string) vim.LocalizedMethodFault {
+	return vim.LocalizedMethodFault{
+		Fault: &types.PbmPropertyMismatchFault{
+			PbmCompatibilityCheckFault: types.PbmCompatibilityCheckFault{
+				Hub: h.placementHub(),
+			},
+			CapabilityInstanceId:        id,
+			RequirementPropertyInstance: p,
+		},
+		LocalizedMessage: msg,
+	}
+}
+
+// propertyValues returns the string values of a property, which may be a single value or a discrete set.
+func propertyValues(val vim.AnyType) []string {
+	switch v := val.(type) {
+	case types.PbmCapabilityDiscreteSet:
+		return propertyValues(&v)
+	case *types.PbmCapabilityDiscreteSet:
+		values := make([]string, len(v.Values))
+		for i := range v.Values {
+			values[i] = propertyString(v.Values[i])
+		}
+		return values
+	default:
+		return []string{propertyString(val)}
+	}
+}
+
+func propertyString(val vim.AnyType) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func propertyInt(val vim.AnyType) (int64, bool) {
+	switch v := val.(type) {
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
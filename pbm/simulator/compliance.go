@@ -0,0 +1,434 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/pbm/methods"
+	"github.com/vmware/govmomi/pbm/types"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	vim "github.com/vmware/govmomi/vim25/types"
+)
+
+// vmEntity resolves a virtualMachine or virtualDiskId entity to the VirtualMachine
+// and its StorageProfile key.
+func vmEntity(ctx *simulator.Context, entity types.PbmServerObjectRef) (*simulator.VirtualMachine, int32, bool) {
+	id, key := entity.Key, simulator.VirtualMachineHomeProfileKey
+
+	switch types.PbmObjectType(entity.ObjectType) {
+	case types.PbmObjectTypeVirtualMachine, types.PbmObjectTypeVirtualMachineAndDisks:
+	case types.PbmObjectTypeVirtualDiskId:
+		vm, disk, ok := strings.Cut(entity.Key, ":")
+		if !ok {
+			return nil, 0, false
+		}
+		k, err := strconv.ParseInt(disk, 10, 32)
+		if err != nil {
+			return nil, 0, false
+		}
+		id, key = vm, int32(k)
+	default:
+		return nil, 0, false
+	}
+
+	ref := vim.ManagedObjectReference{Type: "VirtualMachine", Value: id}
+	vm, ok := ctx.For(vim25.Path).Map.Get(ref).(*simulator.VirtualMachine)
+	return vm, key, ok
+}
+
+// diskEntity resolves a virtualDiskUUID entity to the first class disk and the Datastore where it is placed.
+func diskEntity(ctx *simulator.Context, entity types.PbmServerObjectRef) (*simulator.VStorageObject, vim.ManagedObjectReference, bool) {
+	if types.PbmObjectType(entity.ObjectType) != types.PbmObjectTypeVirtualDiskUUID {
+		return nil, vim.ManagedObjectReference{}, false
+	}
+
+	vctx := ctx.For(vim25.Path)
+	vsom := vctx.Map.VStorageObjectManager()
+
+	var (
+		obj *simulator.VStorageObject
+		ds  vim.ManagedObjectReference
+	)
+
+	vctx.WithLock(vsom, func() {
+		for ref, objs := range vsom.Catalog() {
+			if o, ok := objs[vim.ID{Id: entity.Key}]; ok {
+				obj, ds = o, ref
+				return
+			}
+		}
+	})
+
+	return obj, ds, obj != nil
+}
+
+// associatedProfile returns the ID of the profile associated with the entity, if any.
+func associatedProfile(ctx *simulator.Context, entity types.PbmServerObjectRef) string {
+	var id string
+
+	vctx := ctx.For(vim25.Path)
+
+	if obj, _, ok := diskEntity(ctx, entity); ok {
+		vctx.WithLock(vctx.Map.VStorageObjectManager(), func() {
+			id = obj.StorageProfile
+		})
+		return id
+	}
+
+	vm, key, ok := vmEntity(ctx, entity)
+	if !ok {
+		return ""
+	}
+
+	vctx.WithLock(vm, func() {
+		id = vm.StorageProfile[key]
+	})
+	return id
+}
+
+// associatedEntities returns the VM home, disk and first class disk entities associated with the given profile ID.
+func associatedEntities(ctx *simulator.Context, id string, kind string) []types.PbmServerObjectRef {
+	var res []types.PbmServerObjectRef
+
+	vctx := ctx.For(vim25.Path)
+
+	for _, e := range vctx.Map.All("VirtualMachine") {
+		vm := e.(*simulator.VirtualMachine)
+
+		vctx.WithLock(vm, func() {
+			for key, pid := range vm.StorageProfile {
+				if pid != id {
+					continue
+				}
+				entity := vmEntityRef(vm.Self, key)
+				if kind == "" || kind == entity.ObjectType {
+					res = append(res, entity)
+				}
+			}
+		})
+	}
+
+	if kind != "" && kind != string(types.PbmObjectTypeVirtualDiskUUID) {
+		return res
+	}
+
+	vsom := vctx.Map.VStorageObjectManager()
+	vctx.WithLock(vsom, func() {
+		for _, objs := range vsom.Catalog() {
+			for _, obj := range objs {
+				if obj.StorageProfile == id {
+					res = append(res, types.PbmServerObjectRef{
+						ObjectType: string(types.PbmObjectTypeVirtualDiskUUID),
+						Key:        obj.Config.Id.Id,
+					})
+				}
+			}
+		}
+	})
+
+	return res
+}
+
+func vmEntityRef(vm vim.ManagedObjectReference, key int32) types.PbmServerObjectRef {
+	if key == simulator.VirtualMachineHomeProfileKey {
+		return types.PbmServerObjectRef{
+			ObjectType: string(types.PbmObjectTypeVirtualMachine),
+			Key:        vm.Value,
+		}
+	}
+
+	return types.PbmServerObjectRef{
+		ObjectType: string(types.PbmObjectTypeVirtualDiskId),
+		Key:        fmt.Sprintf("%s:%d", vm.Value, key),
+	}
+}
+
+// entityDatastore returns the Datastore where the VM home or disk is placed.
+func entityDatastore(ctx *simulator.Context, vm *simulator.VirtualMachine, key int32) *vim.ManagedObjectReference {
+	vctx := ctx.For(vim25.Path)
+
+	var name string
+	var ref *vim.ManagedObjectReference
+
+	vctx.WithLock(vm, func() {
+		if key == simulator.VirtualMachineHomeProfileKey {
+			name = vm.Config.Files.VmPathName
+			return
+		}
+
+		device := object.VirtualDeviceList(vm.Config.Hardware.Device).FindByKey(key)
+		if device == nil {
+			return
+		}
+		if b, ok := device.GetVirtualDevice().Backing.(vim.BaseVirtualDeviceFileBackingInfo); ok {
+			info := b.GetVirtualDeviceFileBackingInfo()
+			ref, name = info.Datastore, info.FileName
+		}
+	})
+
+	if ref != nil {
+		return ref
+	}
+
+	var p object.DatastorePath
+	if !p.FromString(name) {
+		return nil
+	}
+
+	if ds := vctx.Map.FindByName(p.Datastore, vm.Datastore); ds != nil {
+		return vim.NewReference(ds.Reference())
+	}
+
+	return nil
+}
+
+// complianceResult is the most recent compliance check of an entity.
+type complianceResult struct {
+	types.PbmComplianceResult
+
+	generation int64 // of the profile checked against
+}
+
+type ComplianceManager struct {
+	vim.ManagedObjectReference
+
+	results map[types.PbmServerObjectRef]*complianceResult
+}
+
+func complianceKey(entity types.PbmServerObjectRef) types.PbmServerObjectRef {
+	return types.PbmServerObjectRef{ObjectType: entity.ObjectType, Key: entity.Key}
+}
+
+// check evaluates the compliance of an entity with the given profile, or its associated profile if nil.
+func (m *ComplianceManager) check(ctx *simulator.Context, entity types.PbmServerObjectRef, profile *types.PbmProfileId) *complianceResult {
+	res := &complianceResult{
+		PbmComplianceResult: types.PbmComplianceResult{
+			CheckTime:            time.Now(),
+			Entity:               entity,
+			ComplianceTaskStatus: string(types.PbmComplianceResultComplianceTaskStatusSuccess),
+			ComplianceStatus:     string(types.PbmComplianceStatusUnknown),
+		},
+	}
+
+	fail := func(fault vim.BaseMethodFault) *complianceResult {
+		res.ComplianceTaskStatus = string(types.PbmComplianceResultComplianceTaskStatusFailed)
+		res.ErrorCause = []vim.LocalizedMethodFault{{Fault: fault}}
+		return res
+	}
+
+	var ds *vim.ManagedObjectReference
+
+	if _, ref, ok := diskEntity(ctx, entity); ok {
+		ds = &ref
+	} else {
+		vm, key, ok := vmEntity(ctx, entity)
+		if !ok {
+			return fail(&vim.InvalidArgument{InvalidProperty: "entity"})
+		}
+		ds = entityDatastore(ctx, vm, key)
+		if ds == nil {
+			return fail(&vim.ManagedObjectNotFound{Obj: vm.Self})
+		}
+	}
+
+	associated := associatedProfile(ctx, entity)
+	id := associated
+	if profile != nil {
+		id = profile.UniqueId
+		res.Mismatch = id != associated
+	}
+
+	if id == "" {
+		res.ComplianceStatus = string(types.PbmComplianceStatusNotApplicable)
+		return res
+	}
+
+	p := ctx.Map.Get(content.ProfileManager).(*ProfileManager).profile(id)
+	if p == nil {
+		return fail(&vim.InvalidArgument{InvalidProperty: "profile"})
+	}
+	res.Profile = &types.PbmProfileId{UniqueId: id}
+	res.generation = p.GenerationId
+
+	h := newHub(ctx, *ds)
+	if h == nil {
+		return fail(&vim.ManagedObjectNotFound{Obj: *ds})
+	}
+
+	faults := h.check(p.Constraints)
+	if len(faults) == 0 {
+		res.ComplianceStatus = string(types.PbmComplianceStatusCompliant)
+	} else {
+		res.ComplianceStatus = string(types.PbmComplianceStatusNonCompliant)
+	}
+
+	for _, f := range faults {
+		if mismatch, ok := f.Fault.(*types.PbmPropertyMismatchFault); ok {
+			res.ViolatedPolicies = append(res.ViolatedPolicies, types.PbmCompliancePolicyStatus{
+				ExpectedValue: types.PbmCapabilityInstance{
+					Id: mismatch.CapabilityInstanceId,
+					Constraint: []types.PbmCapabilityConstraintInstance{{
+						PropertyInstance: []types.PbmCapabilityPropertyInstance{mismatch.RequirementPropertyInstance},
+					}},
+				},
+			})
+		}
+	}
+
+	res.OperationalStatus = &types.PbmComplianceOperationalStatus{
+		Healthy: vim.NewBool(len(faults) == 0),
+	}
+
+	return res
+}
+
+// fetch returns the most recent compliance result of the entity.
+// The entity is checked if it has not been checked before or its profile association has changed,
+// as vCenter does when a profile is associated.
+// The result is outOfDate if the profile has been updated since the last check.
+func (m *ComplianceManager) fetch(ctx *simulator.Context, entity types.PbmServerObjectRef) types.PbmComplianceResult {
+	key := complianceKey(entity)
+	res := m.results[key]
+
+	var current string
+	if res != nil && res.Profile != nil {
+		current = res.Profile.UniqueId
+	}
+
+	if res == nil || current != associatedProfile(ctx, entity) {
+		res = m.check(ctx, entity, nil)
+		m.results[key] = res
+		return res.PbmComplianceResult
+	}
+
+	r := res.PbmComplianceResult
+	if r.Profile != nil {
+		p := ctx.Map.Get(content.ProfileManager).(*ProfileManager).profile(r.Profile.UniqueId)
+		if p == nil || p.GenerationId != res.generation {
+			r.ComplianceStatus = string(types.PbmComplianceStatusOutOfDate)
+		}
+	}
+
+	return r
+}
+
+func (m *ComplianceManager) PbmCheckCompliance(ctx *simulator.Context, req *types.PbmCheckCompliance) soap.HasFault {
+	body := new(methods.PbmCheckComplianceBody)
+	body.Res = new(types.PbmCheckComplianceResponse)
+
+	for _, entity := range req.Entities {
+		res := m.check(ctx, entity, req.Profile)
+		if !res.Mismatch {
+			m.results[complianceKey(entity)] = res
+		}
+		body.Res.Returnval = append(body.Res.Returnval, res.PbmComplianceResult)
+	}
+
+	return body
+}
+
+func (m *ComplianceManager) PbmFetchComplianceResult(ctx *simulator.Context, req *types.PbmFetchComplianceResult) soap.HasFault {
+	body := new(methods.PbmFetchComplianceResultBody)
+	body.Res = new(types.PbmFetchComplianceResultResponse)
+
+	for _, entity := range req.Entities {
+		body.Res.Returnval = append(body.Res.Returnval, m.fetch(ctx, entity))
+	}
+
+	return body
+}
+
+// complianceSeverity orders compliance status from worst to best, for the overall status of a rollup.
+var complianceSeverity = []types.PbmComplianceStatus{
+	types.PbmComplianceStatusNonCompliant,
+	types.PbmComplianceStatusOutOfDate,
+	types.PbmComplianceStatusUnknown,
+	types.PbmComplianceStatusCompliant,
+	types.PbmComplianceStatusNotApplicable,
+}
+
+// rollup returns the compliance of a VM home and its disks, using the given function to check or fetch each result.
+func (m *ComplianceManager) rollup(ctx *simulator.Context, entity types.PbmServerObjectRef, result func(types.PbmServerObjectRef) types.PbmComplianceResult) types.PbmRollupComplianceResult {
+	res := types.PbmRollupComplianceResult{
+		Entity:                      entity,
+		OverallComplianceStatus:     string(types.PbmComplianceStatusUnknown),
+		OverallComplianceTaskStatus: string(types.PbmComplianceResultComplianceTaskStatusSuccess),
+		OldestCheckTime:             time.Now(),
+	}
+
+	vm, key, ok := vmEntity(ctx, entity)
+	if !ok || key != simulator.VirtualMachineHomeProfileKey {
+		res.OverallComplianceTaskStatus = string(types.PbmComplianceResultComplianceTaskStatusFailed)
+		res.ErrorCause = []vim.LocalizedMethodFault{{Fault: &vim.InvalidArgument{InvalidProperty: "entity"}}}
+		return res
+	}
+
+	entities := []types.PbmServerObjectRef{vmEntityRef(vm.Self, key)}
+
+	ctx.For(vim25.Path).WithLock(vm, func() {
+		for _, disk := range object.VirtualDeviceList(vm.Config.Hardware.Device).SelectByType((*vim.VirtualDisk)(nil)) {
+			entities = append(entities, vmEntityRef(vm.Self, disk.GetVirtualDevice().Key))
+		}
+	})
+
+	status := make(map[types.PbmComplianceStatus]bool)
+
+	for _, e := range entities {
+		r := result(e)
+		res.Result = append(res.Result, r)
+		status[types.PbmComplianceStatus(r.ComplianceStatus)] = true
+		if r.CheckTime.Before(res.OldestCheckTime) {
+			res.OldestCheckTime = r.CheckTime
+		}
+	}
+
+	for _, s := range complianceSeverity {
+		if status[s] {
+			res.OverallComplianceStatus = string(s)
+			break
+		}
+	}
+
+	return res
+}
+
+func (m *ComplianceManager) PbmCheckRollupCompliance(ctx *simulator.Context, req *types.PbmCheckRollupCompliance) soap.HasFault {
+	body := new(methods.PbmCheckRollupComplianceBody)
+	body.Res = new(types.PbmCheckRollupComplianceResponse)
+
+	check := func(entity types.PbmServerObjectRef) types.PbmComplianceResult {
+		res := m.check(ctx, entity, nil)
+		m.results[complianceKey(entity)] = res
+		return res.PbmComplianceResult
+	}
+
+	for _, entity := range req.Entity {
+		body.Res.Returnval = append(body.Res.Returnval, m.rollup(ctx, entity, check))
+	}
+
+	return body
+}
+
+func (m *ComplianceManager) PbmFetchRollupComplianceResult(ctx *simulator.Context, req *types.PbmFetchRollupComplianceResult) soap.HasFault {
+	body := new(methods.PbmFetchRollupComplianceResultBody)
+	body.Res = new(types.PbmFetchRollupComplianceResultResponse)
+
+	fetch := func(entity types.PbmServerObjectRef) types.PbmComplianceResult {
+		return m.fetch(ctx, entity)
+	}
+
+	for _, entity := range req.Entity {
+		body.Res.Returnval = append(body.Res.Returnval, m.rollup(ctx, entity, fetch))
+	}
+
+	return body
+}
@@ -68,6 +68,11 @@ func New() *simulator.Registry {
 		ManagedObjectReference: content.PlacementSolver,
 	})
 
+	r.Put(&ComplianceManager{
+		ManagedObjectReference: content.ComplianceManager,
+		results:                make(map[types.PbmServerObjectRef]*complianceResult),
+	})
+
 	return r
 }
 
@@ -119,6 +124,19 @@ func getK8sCompliantNameForPolicy(policyName string) string {
 	return convertedString
 }
 
+// profile returns the capability profile with the given ID, or nil if not found.
+func (m *ProfileManager) profile(id string) *types.PbmCapabilityProfile {
+	for i := range m.profiles {
+		if b, ok := m.profiles[i].(types.BasePbmCapabilityProfile); ok {
+			p := b.GetPbmCapabilityProfile()
+			if p.ProfileId.UniqueId == id {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
 func (m *ProfileManager) init(_ *simulator.Registry) {
 	m.profiles = slices.Clone(vcenter67DefaultProfiles)
 	// Set K8s compliant names for default profiles
@@ -127,8 +145,9 @@ func (m *ProfileManager) init(_ *simulator.Registry) {
 		if !ok {
 			continue
 		}
-		p := b.GetPbmCapabilityProfile()
+		p := *b.GetPbmCapabilityProfile() // copy, as profiles can be updated
 		p.K8sCompliantName = getK8sCompliantNameForPolicy(p.Name)
+		m.profiles[i] = &p
 	}
 
 	// Ensure the default encryption profile has the encryption IOFILTER as this
@@ -199,17 +218,62 @@ func (m *ProfileManager) PbmQueryProfileDetails(req *types.PbmQueryProfileDetail
 	return body
 }
 
-func (m *ProfileManager) PbmQueryAssociatedProfile(req *types.PbmQueryAssociatedProfile) soap.HasFault {
+func (m *ProfileManager) PbmQueryAssociatedProfile(ctx *simulator.Context, req *types.PbmQueryAssociatedProfile) soap.HasFault {
 	body := new(methods.PbmQueryAssociatedProfileBody)
 	body.Res = new(types.PbmQueryAssociatedProfileResponse)
 
+	if id := associatedProfile(ctx, req.Entity); id != "" {
+		body.Res.Returnval = []types.PbmProfileId{{UniqueId: id}}
+	}
+
 	return body
 }
 
-func (m *ProfileManager) PbmQueryAssociatedProfiles(req *types.PbmQueryAssociatedProfiles) soap.HasFault {
+func (m *ProfileManager) PbmQueryAssociatedProfiles(ctx *simulator.Context, req *types.PbmQueryAssociatedProfiles) soap.HasFault {
 	body := new(methods.PbmQueryAssociatedProfilesBody)
 	body.Res = new(types.PbmQueryAssociatedProfilesResponse)
 
+	for _, entity := range req.Entities {
+		res := types.PbmQueryProfileResult{Object: entity}
+
+		if id := associatedProfile(ctx, entity); id != "" {
+			res.ProfileId = []types.PbmProfileId{{UniqueId: id}}
+		}
+
+		body.Res.Returnval = append(body.Res.Returnval, res)
+	}
+
+	return body
+}
+
+func (m *ProfileManager) PbmQueryAssociatedEntity(ctx *simulator.Context, req *types.PbmQueryAssociatedEntity) soap.HasFault {
+	body := new(methods.PbmQueryAssociatedEntityBody)
+
+	if m.profile(req.Profile.UniqueId) == nil {
+		body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "profile"})
+		return body
+	}
+
+	body.Res = &types.PbmQueryAssociatedEntityResponse{
+		Returnval: associatedEntities(ctx, req.Profile.UniqueId, req.EntityType),
+	}
+
+	return body
+}
+
+func (m *ProfileManager) PbmQueryAssociatedEntities(ctx *simulator.Context, req *types.PbmQueryAssociatedEntities) soap.HasFault {
+	body := new(methods.PbmQueryAssociatedEntitiesBody)
+	body.Res = new(types.PbmQueryAssociatedEntitiesResponse)
+
+	for _, id := range req.Profiles {
+		for _, entity := range associatedEntities(ctx, id.UniqueId, "") {
+			body.Res.Returnval = append(body.Res.Returnval, types.PbmQueryProfileResult{
+				Object:    entity,
+				ProfileId: []types.PbmProfileId{id},
+			})
+		}
+	}
+
 	return body
 }
 
@@ -278,6 +342,34 @@ func (m *ProfileManager) PbmCreate(ctx *simulator.Context, req *types.PbmCreate)
 	return body
 }
 
+func (m *ProfileManager) PbmUpdate(ctx *simulator.Context, req *types.PbmUpdate) soap.HasFault {
+	body := new(methods.PbmUpdateBody)
+
+	p := m.profile(req.ProfileId.UniqueId)
+	if p == nil {
+		body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "profileId"})
+		return body
+	}
+
+	spec := req.UpdateSpec
+	if spec.Name != "" {
+		p.Name = spec.Name
+	}
+	if spec.Description != "" {
+		p.Description = spec.Description
+	}
+	if spec.Constraints != nil {
+		p.Constraints = spec.Constraints
+	}
+	p.GenerationId++
+	p.LastUpdatedTime = time.Now()
+	p.LastUpdatedBy = ctx.Session.UserName
+
+	body.Res = new(types.PbmUpdateResponse)
+
+	return body
+}
+
 func (m *ProfileManager) PbmDelete(req *types.PbmDelete) soap.HasFault {
 	body := new(methods.PbmDeleteBody)
 
@@ -371,47 +463,104 @@ type PlacementSolver struct {
 	vim.ManagedObjectReference
 }
 
+// hubs returns the hubs to search, all Datastores if none are specified.
+func (m *PlacementSolver) hubs(ctx *simulator.Context, search []types.PbmPlacementHub) ([]*hub, *soap.Fault) {
+	if len(search) == 0 {
+		for _, ds := range ctx.For(vim25.Path).Map.All("Datastore") {
+			ref := ds.Reference()
+			search = append(search, types.PbmPlacementHub{HubType: ref.Type, HubId: ref.Value})
+		}
+	}
+
+	var (
+		hubs    []*hub
+		invalid []types.PbmPlacementHub
+	)
+
+	for _, ph := range search {
+		h := newHub(ctx, vim.ManagedObjectReference{Type: ph.HubType, Value: ph.HubId})
+		if h == nil {
+			invalid = append(invalid, ph)
+			continue
+		}
+		hubs = append(hubs, h)
+	}
+
+	if len(invalid) != 0 {
+		return nil, simulator.Fault("", &types.PbmNonExistentHubs{Hubs: invalid})
+	}
+
+	return hubs, nil
+}
+
+// check evaluates the requirements against each hub.
+func (m *PlacementSolver) check(hubs []*hub, req []types.BasePbmCapabilityConstraints) []types.PbmPlacementCompatibilityResult {
+	var res []types.PbmPlacementCompatibilityResult
+
+	for _, h := range hubs {
+		r := types.PbmPlacementCompatibilityResult{Hub: h.placementHub()}
+
+		for _, constraints := range req {
+			r.Error = append(r.Error, h.check(constraints)...)
+		}
+
+		res = append(res, r)
+	}
+
+	return res
+}
+
 func (m *PlacementSolver) PbmCheckRequirements(ctx *simulator.Context, req *types.PbmCheckRequirements) soap.HasFault {
 	body := new(methods.PbmCheckRequirementsBody)
-	body.Res = new(types.PbmCheckRequirementsResponse)
-
-	for _, ds := range ctx.For(vim25.Path).Map.All("Datastore") {
-		// TODO: filter
-		ref := ds.Reference()
-		body.Res.Returnval = append(body.Res.Returnval, types.PbmPlacementCompatibilityResult{
-			Hub: types.PbmPlacementHub{
-				HubType: ref.Type,
-				HubId:   ref.Value,
-			},
-			MatchingResources: nil,
-			HowMany:           0,
-			Utilization:       nil,
-			Warning:           nil,
-			Error:             nil,
-		})
+
+	pm := ctx.Map.Get(content.ProfileManager).(*ProfileManager)
+
+	var constraints []types.BasePbmCapabilityConstraints
+
+	for _, r := range req.PlacementSubjectRequirement {
+		switch r := r.(type) {
+		case *types.PbmPlacementCapabilityProfileRequirement:
+			p := pm.profile(r.ProfileId.UniqueId)
+			if p == nil {
+				body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "profileId"})
+				return body
+			}
+			constraints = append(constraints, p.Constraints)
+		case *types.PbmPlacementCapabilityConstraintsRequirement:
+			constraints = append(constraints, r.Constraints)
+		}
+	}
+
+	hubs, fault := m.hubs(ctx, req.HubsToSearch)
+	if fault != nil {
+		body.Fault_ = fault
+		return body
+	}
+
+	body.Res = &types.PbmCheckRequirementsResponse{
+		Returnval: m.check(hubs, constraints),
 	}
 
 	return body
 }
 
-func (m *PlacementSolver) PbmCheckCompatibility(ctx *simulator.Context, _ *types.PbmCheckCompatibility) soap.HasFault {
+func (m *PlacementSolver) PbmCheckCompatibility(ctx *simulator.Context, req *types.PbmCheckCompatibility) soap.HasFault {
 	body := new(methods.PbmCheckCompatibilityBody)
-	body.Res = new(types.PbmCheckCompatibilityResponse)
-
-	for _, ds := range ctx.For(vim25.Path).Map.All("Datastore") {
-		// TODO: filter
-		ref := ds.Reference()
-		body.Res.Returnval = append(body.Res.Returnval, types.PbmPlacementCompatibilityResult{
-			Hub: types.PbmPlacementHub{
-				HubType: ref.Type,
-				HubId:   ref.Value,
-			},
-			MatchingResources: nil,
-			HowMany:           0,
-			Utilization:       nil,
-			Warning:           nil,
-			Error:             nil,
-		})
+
+	p := ctx.Map.Get(content.ProfileManager).(*ProfileManager).profile(req.Profile.UniqueId)
+	if p == nil {
+		body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "profile"})
+		return body
+	}
+
+	hubs, fault := m.hubs(ctx, req.HubsToSearch)
+	if fault != nil {
+		body.Fault_ = fault
+		return body
+	}
+
+	body.Res = &types.PbmCheckCompatibilityResponse{
+		Returnval: m.check(hubs, []types.BasePbmCapabilityConstraints{p.Constraints}),
 	}
 
 	return body
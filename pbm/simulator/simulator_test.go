@@ -6,17 +6,27 @@ package simulator
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/crypto"
+	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/pbm"
 	"github.com/vmware/govmomi/pbm/types"
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
 	vim "github.com/vmware/govmomi/vim25/types"
 )
@@ -301,3 +311,330 @@ func TestSimulator(t *testing.T) {
 	}
 	t.Logf("Profile: %+v successfully deleted", []types.PbmProfileId{*vsanProfileID, *vsansiocProfileID})
 }
+
+func createProfile(ctx context.Context, t *testing.T, pc *pbm.Client, name string, caps ...pbm.Capability) types.PbmProfileId {
+	spec, err := pbm.CreateCapabilityProfileSpec(pbm.CapabilityProfileCreateSpec{
+		Name:           name,
+		Category:       string(types.PbmProfileCategoryEnumREQUIREMENT),
+		CapabilityList: caps,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := pc.CreateProfile(ctx, *spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return *id
+}
+
+// compatible returns the names of compatible datastores and the error messages of incompatible datastores.
+func compatible(ctx context.Context, t *testing.T, c *vim25.Client, pc *pbm.Client, id types.PbmProfileId) ([]string, []string) {
+	res, err := pc.CheckCompatibility(ctx, nil, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	datastores, err := find.NewFinder(c).DatastoreList(ctx, "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := make(map[string]string)
+	for _, ds := range datastores {
+		name[ds.Reference().Value] = ds.Name()
+	}
+
+	var names, errors []string
+	for _, r := range res {
+		if len(r.Error) == 0 {
+			names = append(names, name[r.Hub.HubId])
+		}
+		for _, e := range r.Error {
+			errors = append(errors, e.LocalizedMessage)
+		}
+	}
+
+	sort.Strings(names)
+	return names, errors
+}
+
+func TestPlacementCompatibility(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		pc, err := pbm.NewClient(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		finder := find.NewFinder(c)
+		ds, err := finder.Datastore(ctx, "LocalDS_0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Tag based placement
+		rc := rest.NewClient(c)
+		if err = rc.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		tm := tags.NewManager(rc)
+		cat, err := tm.CreateCategory(ctx, &tags.Category{Name: "tier", Cardinality: "SINGLE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gold, err := tm.CreateTag(ctx, &tags.Tag{Name: "gold", CategoryID: cat})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tagProfile := createProfile(ctx, t, pc, "gold", pbm.Capability{
+			ID:        "tier",
+			Namespace: "http://www.vmware.com/storage/tag",
+			PropertyList: []pbm.Property{{
+				ID:       "com.vmware.storage.tag.tier.property",
+				Value:    "gold,silver",
+				DataType: "set",
+			}},
+		})
+
+		names, errors := compatible(ctx, t, c, pc, tagProfile)
+		assert.Empty(t, names)
+		assert.NotEmpty(t, errors)
+
+		if err = tm.AttachTag(ctx, gold, ds); err != nil {
+			t.Fatal(err)
+		}
+
+		names, _ = compatible(ctx, t, c, pc, tagProfile)
+		assert.Equal(t, []string{"LocalDS_0"}, names)
+
+		// vSAN capabilities
+		cluster, err := finder.ClusterComputeResource(ctx, "DC0_C0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		task, err := cluster.Reconfigure(ctx, &vim.ClusterConfigSpecEx{
+			VsanConfig: &vim.VsanClusterConfigInfo{Enabled: vim.NewBool(true)},
+		}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		vsanDefault, err := pc.ProfileIDByName(ctx, "vSAN Default Storage Policy")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names, _ = compatible(ctx, t, c, pc, types.PbmProfileId{UniqueId: vsanDefault})
+		assert.Equal(t, []string{"vsanDatastore"}, names)
+
+		ftt := func(n string) pbm.Capability {
+			return pbm.Capability{
+				ID:           "hostFailuresToTolerate",
+				Namespace:    "VSAN",
+				PropertyList: []pbm.Property{{ID: "hostFailuresToTolerate", Value: n, DataType: "int"}},
+			}
+		}
+
+		ftt2 := createProfile(ctx, t, pc, "ftt2", ftt("2"))
+		names, errors = compatible(ctx, t, c, pc, ftt2)
+		assert.Empty(t, names)
+		assert.True(t, slicesContain(errors, "requires 2 more usable fault domains"), errors)
+
+		stripe := createProfile(ctx, t, pc, "stripe", pbm.Capability{
+			ID:           "stripeWidth",
+			Namespace:    "VSAN",
+			PropertyList: []pbm.Property{{ID: "stripeWidth", Value: "13", DataType: "int"}},
+		})
+		names, _ = compatible(ctx, t, c, pc, stripe)
+		assert.Empty(t, names)
+
+		// Encryption requires a key provider
+		enc := types.PbmProfileId{UniqueId: DefaultEncryptionProfileID}
+		names, errors = compatible(ctx, t, c, pc, enc)
+		assert.Empty(t, names)
+		assert.True(t, slicesContain(errors, "key provider"), errors)
+
+		m := crypto.NewManagerKmip(c)
+		if err = m.RegisterKmsCluster(ctx, "my-kms", vim.KmipClusterInfoKmsManagementTypeUnknown); err != nil {
+			t.Fatal(err)
+		}
+
+		names, errors = compatible(ctx, t, c, pc, enc)
+		assert.NotEmpty(t, names)
+		assert.Empty(t, errors)
+
+		// Requirements are combined
+		res, err := pc.CheckRequirements(ctx, nil, nil, []types.BasePbmPlacementRequirement{
+			&types.PbmPlacementCapabilityProfileRequirement{ProfileId: tagProfile},
+			&types.PbmPlacementCapabilityProfileRequirement{ProfileId: enc},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []types.PbmPlacementHub{{HubType: "Datastore", HubId: ds.Reference().Value}}, res.CompatibleDatastores())
+
+		_, err = pc.CheckCompatibility(ctx, []types.PbmPlacementHub{{HubType: "Datastore", HubId: "enoent"}}, enc)
+		assert.Error(t, err)
+
+		_, err = pc.CheckCompatibility(ctx, nil, types.PbmProfileId{UniqueId: "enoent"})
+		assert.Error(t, err)
+	})
+}
+
+func slicesContain(messages []string, substr string) bool {
+	for _, msg := range messages {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCompliance(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		pc, err := pbm.NewClient(ctx, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		devices, err := vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		disk := devices.SelectByType((*vim.VirtualDisk)(nil))[0]
+
+		home := types.PbmServerObjectRef{
+			ObjectType: string(types.PbmObjectTypeVirtualMachine),
+			Key:        vm.Reference().Value,
+		}
+		vdisk := types.PbmServerObjectRef{
+			ObjectType: string(types.PbmObjectTypeVirtualDiskId),
+			Key:        fmt.Sprintf("%s:%d", home.Key, disk.GetVirtualDevice().Key),
+		}
+
+		res, err := pc.FetchComplianceResult(ctx, []types.PbmServerObjectRef{home})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(types.PbmComplianceStatusNotApplicable), res[0].ComplianceStatus)
+
+		sioc := createProfile(ctx, t, pc, "sioc", pbm.Capability{
+			ID:           "spm@DATASTOREIOCONTROL",
+			Namespace:    "spm",
+			PropertyList: []pbm.Property{{ID: "shares", Value: "2000", DataType: "int"}},
+		})
+		vsanDefault, err := pc.ProfileIDByName(ctx, "vSAN Default Storage Policy")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Associate the SIOC policy with the VM home and the vSAN policy with the disk
+		spec := vim.VirtualMachineConfigSpec{
+			VmProfile: []vim.BaseVirtualMachineProfileSpec{
+				&vim.VirtualMachineDefinedProfileSpec{ProfileId: sioc.UniqueId},
+			},
+			DeviceChange: []vim.BaseVirtualDeviceConfigSpec{
+				&vim.VirtualDeviceConfigSpec{
+					Operation: vim.VirtualDeviceConfigSpecOperationEdit,
+					Device:    disk,
+					Profile: []vim.BaseVirtualMachineProfileSpec{
+						&vim.VirtualMachineDefinedProfileSpec{ProfileId: vsanDefault},
+					},
+				},
+			},
+		}
+		task, err := vm.Reconfigure(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		ids, err := pc.QueryAssociatedProfile(ctx, vdisk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []types.PbmProfileId{{UniqueId: vsanDefault}}, ids)
+
+		entities, err := pc.QueryAssociatedEntity(ctx, sioc, string(types.PbmObjectTypeVirtualMachine))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []types.PbmServerObjectRef{home}, entities)
+
+		res, err = pc.FetchComplianceResult(ctx, []types.PbmServerObjectRef{home, vdisk})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(types.PbmComplianceStatusCompliant), res[0].ComplianceStatus)
+		assert.Equal(t, string(types.PbmComplianceStatusNonCompliant), res[1].ComplianceStatus)
+		assert.NotEmpty(t, res[1].ViolatedPolicies)
+
+		// Check against a policy other than the associated policy
+		res, err = pc.CheckCompliance(ctx, []types.PbmServerObjectRef{home}, &types.PbmProfileId{UniqueId: vsanDefault})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(types.PbmComplianceStatusNonCompliant), res[0].ComplianceStatus)
+		assert.True(t, res[0].Mismatch)
+
+		// Updating the policy makes the result out of date until checked again
+		err = pc.UpdateProfile(ctx, sioc, types.PbmCapabilityProfileUpdateSpec{Description: "updated"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err = pc.FetchComplianceResult(ctx, []types.PbmServerObjectRef{home})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(types.PbmComplianceStatusOutOfDate), res[0].ComplianceStatus)
+
+		res, err = pc.CheckCompliance(ctx, []types.PbmServerObjectRef{home}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(types.PbmComplianceStatusCompliant), res[0].ComplianceStatus)
+
+		res, err = pc.FetchComplianceResult(ctx, []types.PbmServerObjectRef{home})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(types.PbmComplianceStatusCompliant), res[0].ComplianceStatus)
+
+		// Removing the association
+		spec = vim.VirtualMachineConfigSpec{
+			VmProfile: []vim.BaseVirtualMachineProfileSpec{&vim.VirtualMachineEmptyProfileSpec{}},
+		}
+		task, err = vm.Reconfigure(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		ids, err = pc.QueryAssociatedProfile(ctx, home)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, ids)
+
+		res, err = pc.FetchComplianceResult(ctx, []types.PbmServerObjectRef{home})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(types.PbmComplianceStatusNotApplicable), res[0].ComplianceStatus)
+	})
+}
@@ -141,7 +141,7 @@ func (svm *simVM) syncNetworkConfigToVMGuestProperties() error {
 	return nil
 }
 
-func (svm *simVM) prepareGuestOperation(auth types.BaseGuestAuthentication) types.BaseMethodFault {
+func (svm *simVM) prepareGuestOperation(ctx *Context, auth types.BaseGuestAuthentication) types.BaseMethodFault {
 	if svm == nil || svm.c == nil || svm.c.id == "" {
 		return new(types.GuestOperationsUnavailable)
 	}
@@ -158,6 +158,10 @@ func (svm *simVM) prepareGuestOperation(auth types.BaseGuestAuthentication) type
 		if creds.Username == "" || creds.Password == "" {
 			return new(types.InvalidGuestLogin)
 		}
+	case *types.SAMLTokenAuthentication:
+		if _, fault := svm.vm.samlTokenUser(ctx, creds); fault != nil {
+			return fault
+		}
 	default:
 		return new(types.InvalidGuestLogin)
 	}
@@ -416,7 +420,7 @@ func (svm *simVM) exec(ctx *Context, auth types.BaseGuestAuthentication, args []
 		return "", nil
 	}
 
-	fault := svm.prepareGuestOperation(auth)
+	fault := svm.prepareGuestOperation(ctx, auth)
 	if fault != nil {
 		return "", fault
 	}
@@ -451,7 +455,7 @@ func guestDownload(id string, file string, w http.ResponseWriter) error {
 
 const guestPrefix = "/guestFile/"
 
-// ServeGuest handles container guest file upload/download
+// ServeGuest handles container and toolbox guest file upload/download
 func ServeGuest(w http.ResponseWriter, r *http.Request) {
 	// Real vCenter form: /guestFile?id=139&token=...
 	// vcsim form:        /guestFile/tmp/foo/bar?id=ebc8837b8cb6&token=...
@@ -460,6 +464,15 @@ func ServeGuest(w http.ResponseWriter, r *http.Request) {
 	file := strings.TrimPrefix(r.URL.Path, guestPrefix[:len(guestPrefix)-1])
 	var err error
 
+	if x := takeGuestTransfer(id); x != nil {
+		// in-process toolbox, see ToolboxBackingOptionKey
+		if err = x.serve(w, r, file); err != nil {
+			log.Printf("%s %s: %s", r.Method, r.URL, err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPut:
 		err = guestUpload(id, file, r)
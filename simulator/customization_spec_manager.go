@@ -5,8 +5,11 @@
 package simulator
 
 import (
+	"bytes"
 	"encoding/pem"
 	"fmt"
+	"slices"
+	"strconv"
 	"sync/atomic"
 	"time"
 
@@ -15,6 +18,7 @@ import (
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vim25/xml"
 )
 
 var DefaultCustomizationSpec = []types.CustomizationSpecItem{
@@ -238,7 +242,7 @@ type CustomizationSpecManager struct {
 }
 
 func (m *CustomizationSpecManager) init(r *Registry) {
-	m.items = DefaultCustomizationSpec
+	m.items = slices.Clone(DefaultCustomizationSpec)
 
 	// Real VC is different DN, X509v3 extensions, etc.
 	// This is still useful for testing []byte of DER encoded cert over SOAP
@@ -313,7 +317,7 @@ func (m *CustomizationSpecManager) CreateCustomizationSpec(ctx *Context, req *ty
 		}
 	}
 
-	m.items = append(m.items, req.Item)
+	m.items = append(m.items, m.update(req.Item, ""))
 	body.Res = new(types.CreateCustomizationSpecResponse)
 
 	return body
@@ -324,7 +328,11 @@ func (m *CustomizationSpecManager) OverwriteCustomizationSpec(ctx *Context, req
 
 	for i, item := range m.items {
 		if item.Info.Name == req.Item.Info.Name {
-			m.items[i] = req.Item
+			if v := req.Item.Info.ChangeVersion; v != "" && v != item.Info.ChangeVersion {
+				body.Fault_ = Fault("", new(types.ConcurrentAccess))
+				return body
+			}
+			m.items[i] = m.update(req.Item, item.Info.ChangeVersion)
 			body.Res = new(types.OverwriteCustomizationSpecResponse)
 			return body
 		}
@@ -335,6 +343,114 @@ func (m *CustomizationSpecManager) OverwriteCustomizationSpec(ctx *Context, req
 	return body
 }
 
+// update sets the ChangeVersion and LastUpdateTime of the given item.
+func (m *CustomizationSpecManager) update(item types.CustomizationSpecItem, version string) types.CustomizationSpecItem {
+	now := time.Now()
+	v, _ := strconv.ParseInt(version, 10, 64)
+	item.Info.ChangeVersion = strconv.FormatInt(max(v+1, now.Unix()), 10)
+	item.Info.LastUpdateTime = &now
+	return item
+}
+
+func (m *CustomizationSpecManager) find(name string) int {
+	return slices.IndexFunc(m.items, func(item types.CustomizationSpecItem) bool {
+		return item.Info.Name == name
+	})
+}
+
+func (m *CustomizationSpecManager) DeleteCustomizationSpec(ctx *Context, req *types.DeleteCustomizationSpec) soap.HasFault {
+	body := new(methods.DeleteCustomizationSpecBody)
+
+	i := m.find(req.Name)
+	if i == -1 {
+		body.Fault_ = Fault("", new(types.NotFound))
+		return body
+	}
+
+	m.items = slices.Delete(m.items, i, i+1)
+	body.Res = new(types.DeleteCustomizationSpecResponse)
+
+	return body
+}
+
+func (m *CustomizationSpecManager) DuplicateCustomizationSpec(ctx *Context, req *types.DuplicateCustomizationSpec) soap.HasFault {
+	body := new(methods.DuplicateCustomizationSpecBody)
+
+	i := m.find(req.Name)
+	if i == -1 {
+		body.Fault_ = Fault("", new(types.NotFound))
+		return body
+	}
+	if m.find(req.NewName) != -1 {
+		body.Fault_ = Fault("", &types.AlreadyExists{Name: req.NewName})
+		return body
+	}
+
+	var item types.CustomizationSpecItem
+	deepCopy(&m.items[i], &item)
+	item.Info.Name = req.NewName
+	m.items = append(m.items, m.update(item, ""))
+	body.Res = new(types.DuplicateCustomizationSpecResponse)
+
+	return body
+}
+
+func (m *CustomizationSpecManager) RenameCustomizationSpec(ctx *Context, req *types.RenameCustomizationSpec) soap.HasFault {
+	body := new(methods.RenameCustomizationSpecBody)
+
+	i := m.find(req.Name)
+	if i == -1 {
+		body.Fault_ = Fault("", new(types.NotFound))
+		return body
+	}
+	if m.find(req.NewName) != -1 {
+		body.Fault_ = Fault("", &types.AlreadyExists{Name: req.NewName})
+		return body
+	}
+
+	m.items[i].Info.Name = req.NewName
+	m.items[i] = m.update(m.items[i], m.items[i].Info.ChangeVersion)
+	body.Res = new(types.RenameCustomizationSpecResponse)
+
+	return body
+}
+
+// CustomizationSpecItemToXml encodes the item using the vim25 XML encoding,
+// rather than the format used by real vCenter.
+func (m *CustomizationSpecManager) CustomizationSpecItemToXml(ctx *Context, req *types.CustomizationSpecItemToXml) soap.HasFault {
+	body := new(methods.CustomizationSpecItemToXmlBody)
+
+	b, err := xml.Marshal(req.Item)
+	if err != nil {
+		body.Fault_ = Fault(err.Error(), new(types.SystemError))
+		return body
+	}
+
+	body.Res = &types.CustomizationSpecItemToXmlResponse{
+		Returnval: xml.Header + string(b),
+	}
+
+	return body
+}
+
+func (m *CustomizationSpecManager) XmlToCustomizationSpecItem(ctx *Context, req *types.XmlToCustomizationSpecItem) soap.HasFault {
+	body := new(methods.XmlToCustomizationSpecItemBody)
+
+	var item types.CustomizationSpecItem
+	dec := xml.NewDecoder(bytes.NewReader([]byte(req.SpecItemXml)))
+	dec.TypeFunc = types.TypeFunc()
+	if err := dec.Decode(&item); err != nil {
+		body.Fault_ = Fault(err.Error(), &types.CustomizationFault{})
+		return body
+	}
+
+	body.Res = &types.XmlToCustomizationSpecItemResponse{
+		Returnval: item,
+	}
+
+	return body
+}
+
 func (m *CustomizationSpecManager) Get() mo.Reference {
 	clone := *m
 
@@ -0,0 +1,274 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/xml"
+	"slices"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// guestAlias is a guest alias store entry, as managed by VGAuth in a real guest
+type guestAlias struct {
+	username string
+	cert     string
+	mapped   bool
+	info     types.GuestAuthAliasInfo
+}
+
+type GuestAliasManager struct {
+	mo.GuestAliasManager
+}
+
+// GuestAuthManager validates guest credentials, including SAML tokens mapped via GuestAliasManager
+type GuestAuthManager struct {
+	mo.GuestAuthManager
+}
+
+// prepareGuestOperation validates auth against the toolbox or container backing the VM
+func (vm *VirtualMachine) prepareGuestOperation(ctx *Context, auth types.BaseGuestAuthentication) types.BaseMethodFault {
+	if tbx := vm.tools(); tbx != nil {
+		_, _, fault := tbx.prepareGuestOperation(ctx, auth)
+		return fault
+	}
+
+	return vm.svm.prepareGuestOperation(ctx, auth)
+}
+
+// samlTokenUser returns the guest username for the given SAML token, which must match an alias
+// by signing certificate and subject. The token signature and lifetime are not validated.
+func (vm *VirtualMachine) samlTokenUser(ctx *Context, auth *types.SAMLTokenAuthentication) (string, types.BaseMethodFault) {
+	var token struct {
+		Subject string   `xml:"Subject>NameID"`
+		Certs   []string `xml:"Signature>KeyInfo>X509Data>X509Certificate"`
+	}
+
+	if err := xml.Unmarshal([]byte(auth.Token), &token); err != nil || len(token.Certs) == 0 {
+		return "", new(types.InvalidGuestLogin)
+	}
+
+	subject := strings.TrimSpace(token.Subject)
+	cert := strings.Join(strings.Fields(token.Certs[0]), "")
+
+	username := ""
+
+	ctx.WithLock(vm, func() {
+		for _, alias := range vm.aliases {
+			if alias.cert != cert {
+				continue
+			}
+
+			if named, ok := alias.info.Subject.(*types.GuestAuthNamedSubject); ok && named.Name != subject {
+				continue
+			}
+
+			if auth.Username == "" {
+				if alias.mapped {
+					username = alias.username
+					return
+				}
+				continue
+			}
+
+			if alias.username == auth.Username {
+				username = alias.username
+				return
+			}
+		}
+	})
+
+	if username == "" {
+		return "", new(types.InvalidGuestLogin)
+	}
+
+	return username, nil
+}
+
+func sameSubject(a, b types.BaseGuestAuthSubject) bool {
+	x, xok := a.(*types.GuestAuthNamedSubject)
+	y, yok := b.(*types.GuestAuthNamedSubject)
+	if xok && yok {
+		return x.Name == y.Name
+	}
+	return xok == yok
+}
+
+func (m *GuestAliasManager) AddGuestAlias(ctx *Context, req *types.AddGuestAlias) soap.HasFault {
+	body := new(methods.AddGuestAliasBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if fault := vm.prepareGuestOperation(ctx, req.Auth); fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	if req.Username == "" || req.Base64Cert == "" || req.AliasInfo.Subject == nil {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "aliasInfo"})
+		return body
+	}
+
+	ctx.WithLock(vm, func() {
+		for _, alias := range vm.aliases {
+			if alias.username == req.Username && alias.cert == req.Base64Cert && sameSubject(alias.info.Subject, req.AliasInfo.Subject) {
+				body.Fault_ = Fault("", &types.AlreadyExists{Name: req.Username})
+				return
+			}
+		}
+
+		vm.aliases = append(vm.aliases, guestAlias{
+			username: req.Username,
+			cert:     req.Base64Cert,
+			mapped:   req.MapCert,
+			info:     req.AliasInfo,
+		})
+
+		body.Res = new(types.AddGuestAliasResponse)
+	})
+
+	return body
+}
+
+func (m *GuestAliasManager) RemoveGuestAlias(ctx *Context, req *types.RemoveGuestAlias) soap.HasFault {
+	body := new(methods.RemoveGuestAliasBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if fault := vm.prepareGuestOperation(ctx, req.Auth); fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	ctx.WithLock(vm, func() {
+		n := len(vm.aliases)
+		vm.aliases = slices.DeleteFunc(vm.aliases, func(alias guestAlias) bool {
+			return alias.username == req.Username && alias.cert == req.Base64Cert && sameSubject(alias.info.Subject, req.Subject)
+		})
+
+		if len(vm.aliases) == n {
+			body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "subject"})
+			return
+		}
+
+		body.Res = new(types.RemoveGuestAliasResponse)
+	})
+
+	return body
+}
+
+func (m *GuestAliasManager) RemoveGuestAliasByCert(ctx *Context, req *types.RemoveGuestAliasByCert) soap.HasFault {
+	body := new(methods.RemoveGuestAliasByCertBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if fault := vm.prepareGuestOperation(ctx, req.Auth); fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	ctx.WithLock(vm, func() {
+		n := len(vm.aliases)
+		vm.aliases = slices.DeleteFunc(vm.aliases, func(alias guestAlias) bool {
+			return alias.username == req.Username && alias.cert == req.Base64Cert
+		})
+
+		if len(vm.aliases) == n {
+			body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "base64Cert"})
+			return
+		}
+
+		body.Res = new(types.RemoveGuestAliasByCertResponse)
+	})
+
+	return body
+}
+
+func (m *GuestAliasManager) ListGuestAliases(ctx *Context, req *types.ListGuestAliases) soap.HasFault {
+	body := new(methods.ListGuestAliasesBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if fault := vm.prepareGuestOperation(ctx, req.Auth); fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	var res []types.GuestAliases
+
+	ctx.WithLock(vm, func() {
+		for _, alias := range vm.aliases {
+			if alias.username != req.Username {
+				continue
+			}
+
+			i := slices.IndexFunc(res, func(a types.GuestAliases) bool { return a.Base64Cert == alias.cert })
+			if i == -1 {
+				res = append(res, types.GuestAliases{Base64Cert: alias.cert})
+				i = len(res) - 1
+			}
+
+			res[i].Aliases = append(res[i].Aliases, alias.info)
+		}
+	})
+
+	body.Res = &types.ListGuestAliasesResponse{Returnval: res}
+
+	return body
+}
+
+func (m *GuestAliasManager) ListGuestMappedAliases(ctx *Context, req *types.ListGuestMappedAliases) soap.HasFault {
+	body := new(methods.ListGuestMappedAliasesBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if fault := vm.prepareGuestOperation(ctx, req.Auth); fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	var res []types.GuestMappedAliases
+
+	ctx.WithLock(vm, func() {
+		for _, alias := range vm.aliases {
+			if !alias.mapped {
+				continue
+			}
+
+			i := slices.IndexFunc(res, func(a types.GuestMappedAliases) bool {
+				return a.Base64Cert == alias.cert && a.Username == alias.username
+			})
+			if i == -1 {
+				res = append(res, types.GuestMappedAliases{Base64Cert: alias.cert, Username: alias.username})
+				i = len(res) - 1
+			}
+
+			res[i].Subjects = append(res[i].Subjects, alias.info.Subject)
+		}
+	})
+
+	body.Res = &types.ListGuestMappedAliasesResponse{Returnval: res}
+
+	return body
+}
+
+func (m *GuestAuthManager) ValidateCredentialsInGuest(ctx *Context, req *types.ValidateCredentialsInGuest) soap.HasFault {
+	body := new(methods.ValidateCredentialsInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if fault := vm.prepareGuestOperation(ctx, req.Auth); fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	body.Res = new(types.ValidateCredentialsInGuestResponse)
+
+	return body
+}
@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -45,6 +46,36 @@ func (m *GuestOperationsManager) init(r *Registry) {
 	pm.Self = *m.ProcessManager
 	pm.Manager = process.NewManager()
 	r.Put(pm)
+
+	am := new(GuestAliasManager)
+	if m.AliasManager == nil {
+		m.AliasManager = &types.ManagedObjectReference{
+			Type:  "GuestAliasManager",
+			Value: "guestOperationsAliasManager",
+		}
+	}
+	am.Self = *m.AliasManager
+	r.Put(am)
+
+	auth := new(GuestAuthManager)
+	if m.AuthManager == nil {
+		m.AuthManager = &types.ManagedObjectReference{
+			Type:  "GuestAuthManager",
+			Value: "guestOperationsAuthManager",
+		}
+	}
+	auth.Self = *m.AuthManager
+	r.Put(auth)
+
+	reg := new(GuestWindowsRegistryManager)
+	if m.GuestWindowsRegistryManager == nil {
+		m.GuestWindowsRegistryManager = &types.ManagedObjectReference{
+			Type:  "GuestWindowsRegistryManager",
+			Value: "guestOperationsRegistryManager",
+		}
+	}
+	reg.Self = *m.GuestWindowsRegistryManager
+	r.Put(reg)
 }
 
 type GuestFileManager struct {
@@ -67,7 +98,24 @@ func (m *GuestFileManager) InitiateFileTransferToGuest(ctx *Context, req *types.
 	body := new(methods.InitiateFileTransferToGuestBody)
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
-	err := vm.svm.prepareGuestOperation(req.Auth)
+
+	if tbx := vm.tools(); tbx != nil {
+		fault := tbx.initiateFileTransferToGuest(ctx, req.Auth, req.GuestFilePath, req.Overwrite)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+
+		attr, _ := req.FileAttributes.(*types.GuestPosixFileAttributes)
+
+		body.Res = &types.InitiateFileTransferToGuestResponse{
+			Returnval: tbx.transferURL(ctx, req.Auth, req.GuestFilePath, 0, attr),
+		}
+
+		return body
+	}
+
+	err := vm.svm.prepareGuestOperation(ctx, req.Auth)
 	if err != nil {
 		body.Fault_ = Fault("", err)
 		return body
@@ -84,7 +132,21 @@ func (m *GuestFileManager) InitiateFileTransferFromGuest(ctx *Context, req *type
 	body := new(methods.InitiateFileTransferFromGuestBody)
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
-	err := vm.svm.prepareGuestOperation(req.Auth)
+
+	if tbx := vm.tools(); tbx != nil {
+		info, fault := tbx.initiateFileTransferFromGuest(ctx, req.Auth, req.GuestFilePath)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+
+		info.Url = tbx.transferURL(ctx, req.Auth, req.GuestFilePath, info.Size, nil)
+		body.Res = &types.InitiateFileTransferFromGuestResponse{Returnval: *info}
+
+		return body
+	}
+
+	err := vm.svm.prepareGuestOperation(ctx, req.Auth)
 	if err != nil {
 		body.Fault_ = Fault("", err)
 		return body
@@ -110,13 +172,33 @@ func (m *GuestProcessManager) StartProgramInGuest(ctx *Context, req *types.Start
 	body := new(methods.StartProgramInGuestBody)
 
 	spec := req.Spec.(*types.GuestProgramSpec)
-	auth := req.Auth.(*types.NamePasswordAuthentication)
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	fault := vm.svm.prepareGuestOperation(auth)
+	if tbx := vm.tools(); tbx != nil {
+		pid, fault := tbx.startProgram(ctx, req.Auth, spec)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+
+		body.Res = &types.StartProgramInGuestResponse{Returnval: pid}
+
+		return body
+	}
+
+	fault := vm.svm.prepareGuestOperation(ctx, req.Auth)
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	var owner string
+	switch creds := req.Auth.(type) {
+	case *types.NamePasswordAuthentication:
+		owner = creds.Username
+	case *types.SAMLTokenAuthentication:
+		owner, _ = vm.samlTokenUser(ctx, creds) // validated by prepareGuestOperation
 	}
 
 	args := []string{"exec"}
@@ -140,7 +222,7 @@ func (m *GuestProcessManager) StartProgramInGuest(ctx *Context, req *types.Start
 	}
 
 	proc := process.New()
-	proc.Owner = auth.Username
+	proc.Owner = owner
 
 	pid, err := m.Start(start, proc)
 	if err != nil {
@@ -159,6 +241,19 @@ func (m *GuestProcessManager) ListProcessesInGuest(ctx *Context, req *types.List
 		Res: new(types.ListProcessesInGuestResponse),
 	}
 
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if tbx := vm.tools(); tbx != nil {
+		procs, fault := tbx.listProcesses(ctx, req.Auth, req.Pids)
+		if fault != nil {
+			return &methods.ListProcessesInGuestBody{Fault_: Fault("", fault)}
+		}
+
+		body.Res.Returnval = procs
+
+		return body
+	}
+
 	procs := m.List(req.Pids)
 
 	for _, proc := range procs {
@@ -184,6 +279,18 @@ func (m *GuestProcessManager) ListProcessesInGuest(ctx *Context, req *types.List
 func (m *GuestProcessManager) TerminateProcessInGuest(ctx *Context, req *types.TerminateProcessInGuest) soap.HasFault {
 	body := new(methods.TerminateProcessInGuestBody)
 
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if tbx := vm.tools(); tbx != nil {
+		if fault := tbx.terminateProcess(ctx, req.Auth, req.Pid); fault != nil {
+			body.Fault_ = Fault("", fault)
+		} else {
+			body.Res = new(types.TerminateProcessInGuestResponse)
+		}
+
+		return body
+	}
+
 	if m.Kill(req.Pid) {
 		body.Res = new(types.TerminateProcessInGuestResponse)
 	} else {
@@ -193,6 +300,37 @@ func (m *GuestProcessManager) TerminateProcessInGuest(ctx *Context, req *types.T
 	return body
 }
 
+func (m *GuestProcessManager) ReadEnvironmentVariableInGuest(ctx *Context, req *types.ReadEnvironmentVariableInGuest) soap.HasFault {
+	body := new(methods.ReadEnvironmentVariableInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	var env []string
+	var fault types.BaseMethodFault
+
+	if tbx := vm.tools(); tbx != nil {
+		env, fault = tbx.readEnvironment(ctx, req.Auth, req.Names)
+	} else {
+		var res string
+		res, fault = vm.svm.exec(ctx, req.Auth, []string{"env"})
+		for _, e := range strings.Split(res, "\n") {
+			name, _, _ := strings.Cut(e, "=")
+			if len(req.Names) == 0 || slices.Contains(req.Names, name) {
+				env = append(env, e)
+			}
+		}
+	}
+
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	body.Res = &types.ReadEnvironmentVariableInGuestResponse{Returnval: env}
+
+	return body
+}
+
 func (m *GuestFileManager) mktemp(ctx *Context, req *types.CreateTemporaryFileInGuest, dir bool) (string, types.BaseMethodFault) {
 	args := []string{"mktemp", fmt.Sprintf("--tmpdir=%s", req.DirectoryPath), req.Prefix + "vcsim-XXXXX" + req.Suffix}
 	if dir {
@@ -201,6 +339,10 @@ func (m *GuestFileManager) mktemp(ctx *Context, req *types.CreateTemporaryFileIn
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
+	if tbx := vm.tools(); tbx != nil {
+		return tbx.mktemp(ctx, req.Auth, req, dir)
+	}
+
 	return vm.svm.exec(ctx, req.Auth, args)
 }
 
@@ -286,6 +428,18 @@ func (m *GuestFileManager) ListFilesInGuest(ctx *Context, req *types.ListFilesIn
 		return body
 	}
 
+	if tbx := vm.tools(); tbx != nil {
+		res, fault := tbx.listFiles(ctx, req.Auth, req)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return body
+		}
+
+		body.Res = &types.ListFilesInGuestResponse{Returnval: *res}
+
+		return body
+	}
+
 	res, fault := vm.svm.exec(ctx, req.Auth, listFiles(req))
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
@@ -305,7 +459,12 @@ func (m *GuestFileManager) DeleteFileInGuest(ctx *Context, req *types.DeleteFile
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if tbx := vm.tools(); tbx != nil {
+		fault = tbx.deleteFile(ctx, req.Auth, req.FilePath)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -326,7 +485,12 @@ func (m *GuestFileManager) DeleteDirectoryInGuest(ctx *Context, req *types.Delet
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if tbx := vm.tools(); tbx != nil {
+		fault = tbx.deleteDirectory(ctx, req.Auth, req.DirectoryPath, req.Recursive)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -347,7 +511,12 @@ func (m *GuestFileManager) MakeDirectoryInGuest(ctx *Context, req *types.MakeDir
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if tbx := vm.tools(); tbx != nil {
+		fault = tbx.makeDirectory(ctx, req.Auth, req.DirectoryPath, req.CreateParentDirectories)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -369,7 +538,12 @@ func (m *GuestFileManager) MoveFileInGuest(ctx *Context, req *types.MoveFileInGu
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if tbx := vm.tools(); tbx != nil {
+		fault = tbx.move(ctx, req.Auth, vix.CommandMoveGuestFileEx, req.SrcFilePath, req.DstFilePath, req.Overwrite)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -387,7 +561,12 @@ func (m *GuestFileManager) MoveDirectoryInGuest(ctx *Context, req *types.MoveDir
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	_, fault := vm.svm.exec(ctx, req.Auth, args)
+	var fault types.BaseMethodFault
+	if tbx := vm.tools(); tbx != nil {
+		fault = tbx.move(ctx, req.Auth, vix.CommandMoveGuestDirectory, req.SrcDirectoryPath, req.DstDirectoryPath, false)
+	} else {
+		_, fault = vm.svm.exec(ctx, req.Auth, args)
+	}
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
 		return body
@@ -409,6 +588,16 @@ func (m *GuestFileManager) ChangeFileAttributesInGuest(ctx *Context, req *types.
 		return body
 	}
 
+	if tbx := vm.tools(); tbx != nil {
+		if fault := tbx.changeFileAttributes(ctx, req.Auth, req.GuestFilePath, attr); fault != nil {
+			body.Fault_ = Fault("", fault)
+		} else {
+			body.Res = new(types.ChangeFileAttributesInGuestResponse)
+		}
+
+		return body
+	}
+
 	if attr.Permissions != 0 {
 		args := []string{"chmod", fmt.Sprintf("%#o", attr.Permissions), req.GuestFilePath}
 
@@ -5,12 +5,29 @@
 package simulator
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/guest/toolbox"
+	"github.com/vmware/govmomi/object"
+	tbx "github.com/vmware/govmomi/toolbox"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -73,3 +90,768 @@ func TestFileInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestGuestOperationsToolbox(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("GOOS=%s", runtime.GOOS) // toolbox ListFiles is only implemented for linux
+	}
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		simVm := Map(ctx).Any("VirtualMachine").(*VirtualMachine)
+		vm := object.NewVirtualMachine(c, simVm.Reference())
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: ToolboxBackingOptionKey, Value: "TRUE"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		running, err := vm.IsToolsRunning(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !running {
+			t.Fatal("tools not running")
+		}
+
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+
+		tools, err := toolbox.NewClient(ctx, c, vm, auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// StartProgramInGuest, ListProcessesInGuest and file transfer of the process output
+		var out bytes.Buffer
+		cmd := &exec.Cmd{Path: "echo", Args: []string{"hello"}, Stdout: &out}
+		if err = tools.Run(ctx, cmd); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != "hello\n" {
+			t.Errorf("out=%q", out.String())
+		}
+
+		dir := t.TempDir()
+		name := filepath.Join(dir, "file.txt")
+		data := bytes.Repeat([]byte("vcsim"), hgfsTransferSize/2)
+
+		p := soap.DefaultUpload
+		p.ContentLength = int64(len(data))
+		if err = tools.Upload(ctx, bytes.NewReader(data), name, p, new(types.GuestPosixFileAttributes), false); err != nil {
+			t.Fatal(err)
+		}
+		err = tools.Upload(ctx, bytes.NewReader(data), name, p, new(types.GuestPosixFileAttributes), false)
+		if !fault.Is(err, &types.FileAlreadyExists{}) {
+			t.Errorf("err=%v", err)
+		}
+
+		f, n, err := tools.Download(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, _ := io.ReadAll(f)
+		_ = f.Close()
+		if n != int64(len(data)) || !bytes.Equal(res, data) {
+			t.Errorf("download size=%d, len=%d", n, len(res))
+		}
+
+		fm := tools.FileManager
+		if err = fm.MakeDirectory(ctx, auth, filepath.Join(dir, "a", "b"), true); err != nil {
+			t.Fatal(err)
+		}
+		if err = fm.MoveFile(ctx, auth, name, filepath.Join(dir, "a", "b", "moved.txt"), false); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := fm.ListFiles(ctx, auth, filepath.Join(dir, "a", "b"), 0, 0, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(info.Files) != 1 || info.Files[0].Path != "moved.txt" || info.Files[0].Size != int64(len(data)) {
+			t.Errorf("files=%#v", info.Files)
+		}
+
+		err = fm.DeleteFile(ctx, auth, name)
+		if !fault.Is(err, &types.FileNotFound{}) {
+			t.Errorf("err=%v", err)
+		}
+		if err = fm.DeleteDirectory(ctx, auth, filepath.Join(dir, "a"), true); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("VCSIM_TOOLBOX", "yes")
+		env, err := tools.ProcessManager.ReadEnvironmentVariable(ctx, auth, []string{"VCSIM_TOOLBOX"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(env) != 1 || env[0] != "VCSIM_TOOLBOX=yes" {
+			t.Errorf("env=%v", env)
+		}
+
+		_, err = fm.ListFiles(ctx, &types.NamePasswordAuthentication{Username: "user"}, dir, 0, 0, "")
+		if !fault.Is(err, &types.InvalidGuestLogin{}) {
+			t.Errorf("err=%v", err)
+		}
+
+		// ShutdownGuest is dispatched to the toolbox
+		halted := false
+		simVm.Toolbox(ctx.(*Context)).Power.Halt.Handler = func() error {
+			halted = true
+			return nil
+		}
+		if err = vm.ShutdownGuest(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err = vm.WaitForPowerState(ctx, types.VirtualMachinePowerStatePoweredOff); err != nil {
+			t.Fatal(err)
+		}
+		if !halted {
+			t.Error("halt not dispatched")
+		}
+		if running, _ = vm.IsToolsRunning(ctx); running {
+			t.Error("tools running")
+		}
+
+		_, err = fm.ListFiles(ctx, auth, dir, 0, 0, "")
+		if !fault.Is(err, &types.InvalidPowerState{}) {
+			t.Errorf("err=%v", err)
+		}
+
+		// CustomizeVM_Task is applied by the toolbox when powered on
+		var config *tbx.CustomizationConfig
+		guest := simVm.Toolbox(ctx.(*Context))
+		guest.DeployPkg.Handler = func(c *tbx.CustomizationConfig) error {
+			config = c
+			return nil
+		}
+		// guest.net must match the NicSettingMap
+		guest.GuestInfo.NicInfo = func() *tbx.GuestNicInfo {
+			info := tbx.NewGuestNicInfo()
+			info.V3.Nics = append(info.V3.Nics, tbx.GuestNicV3{MacAddress: "00:50:56:aa:bb:cc"})
+			return info
+		}
+		guest.SendGuestInfo()
+
+		for i := 0; ; i++ {
+			var mvm mo.VirtualMachine
+			if err = vm.Properties(ctx, vm.Reference(), []string{"guest.net"}, &mvm); err != nil {
+				t.Fatal(err)
+			}
+			if len(mvm.Guest.Net) == 1 {
+				break
+			}
+			if i == 100 {
+				t.Fatalf("net=%#v", mvm.Guest.Net)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		spec := types.CustomizationSpec{
+			Identity: &types.CustomizationLinuxPrep{
+				HostName: &types.CustomizationFixedName{Name: "vcsim-toolbox"},
+				Domain:   "example.com",
+			},
+			NicSettingMap: []types.CustomizationAdapterMapping{{
+				Adapter: types.CustomizationIPSettings{
+					Ip:         &types.CustomizationFixedIp{IpAddress: "10.0.0.42"},
+					SubnetMask: "255.255.255.0",
+					Gateway:    []string{"10.0.0.1"},
+				},
+			}},
+		}
+
+		task, err = vm.Customize(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		task, err = vm.PowerOn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if config == nil {
+			t.Fatal("customization not deployed")
+		}
+		if config.HostName != "vcsim-toolbox" || config.DomainName != "example.com" {
+			t.Errorf("config=%#v", config)
+		}
+		if len(config.Nics) != 1 || config.Nics[0].IPAddress != "10.0.0.42" || config.Nics[0].MACAddress == "" {
+			t.Errorf("nics=%#v", config.Nics)
+		}
+	})
+}
+
+func TestGuestFileTransferDirectory(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("GOOS=%s", runtime.GOOS) // toolbox ListFiles is only implemented for linux
+	}
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, Map(ctx).Any("VirtualMachine").Reference())
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: ToolboxBackingOptionKey, Value: "TRUE"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+
+		tools, err := toolbox.NewClient(ctx, c, vm, auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fm := tools.FileManager
+
+		src := t.TempDir()
+		mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+		files := map[string]os.FileMode{
+			"a.txt":       0600,
+			"b/c.txt":     0644,
+			"b/d/e.sh":    0755,
+			"b/d/f/g.txt": 0640,
+		}
+		for name, mode := range files {
+			path := filepath.Join(src, name)
+			if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(path, []byte(name), mode); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+			t.Fatal(err)
+		}
+
+		guestDir := filepath.Join(t.TempDir(), "guest")
+		opts := &guest.TransferOptions{Concurrency: 2}
+
+		if err = fm.UploadDirectory(ctx, auth, src, guestDir, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		// Resume skips files that were already copied
+		if err = os.Remove(filepath.Join(guestDir, "b", "c.txt")); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(guestDir, "a.txt"), []byte("modified"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		opts.Resume = true
+		if err = fm.UploadDirectory(ctx, auth, src, guestDir, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		dst := t.TempDir()
+		if err = fm.DownloadDirectory(ctx, auth, guestDir, dst, nil); err != nil {
+			t.Fatal(err)
+		}
+		err = fm.DownloadDirectory(ctx, auth, guestDir, dst, nil)
+		if !errors.Is(err, os.ErrExist) {
+			t.Errorf("err=%v", err)
+		}
+		if err = fm.DownloadDirectory(ctx, auth, guestDir, dst, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		for name, mode := range files {
+			path := filepath.Join(dst, name)
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != mode {
+				t.Errorf("%s: mode=%s", name, info.Mode())
+			}
+			if !info.ModTime().Equal(mtime) {
+				t.Errorf("%s: mtime=%s", name, info.ModTime())
+			}
+			data, _ := os.ReadFile(path)
+			if string(data) != name {
+				t.Errorf("%s: data=%q", name, data)
+			}
+		}
+
+		if _, err = os.Lstat(filepath.Join(dst, "link")); !os.IsNotExist(err) {
+			t.Errorf("err=%v", err)
+		}
+	})
+}
+
+// streamWriter records the number of writes made while the guest process is running
+type streamWriter struct {
+	buf    bytes.Buffer
+	writes int
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.buf.Write(p)
+}
+
+func TestGuestRunStream(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("GOOS=%s", runtime.GOOS) // toolbox ListFiles is only implemented for linux
+	}
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, Map(ctx).Any("VirtualMachine").Reference())
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: ToolboxBackingOptionKey, Value: "TRUE"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+
+		tools, err := toolbox.NewClient(ctx, c, vm, auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout streamWriter
+		var stderr bytes.Buffer
+		cmd := &exec.Cmd{
+			Path:   "/bin/bash",
+			Args:   []string{"-c", "'for i in 1 2 3; do echo $i; sleep 0.5; done; echo done >&2; exit 3'"},
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}
+
+		err = tools.Stream(ctx, cmd, 100*time.Millisecond)
+		var rc interface{ ExitCode() int }
+		if !errors.As(err, &rc) || rc.ExitCode() != 3 {
+			t.Errorf("err=%v", err)
+		}
+		if stdout.buf.String() != "1\n2\n3\n" || stderr.String() != "done\n" {
+			t.Errorf("stdout=%q, stderr=%q", stdout.buf.String(), stderr.String())
+		}
+		if stdout.writes < 2 {
+			t.Errorf("writes=%d", stdout.writes)
+		}
+
+		// stdin is forwarded while the process is running
+		inr, inw := io.Pipe()
+		outr, outw := io.Pipe()
+		cmd = &exec.Cmd{Path: "/bin/cat", Stdin: inr, Stdout: outw}
+		errc := make(chan error, 1)
+		go func() {
+			errc <- tools.Stream(ctx, cmd, 100*time.Millisecond)
+			_ = outw.Close()
+		}()
+
+		lines := bufio.NewReader(outr)
+		for _, s := range []string{"hello\n", "world\n"} {
+			if _, err = inw.Write([]byte(s)); err != nil {
+				t.Fatal(err)
+			}
+			line, err := lines.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line != s {
+				t.Errorf("line=%q", line)
+			}
+		}
+		_ = inw.Close()
+		if err = <-errc; err != nil {
+			t.Error(err)
+		}
+
+		tctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+
+		cmd = &exec.Cmd{Path: "/bin/sleep", Args: []string{"30"}, Stdout: &stdout}
+		start := time.Now()
+		err = tools.Stream(tctx, cmd, 100*time.Millisecond)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err=%v", err)
+		}
+		if time.Since(start) > 10*time.Second {
+			t.Errorf("timeout not applied")
+		}
+
+		// The process is terminated when the context is done
+		for i := 0; ; i++ {
+			procs, err := tools.ProcessManager.ListProcesses(ctx, auth, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			running := false
+			for _, p := range procs {
+				if strings.Contains(p.CmdLine, "sleep") && p.EndTime == nil {
+					running = true
+				}
+			}
+			if !running {
+				break
+			}
+			if i == 10 {
+				t.Fatal("process still running")
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	})
+}
+
+// testSAMLToken returns an unsigned SAML token with the given signing certificate
+func testSAMLToken(cert string) string {
+	return `<saml2:Assertion xmlns:saml2="urn:oasis:names:tc:SAML:2.0:assertion">` +
+		`<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:KeyInfo><ds:X509Data>` +
+		`<ds:X509Certificate>` + cert + `</ds:X509Certificate>` +
+		`</ds:X509Data></ds:KeyInfo></ds:Signature>` +
+		`<saml2:Subject><saml2:NameID>Administrator@VSPHERE.LOCAL</saml2:NameID></saml2:Subject>` +
+		`</saml2:Assertion>`
+}
+
+func TestGuestAliasManager(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, Map(ctx).Any("VirtualMachine").Reference())
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			ExtraConfig: []types.BaseOptionValue{
+				&types.OptionValue{Key: ToolboxBackingOptionKey, Value: "TRUE"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		om := guest.NewOperationsManager(c, vm.Reference())
+		am, err := om.AliasManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		auth, err := om.AuthManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pm, err := om.ProcessManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cert := base64.StdEncoding.EncodeToString([]byte("vcsim-sso-signing-cert"))
+		token := testSAMLToken(cert)
+
+		info, err := guest.ParseToken(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Base64Cert != cert || info.Subject != "Administrator@VSPHERE.LOCAL" {
+			t.Errorf("info=%#v", info)
+		}
+
+		pass := &types.NamePasswordAuthentication{Username: "root", Password: "vcsim"}
+		saml := &types.SAMLTokenAuthentication{Token: token, Username: "root"}
+		mapped := &types.SAMLTokenAuthentication{Token: token}
+
+		// no aliases yet
+		if err = auth.ValidateCredentials(ctx, saml); !fault.Is(err, &types.InvalidGuestLogin{}) {
+			t.Errorf("err=%v", err)
+		}
+
+		named := types.GuestAuthAliasInfo{Subject: &types.GuestAuthNamedSubject{Name: info.Subject}, Comment: "test"}
+		if err = am.AddAlias(ctx, pass, "root", false, info.Base64Cert, named); err != nil {
+			t.Fatal(err)
+		}
+		if err = am.AddAlias(ctx, pass, "root", false, info.Base64Cert, named); !fault.Is(err, &types.AlreadyExists{}) {
+			t.Errorf("err=%v", err)
+		}
+
+		aliases, err := am.ListAliases(ctx, pass, "root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(aliases) != 1 || aliases[0].Base64Cert != cert || len(aliases[0].Aliases) != 1 || aliases[0].Aliases[0].Comment != "test" {
+			t.Errorf("aliases=%#v", aliases)
+		}
+
+		if err = auth.ValidateCredentials(ctx, saml); err != nil {
+			t.Error(err)
+		}
+		// username is required when the alias is not mapped
+		if err = auth.ValidateCredentials(ctx, mapped); !fault.Is(err, &types.InvalidGuestLogin{}) {
+			t.Errorf("err=%v", err)
+		}
+		// subject mismatch
+		other := &types.SAMLTokenAuthentication{Token: strings.Replace(token, "Administrator", "user", 1), Username: "root"}
+		if err = auth.ValidateCredentials(ctx, other); !fault.Is(err, &types.InvalidGuestLogin{}) {
+			t.Errorf("err=%v", err)
+		}
+
+		if runtime.GOOS == "linux" {
+			pid, err := pm.StartProgram(ctx, saml, &types.GuestProgramSpec{ProgramPath: "/bin/true"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pid == 0 {
+				t.Error("pid=0")
+			}
+		}
+
+		anyone := types.GuestAuthAliasInfo{Subject: new(types.GuestAuthAnySubject)}
+		if err = am.AddAlias(ctx, pass, "root", true, info.Base64Cert, anyone); err != nil {
+			t.Fatal(err)
+		}
+
+		maps, err := am.ListMappedAliases(ctx, pass)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(maps) != 1 || maps[0].Username != "root" || len(maps[0].Subjects) != 1 {
+			t.Errorf("mapped=%#v", maps)
+		}
+
+		for _, a := range []types.BaseGuestAuthentication{mapped, other} {
+			if err = auth.ValidateCredentials(ctx, a); err != nil {
+				t.Error(err)
+			}
+		}
+
+		if err = am.RemoveAlias(ctx, pass, "root", info.Base64Cert, anyone.Subject); err != nil {
+			t.Fatal(err)
+		}
+		if err = am.RemoveAlias(ctx, pass, "root", info.Base64Cert, anyone.Subject); !fault.Is(err, &types.InvalidArgument{}) {
+			t.Errorf("err=%v", err)
+		}
+		if err = am.RemoveAliasByCert(ctx, pass, "root", info.Base64Cert); err != nil {
+			t.Fatal(err)
+		}
+
+		aliases, err = am.ListAliases(ctx, pass, "root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(aliases) != 0 {
+			t.Errorf("aliases=%#v", aliases)
+		}
+		if err = auth.ValidateCredentials(ctx, saml); !fault.Is(err, &types.InvalidGuestLogin{}) {
+			t.Errorf("err=%v", err)
+		}
+	})
+}
+
+func TestGuestContainerStartProgramSAML(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("GOOS=%s", runtime.GOOS)
+	}
+
+	// A fake docker command, such that the container backing does not require docker
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte("#!/bin/sh\nexit 0\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	Test(func(ctx context.Context, c *vim25.Client) {
+		ref := Map(ctx).Any("VirtualMachine").(*VirtualMachine)
+		ref.svm = &simVM{vm: ref, c: &container{id: "vcsim-test"}}
+
+		vm := object.NewVirtualMachine(c, ref.Reference())
+		om := guest.NewOperationsManager(c, vm.Reference())
+		am, err := om.AliasManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pm, err := om.ProcessManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cert := base64.StdEncoding.EncodeToString([]byte("vcsim-sso-signing-cert"))
+		pass := &types.NamePasswordAuthentication{Username: "root", Password: "vcsim"}
+		saml := &types.SAMLTokenAuthentication{Token: testSAMLToken(cert)}
+		spec := &types.GuestProgramSpec{ProgramPath: "/bin/true"}
+
+		if _, err = pm.StartProgram(ctx, saml, spec); !fault.Is(err, &types.InvalidGuestLogin{}) {
+			t.Errorf("err=%v", err)
+		}
+
+		anyone := types.GuestAuthAliasInfo{Subject: new(types.GuestAuthAnySubject)}
+		if err = am.AddAlias(ctx, pass, "root", true, cert, anyone); err != nil {
+			t.Fatal(err)
+		}
+
+		pid, err := pm.StartProgram(ctx, saml, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		procs, err := pm.ListProcesses(ctx, saml, []int64{pid})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(procs) != 1 || procs[0].Owner != "root" {
+			t.Errorf("procs=%#v", procs)
+		}
+	})
+}
+
+func TestGuestRegistryManager(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, Map(ctx).Any("VirtualMachine").Reference())
+
+		om := guest.NewOperationsManager(c, vm.Reference())
+		m, err := om.RegistryManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+		app := guest.RegistryKey(`HKLM\SOFTWARE\MyApp`)
+
+		err = m.CreateKey(ctx, auth, app, false, "")
+		if !fault.Is(err, &types.OperationNotSupportedByGuest{}) {
+			t.Errorf("expected OperationNotSupportedByGuest, got %v", err)
+		}
+
+		task, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{GuestId: string(types.VirtualMachineGuestOsIdentifierWindows9_64Guest)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.CreateKey(ctx, &types.NamePasswordAuthentication{Username: "user"}, app, false, "")
+		if !fault.Is(err, &types.InvalidGuestLogin{}) {
+			t.Errorf("expected InvalidGuestLogin, got %v", err)
+		}
+
+		settings := guest.RegistryKey(`HKEY_LOCAL_MACHINE\SOFTWARE\MyApp\Settings`)
+		if err = m.CreateKey(ctx, auth, settings, false, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.CreateKey(ctx, auth, app, false, "")
+		if !fault.Is(err, &types.GuestRegistryKeyAlreadyExists{}) {
+			t.Errorf("expected GuestRegistryKeyAlreadyExists, got %v", err)
+		}
+
+		err = m.CreateKey(ctx, auth, guest.RegistryKey(`HKXX\SOFTWARE\Key`), false, "")
+		if !fault.Is(err, &types.GuestRegistryKeyInvalid{}) {
+			t.Errorf("expected GuestRegistryKeyInvalid, got %v", err)
+		}
+
+		session := guest.RegistryKey(`HKLM\SOFTWARE\MyApp\Session`)
+		if err = m.CreateKey(ctx, auth, session, true, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.CreateKey(ctx, auth, guest.RegistryKey(`HKLM\SOFTWARE\MyApp\Session\State`), false, "")
+		if !fault.Is(err, &types.GuestRegistryKeyParentVolatile{}) {
+			t.Errorf("expected GuestRegistryKeyParentVolatile, got %v", err)
+		}
+
+		keys, err := m.ListKeys(ctx, auth, app, false, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 2 || keys[0].Key.KeyName.RegistryPath != `HKEY_LOCAL_MACHINE\SOFTWARE\MyApp\Session` {
+			t.Errorf("keys=%#v", keys)
+		}
+
+		keys, err = m.ListKeys(ctx, auth, guest.RegistryKey(`HKLM\SOFTWARE`), true, "^Set")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0].Key.KeyName.RegistryPath != `HKEY_LOCAL_MACHINE\SOFTWARE\MyApp\Settings` {
+			t.Errorf("keys=%#v", keys)
+		}
+
+		values := map[string]any{
+			"String": "1.0",
+			"Expand": guest.ExpandString(`%ProgramFiles%\MyApp`),
+			"Dword":  uint32(42),
+			"Qword":  uint64(1 << 40),
+			"Binary": []byte{0xca, 0xfe},
+			"Multi":  []string{"a", "b"},
+		}
+
+		for name, data := range values {
+			if err = m.Set(ctx, auth, settings, name, data); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		list, err := m.ListValues(ctx, auth, settings, false, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != len(values) {
+			t.Fatalf("values=%#v", list)
+		}
+		for _, val := range list {
+			if !reflect.DeepEqual(guest.RegistryValueData(val.Data), values[val.Name.Name]) {
+				t.Errorf("%s=%#v", val.Name.Name, guest.RegistryValueData(val.Data))
+			}
+		}
+
+		list, err = m.ListValues(ctx, auth, settings, true, "^Exp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 1 || guest.RegistryValueData(list[0].Data) != guest.ExpandString(`C:\Program Files\MyApp`) {
+			t.Errorf("values=%#v", list)
+		}
+		if kind := guest.RegistryValueType(list[0].Data); kind != "REG_EXPAND_SZ" {
+			t.Errorf("type=%s", kind)
+		}
+
+		name := types.GuestRegValueNameSpec{KeyName: settings, Name: "Dword"}
+		if err = m.DeleteValue(ctx, auth, name); err != nil {
+			t.Fatal(err)
+		}
+		err = m.DeleteValue(ctx, auth, name)
+		if !fault.Is(err, &types.GuestRegistryValueNotFound{}) {
+			t.Errorf("expected GuestRegistryValueNotFound, got %v", err)
+		}
+
+		err = m.DeleteKey(ctx, auth, app, false)
+		if !fault.Is(err, &types.GuestRegistryKeyHasSubkeys{}) {
+			t.Errorf("expected GuestRegistryKeyHasSubkeys, got %v", err)
+		}
+		if err = m.DeleteKey(ctx, auth, app, true); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = m.ListValues(ctx, auth, settings, false, "")
+		if !fault.Is(err, &types.GuestRegistryKeyInvalid{}) {
+			t.Errorf("expected GuestRegistryKeyInvalid, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,470 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// GuestWindowsRegistryManager implements registry operations against an in-memory registry per Windows VM.
+// The registry view (WowBitness) is ignored, all views share the same keys.
+type GuestWindowsRegistryManager struct {
+	mo.GuestWindowsRegistryManager
+}
+
+// registryRoots maps the predefined key abbreviations to their names
+var registryRoots = map[string]string{
+	"HKLM": "HKEY_LOCAL_MACHINE",
+	"HKCU": "HKEY_CURRENT_USER",
+	"HKCR": "HKEY_CLASSES_ROOT",
+	"HKU":  "HKEY_USERS",
+	"HKCC": "HKEY_CURRENT_CONFIG",
+}
+
+// registryEnv is used to expand REG_EXPAND_SZ values
+var registryEnv = map[string]string{
+	"SYSTEMDRIVE":  `C:`,
+	"SYSTEMROOT":   `C:\Windows`,
+	"WINDIR":       `C:\Windows`,
+	"PROGRAMFILES": `C:\Program Files`,
+	"PROGRAMDATA":  `C:\ProgramData`,
+	"TEMP":         `C:\Windows\TEMP`,
+	"TMP":          `C:\Windows\TEMP`,
+}
+
+type registryValue struct {
+	name string
+	data types.BaseGuestRegValueDataSpec
+}
+
+type registryKey struct {
+	name     string
+	class    string
+	volatile bool
+	written  time.Time
+	keys     map[string]*registryKey // keyed by lower case name
+	values   map[string]*registryValue
+}
+
+func newRegistryKey(name string) *registryKey {
+	return &registryKey{
+		name:    name,
+		written: time.Now(),
+		keys:    make(map[string]*registryKey),
+		values:  make(map[string]*registryValue),
+	}
+}
+
+func (k *registryKey) subkeys() []*registryKey {
+	keys := make([]*registryKey, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b *registryKey) int {
+		return strings.Compare(strings.ToLower(a.name), strings.ToLower(b.name))
+	})
+	return keys
+}
+
+// newGuestRegistry returns the predefined keys of a Windows registry
+func newGuestRegistry() *registryKey {
+	root := newRegistryKey("")
+
+	for _, name := range registryRoots {
+		root.keys[strings.ToLower(name)] = newRegistryKey(name)
+	}
+
+	hklm := root.keys["hkey_local_machine"]
+	for _, name := range []string{"HARDWARE", "SOFTWARE", "SYSTEM"} {
+		hklm.keys[strings.ToLower(name)] = newRegistryKey(name)
+	}
+
+	return root
+}
+
+// registryPath splits the given registry path, mapping the root key abbreviation if any
+func registryPath(path string) []string {
+	elem := strings.FieldsFunc(path, func(r rune) bool { return r == '\\' })
+	if len(elem) != 0 {
+		if name, ok := registryRoots[strings.ToUpper(elem[0])]; ok {
+			elem[0] = name
+		}
+	}
+	return elem
+}
+
+// lookup returns the key for the given registry path, nil if not found
+func (k *registryKey) lookup(path string) *registryKey {
+	elem := registryPath(path)
+	if len(elem) == 0 {
+		return nil
+	}
+
+	for _, name := range elem {
+		k = k.keys[strings.ToLower(name)]
+		if k == nil {
+			return nil
+		}
+	}
+
+	return k
+}
+
+// guestRegistry validates the VM state and auth, returning the VM registry.
+// The VM must be locked by the caller, which also guards the returned registry.
+func (vm *VirtualMachine) guestRegistry(ctx *Context, auth types.BaseGuestAuthentication) (*registryKey, types.BaseMethodFault) {
+	if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+		return nil, &types.InvalidPowerState{
+			RequestedState: types.VirtualMachinePowerStatePoweredOn,
+			ExistingState:  vm.Runtime.PowerState,
+		}
+	}
+
+	if guestFamily(vm.Config.GuestId) != string(types.VirtualMachineGuestOsFamilyWindowsGuest) {
+		return nil, new(types.OperationNotSupportedByGuest)
+	}
+
+	switch creds := auth.(type) {
+	case *types.NamePasswordAuthentication:
+		if creds.Username == "" || creds.Password == "" {
+			return nil, new(types.InvalidGuestLogin)
+		}
+	case *types.SAMLTokenAuthentication:
+		if _, fault := vm.samlTokenUser(ctx, creds); fault != nil {
+			return nil, fault
+		}
+	default:
+		return nil, new(types.InvalidGuestLogin)
+	}
+
+	if vm.registry == nil {
+		vm.registry = newGuestRegistry()
+	}
+
+	return vm.registry, nil
+}
+
+func registryKeyInvalid(name string) types.BaseMethodFault {
+	return &types.GuestRegistryKeyInvalid{
+		GuestRegistryKeyFault: types.GuestRegistryKeyFault{
+			GuestRegistryFault: types.GuestRegistryFault{WindowsSystemErrorCode: 2}, // ERROR_FILE_NOT_FOUND
+			KeyName:            name,
+		},
+	}
+}
+
+func (m *GuestWindowsRegistryManager) CreateRegistryKeyInGuest(ctx *Context, req *types.CreateRegistryKeyInGuest) soap.HasFault {
+	body := new(methods.CreateRegistryKeyInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	ctx.WithLock(vm, func() {
+		reg, fault := vm.guestRegistry(ctx, req.Auth)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return
+		}
+
+		path := req.KeyName.RegistryPath
+		elem := registryPath(path)
+		if len(elem) < 2 || reg.lookup(elem[0]) == nil {
+			body.Fault_ = Fault("", registryKeyInvalid(path))
+			return
+		}
+
+		if reg.lookup(path) != nil {
+			body.Fault_ = Fault("", &types.GuestRegistryKeyAlreadyExists{
+				GuestRegistryKeyFault: types.GuestRegistryKeyFault{KeyName: path},
+			})
+			return
+		}
+
+		// As RegCreateKeyEx, missing intermediate keys are created
+		key := reg
+		for _, name := range elem {
+			sub := key.keys[strings.ToLower(name)]
+			if sub == nil {
+				if key.volatile && !req.IsVolatile {
+					body.Fault_ = Fault("", &types.GuestRegistryKeyParentVolatile{
+						GuestRegistryKeyFault: types.GuestRegistryKeyFault{KeyName: path},
+					})
+					return
+				}
+				sub = newRegistryKey(name)
+				sub.volatile = req.IsVolatile
+				key.keys[strings.ToLower(name)] = sub
+			}
+			key = sub
+		}
+
+		key.class = req.ClassType
+
+		body.Res = new(types.CreateRegistryKeyInGuestResponse)
+	})
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) ListRegistryKeysInGuest(ctx *Context, req *types.ListRegistryKeysInGuest) soap.HasFault {
+	body := new(methods.ListRegistryKeysInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	ctx.WithLock(vm, func() {
+		reg, fault := vm.guestRegistry(ctx, req.Auth)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return
+		}
+
+		key := reg.lookup(req.KeyName.RegistryPath)
+		if key == nil {
+			body.Fault_ = Fault("", registryKeyInvalid(req.KeyName.RegistryPath))
+			return
+		}
+
+		var match *regexp.Regexp
+		if req.MatchPattern != "" {
+			var err error
+			if match, err = regexp.Compile(req.MatchPattern); err != nil {
+				body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "matchPattern"})
+				return
+			}
+		}
+
+		var res []types.GuestRegKeyRecordSpec
+
+		var list func(string, *registryKey)
+		list = func(path string, key *registryKey) {
+			for _, sub := range key.subkeys() {
+				name := path + `\` + sub.name
+				if match == nil || match.MatchString(sub.name) {
+					res = append(res, types.GuestRegKeyRecordSpec{
+						Key: types.GuestRegKeySpec{
+							KeyName: types.GuestRegKeyNameSpec{
+								RegistryPath: name,
+								WowBitness:   req.KeyName.WowBitness,
+							},
+							ClassType:   sub.class,
+							LastWritten: sub.written,
+						},
+					})
+				}
+				if req.Recursive {
+					list(name, sub)
+				}
+			}
+		}
+
+		list(strings.Join(registryPath(req.KeyName.RegistryPath), `\`), key)
+
+		body.Res = &types.ListRegistryKeysInGuestResponse{Returnval: res}
+	})
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) DeleteRegistryKeyInGuest(ctx *Context, req *types.DeleteRegistryKeyInGuest) soap.HasFault {
+	body := new(methods.DeleteRegistryKeyInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	ctx.WithLock(vm, func() {
+		reg, fault := vm.guestRegistry(ctx, req.Auth)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return
+		}
+
+		path := req.KeyName.RegistryPath
+		elem := registryPath(path)
+		key := reg.lookup(path)
+		if key == nil || len(elem) < 2 {
+			body.Fault_ = Fault("", registryKeyInvalid(path))
+			return
+		}
+
+		if len(key.keys) != 0 && !req.Recursive {
+			body.Fault_ = Fault("", &types.GuestRegistryKeyHasSubkeys{
+				GuestRegistryKeyFault: types.GuestRegistryKeyFault{KeyName: path},
+			})
+			return
+		}
+
+		parent := reg.lookup(strings.Join(elem[:len(elem)-1], `\`))
+		delete(parent.keys, strings.ToLower(elem[len(elem)-1]))
+
+		body.Res = new(types.DeleteRegistryKeyInGuestResponse)
+	})
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) SetRegistryValueInGuest(ctx *Context, req *types.SetRegistryValueInGuest) soap.HasFault {
+	body := new(methods.SetRegistryValueInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	ctx.WithLock(vm, func() {
+		reg, fault := vm.guestRegistry(ctx, req.Auth)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return
+		}
+
+		path := req.Value.Name.KeyName.RegistryPath
+		key := reg.lookup(path)
+		if key == nil {
+			body.Fault_ = Fault("", registryKeyInvalid(path))
+			return
+		}
+
+		if req.Value.Data == nil {
+			body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "value.data"})
+			return
+		}
+
+		name := req.Value.Name.Name
+		key.values[strings.ToLower(name)] = &registryValue{name: name, data: req.Value.Data}
+		key.written = time.Now()
+
+		body.Res = new(types.SetRegistryValueInGuestResponse)
+	})
+
+	return body
+}
+
+// expandRegistryString expands %NAME% variables, leaving unknown variables as-is
+func expandRegistryString(s string) string {
+	var b strings.Builder
+
+	for {
+		start := strings.IndexByte(s, '%')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(s[start+1:], '%')
+		if end == -1 {
+			break
+		}
+		end += start + 1
+
+		b.WriteString(s[:start])
+		if val, ok := registryEnv[strings.ToUpper(s[start+1:end])]; ok {
+			b.WriteString(val)
+			s = s[end+1:]
+		} else {
+			b.WriteString(s[start:end])
+			s = s[end:]
+		}
+	}
+
+	b.WriteString(s)
+
+	return b.String()
+}
+
+func (m *GuestWindowsRegistryManager) ListRegistryValuesInGuest(ctx *Context, req *types.ListRegistryValuesInGuest) soap.HasFault {
+	body := new(methods.ListRegistryValuesInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	ctx.WithLock(vm, func() {
+		reg, fault := vm.guestRegistry(ctx, req.Auth)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return
+		}
+
+		key := reg.lookup(req.KeyName.RegistryPath)
+		if key == nil {
+			body.Fault_ = Fault("", registryKeyInvalid(req.KeyName.RegistryPath))
+			return
+		}
+
+		var match *regexp.Regexp
+		if req.MatchPattern != "" {
+			var err error
+			if match, err = regexp.Compile(req.MatchPattern); err != nil {
+				body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "matchPattern"})
+				return
+			}
+		}
+
+		var res []types.GuestRegValueSpec
+
+		for _, val := range key.values {
+			if match != nil && !match.MatchString(val.name) {
+				continue
+			}
+
+			data := val.data
+			if s, ok := data.(*types.GuestRegValueExpandStringSpec); ok && req.ExpandStrings {
+				data = &types.GuestRegValueExpandStringSpec{Value: expandRegistryString(s.Value)}
+			}
+
+			res = append(res, types.GuestRegValueSpec{
+				Name: types.GuestRegValueNameSpec{KeyName: req.KeyName, Name: val.name},
+				Data: data,
+			})
+		}
+
+		slices.SortFunc(res, func(a, b types.GuestRegValueSpec) int {
+			return strings.Compare(strings.ToLower(a.Name.Name), strings.ToLower(b.Name.Name))
+		})
+
+		body.Res = &types.ListRegistryValuesInGuestResponse{Returnval: res}
+	})
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) DeleteRegistryValueInGuest(ctx *Context, req *types.DeleteRegistryValueInGuest) soap.HasFault {
+	body := new(methods.DeleteRegistryValueInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	ctx.WithLock(vm, func() {
+		reg, fault := vm.guestRegistry(ctx, req.Auth)
+		if fault != nil {
+			body.Fault_ = Fault("", fault)
+			return
+		}
+
+		path := req.ValueName.KeyName.RegistryPath
+		key := reg.lookup(path)
+		if key == nil {
+			body.Fault_ = Fault("", registryKeyInvalid(path))
+			return
+		}
+
+		name := strings.ToLower(req.ValueName.Name)
+		if _, ok := key.values[name]; !ok {
+			body.Fault_ = Fault("", &types.GuestRegistryValueNotFound{
+				GuestRegistryValueFault: types.GuestRegistryValueFault{
+					GuestRegistryFault: types.GuestRegistryFault{WindowsSystemErrorCode: 2}, // ERROR_FILE_NOT_FOUND
+					KeyName:            path,
+					ValueName:          req.ValueName.Name,
+				},
+			})
+			return
+		}
+
+		delete(key.values, name)
+		key.written = time.Now()
+
+		body.Res = new(types.DeleteRegistryValueInGuestResponse)
+	})
+
+	return body
+}
@@ -60,6 +60,9 @@ type Model struct {
 	// Autostart will power on Model created VMs when true
 	Autostart bool `json:"-"`
 
+	// Toolbox backs Model created VMs with an in-process toolbox when true, see ToolboxBackingOptionKey
+	Toolbox bool `json:"-"`
+
 	// Datacenter specifies the number of Datacenter entities to create
 	// Name prefix: DC, vcsim flag: -dc
 	Datacenter int `json:"datacenter"`
@@ -564,6 +567,12 @@ func (m *Model) CreateInfrastructure(ctx *Context) error {
 
 				config.DeviceChange, _ = devices.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
 
+				if m.Toolbox {
+					config.ExtraConfig = []types.BaseOptionValue{
+						&types.OptionValue{Key: ToolboxBackingOptionKey, Value: "TRUE"},
+					}
+				}
+
 				task, err := folders.VmFolder.CreateVM(ctx, config, pool, host)
 				if err != nil {
 					return err
@@ -882,6 +891,7 @@ func (m *Model) Remove() {
 	for _, obj := range ctx.Map.objects {
 		if vm, ok := obj.(*VirtualMachine); ok {
 			vm.svm.remove(ctx)
+			vm.closeToolbox()
 		}
 	}
 	ctx.Map.m.Unlock()
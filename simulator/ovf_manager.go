@@ -278,7 +278,8 @@ func (m *OvfManager) CreateImportSpec(ctx *Context, req *types.CreateImportSpec)
 			if !ok {
 				continue // Parent is unsupported()
 			}
-			d, _ := device.CreateCdrom(c.(*types.VirtualIDEController))
+			// the parent can be an IDE, SATA or SCSI controller
+			d, _ := device.CreateCdrom(c.(types.BaseVirtualController))
 			if len(item.HostResource) != 0 {
 				for _, file := range env.References {
 					if strings.HasSuffix(item.HostResource[0], file.ID) {
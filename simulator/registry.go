@@ -83,6 +83,15 @@ type tagManager interface {
 	DetachTag(types.ManagedObjectReference, types.VslmTagEntry) types.BaseMethodFault
 }
 
+// AttachedTags returns the vAPI tags attached to the given object, if the vapi simulator is registered.
+func (r *Registry) AttachedTags(ref types.ManagedObjectReference) []types.VslmTagEntry {
+	if r.tagManager == nil {
+		return nil
+	}
+	tags, _ := r.tagManager.AttachedTags(ref)
+	return tags
+}
+
 // NewRegistry creates a new instances of Registry
 func NewRegistry() *Registry {
 	r := &Registry{
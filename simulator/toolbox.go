@@ -0,0 +1,492 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vmware/govmomi/toolbox"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+var toolboxMu sync.Mutex
+
+// vmToolbox is an in-process toolbox.Service backing a VirtualMachine.
+// RPC requests are dispatched to the Service directly by the simulator, acting as the VMX,
+// and requests sent by the Service are handled by toolboxChannel.
+type vmToolbox struct {
+	*toolbox.Service
+
+	vm      *VirtualMachine
+	ctx     *Context
+	events  chan vmBackupEvent
+	power   chan string
+	notify  chan struct{}
+	stop    chan struct{}
+	running atomic.Bool
+
+	mu           sync.Mutex
+	updates      []func(*Context)
+	deployStatus string
+	vars         map[string]string
+}
+
+type vmBackupEvent struct {
+	name string
+	code int
+	msg  string
+}
+
+// toolboxChannel implements toolbox.Channel, replying OK to each request sent by the guest,
+// other than info-get which replies with the guestinfo variable value.
+type toolboxChannel struct {
+	tbx   *vmToolbox
+	reply []byte
+}
+
+func (c *toolboxChannel) Start() error {
+	return nil
+}
+
+func (c *toolboxChannel) Stop() error {
+	return nil
+}
+
+func (c *toolboxChannel) Send(buf []byte) error {
+	name, args, _ := bytes.Cut(buf, []byte{' '})
+
+	c.reply = []byte("1 ")
+
+	switch string(name) {
+	case "vmbackup.eventSet":
+		f := strings.SplitN(string(args), " ", 3)
+		if len(f) == 3 {
+			code, _ := strconv.Atoi(f[1])
+			select {
+			case c.tbx.events <- vmBackupEvent{f[0], code, f[2]}:
+			default: // no quiesce operation waiting
+			}
+		}
+	case "tools.os.statechange.status":
+		select {
+		case c.tbx.power <- string(args):
+		default:
+		}
+	case "deployPkg.update.state":
+		c.tbx.setDeployPkgState(string(args))
+	case "SetGuestInfo":
+		kind, data, _ := bytes.Cut(bytes.TrimLeft(args, " "), []byte{' '})
+		id, _ := strconv.Atoi(string(kind))
+		c.tbx.setGuestInfo(id, data)
+	case "info-set":
+		key, val, _ := bytes.Cut(args, []byte{' '})
+		c.tbx.setVariable(string(key), string(val))
+	case "info-get":
+		if val, ok := c.tbx.variable(string(args)); ok {
+			c.reply = append(c.reply, val...)
+		} else {
+			c.reply = []byte("0 No value found")
+		}
+	}
+
+	return nil
+}
+
+func (c *toolboxChannel) Receive() ([]byte, error) {
+	return c.reply, nil
+}
+
+// Toolbox returns the in-process toolbox.Service for this VM, creating it if needed.
+// Once created, guest operations such as snapshot quiescing are driven through the Service,
+// where handler hooks such as VMBackup.Freeze can be used to verify the guest side.
+// Guest details sent by the Service, such as OS, disk and network info, are applied to the VM guest properties.
+// While the VM is powered on, guest operations, guest power operations and customization are also handled by the Service.
+// The Service is created when the VM is powered on if the VM ExtraConfig has ToolboxBackingOptionKey set.
+func (vm *VirtualMachine) Toolbox(ctx *Context) *toolbox.Service {
+	toolboxMu.Lock()
+	tbx := vm.tbx
+	created := tbx == nil
+	if created {
+		tbx = newVMToolbox(ctx, vm)
+		vm.tbx = tbx
+	}
+	toolboxMu.Unlock()
+
+	if created {
+		tbx.SendGuestInfo()
+		ctx.WithLock(vm, func() {
+			tbx.mirrorVariables(vm.Config.ExtraConfig)
+			tbx.apply(ctx)
+			if vm.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn {
+				tbx.running.Store(true)
+				ctx.Update(vm, toolsRunning)
+			}
+		})
+		go tbx.run()
+	}
+
+	return tbx.Service
+}
+
+func newVMToolbox(ctx *Context, vm *VirtualMachine) *vmToolbox {
+	tbx := &vmToolbox{
+		vm: vm,
+		ctx: &Context{
+			Context: context.Background(),
+			Session: ctx.Session,
+			Map:     ctx.Map,
+		},
+		events: make(chan vmBackupEvent, 8),
+		power:  make(chan string, 1),
+		notify: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		vars:   make(map[string]string),
+	}
+
+	c := &toolboxChannel{tbx: tbx}
+	tbx.Service = toolbox.NewService(c, c)
+	tbx.VMBackup.ScriptDir = "" // never run scripts on the simulator host
+	// the simulated guest supports halt and reboot, the VM power state is changed by the simulator
+	tbx.Power.Halt.Handler = func() error { return nil }
+	tbx.Power.Reboot.Handler = func() error { return nil }
+
+	return tbx
+}
+
+func (vm *VirtualMachine) tools() *vmToolbox {
+	toolboxMu.Lock()
+	defer toolboxMu.Unlock()
+
+	return vm.tbx
+}
+
+// run polls for guest info changes, as the toolbox Service does when started,
+// applying updates sent by the toolbox to the VM.
+func (t *vmToolbox) run() {
+	interval := t.GuestInfo.Interval
+	if interval == 0 {
+		interval = toolbox.DefaultGuestInfoInterval
+	}
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-tick.C:
+			if t.running.Load() {
+				t.GuestInfo.Send(false)
+			}
+		case <-t.notify:
+		}
+
+		t.ctx.WithLock(t.vm, func() { t.apply(t.ctx) })
+	}
+}
+
+// closeToolbox stops the in-process toolbox, if any
+func (vm *VirtualMachine) closeToolbox() {
+	toolboxMu.Lock()
+	defer toolboxMu.Unlock()
+
+	if vm.tbx != nil {
+		close(vm.tbx.stop)
+		for id, x := range guestTransfers {
+			if x.tbx == vm.tbx {
+				delete(guestTransfers, id)
+			}
+		}
+		vm.tbx = nil
+	}
+}
+
+func (t *vmToolbox) update(f func(*Context)) {
+	t.mu.Lock()
+	t.updates = append(t.updates, f)
+	t.mu.Unlock()
+
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
+
+// apply guest updates sent by the toolbox, the caller must hold the VM lock
+func (t *vmToolbox) apply(ctx *Context) {
+	t.mu.Lock()
+	updates := t.updates
+	t.updates = nil
+	t.mu.Unlock()
+
+	for _, f := range updates {
+		f(ctx)
+	}
+}
+
+// guestID maps a guest OS short name to a guest ID, for example "ubuntu-64" to "ubuntu64Guest"
+func guestID(name string) string {
+	key := strings.ToLower(strings.ReplaceAll(name, "-", ""))
+
+	for _, id := range GuestID {
+		s := strings.ToLower(strings.ReplaceAll(strings.TrimSuffix(string(id), "Guest"), "_", ""))
+		if s == key {
+			return string(id)
+		}
+	}
+
+	return ""
+}
+
+func guestNicInfo(info *toolbox.GuestNicInfo) ([]types.GuestNicInfo, string) {
+	var nics []types.GuestNicInfo
+	var ip string
+
+	if info.V3 == nil {
+		return nil, ""
+	}
+
+	for i, nic := range info.V3.Nics {
+		n := types.GuestNicInfo{
+			MacAddress:     nic.MacAddress,
+			Connected:      true,
+			DeviceConfigId: int32(4000 + i),
+			IpConfig:       &types.NetIpConfigInfo{},
+		}
+
+		for _, addr := range nic.IPs {
+			a := net.IP(addr.Address.Address).String()
+			if ip == "" && addr.Address.Type == 1 {
+				ip = a
+			}
+			n.IpAddress = append(n.IpAddress, a)
+			n.IpConfig.IpAddress = append(n.IpConfig.IpAddress, types.NetIpConfigInfoIpAddress{
+				IpAddress:    a,
+				PrefixLength: int32(addr.PrefixLength),
+				State:        string(types.NetIpConfigInfoIpAddressStatusPreferred),
+			})
+		}
+
+		nics = append(nics, n)
+	}
+
+	return nics, ip
+}
+
+// setGuestInfo handles the SetGuestInfo request sent by the toolbox
+func (t *vmToolbox) setGuestInfo(kind int, data []byte) {
+	vm := t.vm
+	val := string(data)
+
+	switch kind {
+	case toolbox.GuestInfoIPAddressV2:
+		var info toolbox.GuestNicInfo
+		if err := toolbox.DecodeXDR(data, &info); err != nil {
+			log.Printf("%s: SetGuestInfo %d: %s", vm.Name, kind, err)
+			return
+		}
+		nics, ip := guestNicInfo(&info)
+		t.update(func(ctx *Context) {
+			ctx.Update(vm, []types.PropertyChange{
+				{Name: "guest.net", Val: nics},
+				{Name: "guest.ipAddress", Val: ip},
+				{Name: "summary.guest.ipAddress", Val: ip},
+			})
+		})
+	case toolbox.GuestInfoDiskFreeSpace:
+		var info toolbox.GuestDiskInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			log.Printf("%s: SetGuestInfo %d: %s", vm.Name, kind, err)
+			return
+		}
+		disks := make([]types.GuestDiskInfo, len(info.Disks))
+		for i, d := range info.Disks {
+			disks[i] = types.GuestDiskInfo{
+				DiskPath:       d.Name,
+				Capacity:       int64(d.Size),
+				FreeSpace:      int64(d.Free),
+				FilesystemType: d.Type,
+			}
+		}
+		t.update(func(ctx *Context) {
+			ctx.Update(vm, []types.PropertyChange{{Name: "guest.disk", Val: disks}})
+		})
+	case toolbox.GuestInfoOSNameFull:
+		t.update(func(ctx *Context) {
+			ctx.Update(vm, []types.PropertyChange{
+				{Name: "guest.guestFullName", Val: val},
+				{Name: "summary.guest.guestFullName", Val: val},
+			})
+		})
+	case toolbox.GuestInfoOSName:
+		if id := guestID(val); id != "" {
+			t.update(func(ctx *Context) {
+				ctx.Update(vm, []types.PropertyChange{
+					{Name: "guest.guestId", Val: id},
+					{Name: "guest.guestFamily", Val: guestFamily(id)},
+					{Name: "summary.guest.guestId", Val: id},
+				})
+			})
+		}
+	case toolbox.GuestInfoUptime:
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return
+		}
+		uptime := int32(n / 100) // hundredths of a second
+		t.update(func(ctx *Context) {
+			ctx.Update(vm, []types.PropertyChange{{Name: "summary.quickStats.uptimeSeconds", Val: uptime}})
+		})
+	}
+}
+
+// setVariable handles the info-set request sent by the toolbox
+func (t *vmToolbox) setVariable(key, val string) {
+	vm := t.vm
+
+	switch key {
+	case toolbox.GuestInfoDetailedDataKey:
+		t.update(func(ctx *Context) {
+			ctx.Update(vm, []types.PropertyChange{{Name: "guest.guestDetailedData", Val: val}})
+		})
+	default:
+		if !strings.HasPrefix(strings.ToLower(key), toolbox.GuestVariablePrefix) {
+			return
+		}
+		// visible to the guest now, to the host once applied to ExtraConfig
+		t.mu.Lock()
+		t.vars[strings.ToLower(key)] = val
+		t.mu.Unlock()
+
+		t.update(func(ctx *Context) {
+			spec := &types.VirtualMachineConfigSpec{
+				ExtraConfig: []types.BaseOptionValue{&types.OptionValue{Key: key, Value: val}},
+			}
+			_ = vm.applyExtraConfig(ctx, spec)
+		})
+	}
+}
+
+// variable returns the value of the given guestinfo variable, as requested by the toolbox info-get RPC
+func (t *vmToolbox) variable(key string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	val, ok := t.vars[strings.ToLower(key)]
+	return val, ok
+}
+
+// mirrorVariables makes the guestinfo.* ExtraConfig variables visible to the toolbox, the caller must hold the VM lock
+func (t *vmToolbox) mirrorVariables(options []types.BaseOptionValue) {
+	vars := make(map[string]string)
+
+	for _, o := range options {
+		opt := o.GetOptionValue()
+		key := strings.ToLower(opt.Key)
+		if strings.HasPrefix(key, toolbox.GuestVariablePrefix) {
+			vars[key], _ = opt.Value.(string)
+		}
+	}
+
+	t.mu.Lock()
+	t.vars = vars
+	t.mu.Unlock()
+}
+
+// wait returns the next vmbackup event, or nil if none is sent before the timeout
+func (t *vmToolbox) wait(timeout <-chan time.Time) *vmBackupEvent {
+	select {
+	case e := <-t.events:
+		return &e
+	case <-timeout:
+		return nil
+	}
+}
+
+func quiesceFault(code int, msg string) types.BaseMethodFault {
+	var fault types.BaseMethodFault
+	if code == toolbox.VMBackupScriptError {
+		fault = new(types.ApplicationQuiesceFault)
+	} else {
+		fault = new(types.FilesystemQuiesceFault)
+	}
+
+	fault.GetMethodFault().FaultMessage = []types.LocalizableMessage{
+		{
+			Key:     "msg.snapshot.quiesce.vmerr",
+			Message: fmt.Sprintf("An error occurred while quiescing the virtual machine: %s", msg),
+		},
+	}
+
+	return fault
+}
+
+// quiesce drives the vmbackup protocol as the VMX does, calling snapshot while the guest is quiesced.
+func (t *vmToolbox) quiesce(spec *types.VirtualMachineGuestQuiesceSpec, snapshot func()) types.BaseMethodFault {
+	for len(t.events) != 0 {
+		<-t.events // discard events from a previous operation
+	}
+
+	d := toolbox.DefaultVMBackupTimeout
+	if spec.Timeout > 0 {
+		d = time.Duration(spec.Timeout) * time.Minute
+	}
+	timeout := time.After(d)
+
+	reply := t.Dispatch([]byte("vmbackup.start 1"))
+	if msg, ok := bytes.CutPrefix(reply, []byte("ERR ")); ok {
+		return quiesceFault(toolbox.VMBackupInvalidState, string(msg))
+	}
+
+	var msg string
+
+	for committed := false; !committed; {
+		e := t.wait(timeout)
+		if e == nil {
+			t.Dispatch([]byte("vmbackup.abort"))
+			return quiesceFault(toolbox.VMBackupUnexpectedError, "timeout")
+		}
+
+		switch e.name {
+		case toolbox.VMBackupEventRequestorError:
+			msg = e.msg
+		case toolbox.VMBackupEventRequestorDone:
+			return quiesceFault(e.code, msg)
+		case toolbox.VMBackupEventSnapshotCommit:
+			committed = true
+		}
+	}
+
+	snapshot()
+
+	// The snapshot is consistent at this point, thaw errors are logged but do not fail the operation.
+	t.Dispatch([]byte("vmbackup.snapshotDone"))
+
+	for {
+		e := t.wait(timeout)
+		if e == nil {
+			log.Print("vmbackup: timeout waiting for guest thaw")
+			return nil
+		}
+
+		switch e.name {
+		case toolbox.VMBackupEventRequestorError:
+			log.Printf("vmbackup: guest thaw: %s", e.msg)
+		case toolbox.VMBackupEventRequestorDone:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,797 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/toolbox"
+	"github.com/vmware/govmomi/toolbox/hgfs"
+	"github.com/vmware/govmomi/toolbox/vix"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// ToolboxBackingOptionKey is the ExtraConfig key used to back a VM with an in-process toolbox.Service.
+// When set to "TRUE", the toolbox is started when the VM is powered on. Guest operations, guest power operations,
+// guest info and customization of VMs with a toolbox are then handled through the toolbox RPC dispatch.
+const ToolboxBackingOptionKey = "RUN.toolbox"
+
+// hgfsTransferSize is the size of each hgfs read and write request sent by the simulator
+const hgfsTransferSize = 0x8000
+
+// toolboxAuth is used for requests the VMX makes on its own behalf, such as copying a customization package.
+var toolboxAuth = &types.NamePasswordAuthentication{Username: "root", Password: "vcsim"}
+
+// guestTransfer is a file transfer URL created by InitiateFileTransfer{To,From}Guest
+type guestTransfer struct {
+	ctx  *Context // the transfer is served outside of the request that created it
+	tbx  *vmToolbox
+	auth types.BaseGuestAuthentication
+	size int64
+	attr *types.GuestPosixFileAttributes
+}
+
+var guestTransfers = make(map[string]*guestTransfer)
+
+// toolboxBacking returns true if the VM ExtraConfig enables the in-process toolbox
+func (vm *VirtualMachine) toolboxBacking() bool {
+	for _, opt := range vm.Config.ExtraConfig {
+		val := opt.GetOptionValue()
+		if val.Key == ToolboxBackingOptionKey {
+			s, _ := val.Value.(string)
+			return strings.EqualFold(s, "true")
+		}
+	}
+
+	return false
+}
+
+// startToolbox creates the toolbox if enabled by ExtraConfig and reports tools as running, if the VM has a toolbox.
+func (vm *VirtualMachine) startToolbox(ctx *Context) {
+	if vm.toolboxBacking() {
+		_ = vm.Toolbox(ctx)
+	}
+
+	if tbx := vm.tools(); tbx != nil {
+		tbx.running.Store(true)
+		ctx.Update(vm, toolsRunning)
+	}
+}
+
+// stopToolbox reports tools as not running, if the VM has a toolbox.
+func (vm *VirtualMachine) stopToolbox(ctx *Context) {
+	if tbx := vm.tools(); tbx != nil {
+		tbx.running.Store(false)
+		ctx.Update(vm, toolsNotRunning)
+	}
+}
+
+// prepareGuestOperation validates the tools state and credentials, returning the credentials in VIX format.
+// Tools are running once the power on task has started the toolbox, before the power state is changed.
+func (t *vmToolbox) prepareGuestOperation(ctx *Context, auth types.BaseGuestAuthentication) (uint32, []byte, types.BaseMethodFault) {
+	if !t.running.Load() {
+		if t.vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+			return 0, nil, &types.InvalidPowerState{
+				RequestedState: types.VirtualMachinePowerStatePoweredOn,
+				ExistingState:  t.vm.Runtime.PowerState,
+			}
+		}
+		return 0, nil, new(types.GuestOperationsUnavailable)
+	}
+
+	switch creds := auth.(type) {
+	case *types.NamePasswordAuthentication:
+		if creds.Username == "" || creds.Password == "" {
+			break
+		}
+
+		b, _ := (&vix.UserCredentialNamePassword{
+			Name:     creds.Username,
+			Password: creds.Password,
+		}).MarshalBinary()
+
+		return vix.UserCredentialTypeNamePassword, b, nil
+	case *types.SAMLTokenAuthentication:
+		name, fault := t.vm.samlTokenUser(ctx, creds)
+		if fault != nil {
+			return 0, nil, fault
+		}
+
+		// The token has been verified against the guest aliases, as the host does before relaying to tools
+		b, _ := (&vix.UserCredentialSAMLToken{
+			Token: creds.Token,
+			Name:  name,
+		}).MarshalBinary()
+
+		return vix.UserCredentialTypeSAMLBearerTokenHostVerified, b, nil
+	}
+
+	return 0, nil, new(types.InvalidGuestLogin)
+}
+
+// vixCommand sends a VIX command to the toolbox, as the VMX does via the Vix_1_Relayed_Command RPC,
+// returning the VIX error code and response data.
+func (t *vmToolbox) vixCommand(ctx *Context, auth types.BaseGuestAuthentication, op uint32, flags uint8, req encoding.BinaryMarshaler) (int, []byte, types.BaseMethodFault) {
+	ctype, creds, fault := t.prepareGuestOperation(ctx, auth)
+	if fault != nil {
+		return 0, nil, fault
+	}
+
+	body, err := req.MarshalBinary()
+	if err != nil {
+		return 0, nil, &types.GuestOperationsFault{}
+	}
+
+	var header vix.CommandRequestHeader
+	header.Magic = vix.CommandMagicWord
+	header.OpCode = op
+	header.CommonFlags = flags
+	header.BodyLength = uint32(len(body))
+	header.CredentialLength = uint32(len(creds))
+	header.UserCredentialType = ctype
+
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("Vix_1_Relayed_Command \"vcsim\"\x00")
+	_ = binary.Write(&buf, binary.LittleEndian, &header)
+	_, _ = buf.Write(body)
+	_, _ = buf.Write(creds)
+
+	reply, ok := bytes.CutPrefix(t.Dispatch(buf.Bytes()), []byte("OK "))
+	if !ok {
+		return 0, nil, new(types.GuestOperationsUnavailable)
+	}
+
+	// reply format: "rc errno data", where binary data is prefixed with '#' and text data is NULL terminated
+	f := bytes.SplitN(reply, []byte{' '}, 3)
+	if len(f) != 3 {
+		return 0, nil, new(types.GuestOperationsFault)
+	}
+
+	rc, _ := strconv.Atoi(string(f[0]))
+	data := f[2]
+	if flags&vix.CommandGuestReturnsBinary != 0 {
+		data = bytes.TrimPrefix(data, []byte{'#'})
+	} else {
+		data = bytes.TrimRight(data, "\x00")
+	}
+
+	return rc, data, nil
+}
+
+// vixFault maps a VIX error code to the fault returned by vCenter for the given guest file
+func vixFault(rc int, name string) types.BaseMethodFault {
+	file := types.FileFault{File: name}
+
+	switch rc {
+	case vix.OK:
+		return nil
+	case vix.AuthenticationFail:
+		return new(types.InvalidGuestLogin)
+	case vix.FileNotFound:
+		return &types.FileNotFound{FileFault: file}
+	case vix.FileAlreadyExists:
+		return &types.FileAlreadyExists{FileFault: file}
+	case vix.FileAccessError:
+		return new(types.GuestPermissionDenied)
+	case vix.NotAFile:
+		return &types.NotAFile{FileFault: file}
+	case vix.NotADirectory:
+		return &types.NotADirectory{FileFault: file}
+	case vix.DirectoryNotEmpty:
+		return &types.DirectoryNotEmpty{FileFault: file}
+	case vix.UnrecognizedCommandInGuest:
+		return new(types.OperationNotSupportedByGuest)
+	default:
+		return new(types.GuestOperationsFault)
+	}
+}
+
+// fileCommand sends a VIX command for the given guest file, mapping any VIX error to a fault
+func (t *vmToolbox) fileCommand(ctx *Context, auth types.BaseGuestAuthentication, op uint32, name string, req encoding.BinaryMarshaler) ([]byte, types.BaseMethodFault) {
+	rc, data, fault := t.vixCommand(ctx, auth, op, 0, req)
+	if fault != nil {
+		return nil, fault
+	}
+
+	return data, vixFault(rc, name)
+}
+
+func (t *vmToolbox) startProgram(ctx *Context, auth types.BaseGuestAuthentication, spec *types.GuestProgramSpec) (int64, types.BaseMethodFault) {
+	req := &vix.StartProgramRequest{
+		ProgramPath: spec.ProgramPath,
+		Arguments:   spec.Arguments,
+		WorkingDir:  spec.WorkingDirectory,
+		EnvVars:     spec.EnvVariables,
+	}
+
+	data, fault := t.fileCommand(ctx, auth, vix.CommandStartProgram, spec.ProgramPath, req)
+	if fault != nil {
+		return 0, fault
+	}
+
+	pid, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return 0, new(types.GuestOperationsFault)
+	}
+
+	return pid, nil
+}
+
+// vixProcess as encoded by the toolbox process.Manager
+type vixProcess struct {
+	Name      string `xml:"cmd"`
+	CmdLine   string `xml:"name"`
+	Pid       int64  `xml:"pid"`
+	Owner     string `xml:"user"`
+	StartTime int64  `xml:"start"`
+	ExitCode  int32  `xml:"eCode"`
+	EndTime   int64  `xml:"eTime"`
+}
+
+func (t *vmToolbox) listProcesses(ctx *Context, auth types.BaseGuestAuthentication, pids []int64) ([]types.GuestProcessInfo, types.BaseMethodFault) {
+	data, fault := t.fileCommand(ctx, auth, vix.CommandListProcessesEx, "", &vix.ListProcessesRequest{Pids: pids})
+	if fault != nil {
+		return nil, fault
+	}
+
+	var res struct {
+		Procs []vixProcess `xml:"proc"`
+	}
+	if err := xml.Unmarshal(vixXML(data), &res); err != nil {
+		return nil, new(types.GuestOperationsFault)
+	}
+
+	var procs []types.GuestProcessInfo
+
+	for _, p := range res.Procs {
+		var end *time.Time
+		if p.EndTime != 0 {
+			end = types.NewTime(time.Unix(p.EndTime, 0))
+		}
+
+		procs = append(procs, types.GuestProcessInfo{
+			Name:      p.Name,
+			Pid:       p.Pid,
+			Owner:     p.Owner,
+			CmdLine:   p.CmdLine,
+			StartTime: time.Unix(p.StartTime, 0),
+			EndTime:   end,
+			ExitCode:  p.ExitCode,
+		})
+	}
+
+	return procs, nil
+}
+
+func (t *vmToolbox) terminateProcess(ctx *Context, auth types.BaseGuestAuthentication, pid int64) types.BaseMethodFault {
+	req := new(vix.KillProcessRequest)
+	req.Body.Pid = pid
+
+	rc, _, fault := t.vixCommand(ctx, auth, vix.CommandTerminateProcess, 0, req)
+	if fault != nil {
+		return fault
+	}
+
+	if rc == vix.NoSuchProcess {
+		return &types.GuestProcessNotFound{Pid: pid}
+	}
+
+	return vixFault(rc, "")
+}
+
+func (t *vmToolbox) readEnvironment(ctx *Context, auth types.BaseGuestAuthentication, names []string) ([]string, types.BaseMethodFault) {
+	data, fault := t.fileCommand(ctx, auth, vix.CommandReadEnvVariables, "", &vix.ReadEnvironmentVariablesRequest{Names: names})
+	if fault != nil {
+		return nil, fault
+	}
+
+	var res struct {
+		Env []string `xml:"ev"`
+	}
+	if err := xml.Unmarshal(vixXML(data), &res); err != nil {
+		return nil, new(types.GuestOperationsFault)
+	}
+
+	return res.Env, nil
+}
+
+func (t *vmToolbox) mktemp(ctx *Context, auth types.BaseGuestAuthentication, req *types.CreateTemporaryFileInGuest, dir bool) (string, types.BaseMethodFault) {
+	op := uint32(vix.CommandCreateTemporaryFileEx)
+	if dir {
+		op = vix.CommandCreateTemporaryDirectory
+	}
+
+	data, fault := t.fileCommand(ctx, auth, op, req.DirectoryPath, &vix.CreateTempFileRequest{
+		FilePrefix:    req.Prefix,
+		FileSuffix:    req.Suffix,
+		DirectoryPath: req.DirectoryPath,
+	})
+
+	return string(data), fault
+}
+
+func (t *vmToolbox) deleteFile(ctx *Context, auth types.BaseGuestAuthentication, name string) types.BaseMethodFault {
+	_, fault := t.fileCommand(ctx, auth, vix.CommandDeleteGuestFileEx, name, &vix.FileRequest{GuestPathName: name})
+	return fault
+}
+
+func (t *vmToolbox) deleteDirectory(ctx *Context, auth types.BaseGuestAuthentication, name string, recursive bool) types.BaseMethodFault {
+	req := &vix.DirRequest{GuestPathName: name}
+	req.Body.Recursive = recursive
+
+	_, fault := t.fileCommand(ctx, auth, vix.CommandDeleteGuestDirectoryEx, name, req)
+	return fault
+}
+
+func (t *vmToolbox) makeDirectory(ctx *Context, auth types.BaseGuestAuthentication, name string, parents bool) types.BaseMethodFault {
+	req := &vix.DirRequest{GuestPathName: name}
+	req.Body.Recursive = parents
+
+	_, fault := t.fileCommand(ctx, auth, vix.CommandCreateDirectoryEx, name, req)
+	return fault
+}
+
+func (t *vmToolbox) move(ctx *Context, auth types.BaseGuestAuthentication, op uint32, src, dst string, overwrite bool) types.BaseMethodFault {
+	req := &vix.RenameFileRequest{OldPathName: src, NewPathName: dst}
+	req.Body.Overwrite = overwrite
+
+	rc, _, fault := t.vixCommand(ctx, auth, op, 0, req)
+	if fault != nil {
+		return fault
+	}
+
+	name := src
+	if rc == vix.FileAlreadyExists {
+		name = dst
+	}
+
+	return vixFault(rc, name)
+}
+
+// vixFileInfo as encoded by the toolbox fileExtendedInfoFormat
+type vixFileInfo struct {
+	Name       string `xml:"Name"`
+	Type       int    `xml:"ft"`
+	Size       int64  `xml:"fs"`
+	ModTime    int64  `xml:"mt"`
+	AccessTime int64  `xml:"at"`
+	OwnerID    int32  `xml:"uid"`
+	GroupID    int32  `xml:"gid"`
+	Perm       int64  `xml:"perm"`
+	Target     string `xml:"slt"`
+}
+
+func (f *vixFileInfo) guestFileInfo() types.GuestFileInfo {
+	info := types.GuestFileInfo{
+		Path: f.Name,
+		Type: string(types.GuestFileTypeFile),
+		Size: f.Size,
+		Attributes: &types.GuestPosixFileAttributes{
+			GuestFileAttributes: types.GuestFileAttributes{
+				ModificationTime: types.NewTime(time.Unix(f.ModTime, 0)),
+				AccessTime:       types.NewTime(time.Unix(f.AccessTime, 0)),
+				SymlinkTarget:    f.Target,
+			},
+			OwnerId:     types.NewInt32(f.OwnerID),
+			GroupId:     types.NewInt32(f.GroupID),
+			Permissions: f.Perm,
+		},
+	}
+
+	switch {
+	case f.Type&vix.FileAttributesSymlink != 0:
+		info.Type = string(types.GuestFileTypeSymlink)
+	case f.Type&vix.FileAttributesDirectory != 0:
+		info.Type = string(types.GuestFileTypeDirectory)
+	}
+
+	return info
+}
+
+// vixXML wraps a VIX response in a root element for use with xml.Unmarshal
+func vixXML(data []byte) []byte {
+	return append(append([]byte("<r>"), data...), "</r>"...)
+}
+
+func decodeFileInfo(data []byte) (int32, []types.GuestFileInfo, error) {
+	var res struct {
+		Remaining int32         `xml:"rem"`
+		Files     []vixFileInfo `xml:"fxi"`
+	}
+
+	if err := xml.Unmarshal(vixXML(data), &res); err != nil {
+		return 0, nil, err
+	}
+
+	files := make([]types.GuestFileInfo, len(res.Files))
+	for i := range res.Files {
+		files[i] = res.Files[i].guestFileInfo()
+	}
+
+	return res.Remaining, files, nil
+}
+
+func (t *vmToolbox) listFiles(ctx *Context, auth types.BaseGuestAuthentication, req *types.ListFilesInGuest) (*types.GuestListFileInfo, types.BaseMethodFault) {
+	r := &vix.ListFilesRequest{
+		GuestPathName: req.FilePath,
+		Pattern:       req.MatchPattern,
+	}
+	r.Body.Index = req.Index
+	r.Body.MaxResults = req.MaxResults
+
+	data, fault := t.fileCommand(ctx, auth, vix.CommandListFiles, req.FilePath, r)
+	if fault != nil {
+		return nil, fault
+	}
+
+	remaining, files, err := decodeFileInfo(data)
+	if err != nil {
+		return nil, new(types.GuestOperationsFault)
+	}
+
+	return &types.GuestListFileInfo{Files: files, Remaining: remaining}, nil
+}
+
+func (t *vmToolbox) changeFileAttributes(ctx *Context, auth types.BaseGuestAuthentication, name string, attr *types.GuestPosixFileAttributes) types.BaseMethodFault {
+	req := &vix.SetGuestFileAttributesRequest{GuestPathName: name}
+
+	if attr.ModificationTime != nil {
+		req.Body.FileOptions |= vix.FileAttributeSetModifyDate
+		req.Body.ModificationTime = attr.ModificationTime.Unix()
+	}
+	if attr.AccessTime != nil {
+		req.Body.FileOptions |= vix.FileAttributeSetAccessDate
+		req.Body.AccessTime = attr.AccessTime.Unix()
+	}
+	if attr.OwnerId != nil {
+		req.Body.FileOptions |= vix.FileAttributeSetUnixOwnerid
+		req.Body.OwnerID = *attr.OwnerId
+	}
+	if attr.GroupId != nil {
+		req.Body.FileOptions |= vix.FileAttributeSetUnixGroupid
+		req.Body.GroupID = *attr.GroupId
+	}
+	if attr.Permissions != 0 {
+		req.Body.FileOptions |= vix.FileAttributeSetUnixPermissions
+		req.Body.Permissions = int32(attr.Permissions)
+	}
+
+	_, fault := t.fileCommand(ctx, auth, vix.CommandSetGuestFileAttributes, name, req)
+	return fault
+}
+
+func (t *vmToolbox) initiateFileTransferFromGuest(ctx *Context, auth types.BaseGuestAuthentication, name string) (*types.FileTransferInformation, types.BaseMethodFault) {
+	data, fault := t.fileCommand(ctx, auth, vix.CommandInitiateFileTransferFromGuest, name, &vix.ListFilesRequest{GuestPathName: name})
+	if fault != nil {
+		return nil, fault
+	}
+
+	info := new(types.FileTransferInformation)
+
+	if _, files, err := decodeFileInfo(data); err == nil && len(files) == 1 {
+		info.Size = files[0].Size
+		info.Attributes = files[0].Attributes
+	}
+
+	return info, nil
+}
+
+func (t *vmToolbox) initiateFileTransferToGuest(ctx *Context, auth types.BaseGuestAuthentication, name string, overwrite bool) types.BaseMethodFault {
+	req := &vix.InitiateFileTransferToGuestRequest{GuestPathName: name}
+	req.Body.Overwrite = overwrite
+
+	_, fault := t.fileCommand(ctx, auth, vix.CommandInitiateFileTransferToGuest, name, req)
+	return fault
+}
+
+// transferURL returns a single use URL for transferring the given guest file via ServeGuest,
+// size is the file size reported by InitiateFileTransferFromGuest and attr are applied after an upload.
+func (t *vmToolbox) transferURL(ctx *Context, auth types.BaseGuestAuthentication, path string, size int64, attr *types.GuestPosixFileAttributes) string {
+	id := uuid.NewString()
+
+	toolboxMu.Lock()
+	guestTransfers[id] = &guestTransfer{
+		ctx: &Context{
+			Context: context.Background(),
+			Session: ctx.Session,
+			Map:     ctx.Map,
+		},
+		tbx:  t,
+		auth: auth,
+		size: size,
+		attr: attr,
+	}
+	toolboxMu.Unlock()
+
+	return (&url.URL{
+		Scheme: ctx.svc.Listen.Scheme,
+		Host:   "*", // See guest.FileManager.TransferURL
+		Path:   guestPrefix + strings.TrimPrefix(path, "/"),
+		RawQuery: url.Values{
+			"id":    []string{id},
+			"token": []string{ctx.Session.Key},
+		}.Encode(),
+	}).String()
+}
+
+// takeGuestTransfer returns the transfer for the given id, if any, which can only be used once
+func takeGuestTransfer(id string) *guestTransfer {
+	toolboxMu.Lock()
+	defer toolboxMu.Unlock()
+
+	x, ok := guestTransfers[id]
+	if ok {
+		delete(guestTransfers, id)
+	}
+
+	return x
+}
+
+func (x *guestTransfer) serve(w http.ResponseWriter, r *http.Request, name string) error {
+	switch r.Method {
+	case http.MethodPut:
+		defer r.Body.Close()
+		if err := x.tbx.upload(x.ctx, x.auth, name, r.Body); err != nil {
+			return err
+		}
+		if x.attr != nil {
+			if fault := x.tbx.changeFileAttributes(x.ctx, x.auth, name, x.attr); fault != nil {
+				return fmt.Errorf("%s: %T", name, fault)
+			}
+		}
+		return nil
+	case http.MethodGet:
+		return x.tbx.download(x.ctx, x.auth, name, w, x.size)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return nil
+	}
+}
+
+// hgfsClient returns an hgfs client session, sending requests via the toolbox VIX HgfsSendPacket command
+func (t *vmToolbox) hgfsClient(ctx *Context, auth types.BaseGuestAuthentication) (*hgfs.Client, error) {
+	c := hgfs.NewClient(func(packet []byte) ([]byte, error) {
+		r := new(vix.CommandHgfsSendPacket)
+		r.Packet = packet
+		r.Body.PacketSize = uint32(len(packet))
+
+		rc, data, fault := t.vixCommand(ctx, auth, vix.HgfsSendPacketCommand, vix.CommandGuestReturnsBinary, r)
+		if fault != nil {
+			return nil, fmt.Errorf("hgfs: %T", fault)
+		}
+		if rc != vix.OK {
+			return nil, vix.Error(rc)
+		}
+
+		return data, nil
+	})
+
+	if err := c.CreateSession(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// upload writes the contents of r to the given guest file, as the VMX does for InitiateFileTransferToGuest
+func (t *vmToolbox) upload(ctx *Context, auth types.BaseGuestAuthentication, name string, r io.Reader) error {
+	c, err := t.hgfsClient(ctx, auth)
+	if err != nil {
+		return err
+	}
+	defer c.DestroySession()
+
+	handle, err := c.Open(name, hgfs.OpenModeWriteOnly)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, hgfsTransferSize)
+	var offset uint64
+
+	for {
+		n, rerr := io.ReadFull(r, buf)
+		if n != 0 {
+			if _, err = c.Write(handle, offset, buf[:n]); err != nil {
+				break
+			}
+			offset += uint64(n)
+		}
+		if rerr != nil {
+			if rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+				err = rerr
+			}
+			break
+		}
+	}
+
+	if cerr := c.Close(handle); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// download writes the contents of the given guest file to w, as the VMX does for InitiateFileTransferFromGuest.
+// As with the VMX, Content-Length is the actual size if the data fits in the first read, otherwise the given size.
+func (t *vmToolbox) download(ctx *Context, auth types.BaseGuestAuthentication, name string, w http.ResponseWriter, size int64) error {
+	c, err := t.hgfsClient(ctx, auth)
+	if err != nil {
+		return err
+	}
+	defer c.DestroySession()
+
+	handle, err := c.Open(name, hgfs.OpenModeReadOnly)
+	if err != nil {
+		return err
+	}
+
+	var offset uint64
+
+	for {
+		var data []byte
+		if data, err = c.Read(handle, offset, hgfsTransferSize); err != nil {
+			break
+		}
+		if offset == 0 {
+			if len(data) < hgfsTransferSize {
+				size = int64(len(data))
+			}
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		}
+		if len(data) == 0 {
+			break
+		}
+		if _, err = w.Write(data); err != nil {
+			break
+		}
+		offset += uint64(len(data))
+	}
+
+	if cerr := c.Close(handle); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// powerOp sends a guest power operation RPC, returning false if the toolbox does not support the operation
+func (t *vmToolbox) powerOp(name string) bool {
+	for len(t.power) != 0 {
+		<-t.power
+	}
+
+	t.Dispatch([]byte(name))
+
+	// the status is sent by the toolbox before the Dispatch call returns
+	select {
+	case status := <-t.power:
+		return strings.HasPrefix(status, "1 ")
+	default:
+		return false
+	}
+}
+
+// customizationConfig converts a CustomizationSpec to the toolbox cust.cfg format
+func customizationConfig(spec *types.CustomizationSpec, hostname string, macs []string) *toolbox.CustomizationConfig {
+	config := &toolbox.CustomizationConfig{
+		HostName:    hostname,
+		DNSServers:  spec.GlobalIPSettings.DnsServerList,
+		DNSSuffixes: spec.GlobalIPSettings.DnsSuffixList,
+	}
+
+	if prep, ok := spec.Identity.(*types.CustomizationLinuxPrep); ok {
+		config.DomainName = prep.Domain
+		config.TimeZone = prep.TimeZone
+		if prep.HwClockUTC != nil {
+			config.UTC = *prep.HwClockUTC
+		}
+	}
+
+	for i, s := range spec.NicSettingMap {
+		nic := toolbox.CustomizationNic{
+			Name:       fmt.Sprintf("eth%d", i),
+			MACAddress: s.MacAddress,
+			OnBoot:     true,
+			Gateways:   s.Adapter.Gateway,
+		}
+		if nic.MACAddress == "" && i < len(macs) {
+			nic.MACAddress = macs[i]
+		}
+
+		switch ip := s.Adapter.Ip.(type) {
+		case *types.CustomizationFixedIp:
+			nic.IPAddress = ip.IpAddress
+			nic.Netmask = s.Adapter.SubnetMask
+		default:
+			nic.DHCP = true
+		}
+
+		if ipv6 := s.Adapter.IpV6Spec; ipv6 != nil {
+			for _, x := range ipv6.Ip {
+				if ip, ok := x.(*types.CustomizationFixedIpV6); ok {
+					nic.IPv6 = append(nic.IPv6, toolbox.CustomizationIPv6{Address: ip.IpAddress, Prefix: int(ip.SubnetMask)})
+				}
+			}
+			nic.IPv6Gateways = ipv6.Gateway
+		}
+
+		config.Nics = append(config.Nics, nic)
+	}
+
+	return config
+}
+
+// deployPkg sends the customization package to the toolbox, as the VMX does.
+// The package is not sent if the toolbox does not support customization.
+func (t *vmToolbox) deployPkg(ctx *Context, config *toolbox.CustomizationConfig) error {
+	pkg, err := toolbox.EncodeDeployPkg(config)
+	if err != nil {
+		return err
+	}
+
+	name, ok := bytes.CutPrefix(t.Dispatch([]byte("deployPkg.begin")), []byte("OK "))
+	if !ok {
+		return nil // not supported
+	}
+
+	if err = t.upload(ctx, toolboxAuth, string(name), bytes.NewReader(pkg)); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.deployStatus = ""
+	t.mu.Unlock()
+
+	if _, ok = bytes.CutPrefix(t.Dispatch(append([]byte("deployPkg.deploy "), name...)), []byte("OK ")); ok {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.deployStatus == "" {
+		return errors.New("deployPkg failed")
+	}
+	return errors.New(t.deployStatus)
+}
+
+// setDeployPkgState handles the deployPkg.update.state request sent by the toolbox
+func (t *vmToolbox) setDeployPkgState(args string) {
+	f := strings.SplitN(args, " ", 3)
+	if len(f) != 3 || f[1] == "0" {
+		return
+	}
+
+	t.mu.Lock()
+	t.deployStatus = strings.TrimRight(f[2], "\x00")
+	t.mu.Unlock()
+}
+
+// guestMacAddresses returns the MAC address of each ethernet card
+func guestMacAddresses(devices []types.BaseVirtualDevice) []string {
+	var macs []string
+
+	for _, d := range devices {
+		if nic, ok := d.(types.BaseVirtualEthernetCard); ok {
+			macs = append(macs, nic.GetVirtualEthernetCard().MacAddress)
+		}
+	}
+
+	return macs
+}
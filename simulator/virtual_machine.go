@@ -24,6 +24,7 @@ import (
 
 	"github.com/vmware/govmomi/internal"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/ovf"
 	"github.com/vmware/govmomi/simulator/esx"
 	"github.com/vmware/govmomi/units"
 	"github.com/vmware/govmomi/vim25/methods"
@@ -36,12 +37,19 @@ import (
 type VirtualMachine struct {
 	mo.VirtualMachine
 	DataSets map[string]*DataSet
+	// StorageProfile maps the VM home (VirtualMachineHomeProfileKey) and disk device keys to the storage policy ID
+	// associated via VmProfile and VirtualDeviceConfigSpec.Profile, which is not exposed as a vim property.
+	StorageProfile map[int32]string
 
 	log string
 	sid int32
 	svm *simVM
+	tbx *vmToolbox
 	uid uuid.UUID
 	imc *types.CustomizationSpec
+
+	aliases  []guestAlias
+	registry *registryKey
 }
 
 func asVirtualMachineMO(obj mo.Reference) (*mo.VirtualMachine, bool) {
@@ -291,6 +299,10 @@ func (vm *VirtualMachine) apply(spec *types.VirtualMachineConfigSpec) {
 		vm.Config.Tools = spec.Tools
 	}
 
+	if spec.ScheduledHardwareUpgradeInfo != nil {
+		vm.Config.ScheduledHardwareUpgradeInfo = spec.ScheduledHardwareUpgradeInfo
+	}
+
 	if spec.ConsolePreferences != nil {
 		vm.Config.ConsolePreferences = spec.ConsolePreferences
 	}
@@ -413,6 +425,10 @@ func (vm *VirtualMachine) updateVAppProperty(spec *types.VmConfigSpec) types.Bas
 	info.Product = productInfo
 	info.Property = propertyInfo
 
+	if spec.OvfEnvironmentTransport != nil {
+		info.OvfEnvironmentTransport = spec.OvfEnvironmentTransport
+	}
+
 	return nil
 }
 
@@ -498,6 +514,10 @@ func (vm *VirtualMachine) applyExtraConfig(ctx *Context, spec *types.VirtualMach
 		}
 	}
 
+	if tbx := vm.tools(); tbx != nil {
+		tbx.mirrorVariables(vm.Config.ExtraConfig)
+	}
+
 	// create the container backing before we publish the updates so the simVM is available before handlers
 	// get triggered
 	var fault types.BaseMethodFault
@@ -541,6 +561,11 @@ func (vm *VirtualMachine) applyExtraConfig(ctx *Context, spec *types.VirtualMach
 	change := types.PropertyChange{Name: field.Path, Val: vm.Config.ExtraConfig}
 	ctx.Update(vm, append(changes, change))
 
+	// start the toolbox backing if enabled while the VM is already powered on
+	if vm.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn && vm.toolboxBacking() && vm.tools() == nil {
+		vm.startToolbox(ctx)
+	}
+
 	return fault
 }
 
@@ -1987,6 +2012,25 @@ func (vm *VirtualMachine) updateCrypto(
 	return nil
 }
 
+// VirtualMachineHomeProfileKey is the VirtualMachine.StorageProfile key for the VM home storage policy.
+const VirtualMachineHomeProfileKey = int32(-1)
+
+// applyProfile records the storage policy associated with the VM home or a disk.
+// The Empty and Default profile specs remove an existing association.
+func (vm *VirtualMachine) applyProfile(key int32, profile []types.BaseVirtualMachineProfileSpec) {
+	for _, spec := range profile {
+		switch p := spec.(type) {
+		case *types.VirtualMachineDefinedProfileSpec:
+			if vm.StorageProfile == nil {
+				vm.StorageProfile = make(map[int32]string)
+			}
+			vm.StorageProfile[key] = p.ProfileId
+		case *types.VirtualMachineEmptyProfileSpec, *types.VirtualMachineDefaultProfileSpec:
+			delete(vm.StorageProfile, key)
+		}
+	}
+}
+
 func (vm *VirtualMachine) configureDevices(ctx *Context, spec *types.VirtualMachineConfigSpec) types.BaseMethodFault {
 	var changes []types.PropertyChange
 	field := mo.Field{Path: "config.hardware.device"}
@@ -2039,6 +2083,7 @@ func (vm *VirtualMachine) configureDevices(ctx *Context, spec *types.VirtualMach
 
 			devices = append(devices, dspec.Device)
 			change.Val = dspec.Device
+			vm.applyProfile(device.Key, dspec.Profile)
 			if key != device.Key {
 				// Update ControllerKey refs
 				for i := range spec.DeviceChange {
@@ -2067,10 +2112,12 @@ func (vm *VirtualMachine) configureDevices(ctx *Context, spec *types.VirtualMach
 
 			devices = append(devices, dspec.Device)
 			change.Val = dspec.Device
+			vm.applyProfile(device.Key, dspec.Profile)
 		case types.VirtualDeviceConfigSpecOperationRemove:
 			change.Op = types.PropertyChangeOpRemove
 
 			devices = vm.removeDevice(ctx, devices, dspec)
+			delete(vm.StorageProfile, device.Key)
 		}
 
 		field.Key = device.Key
@@ -2088,6 +2135,8 @@ func (vm *VirtualMachine) configureDevices(ctx *Context, spec *types.VirtualMach
 		return err
 	}
 
+	vm.applyProfile(VirtualMachineHomeProfileKey, spec.VmProfile)
+
 	// Do this after device config, as some may apply to the devices themselves (e.g. ethernet -> guest.net)
 	err = vm.applyExtraConfig(ctx, spec)
 	if err != nil {
@@ -2141,9 +2190,12 @@ func (c *powerVMTask) Run(task *Task) (types.AnyType, types.BaseMethodFault) {
 			&types.VmStartingEvent{VmEvent: event},
 			&types.VmPoweredOnEvent{VmEvent: event},
 		)
+		c.startToolbox(c.ctx)
 		c.customize(c.ctx)
+		c.ovfEnv(c.ctx)
 	case types.VirtualMachinePowerStatePoweredOff:
 		c.svm.stop(c.ctx)
+		c.stopToolbox(c.ctx)
 		c.ctx.postEvent(
 			&types.VmStoppingEvent{VmEvent: event},
 			&types.VmPoweredOffEvent{VmEvent: event},
@@ -2157,6 +2209,7 @@ func (c *powerVMTask) Run(task *Task) (types.AnyType, types.BaseMethodFault) {
 		}
 
 		c.svm.pause(c.ctx)
+		c.stopToolbox(c.ctx)
 		c.ctx.postEvent(
 			&types.VmSuspendingEvent{VmEvent: event},
 			&types.VmSuspendedEvent{VmEvent: event},
@@ -2262,6 +2315,11 @@ func (vm *VirtualMachine) RebootGuest(ctx *Context, req *types.RebootGuest) soap
 		return body
 	}
 
+	if tbx := vm.tools(); tbx != nil && !tbx.powerOp("OS_Reboot") {
+		body.Fault_ = Fault("", new(types.ToolsUnavailable))
+		return body
+	}
+
 	if vm.Guest.ToolsRunningStatus == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
 		vm.svm.restart(ctx)
 		body.Res = new(types.RebootGuestResponse)
@@ -2472,6 +2530,8 @@ func (vm *VirtualMachine) DestroyTask(ctx *Context, req *types.Destroy_Task) soa
 						LocalizedMessage: err.Error()}}}
 		}
 
+		vm.closeToolbox()
+
 		return nil, nil
 	})
 
@@ -2659,6 +2719,10 @@ func (vm *VirtualMachine) CloneVMTask(ctx *Context, req *types.CloneVM_Task) soa
 		if req.Spec.Config != nil && req.Spec.Config.DeviceChange != nil {
 			clone.configureDevices(ctx, &types.VirtualMachineConfigSpec{DeviceChange: req.Spec.Config.DeviceChange})
 		}
+		clone.applyProfile(VirtualMachineHomeProfileKey, req.Spec.Location.Profile)
+		for _, disk := range req.Spec.Location.Disk {
+			clone.applyProfile(disk.DiskId, disk.Profile)
+		}
 		clone.DataSets = copyDataSetsForVmClone(vm.DataSets)
 
 		if req.Spec.Template {
@@ -2737,6 +2801,11 @@ func (vm *VirtualMachine) RelocateVMTask(ctx *Context, req *types.RelocateVM_Tas
 			return nil, err
 		}
 
+		vm.applyProfile(VirtualMachineHomeProfileKey, req.Spec.Profile)
+		for _, disk := range req.Spec.Disk {
+			vm.applyProfile(disk.DiskId, disk.Profile)
+		}
+
 		ctx.postEvent(&types.VmMigratedEvent{
 			VmEvent:          vm.event(ctx),
 			SourceHost:       *ctx.Map.Get(*vm.Runtime.Host).(*HostSystem).eventArgument(),
@@ -2756,6 +2825,98 @@ func (vm *VirtualMachine) RelocateVMTask(ctx *Context, req *types.RelocateVM_Tas
 	}
 }
 
+// ovfEnv provides the OVF environment to the guest, using the VM's vApp transports.
+func (vm *VirtualMachine) ovfEnv(ctx *Context) {
+	if vm.Config.VAppConfig == nil {
+		return
+	}
+
+	info := vm.Config.VAppConfig.GetVmConfigInfo()
+	if len(info.OvfEnvironmentTransport) == 0 {
+		return
+	}
+
+	about := ctx.Map.content().About
+	env := ovf.Env{
+		EsxID: vm.Self.Value,
+		Platform: &ovf.PlatformSection{
+			Kind:    about.Name,
+			Version: about.Version,
+			Vendor:  about.Vendor,
+			Locale:  "en",
+		},
+		Property: &ovf.PropertySection{},
+	}
+
+	for _, p := range info.Property {
+		key := p.Id
+		if p.ClassId != "" {
+			key = p.ClassId + "." + key
+		}
+		if p.InstanceId != "" {
+			key += "." + p.InstanceId
+		}
+
+		val := p.Value
+		if val == "" {
+			val = p.DefaultValue
+		}
+
+		env.Property.Properties = append(env.Property.Properties, ovf.EnvProperty{Key: key, Value: val})
+	}
+
+	for _, transport := range info.OvfEnvironmentTransport {
+		switch transport {
+		case ovf.EnvTransportGuestInfo:
+			_ = vm.applyExtraConfig(ctx, &types.VirtualMachineConfigSpec{
+				ExtraConfig: []types.BaseOptionValue{
+					&types.OptionValue{Key: ovf.EnvGuestInfoKey, Value: env.MarshalManual()},
+				},
+			})
+		case ovf.EnvTransportISO:
+			vm.ovfEnvISO(ctx, env)
+		}
+	}
+}
+
+// ovfEnvISO writes the OVF environment ISO to the VM's directory and inserts it in the first CD-ROM device.
+func (vm *VirtualMachine) ovfEnvISO(ctx *Context, env ovf.Env) {
+	cdrom, err := object.VirtualDeviceList(vm.Config.Hardware.Device).FindCdrom("")
+	if err != nil {
+		vm.logPrintf("ovfEnv: %s", err)
+		return
+	}
+
+	iso, err := env.MarshalISO()
+	if err != nil {
+		vm.logPrintf("ovfEnv: %s", err)
+		return
+	}
+
+	p := vm.vmx(nil)
+	dir := p.Path
+	if path.Ext(dir) == ".vmx" {
+		dir = path.Dir(dir)
+	}
+	p.Path = path.Join(dir, "ovf-env.iso")
+
+	ds := vm.useDatastore(ctx, p.Datastore)
+	if err = os.WriteFile(ds.resolve(ctx, p.Path), iso, 0600); err != nil {
+		vm.logPrintf("ovfEnv: %s", err)
+		return
+	}
+
+	cdrom.Backing = &types.VirtualCdromIsoBackingInfo{
+		VirtualDeviceFileBackingInfo: types.VirtualDeviceFileBackingInfo{
+			FileName:  p.String(),
+			Datastore: &ds.Self,
+		},
+	}
+	if cdrom.Connectable != nil {
+		cdrom.Connectable.StartConnected = true
+	}
+}
+
 func (vm *VirtualMachine) customize(ctx *Context) {
 	if vm.imc == nil {
 		return
@@ -2791,6 +2952,20 @@ func (vm *VirtualMachine) customize(ctx *Context) {
 		hostname = customizeName(vm, c.UserData.ComputerName)
 	}
 
+	if tbx := vm.tools(); tbx != nil {
+		config := customizationConfig(vm.imc, hostname, guestMacAddresses(vm.Config.Hardware.Device))
+		if err := tbx.deployPkg(ctx, config); err != nil {
+			ctx.postEvent(&types.CustomizationFailed{
+				CustomizationEvent: event,
+				Reason:             err.Error(),
+			})
+
+			vm.imc = nil
+			ctx.Update(vm, changes)
+			return
+		}
+	}
+
 	cards := object.VirtualDeviceList(vm.Config.Hardware.Device).SelectByType((*types.VirtualEthernetCard)(nil))
 
 	for i, s := range vm.imc.NicSettingMap {
@@ -2913,68 +3088,87 @@ func (vm *VirtualMachine) CreateSnapshotTask(ctx *Context, req *types.CreateSnap
 	return body
 }
 
-func (vm *VirtualMachine) CreateSnapshotExTask(ctx *Context, req *types.CreateSnapshotEx_Task) soap.HasFault {
-	task := CreateTask(vm, "createSnapshotEx", func(t *Task) (types.AnyType, types.BaseMethodFault) {
-		var changes []types.PropertyChange
+func (vm *VirtualMachine) createSnapshot(ctx *Context, req *types.CreateSnapshotEx_Task) types.ManagedObjectReference {
+	var changes []types.PropertyChange
 
-		if vm.Snapshot == nil {
-			vm.Snapshot = &types.VirtualMachineSnapshotInfo{}
-		}
+	if vm.Snapshot == nil {
+		vm.Snapshot = &types.VirtualMachineSnapshotInfo{}
+	}
 
-		snapshot := &VirtualMachineSnapshot{}
-		snapshot.Vm = vm.Reference()
-		snapshot.Config = copyConfigFromVmConfig(vm.Config)
-		snapshot.DataSets = copyDataSetsForVmClone(vm.DataSets)
+	snapshot := &VirtualMachineSnapshot{}
+	snapshot.Vm = vm.Reference()
+	snapshot.Config = copyConfigFromVmConfig(vm.Config)
+	snapshot.DataSets = copyDataSetsForVmClone(vm.DataSets)
 
-		ctx.Map.Put(snapshot)
+	ctx.Map.Put(snapshot)
 
-		quiesced := false
-		if req.QuiesceSpec != nil {
-			quiesced = true
-		}
+	quiesced := false
+	if req.QuiesceSpec != nil {
+		quiesced = true
+	}
 
-		snapPowerState := vm.Runtime.PowerState
-		if !req.Memory {
-			snapPowerState = types.VirtualMachinePowerStatePoweredOff
-		}
+	snapPowerState := vm.Runtime.PowerState
+	if !req.Memory {
+		snapPowerState = types.VirtualMachinePowerStatePoweredOff
+	}
 
-		treeItem := types.VirtualMachineSnapshotTree{
-			Snapshot:        snapshot.Self,
-			Vm:              snapshot.Vm,
-			Name:            req.Name,
-			Description:     req.Description,
-			Id:              atomic.AddInt32(&vm.sid, 1),
-			CreateTime:      time.Now(),
-			State:           snapPowerState,
-			Quiesced:        quiesced,
-			BackupManifest:  "",
-			ReplaySupported: types.NewBool(false),
-		}
+	treeItem := types.VirtualMachineSnapshotTree{
+		Snapshot:        snapshot.Self,
+		Vm:              snapshot.Vm,
+		Name:            req.Name,
+		Description:     req.Description,
+		Id:              atomic.AddInt32(&vm.sid, 1),
+		CreateTime:      time.Now(),
+		State:           snapPowerState,
+		Quiesced:        quiesced,
+		BackupManifest:  "",
+		ReplaySupported: types.NewBool(false),
+	}
 
-		cur := vm.Snapshot.CurrentSnapshot
-		if cur != nil {
-			parent := ctx.Map.Get(*cur).(*VirtualMachineSnapshot)
-			parent.ChildSnapshot = append(parent.ChildSnapshot, snapshot.Self)
+	cur := vm.Snapshot.CurrentSnapshot
+	if cur != nil {
+		parent := ctx.Map.Get(*cur).(*VirtualMachineSnapshot)
+		parent.ChildSnapshot = append(parent.ChildSnapshot, snapshot.Self)
 
-			ss := findSnapshotInTree(vm.Snapshot.RootSnapshotList, *cur)
-			ss.ChildSnapshotList = append(ss.ChildSnapshotList, treeItem)
-		} else {
-			changes = append(changes, types.PropertyChange{
-				Name: "snapshot.rootSnapshotList",
-				Val:  append(vm.Snapshot.RootSnapshotList, treeItem),
-			})
-			changes = append(changes, types.PropertyChange{
-				Name: "rootSnapshot",
-				Val:  append(vm.RootSnapshot, treeItem.Snapshot),
-			})
-		}
+		ss := findSnapshotInTree(vm.Snapshot.RootSnapshotList, *cur)
+		ss.ChildSnapshotList = append(ss.ChildSnapshotList, treeItem)
+	} else {
+		changes = append(changes, types.PropertyChange{
+			Name: "snapshot.rootSnapshotList",
+			Val:  append(vm.Snapshot.RootSnapshotList, treeItem),
+		})
+		changes = append(changes, types.PropertyChange{
+			Name: "rootSnapshot",
+			Val:  append(vm.RootSnapshot, treeItem.Snapshot),
+		})
+	}
 
-		snapshot.createSnapshotFiles(ctx)
+	snapshot.createSnapshotFiles(ctx)
 
-		changes = append(changes, types.PropertyChange{Name: "snapshot.currentSnapshot", Val: snapshot.Self})
-		ctx.Update(vm, changes)
+	changes = append(changes, types.PropertyChange{Name: "snapshot.currentSnapshot", Val: snapshot.Self})
+	ctx.Update(vm, changes)
+
+	return snapshot.Self
+}
+
+func (vm *VirtualMachine) CreateSnapshotExTask(ctx *Context, req *types.CreateSnapshotEx_Task) soap.HasFault {
+	task := CreateTask(vm, "createSnapshotEx", func(t *Task) (types.AnyType, types.BaseMethodFault) {
+		if req.QuiesceSpec != nil && vm.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn {
+			if tbx := vm.tools(); tbx != nil {
+				var snapshot types.ManagedObjectReference
+
+				fault := tbx.quiesce(req.QuiesceSpec.GetVirtualMachineGuestQuiesceSpec(), func() {
+					snapshot = vm.createSnapshot(ctx, req)
+				})
+				if fault != nil {
+					return nil, fault
+				}
+
+				return snapshot, nil
+			}
+		}
 
-		return snapshot.Self, nil
+		return vm.createSnapshot(ctx, req), nil
 	})
 
 	return &methods.CreateSnapshotEx_TaskBody{
@@ -3160,11 +3354,17 @@ func (vm *VirtualMachine) ShutdownGuest(ctx *Context, c *types.ShutdownGuest) so
 		return r
 	}
 
+	if tbx := vm.tools(); tbx != nil && !tbx.powerOp("OS_Halt") {
+		r.Fault_ = Fault("", new(types.ToolsUnavailable))
+		return r
+	}
+
 	event := vm.event(ctx)
 	ctx.postEvent(&types.VmGuestShutdownEvent{VmEvent: event})
 
 	_ = CreateTask(vm, "shutdownGuest", func(*Task) (types.AnyType, types.BaseMethodFault) {
 		vm.svm.stop(ctx)
+		vm.stopToolbox(ctx)
 
 		ctx.Update(vm, []types.PropertyChange{
 			{Name: "runtime.powerState", Val: types.VirtualMachinePowerStatePoweredOff},
@@ -3193,11 +3393,17 @@ func (vm *VirtualMachine) StandbyGuest(ctx *Context, c *types.StandbyGuest) soap
 		return r
 	}
 
+	if tbx := vm.tools(); tbx != nil && !tbx.powerOp("OS_Suspend") {
+		r.Fault_ = Fault("", new(types.ToolsUnavailable))
+		return r
+	}
+
 	event := vm.event(ctx)
 	ctx.postEvent(&types.VmGuestStandbyEvent{VmEvent: event})
 
 	_ = CreateTask(vm, "standbyGuest", func(*Task) (types.AnyType, types.BaseMethodFault) {
 		vm.svm.pause(ctx)
+		vm.stopToolbox(ctx)
 
 		ctx.Update(vm, []types.PropertyChange{
 			{Name: "runtime.powerState", Val: types.VirtualMachinePowerStateSuspended},
@@ -6,9 +6,13 @@ package simulator
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
+	"net"
 	"os"
+	"path"
 	"reflect"
 	"strings"
 	"testing"
@@ -22,9 +26,11 @@ import (
 	"github.com/vmware/govmomi/fault"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/ovf"
 	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/simulator/esx"
 	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/toolbox"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
@@ -2112,6 +2118,233 @@ func TestVmSnapshotEx(t *testing.T) {
 	}, esx)
 }
 
+func TestVmSnapshotQuiesce(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		simVm := Map(ctx).Any("VirtualMachine").(*VirtualMachine)
+		vm := object.NewVirtualMachine(c, simVm.Reference())
+
+		var calls []string
+		tbx := simVm.Toolbox(ctx.(*Context))
+		tbx.VMBackup.Freeze = func() error {
+			calls = append(calls, "freeze")
+			return nil
+		}
+		tbx.VMBackup.Thaw = func() error {
+			calls = append(calls, "thaw")
+			return nil
+		}
+
+		task, err := vm.CreateSnapshot(ctx, "quiesced", "", false, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(calls, []string{"freeze", "thaw"}) {
+			t.Errorf("calls=%v", calls)
+		}
+
+		ref, err := vm.FindSnapshot(ctx, "quiesced")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref.Value != simVm.Snapshot.RootSnapshotList[0].Snapshot.Value || !simVm.Snapshot.RootSnapshotList[0].Quiesced {
+			t.Errorf("snapshot=%#v", simVm.Snapshot.RootSnapshotList[0])
+		}
+
+		// hooks are not called without quiesce
+		calls = nil
+		task, err = vm.CreateSnapshot(ctx, "crash-consistent", "", false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if len(calls) != 0 {
+			t.Errorf("calls=%v", calls)
+		}
+
+		tbx.VMBackup.Freeze = func() error {
+			return errors.New("device busy")
+		}
+
+		task, err = vm.CreateSnapshot(ctx, "failed", "", false, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = task.Wait(ctx)
+		if !fault.Is(err, &types.FilesystemQuiesceFault{}) {
+			t.Errorf("err=%v", err)
+		}
+
+		if _, err = vm.FindSnapshot(ctx, "failed"); err == nil {
+			t.Error("snapshot should not be created when quiesce fails")
+		}
+	})
+}
+
+func TestVmToolboxGuestInfo(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		simVm := Map(ctx).Any("VirtualMachine").(*VirtualMachine)
+		vm := object.NewVirtualMachine(c, simVm.Reference())
+
+		tbx := simVm.Toolbox(ctx.(*Context))
+
+		tbx.GuestInfo.NicInfo = func() *toolbox.GuestNicInfo {
+			info := toolbox.NewGuestNicInfo()
+			nic := toolbox.GuestNicV3{MacAddress: "00:50:56:aa:bb:cc"}
+			nic.AddIP(&net.IPNet{IP: net.ParseIP("10.0.0.42"), Mask: net.CIDRMask(24, 32)})
+			info.V3.Nics = append(info.V3.Nics, nic)
+			return info
+		}
+		tbx.GuestInfo.DiskInfo = func() (*toolbox.GuestDiskInfo, error) {
+			return &toolbox.GuestDiskInfo{
+				Version: "1",
+				Disks:   []toolbox.GuestDiskEntry{{Name: "/", Free: 1 << 30, Size: 4 << 30, Type: "ext4"}},
+			}, nil
+		}
+		tbx.GuestInfo.OSInfo = func() (*toolbox.GuestOSInfo, error) {
+			return &toolbox.GuestOSInfo{
+				ShortName:     "ubuntu-64",
+				FullName:      "Ubuntu 24.04 LTS",
+				FamilyName:    "Linux",
+				KernelVersion: "6.8.0",
+				Bitness:       64,
+			}, nil
+		}
+		tbx.GuestInfo.Uptime = func() (time.Duration, error) {
+			return time.Hour, nil
+		}
+		tbx.GuestInfo.AppInfo = func() ([]toolbox.GuestApp, error) {
+			return []toolbox.GuestApp{{Name: "sshd"}}, nil
+		}
+
+		tbx.SendGuestInfo()
+
+		var mvm mo.VirtualMachine
+		props := []string{"guest", "summary", "config.extraConfig"}
+
+		for i := 0; ; i++ {
+			if err := vm.Properties(ctx, vm.Reference(), props, &mvm); err != nil {
+				t.Fatal(err)
+			}
+			if mvm.Guest.IpAddress == "10.0.0.42" && len(mvm.Guest.Disk) == 1 {
+				break
+			}
+			if i == 100 {
+				t.Fatalf("guest=%#v", mvm.Guest)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		g := mvm.Guest
+		if g.GuestFullName != "Ubuntu 24.04 LTS" || g.GuestId != string(types.VirtualMachineGuestOsIdentifierUbuntu64Guest) {
+			t.Errorf("guest=%s (%s)", g.GuestFullName, g.GuestId)
+		}
+		if !strings.Contains(g.GuestDetailedData, "kernelVersion='6.8.0'") {
+			t.Errorf("detailed=%s", g.GuestDetailedData)
+		}
+		if g.Disk[0].DiskPath != "/" || g.Disk[0].Capacity != 4<<30 || g.Disk[0].FreeSpace != 1<<30 {
+			t.Errorf("disk=%#v", g.Disk[0])
+		}
+		if len(g.Net) != 1 || g.Net[0].MacAddress != "00:50:56:aa:bb:cc" {
+			t.Errorf("net=%#v", g.Net)
+		}
+		if mvm.Summary.QuickStats.UptimeSeconds != 3600 {
+			t.Errorf("uptime=%d", mvm.Summary.QuickStats.UptimeSeconds)
+		}
+
+		val, ok := object.OptionValueList(mvm.Config.ExtraConfig).GetString(toolbox.GuestInfoAppInfoKey)
+		if !ok || !strings.Contains(val, `"a":"sshd"`) {
+			t.Errorf("appInfo=%s", val)
+		}
+	})
+}
+
+func TestVmToolboxVariables(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		simVm := Map(ctx).Any("VirtualMachine").(*VirtualMachine)
+		vm := object.NewVirtualMachine(c, simVm.Reference())
+
+		vars := simVm.Toolbox(ctx.(*Context)).Variables
+
+		type message struct {
+			ID   int    `json:"id"`
+			Body string `json:"body"`
+		}
+
+		// host -> guest
+		if err := vm.SetGuestInfoJSON(ctx, "request", message{1, "ping"}); err != nil {
+			t.Fatal(err)
+		}
+
+		var req message
+		if err := vars.GetJSON("guestinfo.request", &req); err != nil {
+			t.Fatal(err)
+		}
+		if req.ID != 1 || req.Body != "ping" {
+			t.Errorf("request=%#v", req)
+		}
+
+		// guest -> host
+		if err := vars.SetJSON("response", message{req.ID, "pong"}); err != nil {
+			t.Fatal(err)
+		}
+
+		val, err := vm.WaitForGuestInfo(ctx, "response", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var res message
+		if err = json.Unmarshal([]byte(val), &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.ID != 1 || res.Body != "pong" {
+			t.Errorf("response=%#v", res)
+		}
+
+		res = message{}
+		if err = vm.GuestInfoJSON(ctx, "guestinfo.response", &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Body != "pong" {
+			t.Errorf("response=%#v", res)
+		}
+
+		// the previous response is ignored
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_ = vars.SetJSON("response", message{2, "pong"})
+		}()
+
+		val, err = vm.WaitForGuestInfo(ctx, "response", val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = json.Unmarshal([]byte(val), &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.ID != 2 {
+			t.Errorf("response=%#v", res)
+		}
+
+		// removed by the host
+		if err = vm.SetGuestInfo(ctx, "request", ""); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = vars.Get("request"); !errors.Is(err, toolbox.ErrGuestVariableNotFound) {
+			t.Errorf("err=%v", err)
+		}
+		if _, err = vm.GuestInfo(ctx, "request"); !errors.Is(err, object.ErrGuestInfoNotFound) {
+			t.Errorf("err=%v", err)
+		}
+	})
+}
+
 func TestVmMarkAsTemplate(t *testing.T) {
 	ctx := context.Background()
 
@@ -2468,6 +2701,120 @@ func TestApplyExtraConfig(t *testing.T) {
 	})
 }
 
+func TestOvfEnvTransport(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, Map(ctx).Any("VirtualMachine").Reference())
+
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		devices, err := vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = devices.FindCdrom(""); err != nil {
+			ide, err := devices.FindIDEController("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			cdrom, err := devices.CreateCdrom(ide)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = vm.AddDevice(ctx, cdrom); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		task, err = vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
+			VAppConfig: &types.VmConfigSpec{
+				OvfEnvironmentTransport: []string{ovf.EnvTransportGuestInfo, ovf.EnvTransportISO},
+				Property: []types.VAppPropertySpec{
+					{
+						ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+						Info:            &types.VAppPropertyInfo{Key: 1, Id: "hostname", DefaultValue: "vcsim"},
+					},
+					{
+						ArrayUpdateSpec: types.ArrayUpdateSpec{Operation: types.ArrayUpdateOperationAdd},
+						Info:            &types.VAppPropertyInfo{Key: 2, ClassId: "vm", Id: "ip", InstanceId: "0", Value: "10.0.0.1"},
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		task, err = vm.PowerOn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		var mvm mo.VirtualMachine
+		if err = vm.Properties(ctx, vm.Reference(), []string{"config"}, &mvm); err != nil {
+			t.Fatal(err)
+		}
+
+		expect := map[string]string{
+			"hostname": "vcsim",
+			"vm.ip.0":  "10.0.0.1",
+		}
+
+		var xml string
+		for _, opt := range mvm.Config.ExtraConfig {
+			if o := opt.GetOptionValue(); o.Key == ovf.EnvGuestInfoKey {
+				xml = o.Value.(string)
+			}
+		}
+
+		env, err := ovf.UnmarshalEnv(strings.NewReader(xml))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, vm.Reference().Value, env.EsxID)
+		assert.Equal(t, expect, env.Properties())
+
+		cdrom, err := object.VirtualDeviceList(mvm.Config.Hardware.Device).FindCdrom("")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		backing, ok := cdrom.Backing.(*types.VirtualCdromIsoBackingInfo)
+		if !ok {
+			t.Fatalf("backing=%T", cdrom.Backing)
+		}
+		assert.True(t, cdrom.Connectable.Connected)
+
+		var p object.DatastorePath
+		p.FromString(backing.FileName)
+		ds := Map(ctx).Get(*backing.Datastore).(*Datastore)
+
+		f, err := os.Open(path.Join(ds.Summary.Url, p.Path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		env, err = ovf.UnmarshalEnvISO(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expect, env.Properties())
+	})
+}
+
 func TestLastModifiedAndChangeVersionAreUpdated(t *testing.T) {
 	Test(func(ctx context.Context, c *vim25.Client) {
 		vm := object.NewVirtualMachine(c, Map(ctx).Any("VirtualMachine").Reference())
@@ -24,6 +24,22 @@ type VStorageObject struct {
 	types.VStorageObject
 	types.VStorageObjectSnapshotInfo
 	Metadata []types.KeyValue
+
+	// StorageProfile is the ID of the storage policy associated with the object, if any.
+	StorageProfile string
+}
+
+// applyProfile records the storage policy associated with the object.
+// The Empty and Default profile specs remove an existing association.
+func (obj *VStorageObject) applyProfile(profile []types.BaseVirtualMachineProfileSpec) {
+	for _, spec := range profile {
+		switch p := spec.(type) {
+		case *types.VirtualMachineDefinedProfileSpec:
+			obj.StorageProfile = p.ProfileId
+		case *types.VirtualMachineEmptyProfileSpec, *types.VirtualMachineDefaultProfileSpec:
+			obj.StorageProfile = ""
+		}
+	}
 }
 
 type VcenterVStorageObjectManager struct {
@@ -254,7 +270,9 @@ func (m *VcenterVStorageObjectManager) createObject(ctx *Context, req *types.Cre
 		ProvisioningType: backing.ProvisioningType,
 	}
 
-	objects[obj.Config.Id] = &VStorageObject{VStorageObject: obj}
+	vso := &VStorageObject{VStorageObject: obj}
+	vso.applyProfile(req.Spec.Profile)
+	objects[obj.Config.Id] = vso
 
 	return &obj, nil
 
@@ -304,6 +322,91 @@ func (m *VcenterVStorageObjectManager) DeleteVStorageObjectTask(ctx *Context, re
 	}
 }
 
+func (m *VcenterVStorageObjectManager) RelocateVStorageObjectTask(ctx *Context, req *types.RelocateVStorageObject_Task) soap.HasFault {
+	task := CreateTask(m, "relocateVStorageObject", func(*Task) (types.AnyType, types.BaseMethodFault) {
+		obj := m.object(req.Datastore, req.Id)
+		if obj == nil {
+			return nil, new(types.InvalidArgument)
+		}
+
+		spec := req.Spec.BackingSpec.GetVslmCreateSpecBackingSpec()
+		ds, ok := ctx.Map.Get(spec.Datastore).(*Datastore)
+		if !ok {
+			return nil, &types.ManagedObjectNotFound{Obj: spec.Datastore}
+		}
+
+		if ds.Self != req.Datastore {
+			objects, ok := m.objects[ds.Self]
+			if !ok {
+				objects = make(map[types.ID]*VStorageObject)
+				m.objects[ds.Self] = objects
+				_ = os.MkdirAll(ds.resolve(ctx, "fcd"), 0750)
+			}
+
+			backing := obj.Config.Backing.(*types.BaseConfigInfoDiskFileBackingInfo)
+			path := object.DatastorePath{
+				Datastore: ds.Name,
+				Path:      spec.Path,
+			}
+			if path.Path == "" {
+				path.Path = "fcd/" + obj.Config.Id.Id + ".vmdk"
+			}
+
+			src := ctx.Map.getEntityDatacenter(ctx.Map.Get(req.Datastore).(*Datastore))
+			dst := ctx.Map.getEntityDatacenter(ds)
+			fm := ctx.Map.FileManager()
+			dest := vdmNames(path.String())
+
+			for i, name := range vdmNames(backing.FilePath) {
+				err := fm.moveDatastoreFile(ctx, &types.MoveDatastoreFile_Task{
+					SourceName:            name,
+					SourceDatacenter:      &src.Self,
+					DestinationName:       dest[i],
+					DestinationDatacenter: &dst.Self,
+				})
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			backing.Datastore = ds.Self
+			backing.FilePath = path.String()
+
+			delete(m.objects[req.Datastore], req.Id)
+			objects[req.Id] = obj
+		}
+
+		obj.applyProfile(req.Spec.Profile)
+
+		return &obj.VStorageObject, nil
+	})
+
+	return &methods.RelocateVStorageObject_TaskBody{
+		Res: &types.RelocateVStorageObject_TaskResponse{
+			Returnval: task.Run(ctx),
+		},
+	}
+}
+
+func (m *VcenterVStorageObjectManager) UpdateVStorageObjectPolicyTask(ctx *Context, req *types.UpdateVStorageObjectPolicy_Task) soap.HasFault {
+	task := CreateTask(m, "updateVStorageObjectPolicy", func(*Task) (types.AnyType, types.BaseMethodFault) {
+		obj := m.object(req.Datastore, req.Id)
+		if obj == nil {
+			return nil, new(types.InvalidArgument)
+		}
+
+		obj.applyProfile(req.Profile)
+
+		return nil, nil
+	})
+
+	return &methods.UpdateVStorageObjectPolicy_TaskBody{
+		Res: &types.UpdateVStorageObjectPolicy_TaskResponse{
+			Returnval: task.Run(ctx),
+		},
+	}
+}
+
 func (m *VcenterVStorageObjectManager) RetrieveSnapshotInfo(req *types.RetrieveSnapshotInfo) soap.HasFault {
 	body := new(methods.RetrieveSnapshotInfoBody)
 
@@ -7,6 +7,7 @@ package toolbox
 import (
 	"bytes"
 	"fmt"
+	"sync"
 )
 
 // Channel abstracts the guest<->vmx RPC transport
@@ -25,22 +26,42 @@ var (
 // ChannelOut extends Channel to provide RPCI protocol helpers
 type ChannelOut struct {
 	Channel
+
+	mu sync.Mutex
 }
 
-// Request sends an RPC command to the vmx and checks the return code for success or error
+// Request sends an RPC command to the vmx and checks the return code for success or error.
+// Requests may be sent concurrently, such as by handlers with timers.
 func (c *ChannelOut) Request(request []byte) ([]byte, error) {
-	if err := c.Send(request); err != nil {
+	reply, ok, err := c.request(request)
+	if err != nil {
 		return nil, err
 	}
 
+	if ok {
+		return reply, nil
+	}
+
+	return nil, fmt.Errorf("request %q: %q", request, reply)
+}
+
+// request sends an RPC command to the vmx, returning the reply without the return code and true if the code is success.
+func (c *ChannelOut) request(request []byte) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.Send(request); err != nil {
+		return nil, false, err
+	}
+
 	reply, err := c.Receive()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if bytes.HasPrefix(reply, rpciOK) {
-		return reply[2:], nil
+		return reply[2:], true, nil
 	}
 
-	return nil, fmt.Errorf("request %q: %q", request, reply)
+	return bytes.TrimPrefix(reply, rpciERR), false, nil
 }
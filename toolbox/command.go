@@ -694,6 +694,18 @@ func (c *CommandServer) authenticate(r vix.CommandRequestHeader, data []byte) er
 			fmt.Fprintf(traceLog, "ignoring credentials: %q:%q\n", c.Name, c.Password)
 		}
 
+		return nil
+	case vix.UserCredentialTypeSAMLBearerToken, vix.UserCredentialTypeSAMLBearerTokenHostVerified:
+		var c vix.UserCredentialSAMLToken
+
+		if err := c.UnmarshalBinary(data); err != nil {
+			return err
+		}
+
+		if Trace {
+			fmt.Fprintf(traceLog, "ignoring SAML token credentials: %q\n", c.Name)
+		}
+
 		return nil
 	default:
 		return fmt.Errorf("unsupported UserCredentialType=%d", r.UserCredentialType)
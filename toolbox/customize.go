@@ -0,0 +1,455 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CustomizationConfig is the guest customization config (cust.cfg) generated by vCenter
+// from a CustomizationSpec, as consumed by the open-vm-tools Linux customization engine.
+type CustomizationConfig struct {
+	HostName    string
+	DomainName  string
+	Nics        []CustomizationNic
+	DNSFromDHCP bool
+	DNSServers  []string
+	DNSSuffixes []string
+	TimeZone    string
+	UTC         bool
+}
+
+// CustomizationNic is the config of a single NIC, matched by MAC address
+type CustomizationNic struct {
+	Name         string
+	MACAddress   string
+	OnBoot       bool
+	DHCP         bool
+	IPAddress    string
+	Netmask      string
+	Gateways     []string
+	IPv6         []CustomizationIPv6
+	IPv6Gateways []string
+}
+
+// CustomizationIPv6 is a static IPv6 address and prefix length
+type CustomizationIPv6 struct {
+	Address string
+	Prefix  int
+}
+
+// cust.cfg sections and keys
+const (
+	custNetwork   = "NETWORK"
+	custNicConfig = "NIC-CONFIG"
+	custDNS       = "DNS"
+	custDateTime  = "DATETIME"
+)
+
+func yes(val string) bool {
+	return strings.EqualFold(val, "yes")
+}
+
+func yesno(val bool) string {
+	if val {
+		return "yes"
+	}
+	return "no"
+}
+
+// custSection is a cust.cfg section, keys of list values are of the form KEY|N
+type custSection map[string]string
+
+// list returns the values of KEY|1..N, in index order
+func (s custSection) list(key string) []string {
+	type entry struct {
+		index int
+		val   string
+	}
+	var entries []entry
+	for k, v := range s {
+		name, n, ok := strings.Cut(k, "|")
+		if !ok || name != key {
+			continue
+		}
+		i, err := strconv.Atoi(n)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{i, v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].index < entries[j].index })
+
+	var vals []string
+	for _, e := range entries {
+		vals = append(vals, e.val)
+	}
+	return vals
+}
+
+func split(val string) []string {
+	var vals []string
+	for _, v := range strings.Split(val, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			vals = append(vals, v)
+		}
+	}
+	return vals
+}
+
+// UnmarshalText parses the cust.cfg INI format
+func (c *CustomizationConfig) UnmarshalText(data []byte) error {
+	sections := make(map[string]custSection)
+	var section custSection
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			section = make(custSection)
+			sections[name] = section
+			continue
+		}
+		key, val, ok := strings.Cut(text, "=")
+		if !ok || section == nil {
+			return fmt.Errorf("cust.cfg line %d: invalid entry %q", line, text)
+		}
+		section[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	*c = CustomizationConfig{}
+
+	if s := sections[custNetwork]; s != nil {
+		c.HostName = s["HOSTNAME"]
+		c.DomainName = s["DOMAINNAME"]
+	}
+
+	if s := sections[custNicConfig]; s != nil {
+		for _, name := range split(s["NICS"]) {
+			n := sections[name]
+			if n == nil {
+				return fmt.Errorf("cust.cfg: section %q not found", name)
+			}
+			nic := CustomizationNic{
+				Name:         name,
+				MACAddress:   n["MACADDR"],
+				OnBoot:       yes(n["ONBOOT"]),
+				DHCP:         strings.EqualFold(n["BOOTPROTO"], "dhcp"),
+				IPAddress:    n["IPADDR"],
+				Netmask:      n["NETMASK"],
+				Gateways:     split(n["GATEWAY"]),
+				IPv6Gateways: n.list("IPv6GATEWAY"),
+			}
+			masks := n.list("IPv6NETMASK")
+			for i, addr := range n.list("IPv6ADDR") {
+				ip := CustomizationIPv6{Address: addr, Prefix: 64}
+				if i < len(masks) {
+					prefix, err := strconv.Atoi(masks[i])
+					if err != nil {
+						return fmt.Errorf("cust.cfg: %s IPv6NETMASK: %s", name, err)
+					}
+					ip.Prefix = prefix
+				}
+				nic.IPv6 = append(nic.IPv6, ip)
+			}
+			if nic.MACAddress == "" {
+				return fmt.Errorf("cust.cfg: %s MACADDR is required", name)
+			}
+			c.Nics = append(c.Nics, nic)
+		}
+	}
+
+	if s := sections[custDNS]; s != nil {
+		c.DNSFromDHCP = yes(s["DNSFROMDHCP"])
+		c.DNSServers = s.list("NAMESERVER")
+		c.DNSSuffixes = s.list("SUFFIX")
+	}
+
+	if s := sections[custDateTime]; s != nil {
+		c.TimeZone = s["TIMEZONE"]
+		c.UTC = yes(s["UTC"])
+	}
+
+	return nil
+}
+
+// MarshalText encodes the config in the cust.cfg INI format
+func (c *CustomizationConfig) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+
+	section := func(name string) {
+		fmt.Fprintf(&buf, "[%s]\n", name)
+	}
+	entry := func(key, val string) {
+		if val != "" {
+			fmt.Fprintf(&buf, "%s = %s\n", key, val)
+		}
+	}
+	list := func(key string, vals []string) {
+		for i, val := range vals {
+			entry(fmt.Sprintf("%s|%d", key, i+1), val)
+		}
+	}
+
+	section(custNetwork)
+	entry("NETWORKING", "yes")
+	entry("BOOTPROTO", "dhcp")
+	entry("HOSTNAME", c.HostName)
+	entry("DOMAINNAME", c.DomainName)
+
+	if len(c.Nics) != 0 {
+		names := make([]string, len(c.Nics))
+		for i, nic := range c.Nics {
+			names[i] = nic.Name
+			if names[i] == "" {
+				names[i] = fmt.Sprintf("NIC%d", i+1)
+			}
+		}
+		section(custNicConfig)
+		entry("NICS", strings.Join(names, ","))
+
+		for i, nic := range c.Nics {
+			section(names[i])
+			entry("MACADDR", nic.MACAddress)
+			entry("ONBOOT", yesno(nic.OnBoot))
+			entry("IPv4_MODE", "BACKWARDS_COMPATIBLE")
+			if nic.DHCP {
+				entry("BOOTPROTO", "dhcp")
+			} else {
+				entry("BOOTPROTO", "static")
+				entry("IPADDR", nic.IPAddress)
+				entry("NETMASK", nic.Netmask)
+				entry("GATEWAY", strings.Join(nic.Gateways, ","))
+			}
+			for j, ip := range nic.IPv6 {
+				entry(fmt.Sprintf("IPv6ADDR|%d", j+1), ip.Address)
+				entry(fmt.Sprintf("IPv6NETMASK|%d", j+1), strconv.Itoa(ip.Prefix))
+			}
+			list("IPv6GATEWAY", nic.IPv6Gateways)
+		}
+	}
+
+	section(custDNS)
+	entry("DNSFROMDHCP", yesno(c.DNSFromDHCP))
+	list("SUFFIX", c.DNSSuffixes)
+	list("NAMESERVER", c.DNSServers)
+
+	if c.TimeZone != "" {
+		section(custDateTime)
+		entry("TIMEZONE", c.TimeZone)
+		entry("UTC", yesno(c.UTC))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// LinuxCustomizer applies a CustomizationConfig by writing the hostname, hosts, resolv.conf,
+// timezone and systemd-networkd configuration files relative to Root.
+// The changes take effect after a reboot.
+type LinuxCustomizer struct {
+	Root string
+}
+
+// NewLinuxCustomizer returns a LinuxCustomizer for the given root directory
+func NewLinuxCustomizer(root string) *LinuxCustomizer {
+	return &LinuxCustomizer{Root: root}
+}
+
+func (l *LinuxCustomizer) path(name string) string {
+	return filepath.Join(l.Root, name)
+}
+
+func (l *LinuxCustomizer) writeFile(name string, data []byte) error {
+	name = l.path(name)
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0644)
+}
+
+// Customize applies the given config
+func (l *LinuxCustomizer) Customize(c *CustomizationConfig) error {
+	steps := []func(*CustomizationConfig) error{
+		l.hostname,
+		l.network,
+		l.resolver,
+		l.timezone,
+	}
+
+	for _, step := range steps {
+		if err := step(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *LinuxCustomizer) hostname(c *CustomizationConfig) error {
+	if c.HostName == "" {
+		return nil
+	}
+
+	for _, name := range []string{c.HostName, c.DomainName} {
+		if strings.ContainsFunc(name, unicode.IsSpace) {
+			return fmt.Errorf("invalid host or domain name %q", name)
+		}
+	}
+
+	if err := l.writeFile("/etc/hostname", []byte(c.HostName+"\n")); err != nil {
+		return err
+	}
+
+	names := c.HostName
+	if c.DomainName != "" {
+		names = c.HostName + "." + c.DomainName + " " + c.HostName
+	}
+	entry := "127.0.1.1\t" + names
+
+	var lines []string
+	data, err := os.ReadFile(l.path("/etc/hosts"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if line == "" || strings.HasPrefix(strings.TrimSpace(line), "127.0.1.1") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, entry)
+
+	return l.writeFile("/etc/hosts", []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+// networkDir is where the systemd-networkd config is written, existing files with the networkPrefix are replaced
+const (
+	networkDir    = "/etc/systemd/network"
+	networkPrefix = "10-vmware-"
+)
+
+// nicName matches the NIC names used to construct the file names written to networkDir
+var nicName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func prefixLength(mask string) (int, error) {
+	ip := net.ParseIP(mask).To4()
+	if ip == nil {
+		return 0, fmt.Errorf("invalid netmask %q", mask)
+	}
+	ones, bits := net.IPMask(ip).Size()
+	if bits == 0 {
+		return 0, fmt.Errorf("invalid netmask %q", mask)
+	}
+	return ones, nil
+}
+
+func (l *LinuxCustomizer) network(c *CustomizationConfig) error {
+	if len(c.Nics) == 0 {
+		return nil
+	}
+
+	for _, nic := range c.Nics {
+		if !nicName.MatchString(nic.Name) {
+			return fmt.Errorf("invalid NIC name %q", nic.Name)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(l.path(networkDir), networkPrefix+"*.network"))
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return err
+		}
+	}
+
+	for _, nic := range c.Nics {
+		var buf bytes.Buffer
+
+		fmt.Fprintf(&buf, "[Match]\nMACAddress=%s\n\n[Network]\n", strings.ToLower(nic.MACAddress))
+		if nic.DHCP {
+			fmt.Fprintln(&buf, "DHCP=ipv4")
+		} else {
+			prefix, err := prefixLength(nic.Netmask)
+			if err != nil {
+				return fmt.Errorf("%s: %s", nic.Name, err)
+			}
+			fmt.Fprintf(&buf, "Address=%s/%d\n", nic.IPAddress, prefix)
+			for _, gw := range nic.Gateways {
+				fmt.Fprintf(&buf, "Gateway=%s\n", gw)
+			}
+		}
+		for _, ip := range nic.IPv6 {
+			fmt.Fprintf(&buf, "Address=%s/%d\n", ip.Address, ip.Prefix)
+		}
+		for _, gw := range nic.IPv6Gateways {
+			fmt.Fprintf(&buf, "Gateway=%s\n", gw)
+		}
+
+		name := filepath.Join(networkDir, networkPrefix+strings.ToLower(nic.Name)+".network")
+		if err := l.writeFile(name, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *LinuxCustomizer) resolver(c *CustomizationConfig) error {
+	if c.DNSFromDHCP || (len(c.DNSServers) == 0 && len(c.DNSSuffixes) == 0) {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if len(c.DNSSuffixes) != 0 {
+		fmt.Fprintf(&buf, "search %s\n", strings.Join(c.DNSSuffixes, " "))
+	}
+	for _, server := range c.DNSServers {
+		fmt.Fprintf(&buf, "nameserver %s\n", server)
+	}
+
+	name := l.path("/etc/resolv.conf")
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err // may be a symlink managed by systemd-resolved
+	}
+
+	return l.writeFile("/etc/resolv.conf", buf.Bytes())
+}
+
+func (l *LinuxCustomizer) timezone(c *CustomizationConfig) error {
+	if c.TimeZone == "" {
+		return nil
+	}
+
+	if strings.Contains(c.TimeZone, "..") {
+		return fmt.Errorf("invalid timezone %q", c.TimeZone)
+	}
+
+	if err := l.writeFile("/etc/timezone", []byte(c.TimeZone+"\n")); err != nil {
+		return err
+	}
+
+	localtime := l.path("/etc/localtime")
+	if err := os.Remove(localtime); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Symlink(filepath.Join("/usr/share/zoneinfo", c.TimeZone), localtime)
+}
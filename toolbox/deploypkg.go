@@ -0,0 +1,321 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ToolsDeployPkgState enum as defined in open-vm-tools/lib/include/toolsDeployPkg.h
+const (
+	deployPkgStateIdle = iota
+	deployPkgStatePending
+	deployPkgStateCopying
+	deployPkgStateDeploying
+	deployPkgStateRunning
+	deployPkgStateDone
+)
+
+// ToolsDeployPkgError enum as defined in open-vm-tools/lib/include/toolsDeployPkg.h
+const (
+	deployPkgErrorSuccess = iota
+	deployPkgErrorNotSupported
+	deployPkgErrorPkgNotFound
+	deployPkgErrorRPCInvalid
+	deployPkgErrorCopyFailed
+	deployPkgErrorDeployFailed
+)
+
+// Guest customization status reported via guestinfo.gc.status
+const (
+	CustomizationStarted    = "Started"
+	CustomizationSuccessful = "Successful"
+	CustomizationFailed     = "Failed"
+)
+
+// Package header as defined in open-vm-tools/lib/include/deployPkgFormat.h
+const (
+	deployPkgSignature      = "VMWAREDEPLOYPKG_"
+	deployPkgHeaderLength   = 512
+	deployPkgCommandLength  = 464
+	deployPkgPayloadCab     = 0
+	deployPkgPayloadTarGzip = 1
+
+	// VMWAREDEPLOYPKG_HEADER_FLAGS_SKIP_REBOOT
+	deployPkgFlagSkipReboot = 1
+
+	// custCfg is the name of the customization config within the package payload
+	custCfg = "cust.cfg"
+)
+
+// deployPkgHeader is the VMwareDeployPkgHdr struct, followed by the payload at PayloadOffset
+type deployPkgHeader struct {
+	Signature     [len(deployPkgSignature)]byte
+	MajorVersion  uint8
+	MinorVersion  uint8
+	PayloadType   uint8
+	Flags         uint8  // named "reserved" in deployPkgFormat.h, used for the VMWAREDEPLOYPKG_HEADER_FLAGS
+	Padding       uint32 // aligns PkgLength to 8 bytes
+	PkgLength     uint64
+	PayloadOffset uint64
+	PayloadLength uint64
+	Command       [deployPkgCommandLength]byte
+}
+
+// DeployPkgHandler receives guest customization packages sent by the VMX,
+// as a result of CustomizeVM_Task or a clone with a CustomizationSpec.
+// The VMX requests a package file name via the deployPkg.begin RPC, copies the package
+// to the guest using the HGFS protocol and then invokes the deployPkg.deploy RPC.
+// The package cust.cfg is applied by the Handler, and status reported to the VMX as open-vm-tools does.
+type DeployPkgHandler struct {
+	// Handler applies the customization config, if nil deployPkg requests fail as not supported.
+	Handler func(*CustomizationConfig) error
+
+	// Reboot is called after the customization is successfully applied and the deployPkg.deploy reply is sent,
+	// if not nil and the package header does not have the skip-reboot flag set.
+	Reboot func() error
+
+	out   *ChannelOut
+	after func(func())
+	dir   string
+}
+
+func registerDeployPkgHandler(service *Service) *DeployPkgHandler {
+	handler := &DeployPkgHandler{
+		out:   service.out,
+		after: service.afterResponse,
+	}
+
+	service.RegisterHandler("deployPkg.begin", handler.Begin)
+	service.RegisterHandler("deployPkg.deploy", handler.Deploy)
+
+	return handler
+}
+
+func (h *DeployPkgHandler) request(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+
+	if _, err := h.out.Request([]byte(msg)); err != nil {
+		log.Printf("unable to send %q: %q", msg, err)
+	}
+}
+
+func (h *DeployPkgHandler) update(state, code int, msg string) {
+	h.request("deployPkg.update.state %d %d %s", state, code, msg)
+}
+
+func (h *DeployPkgHandler) status(status string) {
+	h.request("info-set guestinfo.gc.status %s", status)
+}
+
+// Begin is the handler for the deployPkg.begin RPC, returning the guest file name the VMX copies the package to
+func (h *DeployPkgHandler) Begin([]byte) ([]byte, error) {
+	if h.Handler == nil {
+		return nil, errors.New("deployPkg not supported")
+	}
+
+	h.cleanup()
+
+	dir, err := os.MkdirTemp("", "vmware-imc-")
+	if err != nil {
+		return nil, err
+	}
+	h.dir = dir
+
+	return []byte(filepath.Join(dir, "pkg")), nil
+}
+
+func (h *DeployPkgHandler) cleanup() {
+	if h.dir != "" {
+		_ = os.RemoveAll(h.dir)
+		h.dir = ""
+	}
+}
+
+// Deploy is the handler for the deployPkg.deploy RPC, with the package file name returned by Begin as its argument.
+// The customization is applied synchronously and the package file removed.
+func (h *DeployPkgHandler) Deploy(args []byte) ([]byte, error) {
+	dir := h.dir
+	defer h.cleanup()
+
+	if h.Handler == nil {
+		h.update(deployPkgStateDone, deployPkgErrorNotSupported, "deployPkg not supported")
+		return nil, errors.New("deployPkg not supported")
+	}
+
+	name := strings.TrimSpace(string(bytes.TrimRight(args, "\x00")))
+	if name == "" {
+		h.update(deployPkgStateDone, deployPkgErrorRPCInvalid, "package file name not specified")
+		return nil, errors.New("package file name not specified")
+	}
+
+	name = filepath.Clean(name)
+	if dir == "" || filepath.Dir(name) != dir {
+		err := fmt.Errorf("package file %q not within deployPkg.begin directory", name)
+		h.update(deployPkgStateDone, deployPkgErrorRPCInvalid, err.Error())
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		h.update(deployPkgStateDone, deployPkgErrorPkgNotFound, err.Error())
+		return nil, err
+	}
+	defer f.Close()
+
+	h.update(deployPkgStateDeploying, deployPkgErrorSuccess, "")
+	h.status(CustomizationStarted)
+
+	hdr, config, err := decodeDeployPkg(f)
+	if err == nil {
+		h.update(deployPkgStateRunning, deployPkgErrorSuccess, "")
+		err = h.Handler(config)
+	}
+
+	if err != nil {
+		h.status(CustomizationFailed)
+		h.update(deployPkgStateDone, deployPkgErrorDeployFailed, err.Error())
+		return nil, err
+	}
+
+	h.status(CustomizationSuccessful)
+	h.update(deployPkgStateDone, deployPkgErrorSuccess, "")
+
+	if h.Reboot != nil && hdr.Flags&deployPkgFlagSkipReboot == 0 {
+		// as open-vm-tools does, reboot once the VMX has the deploy reply
+		h.after(func() {
+			if err := h.Reboot(); err != nil {
+				log.Printf("deployPkg reboot: %s", err)
+			}
+		})
+	}
+
+	return nil, nil
+}
+
+// DecodeDeployPkg reads a guest customization package, returning the cust.cfg it contains
+func DecodeDeployPkg(r io.Reader) (*CustomizationConfig, error) {
+	_, config, err := decodeDeployPkg(r)
+	return config, err
+}
+
+func decodeDeployPkg(r io.Reader) (*deployPkgHeader, *CustomizationConfig, error) {
+	var hdr deployPkgHeader
+
+	if err := binary.Read(r, binary.LittleEndian, &hdr); err != nil {
+		return nil, nil, fmt.Errorf("reading package header: %s", err)
+	}
+
+	if string(hdr.Signature[:]) != deployPkgSignature {
+		return nil, nil, errors.New("invalid package signature")
+	}
+
+	if hdr.PayloadType != deployPkgPayloadTarGzip {
+		return nil, nil, fmt.Errorf("unsupported package payload type %d", hdr.PayloadType)
+	}
+
+	if hdr.PayloadOffset < deployPkgHeaderLength {
+		return nil, nil, fmt.Errorf("invalid package payload offset %d", hdr.PayloadOffset)
+	}
+
+	if _, err := io.CopyN(io.Discard, r, int64(hdr.PayloadOffset-deployPkgHeaderLength)); err != nil {
+		return nil, nil, fmt.Errorf("reading package payload: %s", err)
+	}
+
+	payload := io.LimitReader(r, int64(hdr.PayloadLength))
+
+	gz, err := gzip.NewReader(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tr := tar.NewReader(gz)
+
+	for {
+		th, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil, nil, errors.New(custCfg + " not found in package")
+			}
+			return nil, nil, err
+		}
+
+		if th.Typeflag != tar.TypeReg || path.Base(th.Name) != custCfg {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		config := new(CustomizationConfig)
+		if err = config.UnmarshalText(data); err != nil {
+			return nil, nil, err
+		}
+
+		return &hdr, config, nil
+	}
+}
+
+// EncodeDeployPkg creates a guest customization package containing the given config
+func EncodeDeployPkg(config *CustomizationConfig) ([]byte, error) {
+	cfg, err := config.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	var payload bytes.Buffer
+	gz := gzip.NewWriter(&payload)
+	tw := tar.NewWriter(gz)
+
+	th := &tar.Header{
+		Name:     custCfg,
+		Mode:     0600,
+		Size:     int64(len(cfg)),
+		Typeflag: tar.TypeReg,
+	}
+
+	if err = tw.WriteHeader(th); err != nil {
+		return nil, err
+	}
+	if _, err = tw.Write(cfg); err != nil {
+		return nil, err
+	}
+	if err = tw.Close(); err != nil {
+		return nil, err
+	}
+	if err = gz.Close(); err != nil {
+		return nil, err
+	}
+
+	hdr := deployPkgHeader{
+		MajorVersion:  1,
+		PayloadType:   deployPkgPayloadTarGzip,
+		PkgLength:     uint64(deployPkgHeaderLength + payload.Len()),
+		PayloadOffset: deployPkgHeaderLength,
+		PayloadLength: uint64(payload.Len()),
+	}
+	copy(hdr.Signature[:], deployPkgSignature)
+
+	var buf bytes.Buffer
+	if err = binary.Write(&buf, binary.LittleEndian, &hdr); err != nil {
+		return nil, err
+	}
+	_, _ = buf.Write(payload.Bytes())
+
+	return buf.Bytes(), nil
+}
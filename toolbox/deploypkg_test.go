@@ -0,0 +1,341 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordChannelOut records RPC requests, replying OK to each
+type recordChannelOut struct {
+	mockChannelOut
+	requests []string
+}
+
+func (c *recordChannelOut) Send(buf []byte) error {
+	c.requests = append(c.requests, string(buf))
+	c.reply = append(c.reply, rpciOK)
+	return nil
+}
+
+var testCustomizationConfig = CustomizationConfig{
+	HostName:   "vm1",
+	DomainName: "example.com",
+	Nics: []CustomizationNic{
+		{
+			Name:       "NIC1",
+			MACAddress: "00:50:56:AB:CD:01",
+			OnBoot:     true,
+			IPAddress:  "10.0.0.10",
+			Netmask:    "255.255.255.0",
+			Gateways:   []string{"10.0.0.1"},
+			IPv6:       []CustomizationIPv6{{Address: "fc00:10::10", Prefix: 64}},
+		},
+		{
+			Name:       "NIC2",
+			MACAddress: "00:50:56:AB:CD:02",
+			OnBoot:     true,
+			DHCP:       true,
+		},
+	},
+	DNSServers:  []string{"10.0.0.2", "10.0.0.3"},
+	DNSSuffixes: []string{"example.com"},
+	TimeZone:    "America/Los_Angeles",
+	UTC:         true,
+}
+
+func TestCustomizationConfig(t *testing.T) {
+	data, err := testCustomizationConfig.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config CustomizationConfig
+	if err = config.UnmarshalText(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(config, testCustomizationConfig) {
+		t.Errorf("%#v != %#v", config, testCustomizationConfig)
+	}
+
+	invalid := []string{
+		"HOSTNAME = vm1",
+		"[NIC-CONFIG]\nNICS = NIC1",
+		"[NIC-CONFIG]\nNICS = NIC1\n[NIC1]\nBOOTPROTO = dhcp",
+	}
+
+	for _, cfg := range invalid {
+		if err = config.UnmarshalText([]byte(cfg)); err == nil {
+			t.Errorf("expected error parsing %q", cfg)
+		}
+	}
+}
+
+func TestDeployPkg(t *testing.T) {
+	pkg, err := EncodeDeployPkg(&testCustomizationConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(recordChannelOut)
+	service := NewService(new(mockChannelIn), out)
+
+	// not supported without a Handler
+	reply := service.Dispatch([]byte("deployPkg.begin"))
+	if !bytes.HasPrefix(reply, []byte("ERR")) {
+		t.Errorf("reply=%q", reply)
+	}
+
+	var config *CustomizationConfig
+	service.DeployPkg.Handler = func(c *CustomizationConfig) error {
+		config = c
+		return nil
+	}
+	reboots := 0
+	service.DeployPkg.Reboot = func() error {
+		reboots++
+		return nil
+	}
+
+	reply = service.Dispatch([]byte("deployPkg.begin"))
+	name, ok := bytes.CutPrefix(reply, []byte("OK "))
+	if !ok {
+		t.Fatalf("reply=%q", reply)
+	}
+
+	if err = os.WriteFile(string(name), pkg, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reply = service.Dispatch(append([]byte("deployPkg.deploy "), name...))
+	if string(reply) != "OK " {
+		t.Errorf("reply=%q", reply)
+	}
+
+	// reboot once the reply is sent
+	if reboots != 0 {
+		t.Errorf("reboots=%d", reboots)
+	}
+	service.sent()
+	if reboots != 1 {
+		t.Errorf("reboots=%d", reboots)
+	}
+
+	if !reflect.DeepEqual(*config, testCustomizationConfig) {
+		t.Errorf("%#v != %#v", *config, testCustomizationConfig)
+	}
+
+	if _, err = os.Stat(string(name)); !os.IsNotExist(err) {
+		t.Errorf("package not removed: %v", err)
+	}
+
+	expect := []string{
+		"deployPkg.update.state 3 0 ",
+		"info-set guestinfo.gc.status Started",
+		"deployPkg.update.state 4 0 ",
+		"info-set guestinfo.gc.status Successful",
+		"deployPkg.update.state 5 0 ",
+	}
+	if !reflect.DeepEqual(out.requests[len(out.requests)-len(expect):], expect) {
+		t.Errorf("requests=%q", out.requests)
+	}
+
+	// skip reboot flag
+	reply = service.Dispatch([]byte("deployPkg.begin"))
+	name, _ = bytes.CutPrefix(reply, []byte("OK "))
+	skip := bytes.Clone(pkg)
+	skip[19] = deployPkgFlagSkipReboot
+	if err = os.WriteFile(string(name), skip, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reply = service.Dispatch(append([]byte("deployPkg.deploy "), name...))
+	if string(reply) != "OK " {
+		t.Errorf("reply=%q", reply)
+	}
+	service.sent()
+	if reboots != 1 {
+		t.Errorf("reboots=%d", reboots)
+	}
+
+	// package must be within the deployPkg.begin directory
+	other := filepath.Join(t.TempDir(), "pkg")
+	if err = os.WriteFile(other, pkg, 0600); err != nil {
+		t.Fatal(err)
+	}
+	_ = service.Dispatch([]byte("deployPkg.begin"))
+	reply = service.Dispatch([]byte("deployPkg.deploy " + other))
+	if !bytes.HasPrefix(reply, []byte("ERR")) {
+		t.Errorf("reply=%q", reply)
+	}
+
+	// handler error
+	service.DeployPkg.Handler = func(*CustomizationConfig) error {
+		return errors.New("customization failed")
+	}
+	out.requests = nil
+
+	reply = service.Dispatch([]byte("deployPkg.begin"))
+	name, _ = bytes.CutPrefix(reply, []byte("OK "))
+	if err = os.WriteFile(string(name), pkg, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reply = service.Dispatch(append([]byte("deployPkg.deploy "), name...))
+	if !bytes.HasPrefix(reply, []byte("ERR")) {
+		t.Errorf("reply=%q", reply)
+	}
+
+	expect = []string{
+		"info-set guestinfo.gc.status Failed",
+		"deployPkg.update.state 5 5 customization failed",
+	}
+	if !reflect.DeepEqual(out.requests[len(out.requests)-len(expect):], expect) {
+		t.Errorf("requests=%q", out.requests)
+	}
+
+	// invalid packages
+	for _, args := range []string{"", filepath.Join(t.TempDir(), "enoent")} {
+		reply = service.Dispatch([]byte("deployPkg.deploy " + args))
+		if !bytes.HasPrefix(reply, []byte("ERR")) {
+			t.Errorf("reply=%q", reply)
+		}
+	}
+
+	if _, err = DecodeDeployPkg(bytes.NewReader(pkg[:deployPkgHeaderLength])); err == nil {
+		t.Error("expected error decoding truncated package")
+	}
+
+	if _, err = DecodeDeployPkg(strings.NewReader(strings.Repeat("x", deployPkgHeaderLength))); err == nil {
+		t.Error("expected error decoding invalid signature")
+	}
+}
+
+func TestDecodeDeployPkg(t *testing.T) {
+	if n := binary.Size(deployPkgHeader{}); n != deployPkgHeaderLength {
+		t.Errorf("header size=%d", n)
+	}
+
+	// package laid out as VMwareDeployPkgHdr, with a cust.cfg in the format written by vCenter
+	f, err := os.Open("testdata/deploypkg-linux.pkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	hdr, config, err := decodeDeployPkg(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hdr.Flags&deployPkgFlagSkipReboot != 0 {
+		t.Errorf("flags=%d", hdr.Flags)
+	}
+
+	expect := CustomizationConfig{
+		HostName:   "vm1",
+		DomainName: "example.com",
+		Nics: []CustomizationNic{
+			{
+				Name:       "NIC1",
+				MACAddress: "00:50:56:ab:cd:01",
+				OnBoot:     true,
+				IPAddress:  "10.0.0.10",
+				Netmask:    "255.255.255.0",
+				Gateways:   []string{"10.0.0.1"},
+			},
+		},
+		DNSServers:  []string{"10.0.0.2"},
+		DNSSuffixes: []string{"example.com"},
+		TimeZone:    "America/Los_Angeles",
+		UTC:         true,
+	}
+
+	if !reflect.DeepEqual(*config, expect) {
+		t.Errorf("%#v != %#v", *config, expect)
+	}
+}
+
+func TestLinuxCustomizer(t *testing.T) {
+	root := t.TempDir()
+
+	hosts := filepath.Join(root, "etc", "hosts")
+	if err := os.MkdirAll(filepath.Dir(hosts), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hosts, []byte("127.0.0.1\tlocalhost\n127.0.1.1\told\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewLinuxCustomizer(root)
+	if err := c.Customize(&testCustomizationConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]string{
+		"etc/hostname":    {"vm1\n"},
+		"etc/hosts":       {"127.0.0.1\tlocalhost\n", "127.0.1.1\tvm1.example.com vm1\n"},
+		"etc/resolv.conf": {"search example.com\n", "nameserver 10.0.0.2\n", "nameserver 10.0.0.3\n"},
+		"etc/timezone":    {"America/Los_Angeles\n"},
+		"etc/systemd/network/10-vmware-nic1.network": {
+			"MACAddress=00:50:56:ab:cd:01\n", "Address=10.0.0.10/24\n", "Gateway=10.0.0.1\n", "Address=fc00:10::10/64\n",
+		},
+		"etc/systemd/network/10-vmware-nic2.network": {"DHCP=ipv4\n"},
+	}
+
+	for name, expect := range files {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range expect {
+			if !strings.Contains(string(data), s) {
+				t.Errorf("%s: %q not found in %q", name, s, data)
+			}
+		}
+	}
+
+	data, err := os.ReadFile(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "old") {
+		t.Errorf("old hosts entry not removed: %q", data)
+	}
+
+	link, err := os.Readlink(filepath.Join(root, "etc", "localtime"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link != "/usr/share/zoneinfo/America/Los_Angeles" {
+		t.Errorf("localtime=%s", link)
+	}
+
+	config := testCustomizationConfig
+	config.Nics = []CustomizationNic{{Name: "NIC1", MACAddress: "00:50:56:AB:CD:01", Netmask: "invalid"}}
+	if err = c.Customize(&config); err == nil {
+		t.Error("expected error with invalid netmask")
+	}
+
+	config = testCustomizationConfig
+	config.Nics = []CustomizationNic{{Name: "../../../etc/cron.d/x", MACAddress: "00:50:56:AB:CD:01", DHCP: true}}
+	if err = c.Customize(&config); err == nil {
+		t.Error("expected error with invalid NIC name")
+	}
+
+	config = testCustomizationConfig
+	config.DomainName = "example.com\n10.0.0.1\tevil.com"
+	if err = c.Customize(&config); err == nil {
+		t.Error("expected error with invalid domain name")
+	}
+}
@@ -185,5 +185,5 @@ func GuestInfoNicInfoRequest() ([]byte, error) {
 		return nil, err
 	}
 
-	return GuestInfoCommand(9 /*INFO_IPADDRESS_V3*/, r), nil
+	return GuestInfoCommand(GuestInfoIPAddressV2, r), nil
 }
@@ -0,0 +1,376 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GuestInfoType enum as defined in open-vm-tools/lib/include/guestInfo.h
+const (
+	GuestInfoDNSName       = 1
+	GuestInfoIPAddress     = 2
+	GuestInfoDiskFreeSpace = 3
+	GuestInfoBuildNumber   = 4
+	GuestInfoOSNameFull    = 5
+	GuestInfoOSName        = 6
+	GuestInfoUptime        = 7
+	GuestInfoMemory        = 8
+	GuestInfoIPAddressV2   = 9
+)
+
+// Guest variables set via the info-set RPC
+const (
+	GuestInfoDetailedDataKey = "guestInfo.detailed.data"
+	GuestInfoAppInfoKey      = "guestinfo.appInfo"
+)
+
+const (
+	// DefaultGuestInfoInterval is the vmtoolsd guestInfo poll interval
+	DefaultGuestInfoInterval = 30 * time.Second
+
+	// DefaultAppInfoInterval is the default interval for sending the running application list
+	DefaultAppInfoInterval = 30 * time.Minute
+)
+
+// GuestDiskInfo as sent by vmtoolsd with the INFO_DISK_FREE_SPACE type, in JSON format.
+type GuestDiskInfo struct {
+	Version string           `json:"version"`
+	Disks   []GuestDiskEntry `json:"disks"`
+}
+
+// GuestDiskEntry is a guest file system
+type GuestDiskEntry struct {
+	Name string `json:"name"`
+	Free uint64 `json:"free,string"`
+	Size uint64 `json:"size,string"`
+	Type string `json:"type,omitempty"`
+}
+
+// GuestOSInfo contains the guest OS details reported to the VMX
+type GuestOSInfo struct {
+	// ShortName is the guest OS short name, for example "ubuntu-64" or "other5xlinux-64"
+	ShortName string
+	// FullName is the guest OS pretty name, for example "Ubuntu 24.04 LTS"
+	FullName      string
+	FamilyName    string
+	DistroName    string
+	DistroVersion string
+	KernelVersion string
+	Architecture  string
+	Bitness       int
+}
+
+// GuestAppInfo as sent by the vmtoolsd appInfo plugin via the guestinfo.appInfo variable
+type GuestAppInfo struct {
+	Version       string     `json:"version"`
+	UpdateCounter int        `json:"updateCounter,string"`
+	PublishTime   string     `json:"publishTime"`
+	Applications  []GuestApp `json:"applications"`
+}
+
+// GuestApp is a running guest application
+type GuestApp struct {
+	Name    string `json:"a"`
+	Version string `json:"v"`
+}
+
+// DetailedData encodes the OS details in the guestInfo.detailed.data format
+func (info *GuestOSInfo) DetailedData() string {
+	fields := []struct {
+		key, val string
+	}{
+		{"architecture", info.Architecture},
+		{"bitness", strconv.Itoa(info.Bitness)},
+		{"distroName", info.DistroName},
+		{"distroVersion", info.DistroVersion},
+		{"familyName", info.FamilyName},
+		{"kernelVersion", info.KernelVersion},
+		{"prettyName", info.FullName},
+	}
+
+	var s []string
+	for _, f := range fields {
+		if f.val != "" && f.val != "0" {
+			s = append(s, fmt.Sprintf("%s='%s'", f.key, strings.ReplaceAll(f.val, "'", "")))
+		}
+	}
+
+	return strings.Join(s, " ")
+}
+
+// ParseDetailedData decodes the guestInfo.detailed.data format, the ShortName field is not included.
+func ParseDetailedData(data string) *GuestOSInfo {
+	info := new(GuestOSInfo)
+
+	for data != "" {
+		key, rest, ok := strings.Cut(strings.TrimSpace(data), "='")
+		if !ok {
+			break
+		}
+		val, next, _ := strings.Cut(rest, "'")
+		data = next
+
+		switch key {
+		case "architecture":
+			info.Architecture = val
+		case "bitness":
+			info.Bitness, _ = strconv.Atoi(val)
+		case "distroName":
+			info.DistroName = val
+		case "distroVersion":
+			info.DistroVersion = val
+		case "familyName":
+			info.FamilyName = val
+		case "kernelVersion":
+			info.KernelVersion = val
+		case "prettyName":
+			info.FullName = val
+		}
+	}
+
+	return info
+}
+
+// linuxShortName returns the guest OS short name, as used by the VMX to map to a guest ID
+func linuxShortName(id, version, kernel string, bitness int) string {
+	major, _, _ := strings.Cut(version, ".")
+
+	var name string
+
+	switch id {
+	case "ubuntu":
+		name = id
+	case "debian", "rhel", "centos", "sles":
+		name = id + major
+	default:
+		kmajor, _, _ := strings.Cut(kernel, ".")
+		name = fmt.Sprintf("other%sxlinux", kmajor)
+	}
+
+	if bitness == 64 {
+		name += "-64"
+	}
+
+	return name
+}
+
+// GuestInfoReporter sends guest details to the VMX, as the vmtoolsd guestInfo and appInfo plugins do.
+// Details are sent periodically while the Service is running, but only when changed since last sent.
+type GuestInfoReporter struct {
+	// NicInfo returns the guest network interfaces, defaults to DefaultGuestNicInfo
+	NicInfo func() *GuestNicInfo
+	// DiskInfo returns the guest file systems, defaults to DefaultGuestDiskInfo
+	DiskInfo func() (*GuestDiskInfo, error)
+	// OSInfo returns the guest OS details, defaults to DefaultGuestOSInfo
+	OSInfo func() (*GuestOSInfo, error)
+	// Uptime returns the guest uptime, defaults to DefaultGuestUptime
+	Uptime func() (time.Duration, error)
+	// AppInfo returns the running applications, defaults to DefaultGuestAppInfo. If nil, applications are not reported.
+	AppInfo func() ([]GuestApp, error)
+
+	// Interval at which details are checked for changes, defaults to DefaultGuestInfoInterval
+	Interval time.Duration
+	// AppInfoInterval at which the running applications are sent, defaults to DefaultAppInfoInterval
+	AppInfoInterval time.Duration
+
+	out *ChannelOut
+
+	mu            sync.Mutex
+	sent          map[string][]byte
+	appInfoTime   time.Time
+	appInfoUpdate int
+}
+
+func newGuestInfoReporter(service *Service) *GuestInfoReporter {
+	return &GuestInfoReporter{
+		NicInfo:  DefaultGuestNicInfo,
+		DiskInfo: DefaultGuestDiskInfo,
+		OSInfo:   DefaultGuestOSInfo,
+		Uptime:   DefaultGuestUptime,
+		AppInfo:  DefaultGuestAppInfo,
+		out:      service.out,
+		sent:     make(map[string][]byte),
+	}
+}
+
+func (r *GuestInfoReporter) interval() time.Duration {
+	if r.Interval == 0 {
+		return DefaultGuestInfoInterval
+	}
+	return r.Interval
+}
+
+// request sends the given request if it differs from the last request sent for key, or if force is true
+func (r *GuestInfoReporter) request(key string, req []byte, force bool) {
+	if !force && bytes.Equal(r.sent[key], req) {
+		return
+	}
+
+	if _, err := r.out.Request(req); err != nil {
+		log.Printf("SendGuestInfo %s: %s", key, err)
+		delete(r.sent, key)
+		return
+	}
+
+	r.sent[key] = req
+}
+
+// Send sends the guest details that have changed since last sent, or all details if force is true.
+func (r *GuestInfoReporter) Send(force bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	requests := []struct {
+		key string
+		req func() ([]byte, error)
+	}{
+		{"nic", r.nicInfoRequest},
+		{"disk", r.diskInfoRequest},
+	}
+
+	for _, x := range requests {
+		req, err := x.req()
+		if err != nil {
+			log.Printf("SendGuestInfo %s: %s", x.key, err)
+			continue
+		}
+		if req != nil {
+			r.request(x.key, req, force)
+		}
+	}
+
+	if r.OSInfo != nil {
+		if info, err := r.OSInfo(); err == nil {
+			r.request("os.full", GuestInfoCommand(GuestInfoOSNameFull, []byte(info.FullName)), force)
+			r.request("os.name", GuestInfoCommand(GuestInfoOSName, []byte(info.ShortName)), force)
+			r.request("os.detailed", []byte(fmt.Sprintf("info-set %s %s", GuestInfoDetailedDataKey, info.DetailedData())), force)
+		} else {
+			log.Printf("SendGuestInfo os: %s", err)
+		}
+	}
+
+	// uptime always changes, sent each time as vmtoolsd does
+	if r.Uptime != nil {
+		if uptime, err := r.Uptime(); err == nil {
+			r.request("uptime", GuestInfoCommand(GuestInfoUptime, []byte(strconv.FormatInt(int64(uptime/(10*time.Millisecond)), 10))), true)
+		}
+	}
+
+	r.sendAppInfo(force)
+}
+
+func (r *GuestInfoReporter) nicInfoRequest() ([]byte, error) {
+	if r.NicInfo == nil {
+		return nil, nil
+	}
+
+	b, err := EncodeXDR(r.NicInfo())
+	if err != nil {
+		return nil, err
+	}
+
+	return GuestInfoCommand(GuestInfoIPAddressV2, b), nil
+}
+
+func (r *GuestInfoReporter) diskInfoRequest() ([]byte, error) {
+	if r.DiskInfo == nil {
+		return nil, nil
+	}
+
+	info, err := r.DiskInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return GuestInfoCommand(GuestInfoDiskFreeSpace, b), nil
+}
+
+func (r *GuestInfoReporter) sendAppInfo(force bool) {
+	if r.AppInfo == nil {
+		return
+	}
+
+	interval := r.AppInfoInterval
+	if interval == 0 {
+		interval = DefaultAppInfoInterval
+	}
+
+	now := time.Now()
+	if !force && now.Sub(r.appInfoTime) < interval {
+		return
+	}
+	r.appInfoTime = now
+
+	apps, err := r.AppInfo()
+	if err != nil {
+		log.Printf("SendGuestInfo appInfo: %s", err)
+		return
+	}
+
+	// change detection is on the application list only, as the counter and time change with each update
+	list, _ := json.Marshal(apps)
+	if !force && bytes.Equal(r.sent["appInfo"], list) {
+		return
+	}
+
+	r.appInfoUpdate++
+	info := GuestAppInfo{
+		Version:       "1",
+		UpdateCounter: r.appInfoUpdate,
+		PublishTime:   now.UTC().Format(time.RFC3339Nano),
+		Applications:  apps,
+	}
+	if info.Applications == nil {
+		info.Applications = []GuestApp{}
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		log.Printf("SendGuestInfo appInfo: %s", err)
+		return
+	}
+
+	msg := fmt.Sprintf("info-set %s %s", GuestInfoAppInfoKey, b)
+	if _, err = r.out.Request([]byte(msg)); err != nil {
+		log.Printf("SendGuestInfo appInfo: %s", err)
+		return
+	}
+
+	r.sent["appInfo"] = list
+}
+
+// DefaultGuestAppInfo returns the running processes as the application list, sorted by name without duplicates.
+func DefaultGuestAppInfo() ([]GuestApp, error) {
+	names, err := guestProcessNames()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	var apps []GuestApp
+	for i, name := range names {
+		if i != 0 && names[i-1] == name {
+			continue
+		}
+		apps = append(apps, GuestApp{Name: name})
+	}
+
+	return apps, nil
+}
@@ -0,0 +1,185 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	procRoot      = "/proc"
+	osReleaseFile = "/etc/os-release"
+)
+
+type mountEntry struct {
+	device string
+	dir    string
+	fstype string
+}
+
+// procMounts returns the mounts of block device backed filesystems, without duplicate mount points
+func procMounts() ([]mountEntry, error) {
+	f, err := os.Open(filepath.Join(procRoot, "self", "mounts"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var mounts []mountEntry
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || !strings.HasPrefix(fields[0], "/dev/") {
+			continue
+		}
+
+		dir := strings.ReplaceAll(fields[1], `\040`, " ")
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		mounts = append(mounts, mountEntry{fields[0], dir, fields[2]})
+	}
+
+	return mounts, scanner.Err()
+}
+
+// DefaultGuestDiskInfo returns the size and free space of block device backed filesystems
+func DefaultGuestDiskInfo() (*GuestDiskInfo, error) {
+	mounts, err := procMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &GuestDiskInfo{Version: "1", Disks: []GuestDiskEntry{}}
+
+	for _, m := range mounts {
+		var fs syscall.Statfs_t
+		if err := syscall.Statfs(m.dir, &fs); err != nil {
+			continue
+		}
+
+		info.Disks = append(info.Disks, GuestDiskEntry{
+			Name: m.dir,
+			Free: fs.Bavail * uint64(fs.Bsize),
+			Size: fs.Blocks * uint64(fs.Bsize),
+			Type: m.fstype,
+		})
+	}
+
+	return info, nil
+}
+
+// DefaultGuestOSInfo returns the OS details from /etc/os-release and uname
+func DefaultGuestOSInfo() (*GuestOSInfo, error) {
+	var uts syscall.Utsname
+	if err := syscall.Uname(&uts); err != nil {
+		return nil, err
+	}
+
+	info := &GuestOSInfo{
+		FamilyName:    "Linux",
+		KernelVersion: utsString(uts.Release[:]),
+		Architecture:  "X86",
+		Bitness:       32 << (^uint(0) >> 63),
+	}
+
+	if strings.HasPrefix(runtime.GOARCH, "arm") {
+		info.Architecture = "Arm"
+	}
+
+	release := map[string]string{}
+	if f, err := os.Open(osReleaseFile); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if key, val, ok := strings.Cut(scanner.Text(), "="); ok {
+				release[key] = strings.Trim(val, `"'`)
+			}
+		}
+		_ = f.Close()
+	}
+
+	info.DistroName = release["NAME"]
+	info.DistroVersion = release["VERSION_ID"]
+	info.FullName = release["PRETTY_NAME"]
+	if info.FullName == "" {
+		info.FullName = "Linux " + info.KernelVersion
+	}
+
+	info.ShortName = linuxShortName(release["ID"], info.DistroVersion, info.KernelVersion, info.Bitness)
+
+	return info, nil
+}
+
+func utsString(b []int8) string {
+	s := make([]byte, 0, len(b))
+	for _, c := range b {
+		if c == 0 {
+			break
+		}
+		s = append(s, byte(c))
+	}
+	return string(s)
+}
+
+// DefaultGuestUptime returns the system uptime from /proc/uptime
+func DefaultGuestUptime() (time.Duration, error) {
+	b, err := os.ReadFile(filepath.Join(procRoot, "uptime"))
+	if err != nil {
+		return 0, err
+	}
+
+	f := strings.Fields(string(b))
+	if len(f) == 0 {
+		return 0, fmt.Errorf("invalid uptime %q", b)
+	}
+
+	secs, err := strconv.ParseFloat(f[0], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// guestProcessNames returns the executable names of all processes, excluding kernel threads
+func guestProcessNames() ([]string, error) {
+	dirs, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, dir := range dirs {
+		if _, err := strconv.Atoi(dir.Name()); err != nil {
+			continue
+		}
+
+		cmdline, err := os.ReadFile(filepath.Join(procRoot, dir.Name(), "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue // exited or kernel thread
+		}
+
+		arg0, _, _ := bytes.Cut(cmdline, []byte{0})
+		if name := filepath.Base(string(arg0)); name != "" && name != "." {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
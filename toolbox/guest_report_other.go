@@ -0,0 +1,36 @@
+//go:build !linux
+// +build !linux
+
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"errors"
+	"runtime"
+	"time"
+)
+
+// DefaultGuestDiskInfo is not supported on this platform, returning an empty list
+func DefaultGuestDiskInfo() (*GuestDiskInfo, error) {
+	return &GuestDiskInfo{Version: "1", Disks: []GuestDiskEntry{}}, nil
+}
+
+// DefaultGuestOSInfo returns the OS family only on this platform
+func DefaultGuestOSInfo() (*GuestOSInfo, error) {
+	return &GuestOSInfo{
+		FullName:   runtime.GOOS,
+		FamilyName: runtime.GOOS,
+	}, nil
+}
+
+// DefaultGuestUptime is not supported on this platform
+func DefaultGuestUptime() (time.Duration, error) {
+	return 0, errors.New("uptime not supported on " + runtime.GOOS)
+}
+
+func guestProcessNames() ([]string, error) {
+	return nil, nil
+}
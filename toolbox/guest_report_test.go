@@ -0,0 +1,177 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// nicInfoOnly disables all but NIC info, for tests that expect a fixed number of requests
+func nicInfoOnly(s *Service) {
+	s.GuestInfo.DiskInfo = nil
+	s.GuestInfo.OSInfo = nil
+	s.GuestInfo.Uptime = nil
+	s.GuestInfo.AppInfo = nil
+}
+
+func TestGuestInfoReporter(t *testing.T) {
+	out := new(recordChannelOut)
+	service := NewService(new(mockChannelIn), out)
+	r := service.GuestInfo
+
+	disk := &GuestDiskInfo{
+		Version: "1",
+		Disks:   []GuestDiskEntry{{Name: "/", Free: 1 << 30, Size: 4 << 30, Type: "ext4"}},
+	}
+	apps := []GuestApp{{Name: "sshd"}}
+
+	r.NicInfo = NewGuestNicInfo
+	r.DiskInfo = func() (*GuestDiskInfo, error) { return disk, nil }
+	r.OSInfo = func() (*GuestOSInfo, error) {
+		return &GuestOSInfo{
+			ShortName:     "ubuntu-64",
+			FullName:      "Ubuntu 24.04 LTS",
+			FamilyName:    "Linux",
+			DistroName:    "Ubuntu",
+			DistroVersion: "24.04",
+			KernelVersion: "6.8.0",
+			Architecture:  "X86",
+			Bitness:       64,
+		}, nil
+	}
+	r.Uptime = func() (time.Duration, error) { return 90 * time.Second, nil }
+	r.AppInfo = func() ([]GuestApp, error) { return apps, nil }
+
+	kinds := func() []string {
+		var k []string
+		for _, req := range out.requests {
+			f := strings.Fields(req)
+			k = append(k, f[0]+" "+f[1])
+		}
+		out.requests = nil
+		return k
+	}
+
+	service.SendGuestInfo()
+
+	expect := []string{
+		"SetGuestInfo 9",
+		"SetGuestInfo 3",
+		"SetGuestInfo 5",
+		"SetGuestInfo 6",
+		"info-set guestInfo.detailed.data",
+		"SetGuestInfo 7",
+		"info-set guestinfo.appInfo",
+	}
+	requests := append([]string(nil), out.requests...)
+	if k := kinds(); !reflect.DeepEqual(k, expect) {
+		t.Errorf("requests=%q", k)
+	}
+
+	if requests[5] != "SetGuestInfo  7 9000" {
+		t.Errorf("uptime=%q", requests[5])
+	}
+	if requests[4] != "info-set guestInfo.detailed.data architecture='X86' bitness='64' distroName='Ubuntu' "+
+		"distroVersion='24.04' familyName='Linux' kernelVersion='6.8.0' prettyName='Ubuntu 24.04 LTS'" {
+		t.Errorf("detailed=%q", requests[4])
+	}
+
+	var info GuestAppInfo
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(requests[6], "info-set guestinfo.appInfo ")), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.UpdateCounter != 1 || !reflect.DeepEqual(info.Applications, apps) {
+		t.Errorf("appInfo=%#v", info)
+	}
+
+	// only uptime is sent when nothing has changed
+	r.AppInfoInterval = time.Nanosecond
+	r.Send(false)
+	if k := kinds(); !reflect.DeepEqual(k, []string{"SetGuestInfo 7"}) {
+		t.Errorf("requests=%q", k)
+	}
+
+	disk.Disks[0].Free = 2 << 30
+	apps = append(apps, GuestApp{Name: "nginx", Version: "1.24"})
+	r.Send(false)
+	if k := kinds(); !reflect.DeepEqual(k, []string{"SetGuestInfo 3", "SetGuestInfo 7", "info-set guestinfo.appInfo"}) {
+		t.Errorf("requests=%q", k)
+	}
+
+	// errors are logged and retried on the next Send
+	r.DiskInfo = func() (*GuestDiskInfo, error) { return nil, errors.New("statfs") }
+	r.Send(false)
+	if k := kinds(); !reflect.DeepEqual(k, []string{"SetGuestInfo 7"}) {
+		t.Errorf("requests=%q", k)
+	}
+}
+
+func TestGuestOSInfo(t *testing.T) {
+	info := &GuestOSInfo{
+		FullName:      "Photon OS 5.0",
+		FamilyName:    "Linux",
+		DistroName:    "VMware Photon OS",
+		DistroVersion: "5.0",
+		KernelVersion: "6.1.10-11.ph5",
+		Architecture:  "X86",
+		Bitness:       64,
+	}
+
+	if parsed := ParseDetailedData(info.DetailedData()); !reflect.DeepEqual(parsed, info) {
+		t.Errorf("%#v != %#v", parsed, info)
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	tests := []struct {
+		id, version, kernel string
+		bitness             int
+		name                string
+	}{
+		{"ubuntu", "24.04", "6.8.0", 64, "ubuntu-64"},
+		{"debian", "12", "6.1.0", 64, "debian12-64"},
+		{"rhel", "9.4", "5.14.0", 64, "rhel9-64"},
+		{"photon", "5.0", "6.1.10", 64, "other6xlinux-64"},
+		{"alpine", "3.20", "5.15.0", 32, "other5xlinux"},
+	}
+
+	for _, test := range tests {
+		if name := linuxShortName(test.id, test.version, test.kernel, test.bitness); name != test.name {
+			t.Errorf("%s %s: %s != %s", test.id, test.version, name, test.name)
+		}
+	}
+
+	os, err := DefaultGuestOSInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.KernelVersion == "" || os.ShortName == "" || os.FullName == "" {
+		t.Errorf("%#v", os)
+	}
+
+	if _, err = DefaultGuestDiskInfo(); err != nil {
+		t.Error(err)
+	}
+
+	if uptime, err := DefaultGuestUptime(); err != nil || uptime <= 0 {
+		t.Errorf("uptime=%s: %v", uptime, err)
+	}
+
+	names, err := guestProcessNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) == 0 {
+		t.Error("no processes found")
+	}
+}
@@ -0,0 +1,92 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// GuestVariablePrefix is the key prefix of variables shared by the guest and VM ExtraConfig
+const GuestVariablePrefix = "guestinfo."
+
+// ErrGuestVariableNotFound is returned by GuestVariables.Get when the variable is not set
+var ErrGuestVariableNotFound = errors.New("guest variable not found")
+
+// GuestVariableKey returns the given key with the GuestVariablePrefix, if not already prefixed.
+func GuestVariableKey(key string) string {
+	if strings.HasPrefix(strings.ToLower(key), GuestVariablePrefix) {
+		return key
+	}
+	return GuestVariablePrefix + key
+}
+
+// GuestVariables gets and sets guestinfo.* variables via the info-get and info-set RPCs.
+// Variables set in the VM ExtraConfig are visible to the guest.  Variables set by the guest
+// are visible to the host via the VM config.extraConfig property with vcsim, but not with vCenter.
+// See object.VirtualMachine.GuestInfo for the host side of this keyspace.
+type GuestVariables struct {
+	out *ChannelOut
+}
+
+// NewGuestVariables returns a GuestVariables instance using the given RPC out channel,
+// for use without a Service, for example with NewBackdoorChannelOut.
+func NewGuestVariables(out Channel) *GuestVariables {
+	return &GuestVariables{out: &ChannelOut{Channel: out}}
+}
+
+// Get returns the value of the given variable, the GuestVariablePrefix is added to key if needed.
+func (v *GuestVariables) Get(key string) (string, error) {
+	key = GuestVariableKey(key)
+
+	reply, ok, err := v.out.request([]byte("info-get " + key))
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		if strings.HasPrefix(string(reply), "No value found") {
+			return "", ErrGuestVariableNotFound
+		}
+		return "", fmt.Errorf("info-get %s: %s", key, reply)
+	}
+
+	return string(reply), nil
+}
+
+// Set sets the value of the given variable, the GuestVariablePrefix is added to key if needed.
+func (v *GuestVariables) Set(key, val string) error {
+	if strings.ContainsFunc(key, unicode.IsSpace) {
+		return fmt.Errorf("invalid guest variable key %q", key)
+	}
+	if strings.Contains(val, "\x00") {
+		return errors.New("guest variable value contains NUL")
+	}
+
+	_, err := v.out.Request([]byte(fmt.Sprintf("info-set %s %s", GuestVariableKey(key), val)))
+	return err
+}
+
+// GetJSON decodes the JSON value of the given variable into val.
+func (v *GuestVariables) GetJSON(key string, val any) error {
+	s, err := v.Get(key)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(s), val)
+}
+
+// SetJSON sets the given variable to the JSON encoding of val.
+func (v *GuestVariables) SetJSON(key string, val any) error {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	return v.Set(key, string(b))
+}
@@ -0,0 +1,95 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// varsChannel implements the info-get and info-set RPCs as the VMX does
+type varsChannel struct {
+	vars  map[string]string
+	reply []byte
+}
+
+func (c *varsChannel) Start() error { return nil }
+func (c *varsChannel) Stop() error  { return nil }
+
+func (c *varsChannel) Send(buf []byte) error {
+	cmd, args, _ := strings.Cut(string(buf), " ")
+
+	switch cmd {
+	case "info-get":
+		if val, ok := c.vars[strings.ToLower(args)]; ok {
+			c.reply = []byte("1 " + val)
+		} else {
+			c.reply = []byte("0 No value found")
+		}
+	case "info-set":
+		key, val, _ := strings.Cut(args, " ")
+		c.vars[strings.ToLower(key)] = val
+		c.reply = []byte("1 ")
+	default:
+		c.reply = []byte("0 Unknown command")
+	}
+
+	return nil
+}
+
+func (c *varsChannel) Receive() ([]byte, error) {
+	return c.reply, nil
+}
+
+func TestGuestVariables(t *testing.T) {
+	c := &varsChannel{vars: map[string]string{"guestinfo.hello": "world"}}
+	vars := NewGuestVariables(c)
+
+	val, err := vars.Get("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "world" {
+		t.Errorf("val=%q", val)
+	}
+
+	_, err = vars.Get("guestinfo.enoent")
+	if !errors.Is(err, ErrGuestVariableNotFound) {
+		t.Errorf("err=%v", err)
+	}
+
+	if err = vars.Set("GuestInfo.Key", "a value with spaces"); err != nil {
+		t.Fatal(err)
+	}
+	if c.vars["guestinfo.key"] != "a value with spaces" {
+		t.Errorf("vars=%v", c.vars)
+	}
+
+	if err = vars.Set("key", "\x00"); err == nil {
+		t.Error("expected error")
+	}
+
+	if err = vars.Set("key name", "value"); err == nil {
+		t.Error("expected error")
+	}
+
+	type message struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	if err = vars.SetJSON("message", message{"vcsim", 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	var m message
+	if err = vars.GetJSON("message", &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "vcsim" || m.Count != 42 {
+		t.Errorf("message=%#v", m)
+	}
+}
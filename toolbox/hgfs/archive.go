@@ -227,8 +227,8 @@ func archiveRead(u *url.URL, tr *tar.Reader) error {
 		}
 
 		// validate to prevent directory traversal
-		if strings.Contains(header.Name, "..") {
-			log.Printf("skipping invalid entry with '..' in name: %s", header.Name)
+		if !filepath.IsLocal(header.Name) {
+			log.Printf("skipping invalid entry with non-local name: %s", header.Name)
 			continue
 		}
 
@@ -251,6 +251,9 @@ func archiveRead(u *url.URL, tr *tar.Reader) error {
 					err = cerr
 				}
 			}
+			if err == nil && !header.ModTime.IsZero() {
+				err = os.Chtimes(name, header.ModTime, header.ModTime)
+			}
 		case tar.TypeSymlink:
 			err = os.Symlink(header.Linkname, name)
 		}
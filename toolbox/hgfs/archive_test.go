@@ -59,7 +59,7 @@ func TestReadArchive(t *testing.T) {
 		}
 	}
 
-	c := NewClient()
+	c := newTestClient()
 	c.s.RegisterFileHandler(ArchiveScheme, NewArchiveHandler())
 
 	status := c.CreateSession()
@@ -213,7 +213,7 @@ func TestWriteArchive(t *testing.T) {
 	_ = tw.Close()
 	_ = gz.Close()
 
-	c := NewClient()
+	c := newTestClient()
 	c.s.RegisterFileHandler(ArchiveScheme, NewArchiveHandler())
 
 	status := c.CreateSession()
@@ -0,0 +1,283 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package hgfs
+
+import (
+	"bytes"
+	"os"
+	"sync/atomic"
+)
+
+// clientIOSize is the Client read and write request size, leaving room in the packet for the header
+const clientIOSize = 0x8000
+
+// Client is an HGFS protocol client, as the VMX is for the toolbox Server.
+// Request packets are sent via the Send function, for example using the VIX HgfsSendPacket command
+// or Server.Dispatch directly, providing access to files and directories without a FUSE mount.
+type Client struct {
+	// Send transports a request packet, returning the reply packet
+	Send func([]byte) ([]byte, error)
+
+	// Share is the name of the share that file names are relative to, defaults to the root share
+	Share string
+
+	SessionID uint64
+
+	requestID uint32
+}
+
+// NewClient returns a Client that sends request packets using the given function
+func NewClient(send func([]byte) ([]byte, error)) *Client {
+	return &Client{Send: send}
+}
+
+// Request sends the given request for op, decoding the reply payload into res
+func (c *Client) Request(op int32, req, res any) error {
+	var err error
+
+	p := new(Packet)
+	p.Payload, err = MarshalBinary(req)
+	if err != nil {
+		return err
+	}
+
+	p.Header = Header{
+		Version:    HeaderVersion,
+		Dummy:      OpNewHeader,
+		HeaderSize: headerSize,
+		RequestID:  atomic.AddUint32(&c.requestID, 1),
+		Op:         op,
+		Flags:      PacketFlagRequest,
+		SessionID:  c.SessionID,
+	}
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	data, err = c.Send(data)
+	if err != nil {
+		return err
+	}
+
+	p = new(Packet)
+	if err = p.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	if p.Status != StatusSuccess {
+		return &Status{Code: p.Status}
+	}
+
+	return UnmarshalBinary(p.Payload, res)
+}
+
+func (c *Client) fileName(name string) FileNameV3 {
+	var f FileNameV3
+	if c.Share == "" {
+		f.FromString(name)
+	} else {
+		f.FromShare(c.Share, name)
+	}
+	return f
+}
+
+// CreateSession creates a new session, which must be created before any other request is sent
+func (c *Client) CreateSession() error {
+	res := new(ReplyCreateSessionV4)
+
+	if err := c.Request(OpCreateSessionV4, new(RequestCreateSessionV4), res); err != nil {
+		return err
+	}
+
+	c.SessionID = res.SessionID
+
+	return nil
+}
+
+// DestroySession destroys the session, closing any files left open
+func (c *Client) DestroySession() error {
+	return c.Request(OpDestroySessionV4, new(RequestDestroySessionV4), new(ReplyDestroySessionV4))
+}
+
+// Stat returns the attributes of the given file
+func (c *Client) Stat(name string) (*AttrV2, error) {
+	f := c.fileName(name)
+	req := &RequestGetattrV2{FileName: FileName{Name: f.Name, Length: f.Length}}
+	res := new(ReplyGetattrV2)
+
+	if err := c.Request(OpGetattrV2, req, res); err != nil {
+		return nil, err
+	}
+
+	return &res.Attr, nil
+}
+
+// Open opens the given file, returning its handle.  Files opened with OpenModeWriteOnly are created or truncated.
+func (c *Client) Open(name string, mode int32) (uint32, error) {
+	req := &RequestOpenV3{OpenMode: mode, FileName: c.fileName(name)}
+	if mode == OpenModeWriteOnly {
+		req.OpenFlags = OpenCreateEmpty
+	}
+	res := new(ReplyOpenV3)
+
+	if err := c.Request(OpOpenV3, req, res); err != nil {
+		return 0, err
+	}
+
+	return res.Handle, nil
+}
+
+// Close closes the given file handle
+func (c *Client) Close(handle uint32) error {
+	return c.Request(OpClose, &RequestClose{Handle: handle}, new(ReplyClose))
+}
+
+// Read reads up to size bytes from the given file handle, an empty reply indicates the end of the file
+func (c *Client) Read(handle uint32, offset uint64, size uint32) ([]byte, error) {
+	req := &RequestReadV3{Handle: handle, Offset: offset, RequiredSize: size}
+	res := new(ReplyReadV3)
+
+	if err := c.Request(OpReadV3, req, res); err != nil {
+		return nil, err
+	}
+
+	return res.Payload, nil
+}
+
+// Write writes data to the given file handle
+func (c *Client) Write(handle uint32, offset uint64, data []byte) (int, error) {
+	req := &RequestWriteV3{Handle: handle, Offset: offset, RequiredSize: uint32(len(data)), Payload: data}
+	res := new(ReplyWriteV3)
+
+	if err := c.Request(OpWriteV3, req, res); err != nil {
+		return 0, err
+	}
+
+	return int(res.ActualSize), nil
+}
+
+// ReadFile returns the contents of the given file
+func (c *Client) ReadFile(name string) ([]byte, error) {
+	handle, err := c.Open(name, OpenModeReadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	for {
+		data, rerr := c.Read(handle, uint64(buf.Len()), clientIOSize)
+		if rerr != nil {
+			err = rerr
+			break
+		}
+		if len(data) == 0 {
+			break
+		}
+		_, _ = buf.Write(data)
+	}
+
+	if cerr := c.Close(handle); err == nil {
+		err = cerr
+	}
+
+	return buf.Bytes(), err
+}
+
+// WriteFile writes data to the given file, creating it if needed
+func (c *Client) WriteFile(name string, data []byte) error {
+	handle, err := c.Open(name, OpenModeWriteOnly)
+	if err != nil {
+		return err
+	}
+
+	var offset int
+
+	for offset < len(data) {
+		n, werr := c.Write(handle, uint64(offset), data[offset:min(offset+clientIOSize, len(data))])
+		if werr != nil {
+			err = werr
+			break
+		}
+		offset += n
+	}
+
+	if cerr := c.Close(handle); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// ReadDir returns the entries of the given directory, an empty name lists the shares
+func (c *Client) ReadDir(name string) ([]DirEntry, error) {
+	req := &RequestSearchOpenV3{}
+	if name == "" {
+		req.DirName.FromShare("", "")
+	} else {
+		req.DirName = c.fileName(name)
+	}
+	res := new(ReplySearchOpenV3)
+
+	if err := c.Request(OpSearchOpenV3, req, res); err != nil {
+		return nil, err
+	}
+
+	var entries []DirEntry
+	var err error
+
+	for {
+		r := new(ReplySearchReadV3)
+		err = c.Request(OpSearchReadV3, &RequestSearchReadV3{Search: res.Search, Offset: uint32(len(entries))}, r)
+		if err != nil || r.Count == 0 {
+			break
+		}
+		entries = append(entries, r.Entry)
+	}
+
+	if cerr := c.Request(OpSearchCloseV3, &RequestSearchCloseV3{Search: res.Search}, new(ReplySearchCloseV3)); err == nil {
+		err = cerr
+	}
+
+	return entries, err
+}
+
+// Mkdir creates the given directory with the given permissions
+func (c *Client) Mkdir(name string, perm os.FileMode) error {
+	req := &RequestCreateDirV3{
+		Mask:       CreateDirValidOwnerPerms | CreateDirValidGroupPerms | CreateDirValidOtherPerms | CreateDirValidFileName,
+		OwnerPerms: uint8(perm>>6) & 7,
+		GroupPerms: uint8(perm>>3) & 7,
+		OtherPerms: uint8(perm) & 7,
+		FileName:   c.fileName(name),
+	}
+
+	return c.Request(OpCreateDirV3, req, new(ReplyCreateDirV3))
+}
+
+// Remove removes the given file
+func (c *Client) Remove(name string) error {
+	return c.Request(OpDeleteFileV3, &RequestDeleteV3{FileName: c.fileName(name)}, new(ReplyDeleteV3))
+}
+
+// RemoveDir removes the given directory, which must be empty
+func (c *Client) RemoveDir(name string) error {
+	return c.Request(OpDeleteDirV3, &RequestDeleteV3{FileName: c.fileName(name)}, new(ReplyDeleteV3))
+}
+
+// Rename renames oldname to newname, replacing newname if it exists and replace is true
+func (c *Client) Rename(oldname, newname string, replace bool) error {
+	req := &RequestRenameV3{
+		OldName: c.fileName(oldname),
+		NewName: c.fileName(newname),
+	}
+	if !replace {
+		req.Hints = RenameHintNoReplaceExisting
+	}
+
+	return c.Request(OpRenameV3, req, new(ReplyRenameV3))
+}
@@ -0,0 +1,177 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package hgfs
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func entryNames(entries []DirEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.FileName.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func status(err error) uint32 {
+	var s *Status
+	if errors.As(err, &s) {
+		return s.Code
+	}
+	return StatusSuccess
+}
+
+func TestClientShares(t *testing.T) {
+	s := NewServer()
+
+	dir := t.TempDir()
+	ro := t.TempDir()
+
+	if err := s.AddShare(Share{Name: "data", Path: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddShare(Share{Name: "ro", Path: ro, ReadOnly: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddShare(Share{Name: "root", Path: dir}); err == nil {
+		t.Error("expected error")
+	}
+
+	c := NewClient(s.Dispatch)
+	if err := c.CreateSession(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.DestroySession(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	shares, err := c.ReadDir("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(shares); len(names) != 3 || names[0] != "data" || names[1] != "ro" || names[2] != "root" {
+		t.Errorf("shares=%v", names)
+	}
+
+	c.Share = "data"
+
+	if err = c.Mkdir("sub", 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Mkdir("sub", 0750); status(err) != StatusFileExists {
+		t.Errorf("err=%v", err)
+	}
+
+	data := bytes.Repeat([]byte("hgfs"), clientIOSize) // multiple write and read requests
+	if err = c.WriteFile("sub/file.txt", data); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "sub", "file.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, data) {
+		t.Errorf("len=%d", len(b))
+	}
+
+	b, err = c.ReadFile("sub/file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, data) {
+		t.Errorf("len=%d", len(b))
+	}
+
+	attr, err := c.Stat("sub/file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attr.Type != FileTypeRegular || attr.Size != uint64(len(data)) {
+		t.Errorf("attr=%#v", attr)
+	}
+
+	if err = c.WriteFile("other.txt", []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Rename("other.txt", "sub/file.txt", false); status(err) != StatusFileExists {
+		t.Errorf("err=%v", err)
+	}
+	if err = c.Rename("other.txt", "sub/renamed.txt", false); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := c.ReadDir("sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(entries); len(names) != 2 || names[0] != "file.txt" || names[1] != "renamed.txt" {
+		t.Errorf("entries=%v", names)
+	}
+
+	if err = c.RemoveDir("sub"); status(err) != StatusDirNotEmpty {
+		t.Errorf("err=%v", err)
+	}
+	if err = c.RemoveDir("sub/file.txt"); status(err) != StatusNotDirectory {
+		t.Errorf("err=%v", err)
+	}
+	if err = c.Remove("sub"); status(err) != StatusOperationNotPermitted {
+		t.Errorf("err=%v", err)
+	}
+	for _, name := range []string{"sub/file.txt", "sub/renamed.txt"} {
+		if err = c.Remove(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = c.RemoveDir("sub"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Stat("sub"); status(err) != StatusNoSuchFileOrDir {
+		t.Errorf("err=%v", err)
+	}
+
+	if _, err = c.ReadFile("../escape"); status(err) != StatusInvalidName {
+		t.Errorf("err=%v", err)
+	}
+
+	c.Share = "ro"
+
+	if err = c.WriteFile("file.txt", data); status(err) != StatusAccessDenied {
+		t.Errorf("err=%v", err)
+	}
+	if err = c.Mkdir("sub", 0755); status(err) != StatusAccessDenied {
+		t.Errorf("err=%v", err)
+	}
+
+	s.RemoveShare("ro")
+
+	if _, err = c.ReadDir("/"); status(err) != StatusNoSuchFileOrDir {
+		t.Errorf("err=%v", err)
+	}
+
+	// root share
+	c.Share = ""
+
+	if err = c.WriteFile(filepath.Join(dir, "root.txt"), data); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = c.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(entries); len(names) != 1 || names[0] != "root.txt" {
+		t.Errorf("entries=%v", names)
+	}
+}
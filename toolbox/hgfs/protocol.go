@@ -7,10 +7,12 @@ package hgfs
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"syscall"
 )
 
 // See: https://github.com/vmware/open-vm-tools/blob/master/open-vm-tools/lib/include/hgfsProto.h
@@ -169,6 +171,10 @@ func errorStatus(err error) uint32 {
 	}
 
 	switch {
+	case errors.Is(err, syscall.ENOTEMPTY): // before IsExist, which is true for ENOTEMPTY
+		return StatusDirNotEmpty
+	case errors.Is(err, syscall.ENOTDIR):
+		return StatusNotDirectory
 	case os.IsNotExist(err):
 		return StatusNoSuchFileOrDir
 	case os.IsExist(err):
@@ -501,11 +507,21 @@ const serverPolicyRootShareName = "root"
 
 // FromString converts name to a FileName
 func (f *FileName) FromString(name string) {
+	f.FromShare(serverPolicyRootShareName, name)
+}
+
+// FromShare converts name, relative to the given share, to a FileName.
+// An empty share name is the share list, as used by the SearchOpen request to list shares.
+func (f *FileName) FromShare(share, name string) {
 	name = strings.TrimPrefix(name, "/")
 
 	cp := strings.Split(name, "/")
 
-	cp = append([]string{serverPolicyRootShareName}, cp...)
+	if share != "" {
+		cp = append([]string{share}, cp...)
+	} else if name == "" {
+		cp = nil
+	}
 
 	f.Name = strings.Join(cp, "\x00")
 	f.Length = uint32(len(f.Name))
@@ -576,6 +592,19 @@ func (f *FileNameV3) Path() string {
 	return (&FileName{Name: f.Name, Length: f.Length}).Path()
 }
 
+// FromShare converts name, relative to the given share, to a FileNameV3
+func (f *FileNameV3) FromShare(share, name string) {
+	p := new(FileName)
+	p.FromShare(share, name)
+	f.Name = p.Name
+	f.Length = p.Length
+}
+
+// size returns the encoded size of a FileNameV3 followed by another field, including the NUL terminator
+func (f *FileNameV3) size() int {
+	return binary.Size(f.Length) + binary.Size(f.Flags) + binary.Size(f.CaseType) + binary.Size(f.ID) + len(f.Name) + 1
+}
+
 // FileType
 const (
 	FileTypeRegular = iota
@@ -833,3 +862,194 @@ type ReplyWriteV3 struct {
 	ActualSize uint32
 	Reserved   uint64
 }
+
+// RequestSearchOpenV3 as defined in hgfsProto.h:HgfsRequestSearchOpenV3
+type RequestSearchOpenV3 struct {
+	Reserved uint64
+	DirName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestSearchOpenV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Reserved, &r.DirName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestSearchOpenV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Reserved, &r.DirName)
+}
+
+// ReplySearchOpenV3 as defined in hgfsProto.h:HgfsReplySearchOpenV3
+type ReplySearchOpenV3 struct {
+	Search   uint32
+	Reserved uint64
+}
+
+// RequestSearchReadV3 as defined in hgfsProto.h:HgfsRequestSearchReadV3
+type RequestSearchReadV3 struct {
+	Search   uint32
+	Offset   uint32
+	Flags    uint32
+	Reserved uint64
+}
+
+// DirEntry as defined in hgfsProto.h:HgfsDirEntry
+type DirEntry struct {
+	NextEntry uint32
+	Attr      AttrV2
+	FileName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (e *DirEntry) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&e.NextEntry, &e.Attr, &e.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (e *DirEntry) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &e.NextEntry, &e.Attr, &e.FileName)
+}
+
+// ReplySearchReadV3 as defined in hgfsProto.h:HgfsReplySearchReadV3.
+// The server replies with a single entry per request, a Count of 0 indicates the end of the search.
+type ReplySearchReadV3 struct {
+	Count    uint64
+	Reserved uint64
+	Entry    DirEntry
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *ReplySearchReadV3) MarshalBinary() ([]byte, error) {
+	if r.Count == 0 {
+		return MarshalBinary(&r.Count, &r.Reserved)
+	}
+	return MarshalBinary(&r.Count, &r.Reserved, &r.Entry)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *ReplySearchReadV3) UnmarshalBinary(data []byte) error {
+	if err := UnmarshalBinary(data, &r.Count, &r.Reserved); err != nil {
+		return err
+	}
+	if r.Count == 0 {
+		return nil
+	}
+	return r.Entry.UnmarshalBinary(data[binary.Size(r.Count)+binary.Size(r.Reserved):])
+}
+
+// RequestSearchCloseV3 as defined in hgfsProto.h:HgfsRequestSearchCloseV3
+type RequestSearchCloseV3 struct {
+	Search   uint32
+	Reserved uint64
+}
+
+// ReplySearchCloseV3 as defined in hgfsProto.h:HgfsReplySearchCloseV3
+type ReplySearchCloseV3 struct {
+	Reserved uint64
+}
+
+// CreateDir valid mask
+const (
+	CreateDirValidSpecialPerms = 1 << iota
+	CreateDirValidOwnerPerms
+	CreateDirValidGroupPerms
+	CreateDirValidOtherPerms
+	CreateDirValidFileName
+	CreateDirValidFileAttr
+)
+
+// RequestCreateDirV3 as defined in hgfsProto.h:HgfsRequestCreateDirV3
+type RequestCreateDirV3 struct {
+	Mask         uint64
+	SpecialPerms uint8
+	OwnerPerms   uint8
+	GroupPerms   uint8
+	OtherPerms   uint8
+	FileAttr     uint64
+	FileName     FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestCreateDirV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Mask, &r.SpecialPerms, &r.OwnerPerms, &r.GroupPerms, &r.OtherPerms, &r.FileAttr, &r.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestCreateDirV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Mask, &r.SpecialPerms, &r.OwnerPerms, &r.GroupPerms, &r.OtherPerms, &r.FileAttr, &r.FileName)
+}
+
+// ReplyCreateDirV3 as defined in hgfsProto.h:HgfsReplyCreateDirV3
+type ReplyCreateDirV3 struct {
+	Reserved uint64
+}
+
+// RequestDeleteV3 as defined in hgfsProto.h:HgfsRequestDeleteV3, used by both OpDeleteFileV3 and OpDeleteDirV3
+type RequestDeleteV3 struct {
+	Hints    uint64
+	Reserved uint64
+	FileName FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestDeleteV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Hints, &r.Reserved, &r.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestDeleteV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Hints, &r.Reserved, &r.FileName)
+}
+
+// ReplyDeleteV3 as defined in hgfsProto.h:HgfsReplyDeleteV3
+type ReplyDeleteV3 struct {
+	Reserved uint64
+}
+
+// Rename hints
+const (
+	RenameHintUseSrcFileDesc = 1 << iota
+	RenameHintUseTargetFileDesc
+	RenameHintNoReplaceExisting
+	RenameHintNoCopyAllowed
+)
+
+// RequestRenameV3 as defined in hgfsProto.h:HgfsRequestRenameV3
+type RequestRenameV3 struct {
+	Hints    uint32
+	Reserved uint64
+	OldName  FileNameV3
+	NewName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestRenameV3) MarshalBinary() ([]byte, error) {
+	name, err := r.OldName.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if len(r.OldName.Name) != 0 {
+		name = append(name, 0) // OldName is followed by NewName, so must be NUL terminated
+	}
+
+	return MarshalBinary(&r.Hints, &r.Reserved, name, &r.NewName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestRenameV3) UnmarshalBinary(data []byte) error {
+	if err := UnmarshalBinary(data, &r.Hints, &r.Reserved, &r.OldName); err != nil {
+		return err
+	}
+
+	offset := binary.Size(r.Hints) + binary.Size(r.Reserved) + r.OldName.size()
+	if offset > len(data) {
+		return ProtocolError(errors.New("invalid rename request"))
+	}
+
+	return r.NewName.UnmarshalBinary(data[offset:])
+}
+
+// ReplyRenameV3 as defined in hgfsProto.h:HgfsReplyRenameV3
+type ReplyRenameV3 struct {
+	Reserved uint64
+}
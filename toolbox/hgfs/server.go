@@ -34,6 +34,7 @@ type Server struct {
 	handlers map[int32]func(*Packet) (any, error)
 	schemes  map[string]FileHandler
 	sessions map[uint64]*session
+	shares   map[string]Share
 	mu       sync.Mutex
 	handle   uint32
 
@@ -50,6 +51,7 @@ func NewServer() *Server {
 	s := &Server{
 		sessions: make(map[uint64]*session),
 		schemes:  make(map[string]FileHandler),
+		shares:   make(map[string]Share),
 		chmod:    os.Chmod,
 		chown:    os.Chown,
 	}
@@ -64,6 +66,13 @@ func NewServer() *Server {
 		OpOpenV3:           s.OpenV3,
 		OpReadV3:           s.ReadV3,
 		OpWriteV3:          s.WriteV3,
+		OpSearchOpenV3:     s.SearchOpenV3,
+		OpSearchReadV3:     s.SearchReadV3,
+		OpSearchCloseV3:    s.SearchCloseV3,
+		OpCreateDirV3:      s.CreateDirV3,
+		OpDeleteFileV3:     s.DeleteFileV3,
+		OpDeleteDirV3:      s.DeleteDirV3,
+		OpRenameV3:         s.RenameV3,
 	}
 
 	for op := range s.handlers {
@@ -203,8 +212,9 @@ func (s *Server) Stat(name string) (os.FileInfo, error) {
 }
 
 type session struct {
-	files map[uint32]File
-	mu    sync.Mutex
+	files    map[uint32]File
+	searches map[uint32][]DirEntry
+	mu       sync.Mutex
 }
 
 // TODO: we currently depend on the VMX to close files and remove sessions,
@@ -212,7 +222,8 @@ type session struct {
 // adding session expiration when implementing OpenModeWriteOnly support.
 func newSession() *session {
 	return &session{
-		files: make(map[uint32]File),
+		files:    make(map[uint32]File),
+		searches: make(map[uint32][]DirEntry),
 	}
 }
 
@@ -321,7 +332,11 @@ func (s *Server) GetattrV2(p *Packet) (any, error) {
 		return nil, err
 	}
 
-	name := req.FileName.Path()
+	name, err := s.resolve(req.FileName.Name, false)
+	if err != nil {
+		return nil, err
+	}
+
 	info, err := s.Stat(name)
 	if err != nil {
 		return nil, err
@@ -342,7 +357,10 @@ func (s *Server) SetattrV2(p *Packet) (any, error) {
 		return nil, err
 	}
 
-	name := req.FileName.Path()
+	name, err := s.resolve(req.FileName.Name, true)
+	if err != nil {
+		return nil, err
+	}
 
 	_, err = os.Stat(name)
 	if err != nil && os.IsNotExist(err) {
@@ -406,7 +424,10 @@ func (s *Server) Open(p *Packet) (any, error) {
 		return nil, err
 	}
 
-	name := req.FileName.Path()
+	name, err := s.resolve(req.FileName.Name, false)
+	if err != nil {
+		return nil, err
+	}
 	mode := req.OpenMode
 
 	if mode != OpenModeReadOnly {
@@ -474,7 +495,10 @@ func (s *Server) OpenV3(p *Packet) (any, error) {
 		return nil, err
 	}
 
-	name := req.FileName.Path()
+	name, err := s.resolve(req.FileName.Name, req.OpenMode != OpenModeReadOnly)
+	if err != nil {
+		return nil, err
+	}
 
 	if req.DesiredLock != LockNone {
 		return nil, &Status{
@@ -571,3 +595,232 @@ func (s *Server) WriteV3(p *Packet) (any, error) {
 
 	return res, nil
 }
+
+// SearchOpenV3 handles OpSearchOpenV3 requests.
+// The directory entries are read when the search is opened, an empty directory name lists the shares.
+func (s *Server) SearchOpenV3(p *Packet) (any, error) {
+	req := new(RequestSearchOpenV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []DirEntry
+
+	if req.DirName.Name == "" {
+		for _, name := range append([]string{serverPolicyRootShareName}, shareNames(s.Shares())...) {
+			entry := DirEntry{}
+			entry.Attr.Mask = AttrValidType
+			entry.Attr.Type = FileTypeDirectory
+			entry.FileName.FromShare("", name)
+			entries = append(entries, entry)
+		}
+	} else {
+		name, err := s.resolve(req.DirName.Name, false)
+		if err != nil {
+			return nil, err
+		}
+
+		files, err := os.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			info, err := file.Info()
+			if err != nil {
+				continue // removed since ReadDir
+			}
+
+			entry := DirEntry{}
+			entry.Attr.Stat(info)
+			entry.FileName.FromShare("", file.Name())
+			entries = append(entries, entry)
+		}
+	}
+
+	res := &ReplySearchOpenV3{
+		Search: s.newHandle(),
+	}
+
+	session.mu.Lock()
+	session.searches[res.Search] = entries
+	session.mu.Unlock()
+
+	return res, nil
+}
+
+func shareNames(shares []Share) []string {
+	names := make([]string, len(shares))
+	for i := range shares {
+		names[i] = shares[i].Name
+	}
+	return names
+}
+
+// SearchReadV3 handles OpSearchReadV3 requests, replying with the entry at the requested offset.
+func (s *Server) SearchReadV3(p *Packet) (any, error) {
+	req := new(RequestSearchReadV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	entries, ok := session.searches[req.Search]
+	session.mu.Unlock()
+
+	if !ok {
+		return nil, &Status{Code: StatusInvalidHandle}
+	}
+
+	res := new(ReplySearchReadV3)
+
+	if int(req.Offset) < len(entries) {
+		res.Count = 1
+		res.Entry = entries[req.Offset]
+	}
+
+	return res, nil
+}
+
+// SearchCloseV3 handles OpSearchCloseV3 requests
+func (s *Server) SearchCloseV3(p *Packet) (any, error) {
+	req := new(RequestSearchCloseV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	_, ok := session.searches[req.Search]
+	delete(session.searches, req.Search)
+	session.mu.Unlock()
+
+	if !ok {
+		return nil, &Status{Code: StatusInvalidHandle}
+	}
+
+	return &ReplySearchCloseV3{}, nil
+}
+
+// CreateDirV3 handles OpCreateDirV3 requests
+func (s *Server) CreateDirV3(p *Packet) (any, error) {
+	req := new(RequestCreateDirV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.resolve(req.FileName.Name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	perm := os.FileMode(0755)
+	if req.Mask&CreateDirValidOwnerPerms == CreateDirValidOwnerPerms {
+		perm = os.FileMode(req.OwnerPerms)<<6 | os.FileMode(req.GroupPerms)<<3 | os.FileMode(req.OtherPerms)
+	}
+
+	if err = os.Mkdir(name, perm); err != nil {
+		return nil, err
+	}
+
+	return &ReplyCreateDirV3{}, nil
+}
+
+func (s *Server) delete(p *Packet, dir bool) (any, error) {
+	req := new(RequestDeleteV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.resolve(req.FileName.Name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Lstat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case dir && !info.IsDir():
+		return nil, &Status{
+			Code: StatusNotDirectory,
+			Err:  fmt.Errorf("%q is not a directory", name),
+		}
+	case !dir && info.IsDir():
+		return nil, &Status{
+			Code: StatusOperationNotPermitted,
+			Err:  fmt.Errorf("%q is a directory", name),
+		}
+	}
+
+	if err = os.Remove(name); err != nil {
+		return nil, err
+	}
+
+	return &ReplyDeleteV3{}, nil
+}
+
+// DeleteFileV3 handles OpDeleteFileV3 requests
+func (s *Server) DeleteFileV3(p *Packet) (any, error) {
+	return s.delete(p, false)
+}
+
+// DeleteDirV3 handles OpDeleteDirV3 requests, the directory must be empty
+func (s *Server) DeleteDirV3(p *Packet) (any, error) {
+	return s.delete(p, true)
+}
+
+// RenameV3 handles OpRenameV3 requests
+func (s *Server) RenameV3(p *Packet) (any, error) {
+	req := new(RequestRenameV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := s.resolve(req.OldName.Name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	dst, err := s.resolve(req.NewName.Name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Hints&RenameHintNoReplaceExisting == RenameHintNoReplaceExisting {
+		if _, err = os.Lstat(dst); err == nil {
+			return nil, &Status{
+				Code: StatusFileExists,
+				Err:  fmt.Errorf("%q exists", dst),
+			}
+		}
+	}
+
+	if err = os.Rename(src, dst); err != nil {
+		return nil, err
+	}
+
+	return &ReplyRenameV3{}, nil
+}
@@ -12,20 +12,20 @@ import (
 	"testing"
 )
 
-type Client struct {
+type testClient struct {
 	s         *Server
 	SessionID uint64
 }
 
-func NewClient() *Client {
+func newTestClient() *testClient {
 	s := NewServer()
 
-	return &Client{
+	return &testClient{
 		s: s,
 	}
 }
 
-func (c *Client) Dispatch(op int32, req any, res any) *Packet {
+func (c *testClient) Dispatch(op int32, req any, res any) *Packet {
 	var err error
 	p := new(Packet)
 	p.Payload, err = MarshalBinary(req)
@@ -66,7 +66,7 @@ func (c *Client) Dispatch(op int32, req any, res any) *Packet {
 	return p
 }
 
-func (c *Client) CreateSession() uint32 {
+func (c *testClient) CreateSession() uint32 {
 	req := new(RequestCreateSessionV4)
 	res := new(ReplyCreateSessionV4)
 
@@ -79,14 +79,14 @@ func (c *Client) CreateSession() uint32 {
 	return p.Status
 }
 
-func (c *Client) DestroySession() uint32 {
+func (c *testClient) DestroySession() uint32 {
 	req := new(RequestDestroySessionV4)
 	res := new(ReplyDestroySessionV4)
 
 	return c.Dispatch(OpDestroySessionV4, req, res).Status
 }
 
-func (c *Client) GetAttr(name string) (*AttrV2, uint32) {
+func (c *testClient) GetAttr(name string) (*AttrV2, uint32) {
 	req := new(RequestGetattrV2)
 	res := new(ReplyGetattrV2)
 
@@ -101,7 +101,7 @@ func (c *Client) GetAttr(name string) (*AttrV2, uint32) {
 	return &res.Attr, p.Status
 }
 
-func (c *Client) SetAttr(name string, attr AttrV2) uint32 {
+func (c *testClient) SetAttr(name string, attr AttrV2) uint32 {
 	req := new(RequestSetattrV2)
 	res := new(ReplySetattrV2)
 
@@ -118,7 +118,7 @@ func (c *Client) SetAttr(name string, attr AttrV2) uint32 {
 	return p.Status
 }
 
-func (c *Client) Open(name string, write ...bool) (uint32, uint32) {
+func (c *testClient) Open(name string, write ...bool) (uint32, uint32) {
 	req := new(RequestOpen)
 	res := new(ReplyOpen)
 
@@ -136,7 +136,7 @@ func (c *Client) Open(name string, write ...bool) (uint32, uint32) {
 	return res.Handle, p.Status
 }
 
-func (c *Client) OpenWrite(name string) (uint32, uint32) {
+func (c *testClient) OpenWrite(name string) (uint32, uint32) {
 	req := new(RequestOpenV3)
 	res := new(ReplyOpenV3)
 
@@ -167,7 +167,7 @@ func (c *Client) OpenWrite(name string) (uint32, uint32) {
 	return res.Handle, p.Status
 }
 
-func (c *Client) Close(handle uint32) uint32 {
+func (c *testClient) Close(handle uint32) uint32 {
 	req := new(RequestClose)
 	res := new(ReplyClose)
 
@@ -177,7 +177,7 @@ func (c *Client) Close(handle uint32) uint32 {
 }
 
 func TestStaleSession(t *testing.T) {
-	c := NewClient()
+	c := newTestClient()
 
 	// list of methods that can return StatusStaleSession
 	invalid := []func() uint32{
@@ -197,7 +197,7 @@ func TestStaleSession(t *testing.T) {
 }
 
 func TestSessionMax(t *testing.T) {
-	c := NewClient()
+	c := newTestClient()
 	var status uint32
 
 	for i := 0; i <= maxSessions+1; i++ {
@@ -211,7 +211,7 @@ func TestSessionMax(t *testing.T) {
 
 func TestSessionDestroy(t *testing.T) {
 	Trace = true
-	c := NewClient()
+	c := newTestClient()
 	c.CreateSession()
 	_, status := c.Open("/etc/resolv.conf")
 	if status != StatusSuccess {
@@ -225,7 +225,7 @@ func TestSessionDestroy(t *testing.T) {
 }
 
 func TestInvalidOp(t *testing.T) {
-	c := NewClient()
+	c := newTestClient()
 	status := c.Dispatch(1024, new(RequestClose), new(ReplyClose)).Status
 	if status != StatusOperationNotSupported {
 		t.Errorf("status=%d", status)
@@ -235,7 +235,7 @@ func TestInvalidOp(t *testing.T) {
 func TestReadV3(t *testing.T) {
 	Trace = testing.Verbose()
 
-	c := NewClient()
+	c := newTestClient()
 	c.CreateSession()
 
 	_, status := c.GetAttr("enoent")
@@ -336,7 +336,7 @@ func TestWriteV3(t *testing.T) {
 
 	name := f.Name()
 
-	c := NewClient()
+	c := newTestClient()
 	c.CreateSession()
 
 	_, status := c.Open("enoent", true)
@@ -0,0 +1,104 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package hgfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Share is a directory exposed by the Server under Name, the first component of an HGFS file name.
+// The "root" share is the server policy root share, mapping HGFS file names to absolute paths.
+type Share struct {
+	Name     string
+	Path     string
+	ReadOnly bool
+}
+
+// AddShare adds the given share to the shares table, replacing any existing share with the same name.
+func (s *Server) AddShare(share Share) error {
+	if share.Name == "" || share.Name == serverPolicyRootShareName || strings.ContainsAny(share.Name, "/\x00") {
+		return fmt.Errorf("invalid share name %q", share.Name)
+	}
+
+	info, err := os.Stat(share.Path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("share %q path %q is not a directory", share.Name, share.Path)
+	}
+
+	s.mu.Lock()
+	s.shares[share.Name] = share
+	s.mu.Unlock()
+
+	return nil
+}
+
+// RemoveShare removes the given share from the shares table.
+func (s *Server) RemoveShare(name string) {
+	s.mu.Lock()
+	delete(s.shares, name)
+	s.mu.Unlock()
+}
+
+// Shares returns the shares table, sorted by name.
+func (s *Server) Shares() []Share {
+	s.mu.Lock()
+	shares := make([]Share, 0, len(s.shares))
+	for _, share := range s.shares {
+		shares = append(shares, share)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].Name < shares[j].Name
+	})
+
+	return shares
+}
+
+// resolve maps the given HGFS file name to a local path.
+// The first component of the name is the share name, the remaining components are relative to the share path.
+func (s *Server) resolve(name string, write bool) (string, error) {
+	cp := strings.Split(name, "\x00")
+
+	if cp[0] == serverPolicyRootShareName {
+		return (&FileName{Name: name}).Path(), nil
+	}
+
+	s.mu.Lock()
+	share, ok := s.shares[cp[0]]
+	s.mu.Unlock()
+
+	if !ok {
+		return "", &Status{
+			Code: StatusNoSuchFileOrDir,
+			Err:  fmt.Errorf("share %q not found", cp[0]),
+		}
+	}
+
+	if write && share.ReadOnly {
+		return "", &Status{
+			Code: StatusAccessDenied,
+			Err:  fmt.Errorf("share %q is read-only", share.Name),
+		}
+	}
+
+	for _, c := range cp[1:] {
+		if c == ".." || strings.ContainsRune(c, filepath.Separator) {
+			return "", &Status{
+				Code: StatusInvalidName,
+				Err:  fmt.Errorf("invalid file name %q in share %q", c, share.Name),
+			}
+		}
+	}
+
+	return filepath.Join(append([]string{share.Path}, cp[1:]...)...), nil
+}
@@ -52,9 +52,14 @@ type Service struct {
 	wg       *sync.WaitGroup
 	delay    time.Duration
 	rpcError bool
+	after    []func()
 
-	Command *CommandServer
-	Power   *PowerCommandHandler
+	Command   *CommandServer
+	Power     *PowerCommandHandler
+	DeployPkg *DeployPkgHandler
+	VMBackup  *VMBackupHandler
+	GuestInfo *GuestInfoReporter
+	Variables *GuestVariables
 
 	PrimaryIP func() string
 }
@@ -64,7 +69,7 @@ func NewService(rpcIn Channel, rpcOut Channel) *Service {
 	s := &Service{
 		name:     "toolbox", // Same name used by vmtoolsd
 		in:       NewTraceChannel(rpcIn),
-		out:      &ChannelOut{NewTraceChannel(rpcOut)},
+		out:      &ChannelOut{Channel: NewTraceChannel(rpcOut)},
 		handlers: make(map[string]Handler),
 		wg:       new(sync.WaitGroup),
 		stop:     make(chan struct{}),
@@ -84,6 +89,14 @@ func NewService(rpcIn Channel, rpcOut Channel) *Service {
 
 	s.Power = registerPowerCommandHandler(s)
 
+	s.DeployPkg = registerDeployPkgHandler(s)
+
+	s.VMBackup = registerVMBackupHandler(s)
+
+	s.GuestInfo = newGuestInfoReporter(s)
+
+	s.Variables = &GuestVariables{out: s.out}
+
 	return s
 }
 
@@ -149,11 +162,16 @@ func (s *Service) Start() error {
 		// Note we Send(response) even when nil, to let the VMX know we are here
 		var response []byte
 
+		poll := time.NewTicker(s.GuestInfo.interval())
+		defer poll.Stop()
+
 		for {
 			select {
 			case <-s.stop:
 				s.stopChannel()
 				return
+			case <-poll.C:
+				s.GuestInfo.Send(false)
 			case <-time.After(time.Millisecond * 10 * s.delay):
 				if err = s.checkReset(); err != nil {
 					continue
@@ -161,6 +179,7 @@ func (s *Service) Start() error {
 
 				err = s.in.Send(response)
 				response = nil
+				s.sent()
 				if err != nil {
 					s.delay = resetDelay
 					s.rpcError = true
@@ -193,6 +212,21 @@ func (s *Service) Wait() {
 	s.wg.Wait()
 }
 
+// afterResponse registers fn to be called once the response to the current request has been sent to the VMX
+func (s *Service) afterResponse(fn func()) {
+	s.after = append(s.after, fn)
+}
+
+// sent calls the functions registered by afterResponse
+func (s *Service) sent() {
+	after := s.after
+	s.after = nil
+
+	for _, fn := range after {
+		fn()
+	}
+}
+
 // Handler is given the raw argument portion of an RPC request and returns a response
 type Handler func([]byte) ([]byte, error)
 
@@ -305,20 +339,7 @@ func (s *Service) CapabilitiesRegister([]byte) ([]byte, error) {
 	return nil, nil
 }
 
+// SendGuestInfo sends all guest details to the VMX, regardless of changes since last sent
 func (s *Service) SendGuestInfo() {
-	info := []func() ([]byte, error){
-		GuestInfoNicInfoRequest,
-	}
-
-	for i, r := range info {
-		b, err := r()
-
-		if err == nil {
-			_, err = s.out.Request(b)
-		}
-
-		if err != nil {
-			log.Printf("SendGuestInfo %d: %s", i, err)
-		}
-	}
+	s.GuestInfo.Send(true)
 }
@@ -133,6 +133,7 @@ func TestServiceRun(t *testing.T) {
 	out := new(mockChannelOut)
 
 	service := NewService(in, out)
+	nicInfoOnly(service)
 
 	in.rpc = []*testRPC{
 		{"reset", "OK ATR toolbox"},
@@ -193,6 +194,7 @@ func TestServiceErrors(t *testing.T) {
 	out := new(mockChannelOut)
 
 	service := NewService(in, out)
+	nicInfoOnly(service)
 
 	service.RegisterHandler("Sorry", func([]byte) ([]byte, error) {
 		return nil, errors.New("i am so sorry")
@@ -9,6 +9,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"runtime"
 	"syscall"
 
 	"github.com/vmware/govmomi/toolbox"
@@ -26,6 +27,15 @@ func main() {
 	if os.Getuid() == 0 {
 		service.Power.Halt.Handler = toolbox.Halt
 		service.Power.Reboot.Handler = toolbox.Reboot
+
+		if runtime.GOOS == "linux" {
+			service.DeployPkg.Handler = toolbox.NewLinuxCustomizer("/").Customize
+			service.DeployPkg.Reboot = toolbox.Reboot
+
+			fs := new(toolbox.FSFreezer)
+			service.VMBackup.Freeze = fs.Freeze
+			service.VMBackup.Thaw = fs.Thaw
+		}
 	}
 
 	err := service.Start()
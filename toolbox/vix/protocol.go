@@ -40,6 +40,10 @@ const (
 
 	// VIX_USER_CREDENTIAL_NAME_PASSWORD
 	UserCredentialTypeNamePassword = 1
+	// VIX_USER_CREDENTIAL_SAML_BEARER_TOKEN
+	UserCredentialTypeSAMLBearerToken = 11
+	// VIX_USER_CREDENTIAL_SAML_BEARER_TOKEN_HOST_VERIFIED
+	UserCredentialTypeSAMLBearerTokenHostVerified = 12
 
 	// VIX_E_* constants from vix.h
 	OK                 = 0
@@ -833,3 +837,60 @@ func (c *UserCredentialNamePassword) MarshalBinary() ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// UserCredentialSAMLToken is the SAML bearer token credential, where Name is the
+// guest account username, which is empty if the token subject is mapped via an alias.
+type UserCredentialSAMLToken struct {
+	Body struct {
+		TokenLength uint32
+		NameLength  uint32
+	}
+
+	Token string
+	Name  string
+}
+
+func (c *UserCredentialSAMLToken) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(bytes.TrimRight(data, "\x00"))
+
+	err := binary.Read(buf, binary.LittleEndian, &c.Body)
+	if err != nil {
+		return err
+	}
+
+	str, err := base64.StdEncoding.DecodeString(buf.String())
+	if err != nil {
+		return err
+	}
+
+	// the token and name are each followed by a NUL, lengths are int64 to avoid uint32 overflow
+	tokenLength, nameLength := int64(c.Body.TokenLength), int64(c.Body.NameLength)
+	end := tokenLength + 1 + nameLength
+	if end >= int64(len(str)) {
+		return Error(InvalidMessageBody)
+	}
+
+	c.Token = string(str[0:tokenLength])
+	c.Name = string(str[tokenLength+1 : end])
+
+	return nil
+}
+
+func (c *UserCredentialSAMLToken) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	c.Body.TokenLength = uint32(len(c.Token))
+	c.Body.NameLength = uint32(len(c.Name))
+
+	_ = binary.Write(buf, binary.LittleEndian, &c.Body)
+
+	src := append([]byte(c.Token+"\x00"), []byte(c.Name+"\x00")...)
+
+	enc := base64.StdEncoding
+	cred := make([]byte, enc.EncodedLen(len(src)))
+	enc.Encode(cred, src)
+	_, _ = buf.Write(cred)
+	_ = buf.WriteByte(0)
+
+	return buf.Bytes(), nil
+}
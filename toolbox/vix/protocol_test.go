@@ -5,6 +5,8 @@
 package vix
 
 import (
+	"encoding/binary"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -53,3 +55,40 @@ func TestMarshalVixMsgStartProgramRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestMarshalUserCredentialSAMLToken(t *testing.T) {
+	creds := []*UserCredentialSAMLToken{
+		{Token: "<saml2:Assertion/>"},
+		{Token: "<saml2:Assertion/>", Name: "root"},
+	}
+
+	for i, in := range creds {
+		buf, err := in.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := new(UserCredentialSAMLToken)
+
+		err = out.UnmarshalBinary(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("%d marshal mismatch", i)
+		}
+	}
+
+	// lengths that overflow uint32 are rejected
+	buf, err := creds[1].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.LittleEndian.PutUint32(buf, math.MaxUint32)
+
+	err = new(UserCredentialSAMLToken).UnmarshalBinary(buf)
+	if err != Error(InvalidMessageBody) {
+		t.Errorf("err=%v", err)
+	}
+}
@@ -0,0 +1,387 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// VMBackup events as defined in open-vm-tools/lib/include/vmBackupSignals.h
+const (
+	VMBackupEventRequestorDone  = "req.done"
+	VMBackupEventRequestorError = "req.error"
+	VMBackupEventRequestorAbort = "req.aborted"
+	VMBackupEventSnapshotCommit = "prov.snapshotCommit"
+	VMBackupEventKeepAlive      = "req.keepAlive"
+)
+
+// VmBackupStatus enum as defined in open-vm-tools/lib/include/vmBackupSignals.h
+const (
+	VMBackupSuccess = iota
+	VMBackupInvalidState
+	VMBackupScriptError
+	VMBackupSyncError
+	VMBackupRemoteAbort
+	VMBackupUnexpectedError
+)
+
+// Backup script arguments, as used by open-vm-tools/services/plugins/vmbackup/scriptOps.c
+const (
+	vmBackupScriptFreeze     = "freeze"
+	vmBackupScriptFreezeFail = "freezeFail"
+	vmBackupScriptThaw       = "thaw"
+)
+
+const (
+	vmBackupStateIdle = iota
+	vmBackupStateFreezing
+	vmBackupStateFrozen
+)
+
+// DefaultVMBackupScriptDir is the directory open-vm-tools runs backup scripts from
+const DefaultVMBackupScriptDir = "/etc/vmware-tools/backupScripts.d"
+
+// DefaultVMBackupTimeout is the time the guest stays quiesced before it is thawed without a vmbackup.snapshotDone request
+const DefaultVMBackupTimeout = 15 * time.Minute
+
+// DefaultVMBackupScriptTimeout is the time a backup script can run before it is killed and considered failed
+const DefaultVMBackupScriptTimeout = 5 * time.Minute
+
+// vmBackupKeepAlive is the interval req.keepAlive events are sent at while the freeze phase is running
+const vmBackupKeepAlive = 15 * time.Second
+
+// VMBackupHandler quiesces the guest for snapshots taken with the quiesce flag.
+// The VMX sends the vmbackup.start RPC, the handler runs the freeze scripts, freezes filesystems
+// and reports the prov.snapshotCommit event. Once the snapshot is taken, the VMX sends
+// the vmbackup.snapshotDone RPC and the handler thaws filesystems, runs the thaw scripts
+// and reports the req.done event.
+type VMBackupHandler struct {
+	// ScriptDir contains the executables run with the "freeze", "thaw" or "freezeFail" argument.
+	// Scripts are run in lexical order on freeze and reverse order on thaw.
+	ScriptDir string
+
+	// Freeze is called after the freeze scripts are run, if not nil.
+	Freeze func() error
+
+	// Thaw is called before the thaw scripts are run, if not nil.
+	Thaw func() error
+
+	// Timeout for the guest to remain quiesced, defaults to DefaultVMBackupTimeout.
+	Timeout time.Duration
+
+	// ScriptTimeout for each backup script to complete, defaults to DefaultVMBackupScriptTimeout.
+	ScriptTimeout time.Duration
+
+	out       *ChannelOut
+	keepAlive time.Duration
+
+	mu      sync.Mutex
+	state   int
+	scripts []string
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	id      int
+}
+
+func registerVMBackupHandler(service *Service) *VMBackupHandler {
+	handler := &VMBackupHandler{
+		ScriptDir: DefaultVMBackupScriptDir,
+		out:       service.out,
+		keepAlive: vmBackupKeepAlive,
+	}
+
+	service.RegisterHandler("vmbackup.start", handler.Start)
+	service.RegisterHandler("vmbackup.snapshotDone", handler.SnapshotDone)
+	service.RegisterHandler("vmbackup.abort", handler.Abort)
+
+	return handler
+}
+
+func (h *VMBackupHandler) event(event string, code int, msg string) {
+	msg = fmt.Sprintf("vmbackup.eventSet %s %d %s", event, code, msg)
+
+	if _, err := h.out.Request([]byte(msg)); err != nil {
+		log.Printf("unable to send %q: %q", msg, err)
+	}
+}
+
+func (h *VMBackupHandler) done(code int, err error) {
+	if err != nil {
+		h.event(VMBackupEventRequestorError, code, err.Error())
+	}
+	h.event(VMBackupEventRequestorDone, code, "")
+}
+
+// Start is the handler for the vmbackup.start RPC.
+// The guest is quiesced asynchronously, with progress reported via vmbackup.eventSet requests.
+func (h *VMBackupHandler) Start([]byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state != vmBackupStateIdle {
+		return nil, errors.New("vmbackup operation already in progress")
+	}
+
+	h.state = vmBackupStateFreezing
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+
+	go h.freeze(ctx)
+
+	return nil, nil
+}
+
+// freeze runs the freeze scripts and Freeze func without holding h.mu, such that the freeze phase can be aborted.
+// The state remains vmBackupStateFreezing until freeze returns, preventing another Start in the meantime.
+func (h *VMBackupHandler) freeze(ctx context.Context) {
+	stop := h.sendKeepAlive()
+
+	code := VMBackupScriptError
+	frozen := false
+
+	scripts, err := h.listScripts()
+	if err == nil {
+		for _, script := range scripts {
+			if err = h.runScript(ctx, script, vmBackupScriptFreeze); err != nil {
+				break
+			}
+			h.scripts = append(h.scripts, script)
+		}
+	}
+
+	if err == nil && h.Freeze != nil {
+		if err = h.Freeze(); err != nil {
+			code = VMBackupSyncError
+		} else {
+			frozen = true
+		}
+	}
+
+	stop()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if ctx.Err() != nil {
+		// aborted, req.aborted was reported by Abort
+		if frozen && h.Thaw != nil {
+			if err = h.Thaw(); err != nil {
+				log.Printf("vmbackup: %s", err)
+			}
+		}
+		h.freezeFail()
+		return
+	}
+
+	if err != nil {
+		h.freezeFail()
+		h.done(code, err)
+		return
+	}
+
+	h.state = vmBackupStateFrozen
+
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = DefaultVMBackupTimeout
+	}
+	h.id++
+	id := h.id
+	h.timer = time.AfterFunc(timeout, func() { h.timeout(id) })
+
+	h.event(VMBackupEventSnapshotCommit, VMBackupSuccess, "")
+}
+
+// sendKeepAlive sends req.keepAlive events until the returned func is called,
+// such that the VMX does not time out while the freeze scripts are running.
+func (h *VMBackupHandler) sendKeepAlive() func() {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(h.keepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				h.event(VMBackupEventKeepAlive, VMBackupSuccess, "")
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// freezeFail runs the scripts that completed the freeze phase with the "freezeFail" argument
+func (h *VMBackupHandler) freezeFail() {
+	for i := len(h.scripts) - 1; i >= 0; i-- {
+		if err := h.runScript(context.Background(), h.scripts[i], vmBackupScriptFreezeFail); err != nil {
+			log.Printf("vmbackup: %s", err)
+		}
+	}
+
+	h.reset()
+}
+
+// thaw must be called with h.mu held and state == vmBackupStateFrozen
+func (h *VMBackupHandler) thaw() (int, error) {
+	defer h.reset()
+
+	if h.Thaw != nil {
+		if err := h.Thaw(); err != nil {
+			return VMBackupSyncError, err
+		}
+	}
+
+	for i := len(h.scripts) - 1; i >= 0; i-- {
+		if err := h.runScript(context.Background(), h.scripts[i], vmBackupScriptThaw); err != nil {
+			return VMBackupScriptError, err
+		}
+	}
+
+	return VMBackupSuccess, nil
+}
+
+func (h *VMBackupHandler) reset() {
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	if h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+	}
+	h.scripts = nil
+	h.state = vmBackupStateIdle
+}
+
+func (h *VMBackupHandler) timeout(id int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state != vmBackupStateFrozen || h.id != id {
+		return // thawed before the timer fired
+	}
+
+	if _, err := h.thaw(); err != nil {
+		log.Printf("vmbackup: %s", err)
+	}
+
+	h.done(VMBackupUnexpectedError, errors.New("timeout waiting for snapshot to complete"))
+}
+
+// SnapshotDone is the handler for the vmbackup.snapshotDone RPC, sent by the VMX once the snapshot is taken.
+func (h *VMBackupHandler) SnapshotDone([]byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state != vmBackupStateFrozen {
+		return nil, errors.New("vmbackup not in frozen state")
+	}
+
+	h.done(h.thaw())
+
+	return nil, nil
+}
+
+// Abort is the handler for the vmbackup.abort RPC, the guest is thawed if quiesced.
+func (h *VMBackupHandler) Abort([]byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch h.state {
+	case vmBackupStateIdle:
+		return nil, errors.New("vmbackup operation not in progress")
+	case vmBackupStateFrozen:
+		if _, err := h.thaw(); err != nil {
+			log.Printf("vmbackup: %s", err)
+		}
+	case vmBackupStateFreezing:
+		if h.cancel == nil {
+			return nil, errors.New("vmbackup operation already aborted")
+		}
+		// the freeze goroutine runs freezeFail and resets the state once the running script is killed
+		h.cancel()
+		h.cancel = nil
+	}
+
+	h.event(VMBackupEventRequestorAbort, VMBackupRemoteAbort, "")
+	h.event(VMBackupEventRequestorDone, VMBackupRemoteAbort, "")
+
+	return nil, nil
+}
+
+// listScripts returns the executable files in ScriptDir, in lexical order
+func (h *VMBackupHandler) listScripts() ([]string, error) {
+	if h.ScriptDir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(h.ScriptDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var scripts []string
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0 {
+			scripts = append(scripts, filepath.Join(h.ScriptDir, entry.Name()))
+		}
+	}
+
+	return scripts, nil
+}
+
+// runScript runs the backup script with the given argument, killing it if ScriptTimeout is exceeded or ctx is canceled
+func (h *VMBackupHandler) runScript(ctx context.Context, script, arg string) error {
+	timeout := h.ScriptTimeout
+	if timeout == 0 {
+		timeout = DefaultVMBackupScriptTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// #nosec: Subprocess launching with variable
+	cmd := exec.CommandContext(ctx, script, arg)
+	cmd.WaitDelay = time.Second // in case the script leaves children with its output open
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timeout after %s", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s: %s %s", script, arg, err, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ioctl requests as defined in linux/fs.h
+const (
+	fiFreeze = 0xc0045877 // _IOWR('X', 119, int)
+	fiThaw   = 0xc0045878 // _IOWR('X', 120, int)
+)
+
+// FSFreezer freezes and thaws filesystems using the FIFREEZE and FITHAW ioctls,
+// as the open-vm-tools syncDriver does.
+type FSFreezer struct {
+	// Mounts to freeze, if empty all block device backed mounts listed in /proc/self/mounts are frozen.
+	Mounts []string
+
+	frozen []*os.File
+}
+
+// Freeze freezes each mount, thawing those already frozen if any fail.
+func (f *FSFreezer) Freeze() error {
+	mounts := f.Mounts
+	if len(mounts) == 0 {
+		var err error
+		if mounts, err = blockDeviceMounts(); err != nil {
+			return err
+		}
+	}
+
+	for _, mount := range mounts {
+		dir, err := os.Open(mount)
+		if err != nil {
+			_ = f.Thaw()
+			return err
+		}
+
+		if err = fsIoctl(dir, fiFreeze); err != nil {
+			_ = dir.Close()
+			if err == syscall.EOPNOTSUPP {
+				continue // filesystem does not support freezing
+			}
+			_ = f.Thaw()
+			return fmt.Errorf("freeze %s: %s", mount, err)
+		}
+
+		f.frozen = append(f.frozen, dir)
+	}
+
+	return nil
+}
+
+// Thaw thaws the mounts frozen by Freeze, in reverse order.
+func (f *FSFreezer) Thaw() error {
+	var rerr error
+
+	for i := len(f.frozen) - 1; i >= 0; i-- {
+		dir := f.frozen[i]
+
+		if err := fsIoctl(dir, fiThaw); err != nil && rerr == nil {
+			rerr = fmt.Errorf("thaw %s: %s", dir.Name(), err)
+		}
+
+		_ = dir.Close()
+	}
+
+	f.frozen = nil
+
+	return rerr
+}
+
+func fsIoctl(dir *os.File, req uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dir.Fd(), req, 0)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+// blockDeviceMounts returns the mount points of block device backed filesystems
+func blockDeviceMounts() ([]string, error) {
+	entries, err := procMounts()
+	if err != nil {
+		return nil, err
+	}
+
+	mounts := make([]string, len(entries))
+	for i := range entries {
+		mounts[i] = entries[i].dir
+	}
+
+	return mounts, nil
+}
@@ -0,0 +1,28 @@
+//go:build !linux
+// +build !linux
+
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"errors"
+	"runtime"
+)
+
+// FSFreezer freezes and thaws filesystems, only supported on Linux.
+type FSFreezer struct {
+	Mounts []string
+}
+
+// Freeze is not supported on this platform.
+func (f *FSFreezer) Freeze() error {
+	return errors.New("filesystem freeze not supported on " + runtime.GOOS)
+}
+
+// Thaw is not supported on this platform.
+func (f *FSFreezer) Thaw() error {
+	return nil
+}
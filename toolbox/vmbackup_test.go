@@ -0,0 +1,240 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package toolbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// eventChannelOut forwards vmbackup events, replying OK to each request
+type eventChannelOut struct {
+	mockChannelOut
+	events chan string
+}
+
+func (c *eventChannelOut) Send(buf []byte) error {
+	if event, ok := bytes.CutPrefix(buf, []byte("vmbackup.eventSet ")); ok {
+		c.events <- string(event)
+	}
+	return nil
+}
+
+func (c *eventChannelOut) Receive() ([]byte, error) {
+	return rpciOK, nil
+}
+
+func (c *eventChannelOut) wait(t *testing.T, expect ...string) {
+	t.Helper()
+
+	for _, e := range expect {
+		select {
+		case event := <-c.events:
+			if event != e {
+				t.Fatalf("event=%q, expected %q", event, e)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timeout waiting for %q", e)
+		}
+	}
+}
+
+func TestVMBackup(t *testing.T) {
+	out := &eventChannelOut{events: make(chan string, 8)}
+	service := NewService(new(mockChannelIn), out)
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	service.VMBackup.ScriptDir = filepath.Join(dir, "scripts")
+
+	if err := os.Mkdir(service.VMBackup.ScriptDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"10-db", "20-app"} {
+		script := fmt.Sprintf("#!/bin/sh\necho %s $1 >> %s\n", name, log)
+		if err := os.WriteFile(filepath.Join(service.VMBackup.ScriptDir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// not executable
+	if err := os.WriteFile(filepath.Join(service.VMBackup.ScriptDir, "README"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	service.VMBackup.Freeze = func() error {
+		calls = append(calls, "freeze")
+		return nil
+	}
+	service.VMBackup.Thaw = func() error {
+		calls = append(calls, "thaw")
+		return nil
+	}
+
+	readLog := func() []string {
+		data, _ := os.ReadFile(log)
+		_ = os.Remove(log)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+
+	// not frozen
+	reply := service.Dispatch([]byte("vmbackup.snapshotDone"))
+	if !bytes.HasPrefix(reply, []byte("ERR")) {
+		t.Errorf("reply=%q", reply)
+	}
+
+	reply = service.Dispatch([]byte("vmbackup.start 0"))
+	if string(reply) != "OK " {
+		t.Fatalf("reply=%q", reply)
+	}
+	out.wait(t, "prov.snapshotCommit 0 ")
+
+	// already in progress
+	reply = service.Dispatch([]byte("vmbackup.start 0"))
+	if !bytes.HasPrefix(reply, []byte("ERR")) {
+		t.Errorf("reply=%q", reply)
+	}
+
+	reply = service.Dispatch([]byte("vmbackup.snapshotDone"))
+	if string(reply) != "OK " {
+		t.Fatalf("reply=%q", reply)
+	}
+	out.wait(t, "req.done 0 ")
+
+	if !reflect.DeepEqual(calls, []string{"freeze", "thaw"}) {
+		t.Errorf("calls=%v", calls)
+	}
+
+	expect := []string{"10-db freeze", "20-app freeze", "20-app thaw", "10-db thaw"}
+	if lines := readLog(); !reflect.DeepEqual(lines, expect) {
+		t.Errorf("log=%q", lines)
+	}
+
+	// abort while frozen
+	calls = nil
+	_ = service.Dispatch([]byte("vmbackup.start 0"))
+	out.wait(t, "prov.snapshotCommit 0 ")
+	reply = service.Dispatch([]byte("vmbackup.abort"))
+	if string(reply) != "OK " {
+		t.Fatalf("reply=%q", reply)
+	}
+	out.wait(t, "req.aborted 4 ", "req.done 4 ")
+	if !reflect.DeepEqual(calls, []string{"freeze", "thaw"}) {
+		t.Errorf("calls=%v", calls)
+	}
+	_ = readLog()
+
+	// timeout
+	service.VMBackup.Timeout = time.Millisecond
+	_ = service.Dispatch([]byte("vmbackup.start 0"))
+	out.wait(t, "prov.snapshotCommit 0 ", "req.error 5 timeout waiting for snapshot to complete", "req.done 5 ")
+	_ = readLog()
+	service.VMBackup.Timeout = 0
+
+	// freeze error
+	service.VMBackup.Freeze = func() error {
+		return errors.New("device busy")
+	}
+	_ = service.Dispatch([]byte("vmbackup.start 0"))
+	out.wait(t, "req.error 3 device busy", "req.done 3 ")
+
+	expect = []string{"10-db freeze", "20-app freeze", "20-app freezeFail", "10-db freezeFail"}
+	if lines := readLog(); !reflect.DeepEqual(lines, expect) {
+		t.Errorf("log=%q", lines)
+	}
+
+	// script error
+	script := filepath.Join(service.VMBackup.ScriptDir, "30-fail")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	_ = service.Dispatch([]byte("vmbackup.start 0"))
+	select {
+	case event := <-out.events:
+		if !strings.HasPrefix(event, "req.error 2 ") {
+			t.Errorf("event=%q", event)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for req.error")
+	}
+	out.wait(t, "req.done 2 ")
+	_ = readLog()
+
+	// hung script is killed
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexec sleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	service.VMBackup.Freeze = nil
+	service.VMBackup.ScriptTimeout = 100 * time.Millisecond
+	_ = service.Dispatch([]byte("vmbackup.start 0"))
+	out.wait(t, "req.error 2 "+script+" freeze: timeout after 100ms ", "req.done 2 ")
+	expect = []string{"10-db freeze", "20-app freeze", "20-app freezeFail", "10-db freezeFail"}
+	if lines := readLog(); !reflect.DeepEqual(lines, expect) {
+		t.Errorf("log=%q", lines)
+	}
+
+	// abort while freezing, with keep alive events sent until the hung script is killed
+	service.VMBackup.ScriptTimeout = 0
+	service.VMBackup.keepAlive = 10 * time.Millisecond
+	_ = service.Dispatch([]byte("vmbackup.start 0"))
+	out.wait(t, "req.keepAlive 0 ")
+	reply = service.Dispatch([]byte("vmbackup.abort"))
+	if string(reply) != "OK " {
+		t.Fatalf("reply=%q", reply)
+	}
+	for event := range out.events {
+		if event != "req.keepAlive 0 " {
+			if event != "req.aborted 4 " {
+				t.Fatalf("event=%q", event)
+			}
+			break
+		}
+	}
+	out.wait(t, "req.done 4 ")
+
+	// freezeFail is run once the script is killed, after which a new operation can start
+	service.VMBackup.keepAlive = vmBackupKeepAlive
+	if err := os.Remove(script); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; ; i++ {
+		reply = service.Dispatch([]byte("vmbackup.start 0"))
+		if string(reply) == "OK " {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("reply=%q", reply)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	for event := range out.events {
+		if event != "req.keepAlive 0 " {
+			if event != "prov.snapshotCommit 0 " {
+				t.Fatalf("event=%q", event)
+			}
+			break
+		}
+	}
+	_ = service.Dispatch([]byte("vmbackup.snapshotDone"))
+	out.wait(t, "req.done 0 ")
+	expect = []string{"10-db freeze", "20-app freeze", "20-app freezeFail", "10-db freezeFail"}
+	if lines := readLog(); !reflect.DeepEqual(lines[:4], expect) {
+		t.Errorf("log=%q", lines)
+	}
+
+	// abort when idle
+	reply = service.Dispatch([]byte("vmbackup.abort"))
+	if !bytes.HasPrefix(reply, []byte("ERR")) {
+		t.Errorf("reply=%q", reply)
+	}
+}
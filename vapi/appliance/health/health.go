@@ -0,0 +1,83 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const Path = "/api/appliance/health"
+
+// Health components
+const (
+	ApplMgmt         = "applmgmt"
+	DatabaseStorage  = "database-storage"
+	Load             = "load"
+	Mem              = "mem"
+	SoftwarePackages = "software-packages"
+	Storage          = "storage"
+	Swap             = "swap"
+	System           = "system"
+)
+
+// Components is the list of appliance health components
+var Components = []string{
+	ApplMgmt,
+	DatabaseStorage,
+	Load,
+	Mem,
+	SoftwarePackages,
+	Storage,
+	Swap,
+	System,
+}
+
+// Health levels
+const (
+	Green  = "green"
+	Yellow = "yellow"
+	Orange = "orange"
+	Red    = "red"
+	Gray   = "gray"
+)
+
+// Manager provides convenience methods to get the appliance health status.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Get returns the health level of the given component, such as Green or Red.
+func (m *Manager) Get(ctx context.Context, component string) (string, error) {
+	r := m.Resource(Path).WithSubpath(component)
+
+	var level string
+	err := m.Do(ctx, r.Request(http.MethodGet), &level)
+
+	return level, err
+}
+
+// Status is the health level of each component
+type Status map[string]string
+
+// LastCheck returns the time of the last system health check.
+func (m *Manager) LastCheck(ctx context.Context) (time.Time, error) {
+	r := m.Resource(Path).WithSubpath(System).WithSubpath("lastcheck")
+
+	var t time.Time
+	err := m.Do(ctx, r.Request(http.MethodGet), &t)
+
+	return t, err
+}
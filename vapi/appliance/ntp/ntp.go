@@ -0,0 +1,75 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package ntp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	Path   = "/api/appliance/ntp"
+	Action = "action"
+	Test   = "test"
+)
+
+// Server statuses reported by Test
+const (
+	ServerReachable   = "SERVER_REACHABLE"
+	ServerUnreachable = "SERVER_UNREACHABLE"
+)
+
+// Manager provides convenience methods to get/set the NTP servers of the appliance.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Get returns the NTP servers.
+func (m *Manager) Get(ctx context.Context) ([]string, error) {
+	r := m.Resource(Path)
+
+	var servers []string
+	err := m.Do(ctx, r.Request(http.MethodGet), &servers)
+
+	return servers, err
+}
+
+// Servers represents the value to be set for NTP
+type Servers struct {
+	Servers []string `json:"servers"`
+}
+
+// Set sets the NTP servers.
+func (m *Manager) Set(ctx context.Context, inp Servers) error {
+	r := m.Resource(Path)
+
+	return m.Do(ctx, r.Request(http.MethodPut, inp), nil)
+}
+
+// TestResult is the status of an NTP server
+type TestResult struct {
+	Server  string                  `json:"server"`
+	Status  string                  `json:"status"`
+	Message rest.LocalizableMessage `json:"message"`
+}
+
+// Test tests the connection to each of the given NTP servers.
+func (m *Manager) Test(ctx context.Context, inp Servers) ([]TestResult, error) {
+	r := m.Resource(Path).WithParam(Action, Test)
+
+	var res []TestResult
+	err := m.Do(ctx, r.Request(http.MethodPost, inp), &res)
+
+	return res, err
+}
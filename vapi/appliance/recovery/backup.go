@@ -0,0 +1,223 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package recovery
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	Path          = "/api/appliance/recovery"
+	BackupPath    = Path + "/backup"
+	JobPath       = BackupPath + "/job"
+	JobDetailPath = JobPath + "/details"
+	PartsPath     = BackupPath + "/parts"
+	SchedulesPath = BackupPath + "/schedules"
+	RestorePath   = Path + "/restore"
+
+	Action   = "action"
+	Cancel   = "cancel"
+	Run      = "run"
+	Validate = "validate"
+)
+
+// Backup location types
+const (
+	LocationFTP   = "FTP"
+	LocationFTPS  = "FTPS"
+	LocationHTTP  = "HTTP"
+	LocationHTTPS = "HTTPS"
+	LocationSCP   = "SCP"
+	LocationSFTP  = "SFTP"
+	LocationNFS   = "NFS"
+	LocationSMB   = "SMB"
+)
+
+// LocationTypes is the list of supported backup location types
+var LocationTypes = []string{
+	LocationFTP,
+	LocationFTPS,
+	LocationHTTP,
+	LocationHTTPS,
+	LocationSCP,
+	LocationSFTP,
+	LocationNFS,
+	LocationSMB,
+}
+
+// Backup job states
+const (
+	StateFailed     = "FAILED"
+	StateInProgress = "INPROGRESS"
+	StateNone       = "NONE"
+	StateSucceeded  = "SUCCEEDED"
+)
+
+// Backup job types
+const (
+	TypeScheduled = "SCHEDULED"
+	TypeManual    = "MANUAL"
+)
+
+// Validation result statuses
+const (
+	StatusOK      = "OK"
+	StatusWarning = "WARNING"
+	StatusError   = "ERROR"
+)
+
+// Manager provides convenience methods to create, schedule and monitor file-based backups of the appliance.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Location describes a backup location
+type Location struct {
+	LocationType     string `json:"location_type"`
+	Location         string `json:"location"`
+	LocationUser     string `json:"location_user,omitempty"`
+	LocationPassword string `json:"location_password,omitempty"`
+}
+
+// BackupRequest describes a one-shot backup
+type BackupRequest struct {
+	Location
+	Parts          []string `json:"parts,omitempty"`
+	BackupPassword string   `json:"backup_password,omitempty"`
+	Comment        string   `json:"comment,omitempty"`
+	FastBackup     bool     `json:"fast_backup,omitempty"`
+}
+
+// JobInfo describes the status of a backup job
+type JobInfo struct {
+	ID           string                    `json:"id"`
+	LocationType string                    `json:"location_type"`
+	Location     string                    `json:"location"`
+	LocationUser string                    `json:"location_user,omitempty"`
+	Type         string                    `json:"type"`
+	State        string                    `json:"state"`
+	Parts        []string                  `json:"parts"`
+	Comment      string                    `json:"comment,omitempty"`
+	Progress     int64                     `json:"progress"`
+	Size         int64                     `json:"size"`
+	Duration     int64                     `json:"duration"`
+	StartTime    time.Time                 `json:"start_time"`
+	EndTime      *time.Time                `json:"end_time,omitempty"`
+	Messages     []rest.LocalizableMessage `json:"messages"`
+}
+
+// Part describes a backup part, a subset of the appliance data
+type Part struct {
+	ID                string                  `json:"id"`
+	Name              rest.LocalizableMessage `json:"name"`
+	Description       rest.LocalizableMessage `json:"description"`
+	SelectedByDefault bool                    `json:"selected_by_default"`
+	Optional          bool                    `json:"optional"`
+}
+
+// ReturnResult is the result of a backup or restore validation
+type ReturnResult struct {
+	Status   string                    `json:"status"`
+	Messages []rest.LocalizableMessage `json:"messages"`
+}
+
+// Validate checks the given backup request can be fulfilled.
+func (m *Manager) Validate(ctx context.Context, req BackupRequest) (*ReturnResult, error) {
+	r := m.Resource(BackupPath).WithParam(Action, Validate)
+
+	spec := struct {
+		Piece BackupRequest `json:"piece"`
+	}{req}
+
+	var res ReturnResult
+	err := m.Do(ctx, r.Request(http.MethodPost, spec), &res)
+
+	return &res, err
+}
+
+// CreateJob starts a one-shot backup job, returning the job ID.
+func (m *Manager) CreateJob(ctx context.Context, req BackupRequest) (string, error) {
+	r := m.Resource(JobPath)
+
+	spec := struct {
+		Piece BackupRequest `json:"piece"`
+	}{req}
+
+	var res JobInfo
+	err := m.Do(ctx, r.Request(http.MethodPost, spec), &res)
+
+	return res.ID, err
+}
+
+// ListJobs returns the IDs of the backup jobs.
+func (m *Manager) ListJobs(ctx context.Context) ([]string, error) {
+	r := m.Resource(JobPath)
+
+	var res []string
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
+
+// GetJob returns the status of the backup job with the given ID.
+func (m *Manager) GetJob(ctx context.Context, id string) (*JobInfo, error) {
+	r := m.Resource(JobPath).WithSubpath(id)
+
+	var res JobInfo
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return &res, err
+}
+
+// CancelJob cancels the backup job with the given ID.
+func (m *Manager) CancelJob(ctx context.Context, id string) error {
+	r := m.Resource(JobPath).WithSubpath(id).WithParam(Action, Cancel)
+
+	return m.Do(ctx, r.Request(http.MethodPost), nil)
+}
+
+// ListJobDetails returns the status of the given backup jobs, or of all jobs if no IDs are given, keyed by job ID.
+func (m *Manager) ListJobDetails(ctx context.Context, ids ...string) (map[string]JobInfo, error) {
+	r := m.Resource(JobDetailPath)
+	for _, id := range ids {
+		r = r.WithParam("keys", id)
+	}
+
+	var res map[string]JobInfo
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
+
+// ListParts returns the backup parts.
+func (m *Manager) ListParts(ctx context.Context) ([]Part, error) {
+	r := m.Resource(PartsPath)
+
+	var res []Part
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
+
+// GetPartSize returns the estimated size, in MB, of the backup part with the given ID.
+func (m *Manager) GetPartSize(ctx context.Context, id string) (int64, error) {
+	r := m.Resource(PartsPath).WithSubpath(id)
+
+	var res int64
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
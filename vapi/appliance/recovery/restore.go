@@ -0,0 +1,43 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package recovery
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// RestoreRequest describes the backup to restore from
+type RestoreRequest struct {
+	Location
+	BackupPassword       string `json:"backup_password,omitempty"`
+	SSOAdminUserName     string `json:"sso_admin_user_name,omitempty"`
+	SSOAdminUserPassword string `json:"sso_admin_user_password,omitempty"`
+	IgnoreWarnings       bool   `json:"ignore_warnings,omitempty"`
+}
+
+// Metadata describes a backup, as read from the backup location
+type Metadata struct {
+	TimeStamp  time.Time                 `json:"timestamp"`
+	Parts      []string                  `json:"parts"`
+	Version    string                    `json:"version"`
+	BoxName    string                    `json:"boxname"`
+	Comment    string                    `json:"comment"`
+	Applicable bool                      `json:"applicable"`
+	Messages   []rest.LocalizableMessage `json:"messages"`
+}
+
+// ValidateRestore reads the backup metadata at the given location and checks the backup can be restored.
+func (m *Manager) ValidateRestore(ctx context.Context, req RestoreRequest) (*Metadata, error) {
+	r := m.Resource(RestorePath).WithParam(Action, Validate)
+
+	var res Metadata
+	err := m.Do(ctx, r.Request(http.MethodPost, req), &res)
+
+	return &res, err
+}
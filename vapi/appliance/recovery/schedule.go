@@ -0,0 +1,114 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package recovery
+
+import (
+	"context"
+	"net/http"
+)
+
+// Days of the week for RecurrenceInfo
+const (
+	Monday    = "MONDAY"
+	Tuesday   = "TUESDAY"
+	Wednesday = "WEDNESDAY"
+	Thursday  = "THURSDAY"
+	Friday    = "FRIDAY"
+	Saturday  = "SATURDAY"
+	Sunday    = "SUNDAY"
+)
+
+// RecurrenceInfo describes when a scheduled backup runs.
+// If Days is empty, the backup runs daily.
+type RecurrenceInfo struct {
+	Minute int      `json:"minute"`
+	Hour   int      `json:"hour"`
+	Days   []string `json:"days,omitempty"`
+}
+
+// RetentionInfo describes how many scheduled backups are kept at the location
+type RetentionInfo struct {
+	MaxCount int `json:"max_count"`
+}
+
+// Schedule describes a scheduled backup
+type Schedule struct {
+	Parts            []string        `json:"parts,omitempty"`
+	BackupPassword   string          `json:"backup_password,omitempty"`
+	Location         string          `json:"location"`
+	LocationUser     string          `json:"location_user,omitempty"`
+	LocationPassword string          `json:"location_password,omitempty"`
+	Enable           *bool           `json:"enable,omitempty"`
+	RecurrenceInfo   *RecurrenceInfo `json:"recurrence_info,omitempty"`
+	RetentionInfo    *RetentionInfo  `json:"retention_info,omitempty"`
+	FastBackup       bool            `json:"fast_backup,omitempty"`
+}
+
+// ScheduleUpdateSpec describes the changes to a scheduled backup
+type ScheduleUpdateSpec struct {
+	Parts            []string        `json:"parts,omitempty"`
+	BackupPassword   string          `json:"backup_password,omitempty"`
+	Location         string          `json:"location,omitempty"`
+	LocationUser     string          `json:"location_user,omitempty"`
+	LocationPassword string          `json:"location_password,omitempty"`
+	Enable           *bool           `json:"enable,omitempty"`
+	RecurrenceInfo   *RecurrenceInfo `json:"recurrence_info,omitempty"`
+	RetentionInfo    *RetentionInfo  `json:"retention_info,omitempty"`
+}
+
+// ListSchedules returns the backup schedules, keyed by schedule ID.
+func (m *Manager) ListSchedules(ctx context.Context) (map[string]Schedule, error) {
+	r := m.Resource(SchedulesPath)
+
+	var res map[string]Schedule
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
+
+// GetSchedule returns the backup schedule with the given ID.
+func (m *Manager) GetSchedule(ctx context.Context, id string) (*Schedule, error) {
+	r := m.Resource(SchedulesPath).WithSubpath(id)
+
+	var res Schedule
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return &res, err
+}
+
+// CreateSchedule creates a backup schedule with the given ID.
+func (m *Manager) CreateSchedule(ctx context.Context, id string, spec Schedule) error {
+	r := m.Resource(SchedulesPath).WithSubpath(id)
+
+	return m.Do(ctx, r.Request(http.MethodPost, spec), nil)
+}
+
+// UpdateSchedule updates the backup schedule with the given ID.
+func (m *Manager) UpdateSchedule(ctx context.Context, id string, spec ScheduleUpdateSpec) error {
+	r := m.Resource(SchedulesPath).WithSubpath(id)
+
+	return m.Do(ctx, r.Request(http.MethodPatch, spec), nil)
+}
+
+// DeleteSchedule deletes the backup schedule with the given ID.
+func (m *Manager) DeleteSchedule(ctx context.Context, id string) error {
+	r := m.Resource(SchedulesPath).WithSubpath(id)
+
+	return m.Do(ctx, r.Request(http.MethodDelete), nil)
+}
+
+// RunSchedule starts a backup job using the schedule with the given ID.
+func (m *Manager) RunSchedule(ctx context.Context, id, comment string) (*JobInfo, error) {
+	r := m.Resource(SchedulesPath).WithSubpath(id).WithParam(Action, Run)
+
+	spec := struct {
+		Comment string `json:"comment,omitempty"`
+	}{comment}
+
+	var res JobInfo
+	err := m.Do(ctx, r.Request(http.MethodPost, spec), &res)
+
+	return &res, err
+}
@@ -0,0 +1,162 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	// Path is the appliance services endpoint
+	Path = "/api/appliance/services"
+	// VMonPath is the vCenter services endpoint, managed by the vMon service lifecycle manager
+	VMonPath = "/api/vcenter/services"
+
+	Action  = "action"
+	Start   = "start"
+	Stop    = "stop"
+	Restart = "restart"
+)
+
+// Service states
+const (
+	StateStarting = "STARTING"
+	StateStopping = "STOPPING"
+	StateStarted  = "STARTED"
+	StateStopped  = "STOPPED"
+)
+
+// vMon service startup types
+const (
+	StartupTypeManual    = "MANUAL"
+	StartupTypeAutomatic = "AUTOMATIC"
+	StartupTypeDisabled  = "DISABLED"
+)
+
+// vMon service health
+const (
+	HealthDegraded            = "DEGRADED"
+	HealthHealthy             = "HEALTHY"
+	HealthHealthyWithWarnings = "HEALTHY_WITH_WARNINGS"
+)
+
+// Manager provides convenience methods to list and control appliance and vMon services.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Info describes an appliance service
+type Info struct {
+	Description string `json:"description"`
+	State       string `json:"state"`
+}
+
+// List returns the appliance services, keyed by service name.
+func (m *Manager) List(ctx context.Context) (map[string]Info, error) {
+	r := m.Resource(Path)
+
+	var res map[string]Info
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
+
+// Get returns the appliance service with the given name.
+func (m *Manager) Get(ctx context.Context, name string) (*Info, error) {
+	r := m.Resource(Path).WithSubpath(name)
+
+	var res Info
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return &res, err
+}
+
+func (m *Manager) control(ctx context.Context, path, name, action string) error {
+	r := m.Resource(path).WithSubpath(name).WithParam(Action, action)
+
+	return m.Do(ctx, r.Request(http.MethodPost), nil)
+}
+
+// Start starts the appliance service with the given name.
+func (m *Manager) Start(ctx context.Context, name string) error {
+	return m.control(ctx, Path, name, Start)
+}
+
+// Stop stops the appliance service with the given name.
+func (m *Manager) Stop(ctx context.Context, name string) error {
+	return m.control(ctx, Path, name, Stop)
+}
+
+// Restart restarts the appliance service with the given name.
+func (m *Manager) Restart(ctx context.Context, name string) error {
+	return m.control(ctx, Path, name, Restart)
+}
+
+// VMonInfo describes a vMon managed service
+type VMonInfo struct {
+	NameKey        string                    `json:"name_key"`
+	DescriptionKey string                    `json:"description_key"`
+	StartupType    string                    `json:"startup_type"`
+	State          string                    `json:"state"`
+	Health         string                    `json:"health,omitempty"`
+	HealthMessages []rest.LocalizableMessage `json:"health_messages,omitempty"`
+}
+
+// VMonUpdateSpec describes the changes to a vMon managed service
+type VMonUpdateSpec struct {
+	StartupType string `json:"startup_type,omitempty"`
+}
+
+// ListVMon returns the vMon managed services, keyed by service name.
+func (m *Manager) ListVMon(ctx context.Context) (map[string]VMonInfo, error) {
+	r := m.Resource(VMonPath)
+
+	var res map[string]VMonInfo
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
+
+// GetVMon returns the vMon managed service with the given name.
+func (m *Manager) GetVMon(ctx context.Context, name string) (*VMonInfo, error) {
+	r := m.Resource(VMonPath).WithSubpath(name)
+
+	var res VMonInfo
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return &res, err
+}
+
+// UpdateVMon updates the vMon managed service with the given name.
+func (m *Manager) UpdateVMon(ctx context.Context, name string, spec VMonUpdateSpec) error {
+	r := m.Resource(VMonPath).WithSubpath(name)
+
+	return m.Do(ctx, r.Request(http.MethodPatch, spec), nil)
+}
+
+// StartVMon starts the vMon managed service with the given name.
+func (m *Manager) StartVMon(ctx context.Context, name string) error {
+	return m.control(ctx, VMonPath, name, Start)
+}
+
+// StopVMon stops the vMon managed service with the given name.
+func (m *Manager) StopVMon(ctx context.Context, name string) error {
+	return m.control(ctx, VMonPath, name, Stop)
+}
+
+// RestartVMon restarts the vMon managed service with the given name.
+func (m *Manager) RestartVMon(ctx context.Context, name string) error {
+	return m.control(ctx, VMonPath, name, Restart)
+}
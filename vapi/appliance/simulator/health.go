@@ -0,0 +1,59 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// levels in order of severity
+var levels = []string{health.Gray, health.Green, health.Yellow, health.Orange, health.Red}
+
+func defaultHealth() health.Status {
+	status := make(health.Status)
+	for _, component := range health.Components {
+		status[component] = health.Green
+	}
+	return status
+}
+
+// path "/api/appliance/health/{component}"
+func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	component := strings.TrimPrefix(r.URL.Path, health.Path+"/")
+
+	switch component {
+	case health.System + "/lastcheck":
+		vapi.StatusOK(w, h.lastcheck)
+	case health.System:
+		// the overall system health is the most severe level of the other components
+		level := health.Green
+		for name, l := range h.health {
+			if name != health.System && slices.Index(levels, l) > slices.Index(levels, level) {
+				level = l
+			}
+		}
+		vapi.StatusOK(w, level)
+	default:
+		level, ok := h.health[component]
+		if !ok {
+			vapi.ApiErrorNotFound(w)
+			return
+		}
+		vapi.StatusOK(w, level)
+	}
+}
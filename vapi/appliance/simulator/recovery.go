@@ -0,0 +1,655 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// metadataFile is written to the backup location along with a file per backup part
+const metadataFile = "backup-metadata.json"
+
+// recoveryState tracks backup jobs and schedules.
+// Backup jobs run synchronously, writing to the path component of the location on the local file system.
+type recoveryState struct {
+	version   string
+	build     string
+	jobs      map[string]*recovery.JobInfo
+	schedules map[string]*recovery.Schedule
+}
+
+// backupMetadata is the content of metadataFile
+type backupMetadata struct {
+	TimeStamp time.Time `json:"timestamp"`
+	Parts     []string  `json:"parts"`
+	Version   string    `json:"version"`
+	Build     string    `json:"build"`
+	BoxName   string    `json:"boxname"`
+	Comment   string    `json:"comment"`
+	Password  string    `json:"password,omitempty"`
+}
+
+var backupParts = []recovery.Part{
+	{
+		ID: "common",
+		Name: rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.backup.parts.common",
+			DefaultMessage: "Inventory and configuration",
+		},
+		Description: rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.backup.parts.common.description",
+			DefaultMessage: "vCenter Server inventory and configuration data",
+		},
+		SelectedByDefault: true,
+		Optional:          false,
+	},
+	{
+		ID: "seat",
+		Name: rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.backup.parts.seat",
+			DefaultMessage: "Stats, Events, and Tasks",
+		},
+		Description: rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.backup.parts.seat.description",
+			DefaultMessage: "Historical performance statistics, events and tasks",
+		},
+		SelectedByDefault: false,
+		Optional:          true,
+	},
+}
+
+// backupPartSize is the estimated size in MB of each backup part
+var backupPartSize = map[string]int64{
+	"common": 256,
+	"seat":   1024,
+}
+
+func newRecoveryState(version, build string) recoveryState {
+	return recoveryState{
+		version:   version,
+		build:     build,
+		jobs:      make(map[string]*recovery.JobInfo),
+		schedules: make(map[string]*recovery.Schedule),
+	}
+}
+
+// boxName returns the host name the request was sent to
+func boxName(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		return r.Host
+	}
+	return host
+}
+
+// localPath maps a backup location URL to a local directory
+func localPath(location string) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	path := u.Path
+	if u.Scheme == "" {
+		path = location
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("location %q is not an absolute path", location)
+	}
+	return filepath.Clean(path), nil
+}
+
+// locationType returns the location type of a scheduled backup location URL
+func locationType(location string) string {
+	u, err := url.Parse(location)
+	if err == nil {
+		kind := strings.ToUpper(u.Scheme)
+		if slices.Contains(recovery.LocationTypes, kind) {
+			return kind
+		}
+	}
+	return ""
+}
+
+func hashPassword(password string) string {
+	if password == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// parts validates the given part IDs, adding those that are not optional
+func (s *recoveryState) parts(ids []string) ([]string, bool) {
+	var parts []string
+	for _, p := range backupParts {
+		if !p.Optional || slices.Contains(ids, p.ID) {
+			parts = append(parts, p.ID)
+		}
+	}
+	for _, id := range ids {
+		if !slices.Contains(parts, id) {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+func (s *recoveryState) newJobID(now time.Time) string {
+	base := now.UTC().Format("20060102-150405") + "-" + s.build
+	id := base
+	for n := 1; s.jobs[id] != nil; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
+
+func validateRecurrence(info *recovery.RecurrenceInfo) bool {
+	if info == nil {
+		return true
+	}
+	if info.Hour < 0 || info.Hour > 23 || info.Minute < 0 || info.Minute > 59 {
+		return false
+	}
+	days := []string{
+		recovery.Monday, recovery.Tuesday, recovery.Wednesday, recovery.Thursday,
+		recovery.Friday, recovery.Saturday, recovery.Sunday,
+	}
+	for _, day := range info.Days {
+		if !slices.Contains(days, day) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateRetention(info *recovery.RetentionInfo) bool {
+	return info == nil || info.MaxCount > 0
+}
+
+// write performs a backup to the local directory dir, returning the size of the files written
+func (s *recoveryState) write(dir, boxname string, req recovery.BackupRequest, now time.Time) (int64, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return 0, err
+	}
+
+	md := backupMetadata{
+		TimeStamp: now,
+		Parts:     req.Parts,
+		Version:   s.version,
+		Build:     s.build,
+		BoxName:   boxname,
+		Comment:   req.Comment,
+		Password:  hashPassword(req.BackupPassword),
+	}
+
+	var size int64
+	for _, part := range req.Parts {
+		data := fmt.Sprintf("vcsim %s backup part %q of %s\n", s.version, part, boxname)
+		if err := os.WriteFile(filepath.Join(dir, part+".data"), []byte(data), 0640); err != nil {
+			return 0, err
+		}
+		size += int64(len(data))
+	}
+
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	size += int64(len(data))
+
+	return size, os.WriteFile(filepath.Join(dir, metadataFile), data, 0640)
+}
+
+// run performs a backup job, recording its status.
+// The dir func maps the location path to the backup directory for the job ID.
+func (s *recoveryState) run(kind, boxname string, req recovery.BackupRequest, dir func(string, string) string) *recovery.JobInfo {
+	now := time.Now()
+	id := s.newJobID(now)
+
+	job := &recovery.JobInfo{
+		ID:           id,
+		LocationType: req.LocationType,
+		Location:     req.Location.Location,
+		LocationUser: req.LocationUser,
+		Type:         kind,
+		State:        recovery.StateSucceeded,
+		Parts:        req.Parts,
+		Comment:      req.Comment,
+		Progress:     100,
+		StartTime:    now,
+		Messages:     []rest.LocalizableMessage{},
+	}
+	s.jobs[id] = job
+
+	base, err := localPath(req.Location.Location)
+	if err == nil {
+		path := dir(base, id)
+		if rel, _ := filepath.Rel(base, path); rel != "." {
+			job.Location = strings.TrimSuffix(req.Location.Location, "/") + "/" + filepath.ToSlash(rel)
+		}
+		job.Size, err = s.write(path, boxname, req, now)
+	}
+	if err != nil {
+		job.State = recovery.StateFailed
+		job.Messages = append(job.Messages, rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.backup.job.failed",
+			DefaultMessage: "Backup job failed: " + err.Error(),
+			Args:           []string{err.Error()},
+		})
+	}
+
+	end := time.Now()
+	job.EndTime = &end
+	job.Duration = int64(end.Sub(now).Seconds())
+
+	return job
+}
+
+// retain removes the oldest scheduled backups in dir exceeding the retention count
+func retain(dir string, info *recovery.RetentionInfo) {
+	if info == nil {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var backups []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "S_") {
+			backups = append(backups, e.Name())
+		}
+	}
+	slices.Sort(backups) // S_<version>_<timestamp>-<build>
+	for len(backups) > info.MaxCount {
+		_ = os.RemoveAll(filepath.Join(dir, backups[0]))
+		backups = backups[1:]
+	}
+}
+
+// path "/api/appliance/recovery/backup"
+func (h *Handler) handleBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Query().Get(recovery.Action) != recovery.Validate {
+		http.NotFound(w, r)
+		return
+	}
+
+	var spec struct {
+		Piece recovery.BackupRequest `json:"piece"`
+	}
+	if !vapi.Decode(r, w, &spec) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := recovery.ReturnResult{Status: recovery.StatusOK, Messages: []rest.LocalizableMessage{}}
+	fail := func(id, msg string) {
+		res.Status = recovery.StatusError
+		res.Messages = append(res.Messages, rest.LocalizableMessage{ID: id, DefaultMessage: msg})
+	}
+
+	req := spec.Piece
+	if !slices.Contains(recovery.LocationTypes, req.LocationType) {
+		fail("com.vmware.applmgmt.backup.invalid_location_type", "Invalid location type: "+req.LocationType)
+	}
+	if _, ok := h.recovery.parts(req.Parts); !ok {
+		fail("com.vmware.applmgmt.backup.invalid_parts", "Invalid backup parts: "+strings.Join(req.Parts, ","))
+	}
+	path, err := localPath(req.Location.Location)
+	if err == nil {
+		if _, err = os.Stat(filepath.Join(path, metadataFile)); err == nil {
+			res.Status = recovery.StatusWarning
+			res.Messages = append(res.Messages, rest.LocalizableMessage{
+				ID:             "com.vmware.applmgmt.backup.location_not_empty",
+				DefaultMessage: "Location already contains a backup, it will be overwritten",
+			})
+		}
+		err = nil
+	}
+	if err != nil {
+		fail("com.vmware.applmgmt.backup.invalid_location", err.Error())
+	}
+
+	vapi.StatusOK(w, res)
+}
+
+// path "/api/appliance/recovery/backup/job"
+func (h *Handler) handleBackupJobs(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		ids := []string{}
+		for id := range h.recovery.jobs {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		vapi.StatusOK(w, ids)
+	case http.MethodPost:
+		var spec struct {
+			Piece recovery.BackupRequest `json:"piece"`
+		}
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		req := spec.Piece
+		parts, ok := h.recovery.parts(req.Parts)
+		if !ok || !slices.Contains(recovery.LocationTypes, req.LocationType) || req.Location.Location == "" {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		req.Parts = parts
+		job := h.recovery.run(recovery.TypeManual, boxName(r), req, func(dir, _ string) string { return dir })
+		vapi.StatusOK(w, job)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/appliance/recovery/backup/job/details"
+func (h *Handler) handleBackupJobDetails(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := r.URL.Query()["keys"]
+	res := make(map[string]*recovery.JobInfo)
+	for id, job := range h.recovery.jobs {
+		if len(keys) == 0 || slices.Contains(keys, id) {
+			res[id] = job
+		}
+	}
+	vapi.StatusOK(w, res)
+}
+
+// path "/api/appliance/recovery/backup/job/{id}"
+func (h *Handler) handleBackupJob(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	job, ok := h.recovery.jobs[serviceName(r, recovery.JobPath)]
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, job)
+	case http.MethodPost:
+		if r.URL.Query().Get(recovery.Action) != recovery.Cancel {
+			http.NotFound(w, r)
+			return
+		}
+		// jobs run to completion when created
+		vapi.ApiErrorNotAllowedInCurrentState(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/appliance/recovery/backup/parts"
+func (h *Handler) handleBackupParts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+	vapi.StatusOK(w, backupParts)
+}
+
+// path "/api/appliance/recovery/backup/parts/{id}"
+func (h *Handler) handleBackupPart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+	size, ok := backupPartSize[serviceName(r, recovery.PartsPath)]
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+	vapi.StatusOK(w, size)
+}
+
+// path "/api/appliance/recovery/backup/schedules"
+func (h *Handler) handleBackupSchedules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := make(map[string]recovery.Schedule)
+	for id, s := range h.recovery.schedules {
+		res[id] = scheduleInfo(s)
+	}
+	vapi.StatusOK(w, res)
+}
+
+// scheduleInfo returns a copy of the given schedule without secrets
+func scheduleInfo(s *recovery.Schedule) recovery.Schedule {
+	info := *s
+	info.BackupPassword = ""
+	info.LocationPassword = ""
+	return info
+}
+
+// path "/api/appliance/recovery/backup/schedules/{id}"
+func (h *Handler) handleBackupSchedule(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := serviceName(r, recovery.SchedulesPath)
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+	s, ok := h.recovery.schedules[id]
+
+	if r.Method == http.MethodPost && r.URL.Query().Get(recovery.Action) == "" {
+		if ok {
+			vapi.ApiErrorAlreadyExists(w)
+			return
+		}
+		var spec recovery.Schedule
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		parts, valid := h.recovery.parts(spec.Parts)
+		if _, err := localPath(spec.Location); err != nil || !valid ||
+			!validateRecurrence(spec.RecurrenceInfo) || !validateRetention(spec.RetentionInfo) {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		spec.Parts = parts
+		if spec.Enable == nil {
+			enable := true
+			spec.Enable = &enable
+		}
+		h.recovery.schedules[id] = &spec
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, scheduleInfo(s))
+	case http.MethodPatch:
+		var spec recovery.ScheduleUpdateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		update := *s
+		if spec.Parts != nil {
+			parts, valid := h.recovery.parts(spec.Parts)
+			if !valid {
+				vapi.ApiErrorInvalidArgument(w)
+				return
+			}
+			update.Parts = parts
+		}
+		if spec.Location != "" {
+			if _, err := localPath(spec.Location); err != nil {
+				vapi.ApiErrorInvalidArgument(w)
+				return
+			}
+			update.Location = spec.Location
+		}
+		if !validateRecurrence(spec.RecurrenceInfo) || !validateRetention(spec.RetentionInfo) {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		if spec.BackupPassword != "" {
+			update.BackupPassword = spec.BackupPassword
+		}
+		if spec.LocationUser != "" {
+			update.LocationUser = spec.LocationUser
+		}
+		if spec.LocationPassword != "" {
+			update.LocationPassword = spec.LocationPassword
+		}
+		if spec.Enable != nil {
+			update.Enable = spec.Enable
+		}
+		if spec.RecurrenceInfo != nil {
+			update.RecurrenceInfo = spec.RecurrenceInfo
+		}
+		if spec.RetentionInfo != nil {
+			update.RetentionInfo = spec.RetentionInfo
+		}
+		*s = update
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(h.recovery.schedules, id)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPost:
+		if r.URL.Query().Get(recovery.Action) != recovery.Run {
+			http.NotFound(w, r)
+			return
+		}
+		var spec struct {
+			Comment string `json:"comment"`
+		}
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		box := boxName(r)
+		req := recovery.BackupRequest{
+			Location: recovery.Location{
+				LocationType:     locationType(s.Location),
+				Location:         s.Location,
+				LocationUser:     s.LocationUser,
+				LocationPassword: s.LocationPassword,
+			},
+			Parts:          s.Parts,
+			BackupPassword: s.BackupPassword,
+			Comment:        spec.Comment,
+			FastBackup:     s.FastBackup,
+		}
+		var base string
+		job := h.recovery.run(recovery.TypeScheduled, box, req, func(dir, id string) string {
+			// <location>/vCenter/sn_<boxname>/S_<version>_<job id>
+			base = filepath.Join(dir, "vCenter", "sn_"+box)
+			return filepath.Join(base, "S_"+h.recovery.version+"_"+id)
+		})
+		if job.State == recovery.StateSucceeded {
+			retain(base, s.RetentionInfo)
+		}
+		vapi.StatusOK(w, job)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/appliance/recovery/restore"
+func (h *Handler) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Query().Get(recovery.Action) != recovery.Validate {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req recovery.RestoreRequest
+	if !vapi.Decode(r, w, &req) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	path, err := localPath(req.Location.Location)
+	if err != nil || !slices.Contains(recovery.LocationTypes, req.LocationType) {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, metadataFile))
+	if err != nil {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	var md backupMetadata
+	if err = json.Unmarshal(data, &md); err != nil {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	res := recovery.Metadata{
+		TimeStamp:  md.TimeStamp,
+		Parts:      md.Parts,
+		Version:    md.Version,
+		BoxName:    md.BoxName,
+		Comment:    md.Comment,
+		Applicable: true,
+		Messages:   []rest.LocalizableMessage{},
+	}
+
+	if md.Password != hashPassword(req.BackupPassword) {
+		res.Applicable = false
+		res.Messages = append(res.Messages, rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.restore.invalid_password",
+			DefaultMessage: "The backup password is incorrect",
+		})
+	}
+	if md.Version != h.recovery.version {
+		res.Applicable = false
+		res.Messages = append(res.Messages, rest.LocalizableMessage{
+			ID:             "com.vmware.applmgmt.restore.version_mismatch",
+			DefaultMessage: fmt.Sprintf("Backup version %s does not match appliance version %s", md.Version, h.recovery.version),
+			Args:           []string{md.Version, h.recovery.version},
+		})
+	}
+
+	vapi.StatusOK(w, res)
+}
@@ -0,0 +1,171 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/vmware/govmomi/vapi/appliance/services"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func defaultServices() map[string]*services.Info {
+	return map[string]*services.Info{
+		"appliance-shell": {Description: "Appliance Shell", State: services.StateStarted},
+		"sshd":            {Description: "SSH Daemon", State: services.StateStarted},
+		"ntpd":            {Description: "NTP Daemon", State: services.StateStopped},
+		"vmware-vmon":     {Description: "VMware Service Lifecycle Manager", State: services.StateStarted},
+		"applmgmt":        {Description: "Appliance Management Service", State: services.StateStarted},
+	}
+}
+
+func defaultVMonServices() map[string]*services.VMonInfo {
+	info := func(name, startup string) *services.VMonInfo {
+		s := &services.VMonInfo{
+			NameKey:        "cis." + name + ".ServiceName",
+			DescriptionKey: "cis." + name + ".ServiceDescription",
+			StartupType:    startup,
+		}
+		vmonState(s, services.StateStopped)
+		if startup == services.StartupTypeAutomatic {
+			vmonState(s, services.StateStarted)
+		}
+		return s
+	}
+
+	return map[string]*services.VMonInfo{
+		"vpxd":            info("vpxd", services.StartupTypeAutomatic),
+		"vapi-endpoint":   info("vapi-endpoint", services.StartupTypeAutomatic),
+		"content-library": info("content-library", services.StartupTypeAutomatic),
+		"eam":             info("eam", services.StartupTypeAutomatic),
+		"sps":             info("sps", services.StartupTypeAutomatic),
+		"vsphere-ui":      info("vsphere-ui", services.StartupTypeAutomatic),
+		"imagebuilder":    info("imagebuilder", services.StartupTypeManual),
+		"rbd":             info("rbd", services.StartupTypeManual),
+	}
+}
+
+// vmonState sets the state of the given service, health is only reported for started services
+func vmonState(s *services.VMonInfo, state string) {
+	s.State = state
+	s.Health = ""
+	if state == services.StateStarted {
+		s.Health = services.HealthHealthy
+	}
+}
+
+// serviceName returns the {name} path param, or an empty string if the path is invalid
+func serviceName(r *http.Request, prefix string) string {
+	name := strings.TrimPrefix(r.URL.Path, prefix+"/")
+	if strings.Contains(name, "/") {
+		return ""
+	}
+	return name
+}
+
+// path "/api/appliance/services"
+func (h *Handler) handleServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	vapi.StatusOK(w, h.services)
+}
+
+// path "/api/appliance/services/{name}"
+func (h *Handler) handleService(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.services[serviceName(r, services.Path)]
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, s)
+	case http.MethodPost:
+		switch r.URL.Query().Get(services.Action) {
+		case services.Start, services.Restart:
+			s.State = services.StateStarted
+		case services.Stop:
+			s.State = services.StateStopped
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/vcenter/services"
+func (h *Handler) handleVMonServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	vapi.StatusOK(w, h.vmon)
+}
+
+// path "/api/vcenter/services/{name}"
+func (h *Handler) handleVMonService(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.vmon[serviceName(r, services.VMonPath)]
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, s)
+	case http.MethodPatch:
+		var spec services.VMonUpdateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		switch spec.StartupType {
+		case "":
+		case services.StartupTypeAutomatic, services.StartupTypeManual, services.StartupTypeDisabled:
+			s.StartupType = spec.StartupType
+		default:
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPost:
+		switch r.URL.Query().Get(services.Action) {
+		case services.Start, services.Restart:
+			if s.StartupType == services.StartupTypeDisabled {
+				vapi.ApiErrorNotAllowedInCurrentState(w)
+				return
+			}
+			vmonState(s, services.StateStarted)
+		case services.Stop:
+			vmonState(s, services.StateStopped)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/vmware/govmomi/simulator"
@@ -16,8 +17,15 @@ import (
 	"github.com/vmware/govmomi/vapi/appliance/access/dcui"
 	"github.com/vmware/govmomi/vapi/appliance/access/shell"
 	"github.com/vmware/govmomi/vapi/appliance/access/ssh"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	"github.com/vmware/govmomi/vapi/appliance/ntp"
+	"github.com/vmware/govmomi/vapi/appliance/recovery"
+	"github.com/vmware/govmomi/vapi/appliance/services"
 	"github.com/vmware/govmomi/vapi/appliance/shutdown"
+	"github.com/vmware/govmomi/vapi/appliance/timesync"
+	"github.com/vmware/govmomi/vapi/appliance/update"
 	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25"
 )
 
 func init() {
@@ -34,6 +42,16 @@ type Handler struct {
 	ssh            ssh.Access
 	shell          shell.Access
 	shutdownConfig shutdown.Config
+
+	mu        sync.Mutex
+	health    health.Status
+	lastcheck time.Time
+	services  map[string]*services.Info
+	vmon      map[string]*services.VMonInfo
+	update    updateState
+	ntp       []string
+	timesync  string
+	recovery  recoveryState
 }
 
 // New creates a Handler instance
@@ -45,6 +63,12 @@ func New(u *url.URL) *Handler {
 		ssh:            ssh.Access{Enabled: false},
 		shell:          shell.Access{Enabled: false, Timeout: 0},
 		shutdownConfig: shutdown.Config{},
+		health:         defaultHealth(),
+		lastcheck:      time.Now(),
+		services:       defaultServices(),
+		vmon:           defaultVMonServices(),
+		ntp:            []string{},
+		timesync:       timesync.Host,
 	}
 }
 
@@ -55,6 +79,30 @@ func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
 	s.HandleFunc(ssh.Path, h.sshAccess)
 	s.HandleFunc(shell.Path, h.shellAccess)
 	s.HandleFunc(shutdown.Path, h.shutdown)
+	s.HandleFunc(health.Path+"/", h.handleHealth)
+	s.HandleFunc(services.Path, h.handleServices)
+	s.HandleFunc(services.Path+"/", h.handleService)
+	s.HandleFunc(services.VMonPath, h.handleVMonServices)
+	s.HandleFunc(services.VMonPath+"/", h.handleVMonService)
+	s.HandleFunc(update.Path, h.handleUpdate)
+	s.HandleFunc(update.PendingPath, h.handlePending)
+	s.HandleFunc(update.PendingPath+"/", h.handlePendingVersion)
+	s.HandleFunc(update.StagedPath, h.handleStaged)
+	s.HandleFunc(ntp.Path, h.handleNTP)
+	s.HandleFunc(timesync.Path, h.handleTimesync)
+	s.HandleFunc(recovery.BackupPath, h.handleBackup)
+	s.HandleFunc(recovery.JobPath, h.handleBackupJobs)
+	s.HandleFunc(recovery.JobDetailPath, h.handleBackupJobDetails)
+	s.HandleFunc(recovery.JobPath+"/", h.handleBackupJob)
+	s.HandleFunc(recovery.PartsPath, h.handleBackupParts)
+	s.HandleFunc(recovery.PartsPath+"/", h.handleBackupPart)
+	s.HandleFunc(recovery.SchedulesPath, h.handleBackupSchedules)
+	s.HandleFunc(recovery.SchedulesPath+"/", h.handleBackupSchedule)
+	s.HandleFunc(recovery.RestorePath, h.handleRestore)
+
+	about := r.Get(vim25.ServiceInstance).(*simulator.ServiceInstance).Content.About
+	h.update = newUpdateState(about.Version)
+	h.recovery = newRecoveryState(about.Version, about.Build)
 }
 
 func (h *Handler) decode(r *http.Request, w http.ResponseWriter, val any) bool {
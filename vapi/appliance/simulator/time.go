@@ -0,0 +1,94 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/vmware/govmomi/vapi/appliance/ntp"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+	"github.com/vmware/govmomi/vapi/appliance/timesync"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// path "/api/appliance/ntp"
+func (h *Handler) handleNTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, h.ntp)
+	case http.MethodPut:
+		var spec ntp.Servers
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		if slices.Contains(spec.Servers, "") {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		h.ntp = append([]string{}, spec.Servers...)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPost:
+		if r.URL.Query().Get(ntp.Action) != ntp.Test {
+			http.NotFound(w, r)
+			return
+		}
+		var spec ntp.Servers
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		// Servers within the reserved ".invalid" domain are reported as unreachable
+		res := []ntp.TestResult{}
+		for _, server := range spec.Servers {
+			status := ntp.TestResult{Server: server, Status: ntp.ServerReachable}
+			if server == "" || strings.HasSuffix(server, ".invalid") {
+				status.Status = ntp.ServerUnreachable
+				status.Message = rest.LocalizableMessage{
+					ID:             "com.vmware.appliance.ntp_unreachable",
+					DefaultMessage: "Server " + server + " is unreachable",
+					Args:           []string{server},
+				}
+			}
+			res = append(res, status)
+		}
+		vapi.StatusOK(w, res)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/appliance/timesync"
+func (h *Handler) handleTimesync(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, h.timesync)
+	case http.MethodPut:
+		var spec timesync.Mode
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		switch spec.Mode {
+		case timesync.Disabled, timesync.Host:
+			h.services["ntpd"].State = services.StateStopped
+		case timesync.NTP:
+			h.services["ntpd"].State = services.StateStarted
+		default:
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		h.timesync = spec.Mode
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
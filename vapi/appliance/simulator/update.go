@@ -0,0 +1,241 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/appliance/update"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// updateState tracks the installed version and available updates.
+// Stage and install tasks complete immediately.
+type updateState struct {
+	version   string
+	pending   []update.Summary
+	staged    *update.StagedInfo
+	task      *update.TaskInfo
+	lastQuery *time.Time
+}
+
+func newUpdateState(version string) updateState {
+	patch := version + ".00100"
+
+	return updateState{
+		version: version,
+		pending: []update.Summary{{
+			Version: patch,
+			Name:    "VC-" + patch + "-Appliance-FP",
+			Description: rest.LocalizableMessage{
+				ID:             "com.vmware.appliance.update.description",
+				DefaultMessage: "Patch for VMware vCenter Server " + version,
+			},
+			Priority:       "HIGH",
+			Severity:       "CRITICAL",
+			UpdateType:     "SECURITY",
+			ReleaseDate:    time.Now().Truncate(24 * time.Hour),
+			RebootRequired: true,
+			Size:           5463,
+		}},
+	}
+}
+
+func (u *updateState) find(version string) *update.Summary {
+	i := slices.IndexFunc(u.pending, func(s update.Summary) bool { return s.Version == version })
+	if i < 0 {
+		return nil
+	}
+	return &u.pending[i]
+}
+
+func (u *updateState) info() update.Info {
+	info := update.Info{
+		State:           update.StateUpToDate,
+		Task:            u.task,
+		Version:         u.version,
+		LatestQueryTime: u.lastQuery,
+	}
+	if len(u.pending) != 0 {
+		info.State = update.StateUpdatesPending
+	}
+	return info
+}
+
+// run records a completed task for the given operation
+func (u *updateState) run(operation, message string) {
+	now := time.Now()
+	msg := rest.LocalizableMessage{ID: "com.vmware.appliance.update." + operation, DefaultMessage: message}
+
+	u.task = &update.TaskInfo{
+		Description: msg,
+		Operation:   "com.vmware.appliance.update." + operation,
+		Status:      update.TaskSucceeded,
+		Progress:    &update.Progress{Total: 100, Completed: 100, Message: msg},
+		StartTime:   &now,
+		EndTime:     &now,
+	}
+}
+
+func (u *updateState) stage(s *update.Summary) bool {
+	if u.staged != nil && u.staged.Version != s.Version {
+		return false
+	}
+	u.staged = &update.StagedInfo{Summary: *s, StagingComplete: true}
+	u.run("stage", "Staging of "+s.Version+" completed")
+	return true
+}
+
+func (u *updateState) install(s *update.Summary) bool {
+	if u.staged == nil || u.staged.Version != s.Version {
+		return false
+	}
+	version := s.Version
+	u.version = version
+	u.staged = nil
+	u.pending = slices.DeleteFunc(u.pending, func(p update.Summary) bool { return p.Version == version })
+	u.run("install", "Installation of "+version+" completed")
+	return true
+}
+
+// path "/api/appliance/update"
+func (h *Handler) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, h.update.info())
+	case http.MethodPost:
+		if r.URL.Query().Get(update.Action) != update.Cancel {
+			http.NotFound(w, r)
+			return
+		}
+		// tasks complete immediately, there is never one to cancel
+		vapi.ApiErrorNotAllowedInCurrentState(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/appliance/update/pending"
+func (h *Handler) handlePending(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.URL.Query().Get("source_type") {
+	case update.SourceLastCheck, update.SourceLocal, update.SourceLocalAndOnline:
+	default:
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.update.lastQuery = &now
+
+	vapi.StatusOK(w, h.update.pending)
+}
+
+// path "/api/appliance/update/pending/{version}"
+func (h *Handler) handlePendingVersion(w http.ResponseWriter, r *http.Request) {
+	version := strings.TrimPrefix(r.URL.Path, update.PendingPath+"/")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.update.find(version)
+	if s == nil {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, update.PendingInfo{
+			Summary:               *s,
+			KnowledgeBase:         "https://knowledge.broadcom.com/",
+			ServicesWillBeStopped: []string{"vpxd", "vsphere-ui"},
+			Staged:                h.update.staged != nil && h.update.staged.Version == version,
+		})
+		return
+	case http.MethodPost:
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	action := r.URL.Query().Get(update.Action)
+
+	var spec update.InstallSpec
+	switch action {
+	case update.Validate, update.Install, update.StageAndInstall:
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+	}
+
+	switch action {
+	case update.Precheck:
+		vapi.StatusOK(w, update.PrecheckResult{
+			CheckTime:               time.Now(),
+			EstimatedTimeToInstall:  30,
+			EstimatedTimeToRollback: 10,
+			RebootRequired:          s.RebootRequired,
+			Issues:                  &update.Notifications{},
+		})
+	case update.Validate:
+		vapi.StatusOK(w, update.Notifications{})
+	case update.Stage:
+		if !h.update.stage(s) {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case update.Install:
+		if !h.update.install(s) {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case update.StageAndInstall:
+		if !h.update.stage(s) || !h.update.install(s) {
+			vapi.ApiErrorNotAllowedInCurrentState(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/appliance/update/staged"
+func (h *Handler) handleStaged(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.update.staged == nil {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, h.update.staged)
+	case http.MethodDelete:
+		h.update.staged = nil
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,55 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package timesync
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const Path = "/api/appliance/timesync"
+
+// Time synchronization modes
+const (
+	Disabled = "DISABLED"
+	NTP      = "NTP"
+	Host     = "HOST"
+)
+
+// Manager provides convenience methods to get/set the time synchronization mode of the appliance.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Get returns the time synchronization mode.
+func (m *Manager) Get(ctx context.Context) (string, error) {
+	r := m.Resource(Path)
+
+	var mode string
+	err := m.Do(ctx, r.Request(http.MethodGet), &mode)
+
+	return mode, err
+}
+
+// Mode represents the value to be set for time synchronization
+type Mode struct {
+	Mode string `json:"mode"`
+}
+
+// Set sets the time synchronization mode.
+func (m *Manager) Set(ctx context.Context, inp Mode) error {
+	r := m.Resource(Path)
+
+	return m.Do(ctx, r.Request(http.MethodPut, inp), nil)
+}
@@ -0,0 +1,241 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package update
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	Path        = "/api/appliance/update"
+	PendingPath = Path + "/pending"
+	StagedPath  = Path + "/staged"
+
+	Action          = "action"
+	Cancel          = "cancel"
+	Precheck        = "precheck"
+	Validate        = "validate"
+	Stage           = "stage"
+	Install         = "install"
+	StageAndInstall = "stage-and-install"
+)
+
+// Update states
+const (
+	StateUpToDate           = "UP_TO_DATE"
+	StateUpdatesPending     = "UPDATES_PENDING"
+	StateStageInProgress    = "STAGE_IN_PROGRESS"
+	StateInstallInProgress  = "INSTALL_IN_PROGRESS"
+	StateInstallFailed      = "INSTALL_FAILED"
+	StateRollbackInProgress = "ROLLBACK_IN_PROGRESS"
+)
+
+// Sources of pending updates
+const (
+	SourceLastCheck      = "LAST_CHECK"
+	SourceLocal          = "LOCAL"
+	SourceLocalAndOnline = "LOCAL_AND_ONLINE"
+)
+
+// Task statuses
+const (
+	TaskPending   = "PENDING"
+	TaskRunning   = "RUNNING"
+	TaskBlocked   = "BLOCKED"
+	TaskSucceeded = "SUCCEEDED"
+	TaskFailed    = "FAILED"
+)
+
+// Manager provides convenience methods to check, stage and install appliance updates.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager with the given client
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Progress of an update task
+type Progress struct {
+	Total     int64                   `json:"total"`
+	Completed int64                   `json:"completed"`
+	Message   rest.LocalizableMessage `json:"message"`
+}
+
+// TaskInfo describes the last update task
+type TaskInfo struct {
+	Description rest.LocalizableMessage `json:"description"`
+	Operation   string                  `json:"operation"`
+	Status      string                  `json:"status"`
+	Progress    *Progress               `json:"progress,omitempty"`
+	StartTime   *time.Time              `json:"start_time,omitempty"`
+	EndTime     *time.Time              `json:"end_time,omitempty"`
+}
+
+// Info describes the appliance update state
+type Info struct {
+	State           string     `json:"state"`
+	Task            *TaskInfo  `json:"task,omitempty"`
+	Version         string     `json:"version"`
+	LatestQueryTime *time.Time `json:"latest_query_time,omitempty"`
+}
+
+// Summary describes an available update
+type Summary struct {
+	Version        string                  `json:"version"`
+	Name           string                  `json:"name"`
+	Description    rest.LocalizableMessage `json:"description"`
+	Priority       string                  `json:"priority"`
+	Severity       string                  `json:"severity"`
+	UpdateType     string                  `json:"update_type"`
+	ReleaseDate    time.Time               `json:"release_date"`
+	RebootRequired bool                    `json:"reboot_required"`
+	Size           int64                   `json:"size"`
+}
+
+// PendingInfo describes an available update in detail
+type PendingInfo struct {
+	Summary
+	KnowledgeBase         string   `json:"knowledge_base,omitempty"`
+	ServicesWillBeStopped []string `json:"services_will_be_stopped,omitempty"`
+	Staged                bool     `json:"staged"`
+}
+
+// StagedInfo describes the staged update
+type StagedInfo struct {
+	Summary
+	StagingComplete bool `json:"staging_complete"`
+}
+
+// Notification is an issue reported by a precheck or validation
+type Notification struct {
+	ID         string                   `json:"id"`
+	Time       *time.Time               `json:"time,omitempty"`
+	Message    rest.LocalizableMessage  `json:"message"`
+	Resolution *rest.LocalizableMessage `json:"resolution,omitempty"`
+}
+
+// Notifications are grouped by severity
+type Notifications struct {
+	Info     []Notification `json:"info,omitempty"`
+	Warnings []Notification `json:"warnings,omitempty"`
+	Errors   []Notification `json:"errors,omitempty"`
+}
+
+// PrecheckResult is the result of an update precheck
+type PrecheckResult struct {
+	CheckTime               time.Time      `json:"check_time"`
+	EstimatedTimeToInstall  int            `json:"estimated_time_to_install,omitempty"`
+	EstimatedTimeToRollback int            `json:"estimated_time_to_rollback,omitempty"`
+	RebootRequired          bool           `json:"reboot_required"`
+	Issues                  *Notifications `json:"issues,omitempty"`
+}
+
+// InstallSpec provides answers to the questions of an update
+type InstallSpec struct {
+	UserData map[string]string `json:"user_data,omitempty"`
+}
+
+// Get returns the appliance update state.
+func (m *Manager) Get(ctx context.Context) (*Info, error) {
+	r := m.Resource(Path)
+
+	var res Info
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return &res, err
+}
+
+// Cancel cancels the update task in progress.
+func (m *Manager) Cancel(ctx context.Context) error {
+	r := m.Resource(Path).WithParam(Action, Cancel)
+
+	return m.Do(ctx, r.Request(http.MethodPost), nil)
+}
+
+// ListPending checks the given source, such as SourceLocalAndOnline, for available updates.
+func (m *Manager) ListPending(ctx context.Context, source string) ([]Summary, error) {
+	r := m.Resource(PendingPath).WithParam("source_type", source)
+
+	var res []Summary
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
+
+// GetPending returns details of the available update with the given version.
+func (m *Manager) GetPending(ctx context.Context, version string) (*PendingInfo, error) {
+	r := m.Resource(PendingPath).WithSubpath(version)
+
+	var res PendingInfo
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return &res, err
+}
+
+// Precheck runs update prechecks for the given version.
+func (m *Manager) Precheck(ctx context.Context, version string) (*PrecheckResult, error) {
+	r := m.Resource(PendingPath).WithSubpath(version).WithParam(Action, Precheck)
+
+	var res PrecheckResult
+	err := m.Do(ctx, r.Request(http.MethodPost), &res)
+
+	return &res, err
+}
+
+// Validate validates the user provided data before installing the given version.
+func (m *Manager) Validate(ctx context.Context, version string, spec InstallSpec) (*Notifications, error) {
+	r := m.Resource(PendingPath).WithSubpath(version).WithParam(Action, Validate)
+
+	var res Notifications
+	err := m.Do(ctx, r.Request(http.MethodPost, spec), &res)
+
+	return &res, err
+}
+
+// Stage starts staging the given version.
+func (m *Manager) Stage(ctx context.Context, version string) error {
+	r := m.Resource(PendingPath).WithSubpath(version).WithParam(Action, Stage)
+
+	return m.Do(ctx, r.Request(http.MethodPost), nil)
+}
+
+// Install starts installing the given, previously staged, version.
+func (m *Manager) Install(ctx context.Context, version string, spec InstallSpec) error {
+	r := m.Resource(PendingPath).WithSubpath(version).WithParam(Action, Install)
+
+	return m.Do(ctx, r.Request(http.MethodPost, spec), nil)
+}
+
+// StageAndInstall starts staging and installing the given version.
+func (m *Manager) StageAndInstall(ctx context.Context, version string, spec InstallSpec) error {
+	r := m.Resource(PendingPath).WithSubpath(version).WithParam(Action, StageAndInstall)
+
+	return m.Do(ctx, r.Request(http.MethodPost, spec), nil)
+}
+
+// GetStaged returns the staged update.
+func (m *Manager) GetStaged(ctx context.Context) (*StagedInfo, error) {
+	r := m.Resource(StagedPath)
+
+	var res StagedInfo
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return &res, err
+}
+
+// DeleteStaged removes the staged update.
+func (m *Manager) DeleteStaged(ctx context.Context) error {
+	r := m.Resource(StagedPath)
+
+	return m.Do(ctx, r.Request(http.MethodDelete), nil)
+}
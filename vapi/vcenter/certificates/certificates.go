@@ -0,0 +1,232 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	// Path is the REST endpoint for the vCenter certificate management API
+	Path = "/api/vcenter/certificate-management/vcenter"
+
+	TLSPath                = Path + "/tls"
+	TLSCSRPath             = Path + "/tls-csr"
+	TrustedRootChainsPath  = Path + "/trusted-root-chains"
+	SigningCertificatePath = Path + "/signing-certificate"
+	VMCARootPath           = Path + "/vmca-root"
+)
+
+// Manager extends rest.Client, adding vCenter certificate management related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// TLSInfo contains information about the vCenter machine SSL certificate.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/CertificateManagement_Vcenter_Tls_Info
+type TLSInfo struct {
+	Version                       int       `json:"version"`
+	ValidFrom                     time.Time `json:"valid_from"`
+	ValidTo                       time.Time `json:"valid_to"`
+	SubjectDN                     string    `json:"subject_dn"`
+	Thumbprint                    string    `json:"thumbprint"`
+	SerialNumber                  string    `json:"serial_number"`
+	SignatureAlgorithm            string    `json:"signature_algorithm"`
+	IssuerDN                      string    `json:"issuer_dn"`
+	IsCA                          bool      `json:"is_CA"`
+	PathLengthConstraint          int       `json:"path_length_constraint"`
+	KeyUsage                      []string  `json:"key_usage"`
+	ExtendedKeyUsage              []string  `json:"extended_key_usage"`
+	SubjectAlternativeName        []string  `json:"subject_alternative_name"`
+	AuthorityInformationAccessURI []string  `json:"authority_information_access_uri"`
+	Cert                          string    `json:"cert"`
+}
+
+// TLSSpec describes a custom machine SSL certificate.
+// Key may be omitted if the certificate was issued for a CSR generated by CreateTLSCSR.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/CertificateManagement_Vcenter_Tls_Spec
+type TLSSpec struct {
+	Cert     string `json:"cert"`
+	Key      string `json:"key,omitempty"`
+	RootCert string `json:"root_cert,omitempty"`
+}
+
+// TLSCSRSpec describes a certificate signing request for the machine SSL certificate.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/CertificateManagement_Vcenter_TlsCsr_Spec
+type TLSCSRSpec struct {
+	KeySize          int      `json:"key_size,omitempty"`
+	CommonName       string   `json:"common_name,omitempty"`
+	Organization     string   `json:"organization"`
+	OrganizationUnit string   `json:"organization_unit"`
+	Locality         string   `json:"locality"`
+	StateOrProvince  string   `json:"state_or_province"`
+	Country          string   `json:"country"`
+	EmailAddress     string   `json:"email_address"`
+	SubjectAltName   []string `json:"subject_alt_name,omitempty"`
+}
+
+// TLSCSR contains a PEM encoded certificate signing request.
+type TLSCSR struct {
+	CSR string `json:"csr"`
+}
+
+// X509CertChain contains a PEM encoded certificate chain.
+type X509CertChain struct {
+	CertChain []string `json:"cert_chain"`
+}
+
+// TrustedRootChainSummary identifies a trusted root certificate chain.
+type TrustedRootChainSummary struct {
+	Chain string `json:"chain"`
+}
+
+// TrustedRootChainInfo contains a trusted root certificate chain.
+type TrustedRootChainInfo struct {
+	CertChain X509CertChain `json:"cert_chain"`
+}
+
+// TrustedRootChainCreateSpec describes a trusted root certificate chain to add.
+type TrustedRootChainCreateSpec struct {
+	CertChain X509CertChain `json:"cert_chain"`
+	Chain     string        `json:"chain,omitempty"`
+}
+
+// SigningCertificateInfo contains the vCenter token signing certificates.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/CertificateManagement_Vcenter_SigningCertificate_Info
+type SigningCertificateInfo struct {
+	ActiveCertChain   X509CertChain   `json:"active_cert_chain"`
+	SigningCertChains []X509CertChain `json:"signing_cert_chains"`
+}
+
+// SigningCertificateSetSpec describes a custom token signing certificate.
+type SigningCertificateSetSpec struct {
+	SigningCertChain X509CertChain `json:"signing_cert_chain"`
+	PrivateKey       string        `json:"private_key"`
+}
+
+// VMCARootCreateSpec describes the subject of a new VMCA root certificate.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/CertificateManagement_Vcenter_VmcaRoot_CreateSpec
+type VMCARootCreateSpec struct {
+	KeySize          int      `json:"key_size,omitempty"`
+	CommonName       string   `json:"common_name,omitempty"`
+	Organization     string   `json:"organization,omitempty"`
+	OrganizationUnit string   `json:"organization_unit,omitempty"`
+	Locality         string   `json:"locality,omitempty"`
+	StateOrProvince  string   `json:"state_or_province,omitempty"`
+	Country          string   `json:"country,omitempty"`
+	EmailAddress     string   `json:"email_address,omitempty"`
+	SubjectAltName   []string `json:"subject_alt_name,omitempty"`
+}
+
+// GetTLS returns the vCenter machine SSL certificate.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/tls/get
+func (c *Manager) GetTLS(ctx context.Context) (*TLSInfo, error) {
+	url := c.Resource(TLSPath)
+	var res TLSInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// ReplaceTLS replaces the vCenter machine SSL certificate with a custom certificate.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/tls/put
+func (c *Manager) ReplaceTLS(ctx context.Context, spec TLSSpec) error {
+	url := c.Resource(TLSPath)
+	return c.Do(ctx, url.Request(http.MethodPut, spec), nil)
+}
+
+// RenewTLS renews the vCenter machine SSL certificate, issued by VMCA, for the given number of days.
+// A duration of 0 uses the server default.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/tlsactionrenew/post
+func (c *Manager) RenewTLS(ctx context.Context, duration int) error {
+	url := c.Resource(TLSPath).WithParam("action", "renew")
+	spec := struct {
+		Duration int `json:"duration,omitempty"`
+	}{duration}
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
+
+// CreateTLSCSR generates a certificate signing request for the vCenter machine SSL certificate.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/tls-csr/post
+func (c *Manager) CreateTLSCSR(ctx context.Context, spec TLSCSRSpec) (*TLSCSR, error) {
+	url := c.Resource(TLSCSRPath)
+	var res TLSCSR
+	return &res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// ListTrustedRootChains returns the identifiers of the trusted root certificate chains.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/trusted-root-chains/get
+func (c *Manager) ListTrustedRootChains(ctx context.Context) ([]TrustedRootChainSummary, error) {
+	url := c.Resource(TrustedRootChainsPath)
+	var res []TrustedRootChainSummary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateTrustedRootChain adds a trusted root certificate chain, returning its identifier.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/trusted-root-chains/post
+func (c *Manager) CreateTrustedRootChain(ctx context.Context, spec TrustedRootChainCreateSpec) (string, error) {
+	url := c.Resource(TrustedRootChainsPath)
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// GetTrustedRootChain returns the trusted root certificate chain with the given identifier.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/trusted-root-chains/chain/get
+func (c *Manager) GetTrustedRootChain(ctx context.Context, chain string) (*TrustedRootChainInfo, error) {
+	url := c.Resource(TrustedRootChainsPath).WithSubpath(chain)
+	var res TrustedRootChainInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// DeleteTrustedRootChain removes the trusted root certificate chain with the given identifier.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/trusted-root-chains/chain/delete
+func (c *Manager) DeleteTrustedRootChain(ctx context.Context, chain string) error {
+	url := c.Resource(TrustedRootChainsPath).WithSubpath(chain)
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// GetSigningCertificate returns the vCenter token signing certificates.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/signing-certificate/get
+func (c *Manager) GetSigningCertificate(ctx context.Context) (*SigningCertificateInfo, error) {
+	url := c.Resource(SigningCertificatePath)
+	var res SigningCertificateInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// SetSigningCertificate replaces the active token signing certificate with a custom certificate.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/signing-certificate/put
+func (c *Manager) SetSigningCertificate(ctx context.Context, spec SigningCertificateSetSpec) error {
+	url := c.Resource(SigningCertificatePath)
+	return c.Do(ctx, url.Request(http.MethodPut, spec), nil)
+}
+
+// RefreshSigningCertificate replaces the active token signing certificate with one issued by VMCA,
+// returning the PEM encoded chain of the new certificate.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/signing-certificateactionrefresh/post
+func (c *Manager) RefreshSigningCertificate(ctx context.Context, force bool) (string, error) {
+	url := c.Resource(SigningCertificatePath).WithParam("action", "refresh")
+	spec := struct {
+		Force bool `json:"force"`
+	}{force}
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// ReplaceVMCARoot replaces the VMCA root certificate with a new self-signed certificate.
+// The machine SSL certificate is reissued by the new root.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/certificate-management/vcenter/vmca-root/post
+func (c *Manager) ReplaceVMCARoot(ctx context.Context, spec VMCARootCreateSpec) error {
+	url := c.Resource(VMCARootPath)
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
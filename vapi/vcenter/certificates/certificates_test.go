@@ -0,0 +1,216 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package certificates_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vapi/vcenter/certificates/simulator"
+)
+
+func parse(t *testing.T, data string) *x509.Certificate {
+	block, _ := pem.Decode([]byte(data))
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+// selfSigned returns a PEM encoded CA certificate, leaf certificate and leaf key
+func selfSigned(t *testing.T) (string, string, string) {
+	encode := func(kind string, der []byte) string {
+		return string(pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: der}))
+	}
+
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, caKey.Public(), caKey)
+	require.NoError(t, err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	leaf := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "vcsim.example.com"},
+		DNSNames:     []string{"vcsim.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, key.Public(), caKey)
+	require.NoError(t, err)
+
+	return encode("CERTIFICATE", caDER), encode("CERTIFICATE", leafDER), encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+func TestCertificates(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := certificates.NewManager(c)
+
+		// the initial certificate is the one served by the simulator
+		info, err := m.GetTLS(ctx)
+		require.NoError(t, err)
+		served := parse(t, info.Cert)
+		assert.Equal(t, simulator.Map(ctx).SessionManager().TLSCert(), base64.StdEncoding.EncodeToString(served.Raw))
+		assert.NotEmpty(t, info.Thumbprint)
+
+		roots, err := m.ListTrustedRootChains(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, roots)
+
+		// renew issues a new certificate from VMCA, keeping the subject
+		require.NoError(t, m.RenewTLS(ctx, 30))
+		renewed, err := m.GetTLS(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, info.Thumbprint, renewed.Thumbprint)
+		assert.Equal(t, info.SubjectDN, renewed.SubjectDN)
+		assert.Equal(t, info.SubjectAlternativeName, renewed.SubjectAlternativeName)
+		assert.WithinDuration(t, renewed.ValidFrom.AddDate(0, 0, 30), renewed.ValidTo, time.Minute)
+		assert.Contains(t, renewed.ExtendedKeyUsage, "serverAuth")
+
+		vmca := parse(t, renewed.Cert)
+		var issuer *x509.Certificate
+		for _, root := range roots {
+			chain, err := m.GetTrustedRootChain(ctx, root.Chain)
+			require.NoError(t, err)
+			cert := parse(t, chain.CertChain.CertChain[0])
+			if vmca.CheckSignatureFrom(cert) == nil {
+				issuer = cert
+			}
+		}
+		require.NotNil(t, issuer, "renewed certificate not issued by a trusted root")
+
+		// replace with a certificate issued by an untrusted CA
+		caPEM, certPEM, keyPEM := selfSigned(t)
+		err = m.ReplaceTLS(ctx, certificates.TLSSpec{Cert: certPEM, Key: keyPEM})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		_, _, otherKey := selfSigned(t)
+		err = m.ReplaceTLS(ctx, certificates.TLSSpec{Cert: certPEM, Key: otherKey, RootCert: caPEM})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		require.NoError(t, m.ReplaceTLS(ctx, certificates.TLSSpec{Cert: certPEM, Key: keyPEM, RootCert: caPEM}))
+		info, err = m.GetTLS(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "CN=vcsim.example.com", info.SubjectDN)
+		assert.Equal(t, "CN=Test CA", info.IssuerDN)
+
+		// root_cert is added to the trusted root chains
+		after, err := m.ListTrustedRootChains(ctx)
+		require.NoError(t, err)
+		assert.Len(t, after, len(roots)+1)
+
+		// generate a CSR, with alternative names split into DNS names and IP addresses
+		csr, err := m.CreateTLSCSR(ctx, certificates.TLSCSRSpec{
+			CommonName:     "vcsim.example.com",
+			Organization:   "VMware",
+			Country:        "US",
+			SubjectAltName: []string{"vcsim.example.com", "127.0.0.1"},
+		})
+		require.NoError(t, err)
+		block, _ := pem.Decode([]byte(csr.CSR))
+		require.NotNil(t, block)
+		req, err := x509.ParseCertificateRequest(block.Bytes)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"vcsim.example.com"}, req.DNSNames)
+		assert.Len(t, req.IPAddresses, 1)
+
+		_, err = m.CreateTLSCSR(ctx, certificates.TLSCSRSpec{KeySize: 1024})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		// trusted root chains
+		id, err := m.CreateTrustedRootChain(ctx, certificates.TrustedRootChainCreateSpec{
+			CertChain: certificates.X509CertChain{CertChain: []string{caPEM}},
+			Chain:     "test",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "test", id)
+
+		_, err = m.CreateTrustedRootChain(ctx, certificates.TrustedRootChainCreateSpec{
+			CertChain: certificates.X509CertChain{CertChain: []string{caPEM}},
+			Chain:     "test",
+		})
+		assert.ErrorContains(t, err, "ALREADY_EXISTS")
+
+		_, err = m.CreateTrustedRootChain(ctx, certificates.TrustedRootChainCreateSpec{
+			CertChain: certificates.X509CertChain{CertChain: []string{"invalid"}},
+		})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		chain, err := m.GetTrustedRootChain(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{caPEM}, chain.CertChain.CertChain)
+
+		require.NoError(t, m.DeleteTrustedRootChain(ctx, id))
+		_, err = m.GetTrustedRootChain(ctx, id)
+		assert.True(t, rest.IsStatusError(err, http.StatusNotFound))
+
+		// signing certificate
+		signing, err := m.GetSigningCertificate(ctx)
+		require.NoError(t, err)
+		require.Len(t, signing.SigningCertChains, 1)
+		assert.Equal(t, signing.ActiveCertChain, signing.SigningCertChains[0])
+
+		refreshed, err := m.RefreshSigningCertificate(ctx, false)
+		require.NoError(t, err)
+		assert.NotEmpty(t, refreshed)
+
+		signing, err = m.GetSigningCertificate(ctx)
+		require.NoError(t, err)
+		assert.Len(t, signing.SigningCertChains, 2)
+		assert.Equal(t, refreshed, signing.ActiveCertChain.CertChain[0]+signing.ActiveCertChain.CertChain[1])
+
+		err = m.SetSigningCertificate(ctx, certificates.SigningCertificateSetSpec{
+			SigningCertChain: certificates.X509CertChain{CertChain: []string{certPEM, caPEM}},
+			PrivateKey:       otherKey,
+		})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		require.NoError(t, m.SetSigningCertificate(ctx, certificates.SigningCertificateSetSpec{
+			SigningCertChain: certificates.X509CertChain{CertChain: []string{certPEM, caPEM}},
+			PrivateKey:       keyPEM,
+		}))
+		signing, err = m.GetSigningCertificate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{certPEM, caPEM}, signing.ActiveCertChain.CertChain)
+
+		// replacing the VMCA root reissues the machine SSL certificate
+		require.NoError(t, m.ReplaceVMCARoot(ctx, certificates.VMCARootCreateSpec{CommonName: "vcsim CA", Organization: "VMware"}))
+		info, err = m.GetTLS(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "CN=vcsim CA,O=VMware", info.IssuerDN)
+		assert.Equal(t, "CN=vcsim.example.com", info.SubjectDN)
+	})
+}
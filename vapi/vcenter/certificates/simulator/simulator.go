@@ -0,0 +1,835 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/vcenter/certificates"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+const (
+	// defaultDuration is the validity period, in days, of certificates issued by the test CA
+	defaultDuration = 730
+
+	defaultKeySize = 2048
+)
+
+func init() {
+	simulator.RegisterEndpoint(func(s *simulator.Service, r *simulator.Registry) {
+		New(s.Listen).Register(s, r)
+	})
+}
+
+// issuer is a certificate along with its private key
+type issuer struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// Handler implements the vCenter certificate management API simulator.
+// Certificates are issued by a test CA generated on first use, standing in for VMCA.
+// The certificate used by the simulator's https listener is not changed by this API.
+type Handler struct {
+	URL *url.URL
+
+	sm *simulator.SessionManager
+
+	mu      sync.Mutex
+	ca      *issuer
+	tls     *x509.Certificate
+	csr     crypto.Signer
+	roots   map[string][]*x509.Certificate
+	signing [][]*x509.Certificate
+}
+
+// New creates a Handler instance
+func New(u *url.URL) *Handler {
+	return &Handler{
+		URL: u,
+	}
+}
+
+// Register certificate management API paths with the vapi simulator's http.ServeMux
+func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
+	if r.IsVPX() {
+		h.sm = r.SessionManager()
+
+		s.HandleFunc(certificates.TLSPath, h.handleTLS)
+		s.HandleFunc(certificates.TLSCSRPath, h.handleCSR)
+		s.HandleFunc(certificates.TrustedRootChainsPath, h.handleRoots)
+		s.HandleFunc(certificates.TrustedRootChainsPath+"/", h.handleRoot)
+		s.HandleFunc(certificates.SigningCertificatePath, h.handleSigning)
+		s.HandleFunc(certificates.VMCARootPath, h.handleVMCARoot)
+	}
+}
+
+// init generates the test CA and initial certificates, if not already done.
+// The initial machine SSL certificate is the certificate served by the simulator, when started with TLS.
+// Must be called with h.mu held.
+func (h *Handler) init() error {
+	if h.ca != nil {
+		return nil
+	}
+
+	ca, err := newCA(certificates.VMCARootCreateSpec{})
+	if err != nil {
+		return err
+	}
+	h.ca = ca
+	h.roots = make(map[string][]*x509.Certificate)
+	h.addRoot(ca.cert)
+
+	if h.sm.TLS != nil {
+		config := h.sm.TLS()
+		if len(config.Certificates) != 0 {
+			cert, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+			if err != nil {
+				return err
+			}
+			h.tls = cert
+			if cert.CheckSignatureFrom(cert) == nil {
+				h.addRoot(cert) // self-signed
+			}
+		}
+	}
+
+	if h.tls == nil {
+		name := h.URL.Hostname()
+		template := &x509.Certificate{Subject: pkix.Name{CommonName: name}}
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = []net.IP{ip}
+		} else {
+			template.DNSNames = []string{name}
+		}
+		key, err := rsa.GenerateKey(rand.Reader, defaultKeySize)
+		if err != nil {
+			return err
+		}
+		if h.tls, err = h.ca.issue(template, key.Public(), defaultDuration); err != nil {
+			return err
+		}
+	}
+
+	return h.refreshSigning()
+}
+
+// addRoot adds the given chain to the trusted root chains
+func (h *Handler) addRoot(chain ...*x509.Certificate) {
+	h.roots[chainID(chain[0])] = chain
+}
+
+// chainID returns the thumbprint of cert without separators, used as the trusted root chain id
+func chainID(cert *x509.Certificate) string {
+	return strings.ReplaceAll(soap.ThumbprintSHA1(cert), ":", "")
+}
+
+// pool returns the trusted root chains as an x509.CertPool
+func (h *Handler) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, chain := range h.roots {
+		for _, cert := range chain {
+			pool.AddCert(cert)
+		}
+	}
+	return pool
+}
+
+// refreshSigning issues a new token signing certificate from the CA and makes it active.
+func (h *Handler) refreshSigning() error {
+	key, err := rsa.GenerateKey(rand.Reader, defaultKeySize)
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "ssoserver-sign"},
+		KeyUsage: x509.KeyUsageDigitalSignature,
+	}
+	cert, err := h.ca.issue(template, key.Public(), defaultDuration)
+	if err != nil {
+		return err
+	}
+	h.signing = append([][]*x509.Certificate{{cert, h.ca.cert}}, h.signing...)
+	return nil
+}
+
+// newCA generates a self-signed CA certificate with the given subject.
+func newCA(spec certificates.VMCARootCreateSpec) (*issuer, error) {
+	if spec.KeySize == 0 {
+		spec.KeySize = defaultKeySize
+	}
+	if spec.CommonName == "" {
+		spec.CommonName = "CA"
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, spec.KeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber(),
+		Subject:               subject(spec),
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            0,
+		MaxPathLenZero:        true,
+	}
+	sans(template, spec.SubjectAltName)
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuer{cert: cert, key: key}, nil
+}
+
+// issue a certificate from the given template, valid for the given number of days.
+func (ca *issuer) issue(template *x509.Certificate, pub crypto.PublicKey, days int) (*x509.Certificate, error) {
+	cert := *template
+	cert.SerialNumber = serialNumber()
+	cert.NotBefore = time.Now()
+	cert.NotAfter = cert.NotBefore.AddDate(0, 0, days)
+	cert.AuthorityKeyId = ca.cert.SubjectKeyId
+	cert.BasicConstraintsValid = true
+	cert.IsCA = false
+	if cert.KeyUsage == 0 {
+		cert.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+		cert.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &cert, ca.cert, pub, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+func serialNumber() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return n
+}
+
+func subject(spec certificates.VMCARootCreateSpec) pkix.Name {
+	name := pkix.Name{CommonName: spec.CommonName}
+	if spec.Organization != "" {
+		name.Organization = []string{spec.Organization}
+	}
+	if spec.OrganizationUnit != "" {
+		name.OrganizationalUnit = []string{spec.OrganizationUnit}
+	}
+	if spec.Locality != "" {
+		name.Locality = []string{spec.Locality}
+	}
+	if spec.StateOrProvince != "" {
+		name.Province = []string{spec.StateOrProvince}
+	}
+	if spec.Country != "" {
+		name.Country = []string{spec.Country}
+	}
+	return name
+}
+
+// sans sets the template's subject alternative names, which may be DNS names or IP addresses
+func sans(template *x509.Certificate, names []string) {
+	for _, name := range names {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+		}
+	}
+}
+
+func encode(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+func encodeChain(chain []*x509.Certificate) certificates.X509CertChain {
+	var res certificates.X509CertChain
+	for _, cert := range chain {
+		res.CertChain = append(res.CertChain, encode(cert))
+	}
+	return res
+}
+
+// parseCerts decodes all PEM encoded certificates in the given strings
+func parseCerts(data ...string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for _, s := range data {
+		rest := []byte(s)
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, cert)
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+
+	return certs, nil
+}
+
+// parseKey decodes a PEM encoded PKCS#1, PKCS#8 or EC private key
+func parseKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no private key found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	return signer, nil
+}
+
+// matches returns true if key is the private key of cert
+func matches(cert *x509.Certificate, key crypto.Signer) bool {
+	if key == nil {
+		return false
+	}
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && pub.Equal(cert.PublicKey)
+}
+
+var keyUsage = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "digitalSignature"},
+	{x509.KeyUsageContentCommitment, "nonRepudiation"},
+	{x509.KeyUsageKeyEncipherment, "keyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "dataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "keyAgreement"},
+	{x509.KeyUsageCertSign, "keyCertSign"},
+	{x509.KeyUsageCRLSign, "cRLSign"},
+	{x509.KeyUsageEncipherOnly, "encipherOnly"},
+	{x509.KeyUsageDecipherOnly, "decipherOnly"},
+}
+
+var extKeyUsage = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:             "anyExtendedKeyUsage",
+	x509.ExtKeyUsageServerAuth:      "serverAuth",
+	x509.ExtKeyUsageClientAuth:      "clientAuth",
+	x509.ExtKeyUsageCodeSigning:     "codeSigning",
+	x509.ExtKeyUsageEmailProtection: "emailProtection",
+	x509.ExtKeyUsageTimeStamping:    "timeStamping",
+	x509.ExtKeyUsageOCSPSigning:     "OCSPSigning",
+}
+
+// info converts cert to certificates.TLSInfo
+func info(cert *x509.Certificate) certificates.TLSInfo {
+	res := certificates.TLSInfo{
+		Version:                       cert.Version,
+		ValidFrom:                     cert.NotBefore,
+		ValidTo:                       cert.NotAfter,
+		SubjectDN:                     cert.Subject.String(),
+		Thumbprint:                    soap.ThumbprintSHA1(cert),
+		SerialNumber:                  cert.SerialNumber.String(),
+		SignatureAlgorithm:            cert.SignatureAlgorithm.String(),
+		IssuerDN:                      cert.Issuer.String(),
+		IsCA:                          cert.IsCA,
+		PathLengthConstraint:          -1,
+		KeyUsage:                      []string{},
+		ExtendedKeyUsage:              []string{},
+		SubjectAlternativeName:        slices.Clone(cert.DNSNames),
+		AuthorityInformationAccessURI: slices.Clone(cert.IssuingCertificateURL),
+		Cert:                          encode(cert),
+	}
+
+	if cert.IsCA && (cert.MaxPathLen > 0 || cert.MaxPathLenZero) {
+		res.PathLengthConstraint = cert.MaxPathLen
+	}
+
+	for _, u := range keyUsage {
+		if cert.KeyUsage&u.usage != 0 {
+			res.KeyUsage = append(res.KeyUsage, u.name)
+		}
+	}
+
+	for _, u := range cert.ExtKeyUsage {
+		if name, ok := extKeyUsage[u]; ok {
+			res.ExtendedKeyUsage = append(res.ExtendedKeyUsage, name)
+		}
+	}
+
+	for _, ip := range cert.IPAddresses {
+		res.SubjectAlternativeName = append(res.SubjectAlternativeName, ip.String())
+	}
+	res.SubjectAlternativeName = append(res.SubjectAlternativeName, cert.EmailAddresses...)
+	if res.SubjectAlternativeName == nil {
+		res.SubjectAlternativeName = []string{}
+	}
+	if res.AuthorityInformationAccessURI == nil {
+		res.AuthorityInformationAccessURI = []string{}
+	}
+
+	return res
+}
+
+// withState invokes f with h.mu held and the certificate state initialized, responding with an error if f fails.
+func (h *Handler) withState(w http.ResponseWriter, r *http.Request, f func() error) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	err := h.init()
+	if err == nil {
+		err = f()
+	}
+	if err == nil {
+		return true
+	}
+
+	log.Printf("%s %s: %s", r.Method, r.RequestURI, err)
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		apiErr.respond(w)
+	} else {
+		vapi.ApiErrorGeneral(w)
+	}
+
+	return false
+}
+
+// apiError maps a validation failure to a REST error response
+type apiError struct {
+	err     error
+	respond func(http.ResponseWriter)
+}
+
+func (e *apiError) Error() string {
+	return e.err.Error()
+}
+
+func invalidArgument(err error) error {
+	return &apiError{err, vapi.ApiErrorInvalidArgument}
+}
+
+// path "/api/vcenter/certificate-management/vcenter/tls"
+func (h *Handler) handleTLS(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var res certificates.TLSInfo
+		ok := h.withState(w, r, func() error {
+			res = info(h.tls)
+			return nil
+		})
+		if ok {
+			vapi.StatusOK(w, res)
+		}
+	case http.MethodPut:
+		var spec certificates.TLSSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		ok := h.withState(w, r, func() error {
+			return h.replaceTLS(spec)
+		})
+		if ok {
+			vapi.StatusOK(w)
+		}
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "renew" {
+			http.NotFound(w, r)
+			return
+		}
+		var spec struct {
+			Duration int `json:"duration"`
+		}
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		if spec.Duration < 0 {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		if spec.Duration == 0 {
+			spec.Duration = defaultDuration
+		}
+		ok := h.withState(w, r, func() error {
+			return h.renewTLS(spec.Duration)
+		})
+		if ok {
+			vapi.StatusOK(w)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// renewTLS reissues the machine SSL certificate from the CA, keeping its subject and alternative names.
+func (h *Handler) renewTLS(days int) error {
+	template := &x509.Certificate{
+		Subject:        h.tls.Subject,
+		DNSNames:       h.tls.DNSNames,
+		IPAddresses:    h.tls.IPAddresses,
+		EmailAddresses: h.tls.EmailAddresses,
+	}
+	cert, err := h.ca.issue(template, h.tls.PublicKey, days)
+	if err != nil {
+		return err
+	}
+	h.tls = cert
+	return nil
+}
+
+// replaceTLS validates and installs a custom machine SSL certificate.
+// The key may be omitted when the certificate was issued for the last generated CSR.
+func (h *Handler) replaceTLS(spec certificates.TLSSpec) error {
+	certs, err := parseCerts(spec.Cert)
+	if err != nil {
+		return invalidArgument(err)
+	}
+	cert := certs[0]
+
+	key := h.csr
+	if spec.Key != "" {
+		if key, err = parseKey(spec.Key); err != nil {
+			return invalidArgument(err)
+		}
+	}
+	if !matches(cert, key) {
+		return invalidArgument(errors.New("private key does not match certificate"))
+	}
+
+	var roots []*x509.Certificate
+	pool := h.pool()
+	if spec.RootCert != "" {
+		if roots, err = parseCerts(spec.RootCert); err != nil {
+			return invalidArgument(err)
+		}
+		for _, root := range roots {
+			pool.AddCert(root)
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return invalidArgument(err)
+	}
+
+	if len(roots) != 0 {
+		h.addRoot(roots...)
+	}
+	h.tls = cert
+	h.csr = nil
+
+	return nil
+}
+
+// path "/api/vcenter/certificate-management/vcenter/tls-csr"
+func (h *Handler) handleCSR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+
+	var spec certificates.TLSCSRSpec
+	if !vapi.Decode(r, w, &spec) {
+		return
+	}
+	if spec.KeySize == 0 {
+		spec.KeySize = defaultKeySize
+	}
+	if spec.KeySize < defaultKeySize || spec.KeySize > 16384 {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	var res certificates.TLSCSR
+	ok := h.withState(w, r, func() error {
+		if spec.CommonName == "" {
+			spec.CommonName = h.tls.Subject.CommonName
+		}
+
+		key, err := rsa.GenerateKey(rand.Reader, spec.KeySize)
+		if err != nil {
+			return err
+		}
+
+		template := &x509.CertificateRequest{
+			Subject: subject(certificates.VMCARootCreateSpec{
+				CommonName:       spec.CommonName,
+				Organization:     spec.Organization,
+				OrganizationUnit: spec.OrganizationUnit,
+				Locality:         spec.Locality,
+				StateOrProvince:  spec.StateOrProvince,
+				Country:          spec.Country,
+			}),
+		}
+		if spec.EmailAddress != "" {
+			template.EmailAddresses = []string{spec.EmailAddress}
+		}
+		names := spec.SubjectAltName
+		if len(names) == 0 {
+			names = []string{spec.CommonName}
+		}
+		for _, name := range names {
+			if ip := net.ParseIP(name); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else {
+				template.DNSNames = append(template.DNSNames, name)
+			}
+		}
+
+		der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+		if err != nil {
+			return invalidArgument(err)
+		}
+
+		h.csr = key
+		res.CSR = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+		return nil
+	})
+	if ok {
+		vapi.StatusOK(w, res)
+	}
+}
+
+// path "/api/vcenter/certificate-management/vcenter/trusted-root-chains"
+func (h *Handler) handleRoots(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var res []certificates.TrustedRootChainSummary
+		ok := h.withState(w, r, func() error {
+			res = []certificates.TrustedRootChainSummary{}
+			for id := range h.roots {
+				res = append(res, certificates.TrustedRootChainSummary{Chain: id})
+			}
+			slices.SortFunc(res, func(a, b certificates.TrustedRootChainSummary) int {
+				return strings.Compare(a.Chain, b.Chain)
+			})
+			return nil
+		})
+		if ok {
+			vapi.StatusOK(w, res)
+		}
+	case http.MethodPost:
+		var spec certificates.TrustedRootChainCreateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		var id string
+		ok := h.withState(w, r, func() error {
+			chain, err := parseCerts(spec.CertChain.CertChain...)
+			if err != nil {
+				return invalidArgument(err)
+			}
+			id = spec.Chain
+			if id == "" {
+				id = chainID(chain[0])
+			}
+			if _, exists := h.roots[id]; exists {
+				return &apiError{errors.New("chain already exists: " + id), vapi.ApiErrorAlreadyExists}
+			}
+			h.roots[id] = chain
+			return nil
+		})
+		if ok {
+			vapi.StatusOK(w, id)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/vcenter/certificate-management/vcenter/trusted-root-chains/{chain}"
+func (h *Handler) handleRoot(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, certificates.TrustedRootChainsPath+"/")
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		http.NotFound(w, r)
+		return
+	}
+
+	var res certificates.TrustedRootChainInfo
+	ok := h.withState(w, r, func() error {
+		chain, exists := h.roots[id]
+		if !exists {
+			return &apiError{errors.New("chain not found: " + id), vapi.ApiErrorNotFound}
+		}
+
+		if r.Method == http.MethodGet {
+			res.CertChain = encodeChain(chain)
+			return nil
+		}
+		if slices.ContainsFunc(chain, h.ca.cert.Equal) {
+			return &apiError{errors.New("chain contains the VMCA root certificate"), vapi.ApiErrorResourceInUse}
+		}
+		delete(h.roots, id)
+		return nil
+	})
+	if !ok {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		vapi.StatusOK(w, res)
+	} else {
+		vapi.StatusOK(w)
+	}
+}
+
+// path "/api/vcenter/certificate-management/vcenter/signing-certificate"
+func (h *Handler) handleSigning(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		var res certificates.SigningCertificateInfo
+		ok := h.withState(w, r, func() error {
+			res.ActiveCertChain = encodeChain(h.signing[0])
+			for _, chain := range h.signing {
+				res.SigningCertChains = append(res.SigningCertChains, encodeChain(chain))
+			}
+			return nil
+		})
+		if ok {
+			vapi.StatusOK(w, res)
+		}
+	case http.MethodPut:
+		var spec certificates.SigningCertificateSetSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		ok := h.withState(w, r, func() error {
+			chain, err := parseCerts(spec.SigningCertChain.CertChain...)
+			if err != nil {
+				return invalidArgument(err)
+			}
+			key, err := parseKey(spec.PrivateKey)
+			if err != nil {
+				return invalidArgument(err)
+			}
+			if !matches(chain[0], key) {
+				return invalidArgument(errors.New("private key does not match certificate"))
+			}
+			h.signing = append([][]*x509.Certificate{chain}, h.signing...)
+			return nil
+		})
+		if ok {
+			vapi.StatusOK(w)
+		}
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "refresh" {
+			http.NotFound(w, r)
+			return
+		}
+		var res string
+		ok := h.withState(w, r, func() error {
+			if err := h.refreshSigning(); err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			for _, cert := range h.signing[0] {
+				buf.WriteString(encode(cert))
+			}
+			res = buf.String()
+			return nil
+		})
+		if ok {
+			vapi.StatusOK(w, res)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/vcenter/certificate-management/vcenter/vmca-root"
+func (h *Handler) handleVMCARoot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+
+	var spec certificates.VMCARootCreateSpec
+	if !vapi.Decode(r, w, &spec) {
+		return
+	}
+	if spec.KeySize != 0 && (spec.KeySize < defaultKeySize || spec.KeySize > 16384) {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	ok := h.withState(w, r, func() error {
+		ca, err := newCA(spec)
+		if err != nil {
+			return err
+		}
+		h.ca = ca
+		h.addRoot(ca.cert)
+
+		// certificates issued by VMCA are regenerated with the new root
+		if err = h.renewTLS(defaultDuration); err != nil {
+			return err
+		}
+		return h.refreshSigning()
+	})
+	if ok {
+		vapi.StatusOK(w)
+	}
+}
@@ -0,0 +1,289 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	// CustomizationSpecsPath is the REST endpoint for the guest customization specification API
+	CustomizationSpecsPath = "/api/vcenter/guest/customization-specs"
+)
+
+// Operating system types of a customization specification
+const (
+	OSTypeLinux   = "LINUX"
+	OSTypeWindows = "WINDOWS"
+)
+
+// Export formats of a customization specification
+const (
+	FormatJSON = "JSON"
+	FormatXML  = "XML"
+)
+
+// Hostname generator types
+const (
+	HostnameFixed             = "FIXED"
+	HostnamePrefix            = "PREFIX"
+	HostnameVirtualMachine    = "VIRTUAL_MACHINE"
+	HostnameUserInputRequired = "USER_INPUT_REQUIRED"
+)
+
+// IP address assignment types
+const (
+	IPDHCP              = "DHCP"
+	IPStatic            = "STATIC"
+	IPUserInputRequired = "USER_INPUT_REQUIRED"
+)
+
+// Manager extends rest.Client, adding guest customization specification related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// HostnameGenerator describes how the guest hostname is generated.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_HostnameGenerator
+type HostnameGenerator struct {
+	Type      string `json:"type"`
+	FixedName string `json:"fixed_name,omitempty"`
+	Prefix    string `json:"prefix,omitempty"`
+}
+
+// LinuxConfiguration contains the Linux guest customization settings.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_LinuxConfiguration
+type LinuxConfiguration struct {
+	Hostname   *HostnameGenerator `json:"hostname,omitempty"`
+	Domain     string             `json:"domain"`
+	TimeZone   string             `json:"time_zone,omitempty"`
+	ScriptText string             `json:"script_text,omitempty"`
+}
+
+// UserData contains the Windows user and license settings.
+type UserData struct {
+	ComputerName HostnameGenerator `json:"computer_name"`
+	FullName     string            `json:"full_name"`
+	Organization string            `json:"organization"`
+	ProductKey   string            `json:"product_key"`
+}
+
+// GuiUnattended contains the Windows unattended installation settings.
+type GuiUnattended struct {
+	AutoLogon      bool   `json:"auto_logon"`
+	AutoLogonCount int    `json:"auto_logon_count"`
+	Password       string `json:"password,omitempty"`
+	TimeZone       int    `json:"time_zone"`
+}
+
+// DomainConfiguration describes the Windows workgroup or domain to join.
+type DomainConfiguration struct {
+	Type           string `json:"type"`
+	Workgroup      string `json:"workgroup,omitempty"`
+	Domain         string `json:"domain,omitempty"`
+	DomainUsername string `json:"domain_username,omitempty"`
+	DomainPassword string `json:"domain_password,omitempty"`
+}
+
+// WindowsSysprep contains the Windows sysprep settings.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_WindowsSysprep
+type WindowsSysprep struct {
+	GuiRunOnceCommands []string             `json:"gui_run_once_commands,omitempty"`
+	UserData           UserData             `json:"user_data"`
+	GuiUnattended      GuiUnattended        `json:"gui_unattended"`
+	Domain             *DomainConfiguration `json:"domain,omitempty"`
+}
+
+// WindowsConfiguration contains the Windows guest customization settings.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_WindowsConfiguration
+type WindowsConfiguration struct {
+	Reboot     string          `json:"reboot,omitempty"`
+	Sysprep    *WindowsSysprep `json:"sysprep,omitempty"`
+	SysprepXML string          `json:"sysprep_xml,omitempty"`
+}
+
+// ConfigurationSpec contains the guest operating system specific settings.
+// Exactly one of the fields is set.
+type ConfigurationSpec struct {
+	LinuxConfig   *LinuxConfiguration   `json:"linux_config,omitempty"`
+	WindowsConfig *WindowsConfiguration `json:"windows_config,omitempty"`
+}
+
+// GlobalDNSSettings contains the DNS settings applied to all network adapters.
+type GlobalDNSSettings struct {
+	DNSSuffixList []string `json:"dns_suffix_list,omitempty"`
+	DNSServers    []string `json:"dns_servers,omitempty"`
+}
+
+// Ipv4 contains the IPv4 settings of a network adapter.
+type Ipv4 struct {
+	Type      string   `json:"type"`
+	IPAddress string   `json:"ip_address,omitempty"`
+	Prefix    int      `json:"prefix,omitempty"`
+	Gateways  []string `json:"gateways,omitempty"`
+}
+
+// Ipv6Address is a static IPv6 address.
+type Ipv6Address struct {
+	IPAddress string `json:"ip_address"`
+	Prefix    int    `json:"prefix"`
+}
+
+// Ipv6 contains the IPv6 settings of a network adapter.
+type Ipv6 struct {
+	Type     string        `json:"type"`
+	Ipv6     []Ipv6Address `json:"ipv6,omitempty"`
+	Gateways []string      `json:"gateways,omitempty"`
+}
+
+// WindowsNetworkAdapterSettings contains the Windows specific settings of a network adapter.
+type WindowsNetworkAdapterSettings struct {
+	DNSServers  []string `json:"dns_servers,omitempty"`
+	DNSDomain   string   `json:"dns_domain,omitempty"`
+	WINSServers []string `json:"wins_servers,omitempty"`
+	NetBIOSMode string   `json:"net_BIOS_mode,omitempty"`
+}
+
+// IPSettings contains the settings of a network adapter.
+type IPSettings struct {
+	Ipv4    *Ipv4                          `json:"ipv4,omitempty"`
+	Ipv6    *Ipv6                          `json:"ipv6,omitempty"`
+	Windows *WindowsNetworkAdapterSettings `json:"windows,omitempty"`
+}
+
+// AdapterMapping associates IP settings with a network adapter.
+type AdapterMapping struct {
+	MacAddress string     `json:"mac_address,omitempty"`
+	Adapter    IPSettings `json:"adapter"`
+}
+
+// CustomizationSpec describes the guest customization to apply to a virtual machine.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_CustomizationSpec
+type CustomizationSpec struct {
+	ConfigurationSpec ConfigurationSpec `json:"configuration_spec"`
+	GlobalDNSSettings GlobalDNSSettings `json:"global_DNS_settings"`
+	Interfaces        []AdapterMapping  `json:"interfaces"`
+}
+
+// Summary contains commonly used information about a customization specification.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_CustomizationSpecs_Summary
+type Summary struct {
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	OSType       string    `json:"os_type"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Info contains information about a customization specification.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_CustomizationSpecs_Info
+type Info struct {
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Fingerprint  string            `json:"fingerprint"`
+	LastModified time.Time         `json:"last_modified"`
+	Spec         CustomizationSpec `json:"spec"`
+}
+
+// CreateSpec describes a customization specification to create.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_CustomizationSpecs_CreateSpec
+type CreateSpec struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Spec        CustomizationSpec `json:"spec"`
+}
+
+// Spec describes the new contents of an existing customization specification.
+// Fingerprint is required and must be the Info.Fingerprint returned by GetSpec,
+// the update fails if the specification was modified since it was read.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Guest_CustomizationSpecs_Spec
+type Spec struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Fingerprint string            `json:"fingerprint"`
+	Spec        CustomizationSpec `json:"spec"`
+}
+
+// FilterSpec contains properties used to filter the result of ListSpecs.
+type FilterSpec struct {
+	Names  []string
+	OSType string
+}
+
+// ListSpecs returns the customization specifications matching the given filter.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/guest/customization-specs/get
+func (c *Manager) ListSpecs(ctx context.Context, filter FilterSpec) ([]Summary, error) {
+	url := c.Resource(CustomizationSpecsPath)
+	for _, name := range filter.Names {
+		url.WithParam("names", name)
+	}
+	if filter.OSType != "" {
+		url.WithParam("OS_type", filter.OSType)
+	}
+	var res []Summary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateSpec creates a customization specification.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/guest/customization-specs/post
+func (c *Manager) CreateSpec(ctx context.Context, spec CreateSpec) error {
+	url := c.Resource(CustomizationSpecsPath)
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
+
+// GetSpec returns the customization specification with the given name.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/guest/customization-specs/name/get
+func (c *Manager) GetSpec(ctx context.Context, name string) (*Info, error) {
+	url := c.Resource(CustomizationSpecsPath).WithSubpath(name)
+	var res Info
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// SetSpec updates the customization specification with the given name.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/guest/customization-specs/name/put
+func (c *Manager) SetSpec(ctx context.Context, name string, spec Spec) error {
+	url := c.Resource(CustomizationSpecsPath).WithSubpath(name)
+	return c.Do(ctx, url.Request(http.MethodPut, spec), nil)
+}
+
+// DeleteSpec deletes the customization specification with the given name.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/guest/customization-specs/name/delete
+func (c *Manager) DeleteSpec(ctx context.Context, name string) error {
+	url := c.Resource(CustomizationSpecsPath).WithSubpath(name)
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// ExportSpec returns the customization specification with the given name, encoded in the given format.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/guest/customization-specs/name__action=export/post
+func (c *Manager) ExportSpec(ctx context.Context, name string, format string) (string, error) {
+	url := c.Resource(CustomizationSpecsPath).WithSubpath(name).WithParam("action", "export")
+	spec := struct {
+		Format string `json:"format"`
+	}{format}
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// ImportSpec decodes a customization specification in the format returned by ExportSpec.
+// The result can be passed to CreateSpec.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/guest/customization-specs__action=import/post
+func (c *Manager) ImportSpec(ctx context.Context, data string) (*CreateSpec, error) {
+	url := c.Resource(CustomizationSpecsPath).WithParam("action", "import")
+	spec := struct {
+		Spec string `json:"spec"`
+	}{data}
+	var res CreateSpec
+	return &res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
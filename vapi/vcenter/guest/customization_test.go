@@ -0,0 +1,146 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package guest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vapi/vcenter/guest/simulator"
+)
+
+func TestCustomizationSpecs(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := guest.NewManager(c)
+
+		specs, err := m.ListSpecs(ctx, guest.FilterSpec{})
+		require.NoError(t, err)
+		assert.Len(t, specs, len(simulator.DefaultCustomizationSpec))
+
+		specs, err = m.ListSpecs(ctx, guest.FilterSpec{OSType: guest.OSTypeWindows})
+		require.NoError(t, err)
+		require.Len(t, specs, 2)
+		for _, spec := range specs {
+			info, err := m.GetSpec(ctx, spec.Name)
+			require.NoError(t, err)
+			require.NotNil(t, info.Spec.ConfigurationSpec.WindowsConfig)
+			assert.NotNil(t, info.Spec.ConfigurationSpec.WindowsConfig.Sysprep)
+		}
+
+		info, err := m.GetSpec(ctx, "vcsim-linux")
+		require.NoError(t, err)
+		require.NotNil(t, info.Spec.ConfigurationSpec.LinuxConfig)
+		assert.Equal(t, "eng.vmware.com", info.Spec.ConfigurationSpec.LinuxConfig.Domain)
+		assert.Equal(t, guest.HostnameVirtualMachine, info.Spec.ConfigurationSpec.LinuxConfig.Hostname.Type)
+
+		_, err = m.GetSpec(ctx, "enoent")
+		assert.True(t, rest.IsStatusError(err, http.StatusNotFound))
+
+		spec := guest.CreateSpec{
+			Name:        "rest-linux",
+			Description: "created via REST",
+			Spec: guest.CustomizationSpec{
+				ConfigurationSpec: guest.ConfigurationSpec{
+					LinuxConfig: &guest.LinuxConfiguration{
+						Hostname: &guest.HostnameGenerator{Type: guest.HostnameFixed, FixedName: "rest"},
+						Domain:   "example.com",
+					},
+				},
+				GlobalDNSSettings: guest.GlobalDNSSettings{DNSServers: []string{"10.0.0.1"}},
+				Interfaces: []guest.AdapterMapping{{
+					Adapter: guest.IPSettings{
+						Ipv4: &guest.Ipv4{Type: guest.IPStatic, IPAddress: "10.0.0.10", Prefix: 24, Gateways: []string{"10.0.0.1"}},
+					},
+				}},
+			},
+		}
+
+		require.NoError(t, m.CreateSpec(ctx, spec))
+		assert.ErrorContains(t, m.CreateSpec(ctx, spec), "ALREADY_EXISTS")
+
+		invalid := spec
+		invalid.Name = "invalid"
+		invalid.Spec.ConfigurationSpec = guest.ConfigurationSpec{}
+		assert.ErrorContains(t, m.CreateSpec(ctx, invalid), "INVALID_ARGUMENT")
+
+		// the spec is visible via the SOAP API
+		item, err := object.NewCustomizationSpecManager(vc).GetCustomizationSpec(ctx, "rest-linux")
+		require.NoError(t, err)
+		assert.Equal(t, "Linux", item.Info.Type)
+		assert.Equal(t, "255.255.255.0", item.Spec.NicSettingMap[0].Adapter.SubnetMask)
+		assert.Equal(t, "rest", item.Spec.Identity.(*types.CustomizationLinuxPrep).HostName.(*types.CustomizationFixedName).Name)
+
+		info, err = m.GetSpec(ctx, "rest-linux")
+		require.NoError(t, err)
+		assert.Equal(t, spec.Description, info.Description)
+		assert.Equal(t, spec.Spec.Interfaces, info.Spec.Interfaces)
+		assert.NotEmpty(t, info.Fingerprint)
+
+		// update without a fingerprint fails
+		update := guest.Spec{
+			Name:        info.Name,
+			Description: "updated",
+			Spec:        info.Spec,
+		}
+		assert.ErrorContains(t, m.SetSpec(ctx, info.Name, update), "INVALID_ARGUMENT")
+
+		// update with a stale fingerprint fails
+		update.Fingerprint = info.Fingerprint
+		require.NoError(t, m.SetSpec(ctx, info.Name, update))
+		assert.ErrorContains(t, m.SetSpec(ctx, info.Name, update), "CONCURRENT_CHANGE")
+
+		info, err = m.GetSpec(ctx, "rest-linux")
+		require.NoError(t, err)
+		assert.Equal(t, "updated", info.Description)
+
+		// export and import round trip
+		for _, format := range []string{guest.FormatJSON, guest.FormatXML} {
+			data, err := m.ExportSpec(ctx, "rest-linux", format)
+			require.NoError(t, err)
+			if format == guest.FormatXML {
+				assert.True(t, strings.HasPrefix(data, "<?xml"))
+			}
+
+			imported, err := m.ImportSpec(ctx, data)
+			require.NoError(t, err)
+			assert.Equal(t, "rest-linux", imported.Name)
+			assert.Equal(t, "updated", imported.Description)
+			assert.Equal(t, info.Spec, imported.Spec)
+
+			imported.Name = "rest-linux-" + strings.ToLower(format)
+			require.NoError(t, m.CreateSpec(ctx, *imported))
+		}
+
+		_, err = m.ExportSpec(ctx, "rest-linux", "YAML")
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		_, err = m.ImportSpec(ctx, "{invalid")
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		specs, err = m.ListSpecs(ctx, guest.FilterSpec{Names: []string{"rest-linux", "rest-linux-xml"}})
+		require.NoError(t, err)
+		assert.Len(t, specs, 2)
+
+		require.NoError(t, m.DeleteSpec(ctx, "rest-linux"))
+		err = m.DeleteSpec(ctx, "rest-linux")
+		assert.True(t, rest.IsStatusError(err, http.StatusNotFound))
+	})
+}
@@ -0,0 +1,317 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func init() {
+	simulator.RegisterEndpoint(func(s *simulator.Service, r *simulator.Registry) {
+		New(s.Listen).Register(s, r)
+	})
+}
+
+// Handler implements the guest customization specification API simulator.
+// Specifications are stored by the SOAP CustomizationSpecManager.
+type Handler struct {
+	URL *url.URL
+}
+
+// New creates a Handler instance
+func New(u *url.URL) *Handler {
+	return &Handler{
+		URL: u,
+	}
+}
+
+// Register guest customization specification API paths with the vapi simulator's http.ServeMux
+func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
+	if r.IsVPX() {
+		s.HandleFunc(guest.CustomizationSpecsPath, h.specs)
+		s.HandleFunc(guest.CustomizationSpecsPath+"/", h.spec)
+	}
+}
+
+// withManager invokes f with the SOAP CustomizationSpecManager, translating faults to REST errors.
+func (h *Handler) withManager(w http.ResponseWriter, r *http.Request, f func(context.Context, *object.CustomizationSpecManager) error) bool {
+	err := vapi.WithClient(*h.URL, func(ctx context.Context, c *vim25.Client) error {
+		return f(ctx, object.NewCustomizationSpecManager(c))
+	})
+	if err == nil {
+		return true
+	}
+
+	log.Printf("%s %s: %s", r.Method, r.RequestURI, err)
+
+	switch {
+	case fault.Is(err, &types.NotFound{}):
+		vapi.ApiErrorNotFound(w)
+	case fault.Is(err, &types.AlreadyExists{}):
+		vapi.ApiErrorAlreadyExists(w)
+	case fault.Is(err, &types.ConcurrentAccess{}):
+		vapi.ApiErrorConcurrentChange(w)
+	case fault.Is(err, &types.CustomizationFault{}), fault.Is(err, &types.InvalidArgument{}):
+		vapi.ApiErrorInvalidArgument(w)
+	default:
+		vapi.ApiErrorGeneral(w)
+	}
+
+	return false
+}
+
+func info(item *types.CustomizationSpecItem) guest.Info {
+	var modified time.Time
+	if item.Info.LastUpdateTime != nil {
+		modified = *item.Info.LastUpdateTime
+	}
+
+	return guest.Info{
+		Name:         item.Info.Name,
+		Description:  item.Info.Description,
+		Fingerprint:  item.Info.ChangeVersion,
+		LastModified: modified,
+		Spec:         RestSpec(item.Spec),
+	}
+}
+
+// item converts the given REST spec to a CustomizationSpecItem, responding with INVALID_ARGUMENT on failure.
+func item(w http.ResponseWriter, name, description string, spec guest.CustomizationSpec) (*types.CustomizationSpecItem, bool) {
+	if name == "" {
+		vapi.ApiErrorInvalidArgument(w)
+		return nil, false
+	}
+
+	vspec, kind, err := VimSpec(spec)
+	if err != nil {
+		log.Printf("customization spec %q: %s", name, err)
+		vapi.ApiErrorInvalidArgument(w)
+		return nil, false
+	}
+
+	return &types.CustomizationSpecItem{
+		Info: types.CustomizationSpecInfo{
+			Name:        name,
+			Description: description,
+			Type:        kind,
+		},
+		Spec: *vspec,
+	}, true
+}
+
+// path "/api/vcenter/guest/customization-specs"
+func (h *Handler) specs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		names := q["names"]
+		os := q.Get("OS_type")
+
+		res := []guest.Summary{}
+		ok := h.withManager(w, r, func(ctx context.Context, m *object.CustomizationSpecManager) error {
+			specs, err := m.Info(ctx)
+			if err != nil {
+				return err
+			}
+			for _, spec := range specs {
+				if len(names) != 0 && !slices.Contains(names, spec.Name) {
+					continue
+				}
+				if os != "" && os != osType(spec.Type) {
+					continue
+				}
+				summary := guest.Summary{
+					Name:        spec.Name,
+					Description: spec.Description,
+					OSType:      osType(spec.Type),
+				}
+				if spec.LastUpdateTime != nil {
+					summary.LastModified = *spec.LastUpdateTime
+				}
+				res = append(res, summary)
+			}
+			return nil
+		})
+		if ok {
+			vapi.StatusOK(w, res)
+		}
+	case http.MethodPost:
+		if r.URL.Query().Get("action") == "import" {
+			h.importSpec(w, r)
+			return
+		}
+
+		var spec guest.CreateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+
+		item, ok := item(w, spec.Name, spec.Description, spec.Spec)
+		if !ok {
+			return
+		}
+
+		ok = h.withManager(w, r, func(ctx context.Context, m *object.CustomizationSpecManager) error {
+			return m.CreateCustomizationSpec(ctx, *item)
+		})
+		if ok {
+			vapi.StatusOK(w)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) importSpec(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Spec string `json:"spec"`
+	}
+	if !vapi.Decode(r, w, &req) {
+		return
+	}
+
+	data := strings.TrimSpace(req.Spec)
+
+	if !strings.HasPrefix(data, "<") {
+		var spec guest.CreateSpec
+		if err := json.Unmarshal([]byte(data), &spec); err != nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		vapi.StatusOK(w, spec)
+		return
+	}
+
+	var spec guest.CreateSpec
+	ok := h.withManager(w, r, func(ctx context.Context, m *object.CustomizationSpecManager) error {
+		item, err := m.XmlToCustomizationSpecItem(ctx, data)
+		if err != nil {
+			return err
+		}
+		spec = guest.CreateSpec{
+			Name:        item.Info.Name,
+			Description: item.Info.Description,
+			Spec:        RestSpec(item.Spec),
+		}
+		return nil
+	})
+	if ok {
+		vapi.StatusOK(w, spec)
+	}
+}
+
+// path "/api/vcenter/guest/customization-specs/{name}"
+func (h *Handler) spec(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, guest.CustomizationSpecsPath+"/")
+	if name == "" || strings.Contains(name, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var res guest.Info
+		ok := h.withManager(w, r, func(ctx context.Context, m *object.CustomizationSpecManager) error {
+			item, err := m.GetCustomizationSpec(ctx, name)
+			if err != nil {
+				return err
+			}
+			res = info(item)
+			return nil
+		})
+		if ok {
+			vapi.StatusOK(w, res)
+		}
+	case http.MethodPut:
+		var spec guest.Spec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		if spec.Name != name || spec.Fingerprint == "" {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+
+		item, ok := item(w, spec.Name, spec.Description, spec.Spec)
+		if !ok {
+			return
+		}
+		item.Info.ChangeVersion = spec.Fingerprint
+
+		ok = h.withManager(w, r, func(ctx context.Context, m *object.CustomizationSpecManager) error {
+			return m.OverwriteCustomizationSpec(ctx, *item)
+		})
+		if ok {
+			vapi.StatusOK(w)
+		}
+	case http.MethodDelete:
+		ok := h.withManager(w, r, func(ctx context.Context, m *object.CustomizationSpecManager) error {
+			return m.DeleteCustomizationSpec(ctx, name)
+		})
+		if ok {
+			vapi.StatusOK(w)
+		}
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "export" {
+			http.NotFound(w, r)
+			return
+		}
+		h.exportSpec(w, r, name)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) exportSpec(w http.ResponseWriter, r *http.Request, name string) {
+	var req struct {
+		Format string `json:"format"`
+	}
+	if !vapi.Decode(r, w, &req) {
+		return
+	}
+	if req.Format != guest.FormatJSON && req.Format != guest.FormatXML {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	var res string
+	ok := h.withManager(w, r, func(ctx context.Context, m *object.CustomizationSpecManager) error {
+		item, err := m.GetCustomizationSpec(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if req.Format == guest.FormatXML {
+			res, err = m.CustomizationSpecItemToXml(ctx, *item)
+			return err
+		}
+
+		b, err := json.MarshalIndent(guest.CreateSpec{
+			Name:        item.Info.Name,
+			Description: item.Info.Description,
+			Spec:        RestSpec(item.Spec),
+		}, "", "  ")
+		res = string(b)
+		return err
+	})
+	if ok {
+		vapi.StatusOK(w, res)
+	}
+}
@@ -0,0 +1,400 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// The REST API and the SOAP CustomizationSpecManager describe the same specification in different shapes.
+// The functions in this file convert between the two, so that both APIs can share the SOAP inventory.
+
+var (
+	rebootOptions = map[string]types.CustomizationSysprepRebootOption{
+		"REBOOT":    types.CustomizationSysprepRebootOptionReboot,
+		"NO_REBOOT": types.CustomizationSysprepRebootOptionNoreboot,
+		"SHUTDOWN":  types.CustomizationSysprepRebootOptionShutdown,
+	}
+
+	netBIOSModes = map[string]types.CustomizationNetBIOSMode{
+		"USE_DHCP": types.CustomizationNetBIOSModeEnableNetBIOSViaDhcp,
+		"ENABLE":   types.CustomizationNetBIOSModeEnableNetBIOS,
+		"DISABLE":  types.CustomizationNetBIOSModeDisableNetBIOS,
+	}
+)
+
+func key[K comparable, V comparable](m map[K]V, val V) K {
+	var zero K
+	for k, v := range m {
+		if v == val {
+			return k
+		}
+	}
+	return zero
+}
+
+// osType returns the REST OS type of the given CustomizationSpecInfo.Type
+func osType(kind string) string {
+	if kind == "Windows" {
+		return guest.OSTypeWindows
+	}
+	return guest.OSTypeLinux
+}
+
+func vimName(name *guest.HostnameGenerator) (types.BaseCustomizationName, error) {
+	if name == nil {
+		return &types.CustomizationVirtualMachineName{}, nil
+	}
+
+	switch name.Type {
+	case guest.HostnameFixed:
+		return &types.CustomizationFixedName{Name: name.FixedName}, nil
+	case guest.HostnamePrefix:
+		return &types.CustomizationPrefixName{Base: name.Prefix}, nil
+	case guest.HostnameVirtualMachine:
+		return &types.CustomizationVirtualMachineName{}, nil
+	case guest.HostnameUserInputRequired:
+		return &types.CustomizationUnknownName{}, nil
+	default:
+		return nil, fmt.Errorf("invalid hostname type %q", name.Type)
+	}
+}
+
+func restName(name types.BaseCustomizationName) guest.HostnameGenerator {
+	switch name := name.(type) {
+	case *types.CustomizationFixedName:
+		return guest.HostnameGenerator{Type: guest.HostnameFixed, FixedName: name.Name}
+	case *types.CustomizationPrefixName:
+		return guest.HostnameGenerator{Type: guest.HostnamePrefix, Prefix: name.Base}
+	case *types.CustomizationVirtualMachineName:
+		return guest.HostnameGenerator{Type: guest.HostnameVirtualMachine}
+	default:
+		return guest.HostnameGenerator{Type: guest.HostnameUserInputRequired}
+	}
+}
+
+func vimPassword(password string) *types.CustomizationPassword {
+	if password == "" {
+		return nil
+	}
+	return &types.CustomizationPassword{Value: password, PlainText: true}
+}
+
+func restPassword(password *types.CustomizationPassword) string {
+	if password == nil || !password.PlainText {
+		return ""
+	}
+	return password.Value
+}
+
+func vimIdentity(spec guest.ConfigurationSpec) (types.BaseCustomizationIdentitySettings, types.BaseCustomizationOptions, string, error) {
+	switch {
+	case spec.LinuxConfig != nil && spec.WindowsConfig == nil:
+		c := spec.LinuxConfig
+		name, err := vimName(c.Hostname)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		prep := &types.CustomizationLinuxPrep{
+			HostName:   name,
+			Domain:     c.Domain,
+			TimeZone:   c.TimeZone,
+			ScriptText: c.ScriptText,
+		}
+		return prep, &types.CustomizationLinuxOptions{}, "Linux", nil
+	case spec.WindowsConfig != nil && spec.LinuxConfig == nil:
+		c := spec.WindowsConfig
+		options := &types.CustomizationWinOptions{
+			Reboot: types.CustomizationSysprepRebootOptionReboot,
+		}
+		if c.Reboot != "" {
+			reboot, ok := rebootOptions[c.Reboot]
+			if !ok {
+				return nil, nil, "", fmt.Errorf("invalid reboot option %q", c.Reboot)
+			}
+			options.Reboot = reboot
+		}
+
+		if c.SysprepXML != "" {
+			return &types.CustomizationSysprepText{Value: c.SysprepXML}, options, "Windows", nil
+		}
+		if c.Sysprep == nil {
+			return nil, nil, "", fmt.Errorf("one of sysprep or sysprep_xml must be set")
+		}
+
+		name, err := vimName(&c.Sysprep.UserData.ComputerName)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		prep := &types.CustomizationSysprep{
+			GuiUnattended: types.CustomizationGuiUnattended{
+				Password:       vimPassword(c.Sysprep.GuiUnattended.Password),
+				TimeZone:       int32(c.Sysprep.GuiUnattended.TimeZone),
+				AutoLogon:      c.Sysprep.GuiUnattended.AutoLogon,
+				AutoLogonCount: int32(c.Sysprep.GuiUnattended.AutoLogonCount),
+			},
+			UserData: types.CustomizationUserData{
+				FullName:     c.Sysprep.UserData.FullName,
+				OrgName:      c.Sysprep.UserData.Organization,
+				ComputerName: name,
+				ProductId:    c.Sysprep.UserData.ProductKey,
+			},
+		}
+
+		if len(c.Sysprep.GuiRunOnceCommands) != 0 {
+			prep.GuiRunOnce = &types.CustomizationGuiRunOnce{CommandList: c.Sysprep.GuiRunOnceCommands}
+		}
+
+		if d := c.Sysprep.Domain; d != nil {
+			switch d.Type {
+			case "WORKGROUP":
+				prep.Identification.JoinWorkgroup = d.Workgroup
+			case "DOMAIN":
+				prep.Identification.JoinDomain = d.Domain
+				prep.Identification.DomainAdmin = d.DomainUsername
+				prep.Identification.DomainAdminPassword = vimPassword(d.DomainPassword)
+			default:
+				return nil, nil, "", fmt.Errorf("invalid domain type %q", d.Type)
+			}
+		}
+
+		return prep, options, "Windows", nil
+	default:
+		return nil, nil, "", fmt.Errorf("one of linux_config or windows_config must be set")
+	}
+}
+
+func restConfiguration(spec types.CustomizationSpec) guest.ConfigurationSpec {
+	var reboot string
+	if options, ok := spec.Options.(*types.CustomizationWinOptions); ok {
+		reboot = key(rebootOptions, options.Reboot)
+	}
+
+	switch id := spec.Identity.(type) {
+	case *types.CustomizationLinuxPrep:
+		name := restName(id.HostName)
+		return guest.ConfigurationSpec{
+			LinuxConfig: &guest.LinuxConfiguration{
+				Hostname:   &name,
+				Domain:     id.Domain,
+				TimeZone:   id.TimeZone,
+				ScriptText: id.ScriptText,
+			},
+		}
+	case *types.CustomizationSysprepText:
+		return guest.ConfigurationSpec{
+			WindowsConfig: &guest.WindowsConfiguration{
+				Reboot:     reboot,
+				SysprepXML: id.Value,
+			},
+		}
+	case *types.CustomizationSysprep:
+		sysprep := &guest.WindowsSysprep{
+			UserData: guest.UserData{
+				ComputerName: restName(id.UserData.ComputerName),
+				FullName:     id.UserData.FullName,
+				Organization: id.UserData.OrgName,
+				ProductKey:   id.UserData.ProductId,
+			},
+			GuiUnattended: guest.GuiUnattended{
+				AutoLogon:      id.GuiUnattended.AutoLogon,
+				AutoLogonCount: int(id.GuiUnattended.AutoLogonCount),
+				Password:       restPassword(id.GuiUnattended.Password),
+				TimeZone:       int(id.GuiUnattended.TimeZone),
+			},
+		}
+		if id.GuiRunOnce != nil {
+			sysprep.GuiRunOnceCommands = id.GuiRunOnce.CommandList
+		}
+		switch {
+		case id.Identification.JoinDomain != "":
+			sysprep.Domain = &guest.DomainConfiguration{
+				Type:           "DOMAIN",
+				Domain:         id.Identification.JoinDomain,
+				DomainUsername: id.Identification.DomainAdmin,
+				DomainPassword: restPassword(id.Identification.DomainAdminPassword),
+			}
+		case id.Identification.JoinWorkgroup != "":
+			sysprep.Domain = &guest.DomainConfiguration{
+				Type:      "WORKGROUP",
+				Workgroup: id.Identification.JoinWorkgroup,
+			}
+		}
+		return guest.ConfigurationSpec{
+			WindowsConfig: &guest.WindowsConfiguration{
+				Reboot:  reboot,
+				Sysprep: sysprep,
+			},
+		}
+	default:
+		return guest.ConfigurationSpec{}
+	}
+}
+
+func vimAdapter(spec guest.IPSettings) (types.CustomizationIPSettings, error) {
+	settings := types.CustomizationIPSettings{
+		Ip: &types.CustomizationDhcpIpGenerator{},
+	}
+
+	if ip := spec.Ipv4; ip != nil {
+		switch ip.Type {
+		case guest.IPDHCP:
+		case guest.IPStatic:
+			if net.ParseIP(ip.IPAddress).To4() == nil || ip.Prefix < 0 || ip.Prefix > 32 {
+				return settings, fmt.Errorf("invalid ipv4 address %s/%d", ip.IPAddress, ip.Prefix)
+			}
+			settings.Ip = &types.CustomizationFixedIp{IpAddress: ip.IPAddress}
+			settings.SubnetMask = net.IP(net.CIDRMask(ip.Prefix, 32)).String()
+		case guest.IPUserInputRequired:
+			settings.Ip = &types.CustomizationUnknownIpGenerator{}
+		default:
+			return settings, fmt.Errorf("invalid ipv4 type %q", ip.Type)
+		}
+		settings.Gateway = ip.Gateways
+	}
+
+	if ip := spec.Ipv6; ip != nil {
+		settings.IpV6Spec = &types.CustomizationIPSettingsIpV6AddressSpec{
+			Gateway: ip.Gateways,
+		}
+		switch ip.Type {
+		case guest.IPDHCP:
+			settings.IpV6Spec.Ip = []types.BaseCustomizationIpV6Generator{&types.CustomizationDhcpIpV6Generator{}}
+		case guest.IPStatic:
+			for _, addr := range ip.Ipv6 {
+				settings.IpV6Spec.Ip = append(settings.IpV6Spec.Ip, &types.CustomizationFixedIpV6{
+					IpAddress:  addr.IPAddress,
+					SubnetMask: int32(addr.Prefix),
+				})
+			}
+		case guest.IPUserInputRequired:
+			settings.IpV6Spec.Ip = []types.BaseCustomizationIpV6Generator{&types.CustomizationUnknownIpV6Generator{}}
+		default:
+			return settings, fmt.Errorf("invalid ipv6 type %q", ip.Type)
+		}
+	}
+
+	if w := spec.Windows; w != nil {
+		settings.DnsServerList = w.DNSServers
+		settings.DnsDomain = w.DNSDomain
+		if len(w.WINSServers) > 0 {
+			settings.PrimaryWINS = w.WINSServers[0]
+		}
+		if len(w.WINSServers) > 1 {
+			settings.SecondaryWINS = w.WINSServers[1]
+		}
+		if w.NetBIOSMode != "" {
+			mode, ok := netBIOSModes[w.NetBIOSMode]
+			if !ok {
+				return settings, fmt.Errorf("invalid net_BIOS_mode %q", w.NetBIOSMode)
+			}
+			settings.NetBIOS = mode
+		}
+	}
+
+	return settings, nil
+}
+
+func restAdapter(settings types.CustomizationIPSettings) guest.IPSettings {
+	var spec guest.IPSettings
+
+	ipv4 := &guest.Ipv4{Type: guest.IPDHCP, Gateways: settings.Gateway}
+	switch ip := settings.Ip.(type) {
+	case *types.CustomizationFixedIp:
+		ipv4.Type = guest.IPStatic
+		ipv4.IPAddress = ip.IpAddress
+		ipv4.Prefix, _ = net.IPMask(net.ParseIP(settings.SubnetMask).To4()).Size()
+	case *types.CustomizationUnknownIpGenerator:
+		ipv4.Type = guest.IPUserInputRequired
+	}
+	spec.Ipv4 = ipv4
+
+	if v6 := settings.IpV6Spec; v6 != nil {
+		ipv6 := &guest.Ipv6{Type: guest.IPDHCP, Gateways: v6.Gateway}
+		for _, ip := range v6.Ip {
+			switch ip := ip.(type) {
+			case *types.CustomizationFixedIpV6:
+				ipv6.Type = guest.IPStatic
+				ipv6.Ipv6 = append(ipv6.Ipv6, guest.Ipv6Address{IPAddress: ip.IpAddress, Prefix: int(ip.SubnetMask)})
+			case *types.CustomizationUnknownIpV6Generator:
+				ipv6.Type = guest.IPUserInputRequired
+			}
+		}
+		spec.Ipv6 = ipv6
+	}
+
+	if len(settings.DnsServerList) != 0 || settings.DnsDomain != "" || settings.PrimaryWINS != "" || settings.NetBIOS != "" {
+		w := &guest.WindowsNetworkAdapterSettings{
+			DNSServers:  settings.DnsServerList,
+			DNSDomain:   settings.DnsDomain,
+			NetBIOSMode: key(netBIOSModes, settings.NetBIOS),
+		}
+		for _, wins := range []string{settings.PrimaryWINS, settings.SecondaryWINS} {
+			if wins != "" {
+				w.WINSServers = append(w.WINSServers, wins)
+			}
+		}
+		spec.Windows = w
+	}
+
+	return spec
+}
+
+// VimSpec converts a REST customization specification to a vim25 customization specification.
+// The returned string is the CustomizationSpecInfo.Type of the specification.
+func VimSpec(spec guest.CustomizationSpec) (*types.CustomizationSpec, string, error) {
+	identity, options, kind, err := vimIdentity(spec.ConfigurationSpec)
+	if err != nil {
+		return nil, "", err
+	}
+
+	res := &types.CustomizationSpec{
+		Options:  options,
+		Identity: identity,
+		GlobalIPSettings: types.CustomizationGlobalIPSettings{
+			DnsSuffixList: spec.GlobalDNSSettings.DNSSuffixList,
+			DnsServerList: spec.GlobalDNSSettings.DNSServers,
+		},
+	}
+
+	for _, nic := range spec.Interfaces {
+		adapter, err := vimAdapter(nic.Adapter)
+		if err != nil {
+			return nil, "", err
+		}
+		res.NicSettingMap = append(res.NicSettingMap, types.CustomizationAdapterMapping{
+			MacAddress: nic.MacAddress,
+			Adapter:    adapter,
+		})
+	}
+
+	return res, kind, nil
+}
+
+// RestSpec converts a vim25 customization specification to a REST customization specification.
+func RestSpec(spec types.CustomizationSpec) guest.CustomizationSpec {
+	res := guest.CustomizationSpec{
+		ConfigurationSpec: restConfiguration(spec),
+		GlobalDNSSettings: guest.GlobalDNSSettings{
+			DNSSuffixList: spec.GlobalIPSettings.DnsSuffixList,
+			DNSServers:    spec.GlobalIPSettings.DnsServerList,
+		},
+		Interfaces: []guest.AdapterMapping{},
+	}
+
+	for _, nic := range spec.NicSettingMap {
+		res.Interfaces = append(res.Interfaces, guest.AdapterMapping{
+			MacAddress: nic.MacAddress,
+			Adapter:    restAdapter(nic.Adapter),
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,267 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package identity
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	// ProvidersPath is the REST endpoint for the vCenter identity providers API
+	ProvidersPath = "/api/vcenter/identity/providers"
+)
+
+// Provider configuration types
+const (
+	ConfigOauth2 = "Oauth2"
+	ConfigOidc   = "Oidc"
+)
+
+// Identity management protocols used to query users and groups of a provider
+const (
+	ProtocolREST    = "REST"
+	ProtocolSCIM    = "SCIM"
+	ProtocolSCIM2   = "SCIM2_0"
+	ProtocolLDAP    = "LDAP"
+	ProtocolDefault = ""
+)
+
+// Client authentication methods of an Oauth2 provider
+const (
+	ClientSecretBasic = "CLIENT_SECRET_BASIC"
+	ClientSecretPost  = "CLIENT_SECRET_POST"
+	ClientSecretJWT   = "CLIENT_SECRET_JWT"
+	PrivateKeyJWT     = "PRIVATE_KEY_JWT"
+)
+
+// Manager extends rest.Client, adding vCenter identity provider related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// ClaimMap maps token claim names and values to vCenter groups.
+// For example, {"groups": {"admins": ["Administrators@vsphere.local"]}}
+// grants members of the "admins" group claim membership of the vCenter "Administrators" group.
+type ClaimMap map[string]map[string][]string
+
+// Oauth2Summary contains commonly used information about an Oauth2 provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_Oauth2Summary
+type Oauth2Summary struct {
+	AuthEndpoint         string              `json:"auth_endpoint"`
+	TokenEndpoint        string              `json:"token_endpoint"`
+	ClientID             string              `json:"client_id"`
+	AuthenticationHeader string              `json:"authentication_header"`
+	AuthQueryParams      map[string][]string `json:"auth_query_params,omitempty"`
+}
+
+// OidcSummary contains commonly used information about an OIDC provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_OidcSummary
+type OidcSummary struct {
+	DiscoveryEndpoint    string              `json:"discovery_endpoint,omitempty"`
+	LogoutEndpoint       string              `json:"logout_endpoint,omitempty"`
+	AuthEndpoint         string              `json:"auth_endpoint"`
+	TokenEndpoint        string              `json:"token_endpoint"`
+	ClientID             string              `json:"client_id"`
+	AuthenticationHeader string              `json:"authentication_header"`
+	AuthQueryParams      map[string][]string `json:"auth_query_params,omitempty"`
+}
+
+// Summary contains commonly used information about an identity provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_Summary
+type Summary struct {
+	Provider        string              `json:"provider"`
+	Name            string              `json:"name,omitempty"`
+	ConfigTag       string              `json:"config_tag"`
+	Oauth2          *Oauth2Summary      `json:"oauth2,omitempty"`
+	Oidc            *OidcSummary        `json:"oidc,omitempty"`
+	IsDefault       bool                `json:"is_default"`
+	DomainNames     []string            `json:"domain_names,omitempty"`
+	AuthQueryParams map[string][]string `json:"auth_query_params,omitempty"`
+}
+
+// Oauth2Info contains the configuration of an Oauth2 provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_Oauth2Info
+type Oauth2Info struct {
+	AuthEndpoint         string              `json:"auth_endpoint"`
+	TokenEndpoint        string              `json:"token_endpoint"`
+	PublicKeyURI         string              `json:"public_key_uri"`
+	ClientID             string              `json:"client_id"`
+	ClientSecret         string              `json:"client_secret,omitempty"`
+	ClaimMap             ClaimMap            `json:"claim_map"`
+	Issuer               string              `json:"issuer"`
+	AuthenticationMethod string              `json:"authentication_method"`
+	AuthQueryParams      map[string][]string `json:"auth_query_params,omitempty"`
+}
+
+// OidcInfo contains the configuration of an OIDC provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_OidcInfo
+type OidcInfo struct {
+	DiscoveryEndpoint    string              `json:"discovery_endpoint"`
+	LogoutEndpoint       string              `json:"logout_endpoint,omitempty"`
+	AuthEndpoint         string              `json:"auth_endpoint"`
+	TokenEndpoint        string              `json:"token_endpoint"`
+	PublicKeyURI         string              `json:"public_key_uri"`
+	ClientID             string              `json:"client_id"`
+	ClientSecret         string              `json:"client_secret,omitempty"`
+	ClaimMap             ClaimMap            `json:"claim_map"`
+	Issuer               string              `json:"issuer"`
+	AuthenticationMethod string              `json:"authentication_method"`
+	AuthQueryParams      map[string][]string `json:"auth_query_params,omitempty"`
+}
+
+// Info contains the configuration of an identity provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_Info
+type Info struct {
+	Name            string              `json:"name,omitempty"`
+	OrgIDs          []string            `json:"org_ids"`
+	ConfigTag       string              `json:"config_tag"`
+	Oauth2          *Oauth2Info         `json:"oauth2,omitempty"`
+	Oidc            *OidcInfo           `json:"oidc,omitempty"`
+	IsDefault       bool                `json:"is_default"`
+	DomainNames     []string            `json:"domain_names,omitempty"`
+	AuthQueryParams map[string][]string `json:"auth_query_params,omitempty"`
+	IdmProtocol     string              `json:"idm_protocol,omitempty"`
+	IdmEndpoints    []string            `json:"idm_endpoints,omitempty"`
+	UpnClaim        string              `json:"upn_claim,omitempty"`
+	GroupsClaim     string              `json:"groups_claim,omitempty"`
+}
+
+// Oauth2CreateSpec describes an Oauth2 provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_Oauth2CreateSpec
+type Oauth2CreateSpec struct {
+	AuthEndpoint         string              `json:"auth_endpoint"`
+	TokenEndpoint        string              `json:"token_endpoint"`
+	PublicKeyURI         string              `json:"public_key_uri"`
+	ClientID             string              `json:"client_id"`
+	ClientSecret         string              `json:"client_secret"`
+	ClaimMap             ClaimMap            `json:"claim_map"`
+	Issuer               string              `json:"issuer"`
+	AuthenticationMethod string              `json:"authentication_method"`
+	AuthQueryParams      map[string][]string `json:"auth_query_params,omitempty"`
+}
+
+// OidcCreateSpec describes an OIDC provider, the endpoints of which are read from the discovery endpoint.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_OidcCreateSpec
+type OidcCreateSpec struct {
+	DiscoveryEndpoint string   `json:"discovery_endpoint"`
+	LogoutEndpoint    string   `json:"logout_endpoint,omitempty"`
+	ClientID          string   `json:"client_id"`
+	ClientSecret      string   `json:"client_secret"`
+	ClaimMap          ClaimMap `json:"claim_map"`
+}
+
+// CreateSpec describes an identity provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_CreateSpec
+type CreateSpec struct {
+	ConfigTag       string              `json:"config_tag"`
+	Oauth2          *Oauth2CreateSpec   `json:"oauth2,omitempty"`
+	Oidc            *OidcCreateSpec     `json:"oidc,omitempty"`
+	OrgIDs          []string            `json:"org_ids,omitempty"`
+	IsDefault       bool                `json:"is_default,omitempty"`
+	Name            string              `json:"name,omitempty"`
+	DomainNames     []string            `json:"domain_names,omitempty"`
+	AuthQueryParams map[string][]string `json:"auth_query_params,omitempty"`
+	IdmProtocol     string              `json:"idm_protocol,omitempty"`
+	IdmEndpoints    []string            `json:"idm_endpoints,omitempty"`
+	UpnClaim        string              `json:"upn_claim,omitempty"`
+	GroupsClaim     string              `json:"groups_claim,omitempty"`
+}
+
+// Oauth2UpdateSpec describes changes to an Oauth2 provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_Oauth2UpdateSpec
+type Oauth2UpdateSpec struct {
+	AuthEndpoint         string              `json:"auth_endpoint,omitempty"`
+	TokenEndpoint        string              `json:"token_endpoint,omitempty"`
+	PublicKeyURI         string              `json:"public_key_uri,omitempty"`
+	ClientID             string              `json:"client_id,omitempty"`
+	ClientSecret         string              `json:"client_secret,omitempty"`
+	ClaimMap             ClaimMap            `json:"claim_map,omitempty"`
+	Issuer               string              `json:"issuer,omitempty"`
+	AuthenticationMethod string              `json:"authentication_method,omitempty"`
+	AuthQueryParams      map[string][]string `json:"auth_query_params,omitempty"`
+}
+
+// OidcUpdateSpec describes changes to an OIDC provider.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_OidcUpdateSpec
+type OidcUpdateSpec struct {
+	DiscoveryEndpoint string   `json:"discovery_endpoint,omitempty"`
+	LogoutEndpoint    string   `json:"logout_endpoint,omitempty"`
+	ClientID          string   `json:"client_id,omitempty"`
+	ClientSecret      string   `json:"client_secret,omitempty"`
+	ClaimMap          ClaimMap `json:"claim_map,omitempty"`
+}
+
+// UpdateSpec describes changes to an identity provider, unset fields are not changed.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/data-structures/Identity_Providers_UpdateSpec
+type UpdateSpec struct {
+	ConfigTag        string              `json:"config_tag"`
+	Oauth2           *Oauth2UpdateSpec   `json:"oauth2,omitempty"`
+	Oidc             *OidcUpdateSpec     `json:"oidc,omitempty"`
+	OrgIDs           []string            `json:"org_ids,omitempty"`
+	MakeDefault      bool                `json:"make_default,omitempty"`
+	Name             string              `json:"name,omitempty"`
+	DomainNames      []string            `json:"domain_names,omitempty"`
+	AuthQueryParams  map[string][]string `json:"auth_query_params,omitempty"`
+	IdmProtocol      string              `json:"idm_protocol,omitempty"`
+	IdmEndpoints     []string            `json:"idm_endpoints,omitempty"`
+	UpnClaim         string              `json:"upn_claim,omitempty"`
+	ResetUpnClaim    bool                `json:"reset_upn_claim,omitempty"`
+	GroupsClaim      string              `json:"groups_claim,omitempty"`
+	ResetGroupsClaim bool                `json:"reset_groups_claim,omitempty"`
+}
+
+// ListProviders returns a summary of the identity providers.
+func (m *Manager) ListProviders(ctx context.Context) ([]Summary, error) {
+	r := m.Resource(ProvidersPath)
+
+	var res []Summary
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return res, err
+}
+
+// GetProvider returns the configuration of the given identity provider.
+func (m *Manager) GetProvider(ctx context.Context, id string) (*Info, error) {
+	r := m.Resource(ProvidersPath).WithSubpath(id)
+
+	var res Info
+	err := m.Do(ctx, r.Request(http.MethodGet), &res)
+
+	return &res, err
+}
+
+// CreateProvider creates an identity provider, returning its ID.
+func (m *Manager) CreateProvider(ctx context.Context, spec CreateSpec) (string, error) {
+	r := m.Resource(ProvidersPath)
+
+	var res string
+	err := m.Do(ctx, r.Request(http.MethodPost, spec), &res)
+
+	return res, err
+}
+
+// UpdateProvider updates the given identity provider.
+func (m *Manager) UpdateProvider(ctx context.Context, id string, spec UpdateSpec) error {
+	r := m.Resource(ProvidersPath).WithSubpath(id)
+
+	return m.Do(ctx, r.Request(http.MethodPatch, spec), nil)
+}
+
+// DeleteProvider deletes the given identity provider.
+func (m *Manager) DeleteProvider(ctx context.Context, id string) error {
+	r := m.Resource(ProvidersPath).WithSubpath(id)
+
+	return m.Do(ctx, r.Request(http.MethodDelete), nil)
+}
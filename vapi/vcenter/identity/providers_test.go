@@ -0,0 +1,131 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package identity_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vapi/vcenter/identity/simulator"
+)
+
+func TestProviders(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := identity.NewManager(c)
+
+		providers, err := m.ListProviders(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, providers)
+
+		claims := identity.ClaimMap{
+			"groups": {"vc-admins": {"Administrators@vsphere.local"}},
+		}
+
+		oidc, err := m.CreateProvider(ctx, identity.CreateSpec{
+			ConfigTag: identity.ConfigOidc,
+			Name:      "okta",
+			IsDefault: true,
+			Oidc: &identity.OidcCreateSpec{
+				DiscoveryEndpoint: "https://idp.example.com/.well-known/openid-configuration",
+				ClientID:          "vcenter",
+				ClientSecret:      "secret",
+				ClaimMap:          claims,
+			},
+			DomainNames: []string{"example.com"},
+			UpnClaim:    "email",
+			GroupsClaim: "groups",
+		})
+		require.NoError(t, err)
+
+		info, err := m.GetProvider(ctx, oidc)
+		require.NoError(t, err)
+		assert.True(t, info.IsDefault)
+		assert.Equal(t, "https://idp.example.com", info.Oidc.Issuer)
+		assert.Equal(t, "https://idp.example.com/oauth2/token", info.Oidc.TokenEndpoint)
+		assert.Equal(t, claims, info.Oidc.ClaimMap)
+		assert.Empty(t, info.Oidc.ClientSecret)
+
+		// config_tag must match the spec
+		_, err = m.CreateProvider(ctx, identity.CreateSpec{
+			ConfigTag: identity.ConfigOauth2,
+			Oidc:      &identity.OidcCreateSpec{DiscoveryEndpoint: "https://idp.example.com", ClientID: "vcenter"},
+		})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		spec := identity.CreateSpec{
+			ConfigTag: identity.ConfigOauth2,
+			Name:      "adfs",
+			IsDefault: true,
+			Oauth2: &identity.Oauth2CreateSpec{
+				AuthEndpoint:         "https://adfs.example.com/adfs/oauth2/authorize",
+				TokenEndpoint:        "https://adfs.example.com/adfs/oauth2/token",
+				PublicKeyURI:         "https://adfs.example.com/adfs/discovery/keys",
+				ClientID:             "vcenter",
+				ClientSecret:         "secret",
+				Issuer:               "https://adfs.example.com/adfs",
+				AuthenticationMethod: "INVALID",
+			},
+		}
+		_, err = m.CreateProvider(ctx, spec)
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		spec.Oauth2.AuthenticationMethod = identity.ClientSecretBasic
+		adfs, err := m.CreateProvider(ctx, spec)
+		require.NoError(t, err)
+
+		// only one provider is the default
+		providers, err = m.ListProviders(ctx)
+		require.NoError(t, err)
+		require.Len(t, providers, 2)
+		for _, p := range providers {
+			assert.Equal(t, p.Provider == adfs, p.IsDefault)
+			if p.Oauth2 != nil {
+				assert.Equal(t, "Basic dmNlbnRlcjpzZWNyZXQ=", p.Oauth2.AuthenticationHeader)
+			}
+		}
+
+		err = m.UpdateProvider(ctx, oidc, identity.UpdateSpec{
+			ConfigTag:     identity.ConfigOidc,
+			MakeDefault:   true,
+			ResetUpnClaim: true,
+			Oidc: &identity.OidcUpdateSpec{
+				ClaimMap: identity.ClaimMap{"groups": {"vc-users": {"Users@vsphere.local"}}},
+			},
+		})
+		require.NoError(t, err)
+
+		info, err = m.GetProvider(ctx, oidc)
+		require.NoError(t, err)
+		assert.True(t, info.IsDefault)
+		assert.Empty(t, info.UpnClaim)
+		assert.Equal(t, "groups", info.GroupsClaim)
+		assert.Contains(t, info.Oidc.ClaimMap["groups"], "vc-users")
+
+		info, err = m.GetProvider(ctx, adfs)
+		require.NoError(t, err)
+		assert.False(t, info.IsDefault)
+
+		err = m.UpdateProvider(ctx, adfs, identity.UpdateSpec{ConfigTag: identity.ConfigOidc})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		require.NoError(t, m.DeleteProvider(ctx, adfs))
+
+		_, err = m.GetProvider(ctx, adfs)
+		assert.True(t, rest.IsStatusError(err, http.StatusNotFound))
+	})
+}
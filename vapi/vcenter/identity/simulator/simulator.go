@@ -0,0 +1,445 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vapi/vcenter/identity"
+)
+
+func init() {
+	simulator.RegisterEndpoint(func(s *simulator.Service, r *simulator.Registry) {
+		New(s.Listen).Register(s, r)
+	})
+}
+
+// Handler implements the vCenter identity providers API simulator.
+// OIDC provider endpoints are derived from the discovery endpoint rather than fetched.
+type Handler struct {
+	URL *url.URL
+
+	mu        sync.Mutex
+	providers map[string]*identity.Info
+}
+
+// New creates a Handler instance
+func New(u *url.URL) *Handler {
+	return &Handler{
+		URL:       u,
+		providers: make(map[string]*identity.Info),
+	}
+}
+
+// Register identity provider API paths with the vapi simulator's http.ServeMux
+func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
+	if r.IsVPX() {
+		s.HandleFunc(identity.ProvidersPath, h.handleProviders)
+		s.HandleFunc(identity.ProvidersPath+"/", h.handleProvider)
+	}
+}
+
+var (
+	protocols = []string{
+		identity.ProtocolDefault,
+		identity.ProtocolREST,
+		identity.ProtocolSCIM,
+		identity.ProtocolSCIM2,
+		identity.ProtocolLDAP,
+	}
+
+	authMethods = []string{
+		identity.ClientSecretBasic,
+		identity.ClientSecretPost,
+		identity.ClientSecretJWT,
+		identity.PrivateKeyJWT,
+	}
+)
+
+func invalid(r *http.Request, w http.ResponseWriter, err error) {
+	log.Printf("%s %s: %s", r.Method, r.RequestURI, err)
+	vapi.ApiErrorInvalidArgument(w)
+}
+
+// validURL returns an error if s is not an absolute http(s) URL
+func validURL(field, s string) error {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid %s: %q", field, s)
+	}
+	return nil
+}
+
+// discover returns the OIDC provider configuration for the given discovery endpoint,
+// using the issuer base URL in place of fetching the discovery document.
+func discover(spec identity.OidcCreateSpec) (*identity.OidcInfo, error) {
+	if err := validURL("discovery_endpoint", spec.DiscoveryEndpoint); err != nil {
+		return nil, err
+	}
+	if spec.LogoutEndpoint != "" {
+		if err := validURL("logout_endpoint", spec.LogoutEndpoint); err != nil {
+			return nil, err
+		}
+	}
+	if spec.ClientID == "" {
+		return nil, errors.New("client_id is required")
+	}
+
+	issuer := strings.TrimSuffix(spec.DiscoveryEndpoint, "/.well-known/openid-configuration")
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	return &identity.OidcInfo{
+		DiscoveryEndpoint:    spec.DiscoveryEndpoint,
+		LogoutEndpoint:       spec.LogoutEndpoint,
+		AuthEndpoint:         issuer + "/oauth2/authorize",
+		TokenEndpoint:        issuer + "/oauth2/token",
+		PublicKeyURI:         issuer + "/oauth2/keys",
+		ClientID:             spec.ClientID,
+		ClientSecret:         spec.ClientSecret,
+		ClaimMap:             claims(spec.ClaimMap),
+		Issuer:               issuer,
+		AuthenticationMethod: identity.ClientSecretBasic,
+	}, nil
+}
+
+func validateOauth2(spec *identity.Oauth2Info) error {
+	for field, val := range map[string]string{
+		"auth_endpoint":  spec.AuthEndpoint,
+		"token_endpoint": spec.TokenEndpoint,
+		"public_key_uri": spec.PublicKeyURI,
+	} {
+		if err := validURL(field, val); err != nil {
+			return err
+		}
+	}
+	if spec.ClientID == "" {
+		return errors.New("client_id is required")
+	}
+	if spec.Issuer == "" {
+		return errors.New("issuer is required")
+	}
+	if !slices.Contains(authMethods, spec.AuthenticationMethod) {
+		return fmt.Errorf("invalid authentication_method: %q", spec.AuthenticationMethod)
+	}
+	return nil
+}
+
+func claims(m identity.ClaimMap) identity.ClaimMap {
+	if m == nil {
+		return identity.ClaimMap{}
+	}
+	return m
+}
+
+func validateIdm(protocol string, endpoints []string) error {
+	if !slices.Contains(protocols, protocol) {
+		return fmt.Errorf("invalid idm_protocol: %q", protocol)
+	}
+	for _, e := range endpoints {
+		if err := validURL("idm_endpoints", e); err != nil && protocol != identity.ProtocolLDAP {
+			return err
+		}
+	}
+	return nil
+}
+
+// create validates the given spec, returning the provider configuration
+func create(spec identity.CreateSpec) (*identity.Info, error) {
+	info := &identity.Info{
+		Name:            spec.Name,
+		OrgIDs:          spec.OrgIDs,
+		ConfigTag:       spec.ConfigTag,
+		IsDefault:       spec.IsDefault,
+		DomainNames:     spec.DomainNames,
+		AuthQueryParams: spec.AuthQueryParams,
+		IdmProtocol:     spec.IdmProtocol,
+		IdmEndpoints:    spec.IdmEndpoints,
+		UpnClaim:        spec.UpnClaim,
+		GroupsClaim:     spec.GroupsClaim,
+	}
+	if info.OrgIDs == nil {
+		info.OrgIDs = []string{}
+	}
+
+	switch spec.ConfigTag {
+	case identity.ConfigOauth2:
+		if spec.Oauth2 == nil || spec.Oidc != nil {
+			return nil, errors.New("oauth2 spec is required")
+		}
+		info.Oauth2 = &identity.Oauth2Info{
+			AuthEndpoint:         spec.Oauth2.AuthEndpoint,
+			TokenEndpoint:        spec.Oauth2.TokenEndpoint,
+			PublicKeyURI:         spec.Oauth2.PublicKeyURI,
+			ClientID:             spec.Oauth2.ClientID,
+			ClientSecret:         spec.Oauth2.ClientSecret,
+			ClaimMap:             claims(spec.Oauth2.ClaimMap),
+			Issuer:               spec.Oauth2.Issuer,
+			AuthenticationMethod: spec.Oauth2.AuthenticationMethod,
+			AuthQueryParams:      spec.Oauth2.AuthQueryParams,
+		}
+		if err := validateOauth2(info.Oauth2); err != nil {
+			return nil, err
+		}
+	case identity.ConfigOidc:
+		if spec.Oidc == nil || spec.Oauth2 != nil {
+			return nil, errors.New("oidc spec is required")
+		}
+		oidc, err := discover(*spec.Oidc)
+		if err != nil {
+			return nil, err
+		}
+		info.Oidc = oidc
+	default:
+		return nil, fmt.Errorf("invalid config_tag: %q", spec.ConfigTag)
+	}
+
+	return info, validateIdm(info.IdmProtocol, info.IdmEndpoints)
+}
+
+// update applies the given spec to a copy of the provider configuration
+func update(current *identity.Info, spec identity.UpdateSpec) (*identity.Info, error) {
+	if spec.ConfigTag != current.ConfigTag {
+		return nil, fmt.Errorf("config_tag %q does not match %q", spec.ConfigTag, current.ConfigTag)
+	}
+
+	info := *current
+
+	switch info.ConfigTag {
+	case identity.ConfigOauth2:
+		if spec.Oidc != nil {
+			return nil, errors.New("oidc spec is not applicable")
+		}
+		if s := spec.Oauth2; s != nil {
+			oauth2 := *info.Oauth2
+			set(&oauth2.AuthEndpoint, s.AuthEndpoint)
+			set(&oauth2.TokenEndpoint, s.TokenEndpoint)
+			set(&oauth2.PublicKeyURI, s.PublicKeyURI)
+			set(&oauth2.ClientID, s.ClientID)
+			set(&oauth2.ClientSecret, s.ClientSecret)
+			set(&oauth2.Issuer, s.Issuer)
+			set(&oauth2.AuthenticationMethod, s.AuthenticationMethod)
+			if s.ClaimMap != nil {
+				oauth2.ClaimMap = s.ClaimMap
+			}
+			if s.AuthQueryParams != nil {
+				oauth2.AuthQueryParams = s.AuthQueryParams
+			}
+			if err := validateOauth2(&oauth2); err != nil {
+				return nil, err
+			}
+			info.Oauth2 = &oauth2
+		}
+	case identity.ConfigOidc:
+		if spec.Oauth2 != nil {
+			return nil, errors.New("oauth2 spec is not applicable")
+		}
+		if s := spec.Oidc; s != nil {
+			c := identity.OidcCreateSpec{
+				DiscoveryEndpoint: info.Oidc.DiscoveryEndpoint,
+				LogoutEndpoint:    info.Oidc.LogoutEndpoint,
+				ClientID:          info.Oidc.ClientID,
+				ClientSecret:      info.Oidc.ClientSecret,
+				ClaimMap:          info.Oidc.ClaimMap,
+			}
+			set(&c.DiscoveryEndpoint, s.DiscoveryEndpoint)
+			set(&c.LogoutEndpoint, s.LogoutEndpoint)
+			set(&c.ClientID, s.ClientID)
+			set(&c.ClientSecret, s.ClientSecret)
+			if s.ClaimMap != nil {
+				c.ClaimMap = s.ClaimMap
+			}
+			oidc, err := discover(c)
+			if err != nil {
+				return nil, err
+			}
+			info.Oidc = oidc
+		}
+	}
+
+	set(&info.Name, spec.Name)
+	set(&info.IdmProtocol, spec.IdmProtocol)
+	set(&info.UpnClaim, spec.UpnClaim)
+	set(&info.GroupsClaim, spec.GroupsClaim)
+	if spec.ResetUpnClaim {
+		info.UpnClaim = ""
+	}
+	if spec.ResetGroupsClaim {
+		info.GroupsClaim = ""
+	}
+	if spec.OrgIDs != nil {
+		info.OrgIDs = spec.OrgIDs
+	}
+	if spec.DomainNames != nil {
+		info.DomainNames = spec.DomainNames
+	}
+	if spec.AuthQueryParams != nil {
+		info.AuthQueryParams = spec.AuthQueryParams
+	}
+	if spec.IdmEndpoints != nil {
+		info.IdmEndpoints = spec.IdmEndpoints
+	}
+	if spec.MakeDefault {
+		info.IsDefault = true
+	}
+
+	return &info, validateIdm(info.IdmProtocol, info.IdmEndpoints)
+}
+
+func set(field *string, val string) {
+	if val != "" {
+		*field = val
+	}
+}
+
+// authHeader returns the client authentication header sent to the token endpoint
+func authHeader(method, id, secret string) string {
+	if method != identity.ClientSecretBasic {
+		return ""
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(id+":"+secret))
+}
+
+func summary(id string, info *identity.Info) identity.Summary {
+	s := identity.Summary{
+		Provider:        id,
+		Name:            info.Name,
+		ConfigTag:       info.ConfigTag,
+		IsDefault:       info.IsDefault,
+		DomainNames:     info.DomainNames,
+		AuthQueryParams: info.AuthQueryParams,
+	}
+	if p := info.Oauth2; p != nil {
+		s.Oauth2 = &identity.Oauth2Summary{
+			AuthEndpoint:         p.AuthEndpoint,
+			TokenEndpoint:        p.TokenEndpoint,
+			ClientID:             p.ClientID,
+			AuthenticationHeader: authHeader(p.AuthenticationMethod, p.ClientID, p.ClientSecret),
+			AuthQueryParams:      p.AuthQueryParams,
+		}
+	}
+	if p := info.Oidc; p != nil {
+		s.Oidc = &identity.OidcSummary{
+			DiscoveryEndpoint:    p.DiscoveryEndpoint,
+			LogoutEndpoint:       p.LogoutEndpoint,
+			AuthEndpoint:         p.AuthEndpoint,
+			TokenEndpoint:        p.TokenEndpoint,
+			ClientID:             p.ClientID,
+			AuthenticationHeader: authHeader(p.AuthenticationMethod, p.ClientID, p.ClientSecret),
+			AuthQueryParams:      p.AuthQueryParams,
+		}
+	}
+	return s
+}
+
+// redact returns a copy of the provider configuration without the client secret
+func redact(info *identity.Info) identity.Info {
+	res := *info
+	if res.Oauth2 != nil {
+		oauth2 := *res.Oauth2
+		oauth2.ClientSecret = ""
+		res.Oauth2 = &oauth2
+	}
+	if res.Oidc != nil {
+		oidc := *res.Oidc
+		oidc.ClientSecret = ""
+		res.Oidc = &oidc
+	}
+	return res
+}
+
+// setDefault clears the default flag of all providers other than id
+func (h *Handler) setDefault(id string) {
+	for pid, p := range h.providers {
+		if pid != id {
+			p.IsDefault = false
+		}
+	}
+}
+
+// path "/api/vcenter/identity/providers"
+func (h *Handler) handleProviders(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []identity.Summary{}
+		for id, p := range h.providers {
+			res = append(res, summary(id, p))
+		}
+		slices.SortFunc(res, func(a, b identity.Summary) int {
+			return strings.Compare(a.Name+a.Provider, b.Name+b.Provider)
+		})
+		vapi.StatusOK(w, res)
+	case http.MethodPost:
+		var spec identity.CreateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		info, err := create(spec)
+		if err != nil {
+			invalid(r, w, err)
+			return
+		}
+		id := uuid.New().String()
+		h.providers[id] = info
+		if info.IsDefault {
+			h.setDefault(id)
+		}
+		vapi.StatusOK(w, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/vcenter/identity/providers/{id}"
+func (h *Handler) handleProvider(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := strings.TrimPrefix(r.URL.Path, identity.ProvidersPath+"/")
+	p, ok := h.providers[id]
+	if !ok {
+		vapi.ApiErrorNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, redact(p))
+	case http.MethodPatch:
+		var spec identity.UpdateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		info, err := update(p, spec)
+		if err != nil {
+			invalid(r, w, err)
+			return
+		}
+		h.providers[id] = info
+		if info.IsDefault {
+			h.setDefault(id)
+		}
+		vapi.StatusOK(w)
+	case http.MethodDelete:
+		delete(h.providers, id)
+		vapi.StatusOK(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,130 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/vmware/govmomi/event"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	guest "github.com/vmware/govmomi/vapi/vcenter/guest/simulator"
+	vmapi "github.com/vmware/govmomi/vapi/vm"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// path "/api/vcenter/vm/{}/guest/customization"
+func (h *Handler) handleVmGuestCustomization(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getCustomization(w, r, vm)
+	case http.MethodPut:
+		h.setCustomization(w, r, vm)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) setCustomization(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	var req vmapi.CustomizationSetSpec
+	if !vapi.Decode(r, w, &req) {
+		return
+	}
+
+	var spec *types.CustomizationSpec
+
+	switch {
+	case req.Name != "" && req.Spec == nil:
+	case req.Spec != nil && req.Name == "":
+		var err error
+		spec, _, err = guest.VimSpec(*req.Spec)
+		if err != nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+	default:
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	ok := h.withClient(w, r, func(ctx context.Context, c *vim25.Client) error {
+		if spec == nil {
+			item, err := object.NewCustomizationSpecManager(c).GetCustomizationSpec(ctx, req.Name)
+			if err != nil {
+				return err
+			}
+			spec = &item.Spec
+		}
+
+		task, err := object.NewVirtualMachine(c, vm.Self).Customize(ctx, *spec)
+		if err != nil {
+			return err
+		}
+		return task.Wait(ctx)
+	})
+	if ok {
+		vapi.StatusOK(w)
+	}
+}
+
+// getCustomization derives the customization status from the pending customization and the VM's customization events.
+func (h *Handler) getCustomization(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	var pending bool
+	h.read(vm, func() {
+		pending = vm.Config.Tools != nil && vm.Config.Tools.PendingCustomization != ""
+	})
+
+	if pending {
+		vapi.StatusOK(w, vmapi.CustomizationInfo{Status: vmapi.CustomizationStatusPending})
+		return
+	}
+
+	info := vmapi.CustomizationInfo{Status: vmapi.CustomizationStatusIdle}
+
+	ok := h.withClient(w, r, func(ctx context.Context, c *vim25.Client) error {
+		events, err := event.NewManager(c).QueryEvents(ctx, types.EventFilterSpec{
+			Entity: &types.EventFilterSpecByEntity{
+				Entity:    vm.Self,
+				Recursion: types.EventFilterSpecRecursionOptionSelf,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		slices.SortFunc(events, func(a, b types.BaseEvent) int {
+			return cmp.Compare(a.GetEvent().Key, b.GetEvent().Key)
+		})
+
+		for _, e := range events {
+			created := e.GetEvent().CreatedTime
+
+			switch c := e.(type) {
+			case *types.CustomizationStartedEvent:
+				info = vmapi.CustomizationInfo{Status: vmapi.CustomizationStatusRunning, StartTime: &created}
+			case *types.CustomizationSucceeded:
+				info.Status = vmapi.CustomizationStatusSucceeded
+				info.EndTime = &created
+			case types.BaseCustomizationFailed:
+				info.Status = vmapi.CustomizationStatusFailed
+				info.Error = c.GetCustomizationFailed().Reason
+				if info.Error == "" {
+					info.Error = e.GetEvent().FullFormattedMessage
+				}
+				info.EndTime = &created
+			}
+		}
+
+		return nil
+	})
+	if ok {
+		vapi.StatusOK(w, info)
+	}
+}
@@ -5,14 +5,11 @@
 package simulator
 
 import (
-	"context"
 	"log"
 	"net/http"
 	"net/url"
 	"strings"
 
-	"github.com/google/uuid"
-
 	"github.com/vmware/govmomi/simulator"
 	"github.com/vmware/govmomi/vapi/rest"
 	vapi "github.com/vmware/govmomi/vapi/simulator"
@@ -57,6 +54,7 @@ func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
 		h.registry = r
 		s.HandleFunc(restPathPrefix, h.handle)
 		s.HandleFunc(apiPathPrefix, h.handle)
+		s.HandleFunc(internal.VCenterVMPath, h.handleVms)
 	}
 }
 
@@ -90,20 +88,30 @@ func (h *Handler) handleVm(w http.ResponseWriter, r *http.Request, tail []string
 	if vm == nil {
 		return
 	}
-	ctx := &simulator.Context{
-		Context: context.Background(),
-		Session: &simulator.Session{
-			UserSession: types.UserSession{
-				Key: uuid.New().String(),
-			},
-			Registry: h.registry,
-		},
-		Map: h.registry,
+	if len(tail) != 0 {
+		// Changes made via the SOAP API acquire the VM lock themselves
+		switch tail[0] {
+		case "hardware":
+			h.handleVmHardware(w, r, tail[1:], vm)
+			return
+		case "power":
+			h.handleVmPower(w, r, vm)
+			return
+		case "guest":
+			h.handleVmGuest(w, r, tail[1:], vm)
+			return
+		case "tools":
+			h.handleVmTools(w, r, vm)
+			return
+		}
 	}
+	ctx := h.context()
 	h.registry.WithLock(ctx, vm.Reference(), func() {
 		if len(tail) == 0 {
 			// "/api/vcenter/vm/{}"
 			switch r.Method {
+			case http.MethodGet:
+				h.getVM(w, vm)
 			case http.MethodDelete:
 				h.deleteVM(w, r, ctx, vm)
 			default:
@@ -0,0 +1,977 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/fault"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	vmapi "github.com/vmware/govmomi/vapi/vm"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const (
+	// Defaults used by CreateVM when the spec omits a value
+	defaultMemoryMiB = 1024
+	defaultDiskSize  = 16 << 30
+)
+
+var (
+	hardwareUpgradePolicies = map[string]string{
+		string(types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicyNever):          vmapi.HardwareUpgradePolicyNever,
+		string(types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicyOnSoftPowerOff): vmapi.HardwareUpgradePolicyAfterCleanShutdown,
+		string(types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicyAlways):         vmapi.HardwareUpgradePolicyAlways,
+	}
+
+	toolsUpgradePolicies = map[string]string{
+		string(types.UpgradePolicyManual):              vmapi.ToolsUpgradePolicyManual,
+		string(types.UpgradePolicyUpgradeAtPowerCycle): vmapi.ToolsUpgradePolicyUpgradeAtPowerCycle,
+	}
+
+	toolsRunStates = map[string]string{
+		string(types.VirtualMachineToolsRunningStatusGuestToolsNotRunning):       vmapi.ToolsRunStateNotRunning,
+		string(types.VirtualMachineToolsRunningStatusGuestToolsRunning):          vmapi.ToolsRunStateRunning,
+		string(types.VirtualMachineToolsRunningStatusGuestToolsExecutingScripts): vmapi.ToolsRunStateExecutingScripts,
+	}
+
+	nicTypes = map[string]string{
+		"E1000":   "e1000",
+		"E1000E":  "e1000e",
+		"PCNET32": "pcnet32",
+		"VMXNET":  "vmxnet",
+		"VMXNET2": "vmxnet2",
+		"VMXNET3": "vmxnet3",
+	}
+)
+
+// enum converts a vim25 camel case value, such as "poweredOn", to a REST enum value, such as "POWERED_ON".
+func enum(s string) string {
+	var b strings.Builder
+	var prev rune
+	for i, r := range s {
+		if i > 0 && (unicode.IsUpper(r) || unicode.IsDigit(r) && !unicode.IsDigit(prev)) && prev != '_' {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return b.String()
+}
+
+// reverse returns the key of the given map value.
+func reverse(m map[string]string, val string) (string, bool) {
+	for k, v := range m {
+		if v == val {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// guestOS converts a vim25 guest ID, such as "otherLinux64Guest", to a REST guest OS, such as "OTHER_LINUX_64".
+func guestOS(id string) string {
+	return enum(strings.TrimSuffix(id, "Guest"))
+}
+
+// guestID converts a REST guest OS to a vim25 guest ID.
+func guestID(os string) (string, bool) {
+	for _, id := range types.VirtualMachineGuestOsIdentifier("").Strings() {
+		if guestOS(id) == os {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// hardwareVersion converts a vim25 hardware version, such as "vmx-20", to a REST version, such as "VMX_20".
+func hardwareVersion(version string) string {
+	return strings.ToUpper(strings.ReplaceAll(version, "-", "_"))
+}
+
+func vmxVersion(version string) string {
+	return strings.ToLower(strings.ReplaceAll(version, "_", "-"))
+}
+
+func (h *Handler) context() *simulator.Context {
+	return &simulator.Context{
+		Context: context.Background(),
+		Session: &simulator.Session{
+			UserSession: types.UserSession{
+				Key: uuid.New().String(),
+			},
+			Registry: h.registry,
+		},
+		Map: h.registry,
+	}
+}
+
+// read invokes f with the VM locked.
+func (h *Handler) read(vm *simulator.VirtualMachine, f func()) {
+	ctx := h.context()
+	h.registry.WithLock(ctx, vm.Reference(), f)
+}
+
+// withClient invokes f with a SOAP client, so that changes made via the REST API go through the same
+// code paths as the SOAP API.  Faults returned by f are translated to REST errors.
+func (h *Handler) withClient(w http.ResponseWriter, r *http.Request, f func(context.Context, *vim25.Client) error) bool {
+	err := vapi.WithClient(*h.u, f)
+	if err == nil {
+		return true
+	}
+
+	log.Printf("%s %s: %s", r.Method, r.RequestURI, err)
+
+	var state *types.InvalidPowerState
+	if _, ok := fault.As(err, &state); ok && state.ExistingState == state.RequestedState {
+		vapi.ApiErrorAlreadyInDesiredState(w)
+		return false
+	}
+
+	switch {
+	case fault.Is(err, &types.InvalidPowerState{}), fault.Is(err, &types.InvalidPowerStateFault{}), fault.Is(err, &types.InvalidState{}),
+		fault.Is(err, &types.CustomizationPending{}):
+		vapi.ApiErrorNotAllowedInCurrentState(w)
+	case fault.Is(err, &types.InvalidArgument{}), fault.Is(err, &types.NotSupported{}), fault.Is(err, &types.NicSettingMismatch{}):
+		vapi.ApiErrorInvalidArgument(w)
+	case fault.Is(err, &types.DuplicateName{}), fault.Is(err, &types.FileAlreadyExists{}):
+		vapi.ApiErrorAlreadyExists(w)
+	case fault.Is(err, &types.ManagedObjectNotFound{}), fault.Is(err, &types.NotFound{}):
+		vapi.ApiErrorNotFound(w)
+	default:
+		vapi.ApiErrorGeneral(w)
+	}
+
+	return false
+}
+
+// reconfigure applies the given spec to the VM using ReconfigVM_Task.
+func (h *Handler) reconfigure(w http.ResponseWriter, r *http.Request, ref types.ManagedObjectReference, spec types.VirtualMachineConfigSpec) {
+	ok := h.withClient(w, r, func(ctx context.Context, c *vim25.Client) error {
+		task, err := object.NewVirtualMachine(c, ref).Reconfigure(ctx, spec)
+		if err != nil {
+			return err
+		}
+		return task.Wait(ctx)
+	})
+	if ok {
+		vapi.StatusOK(w)
+	}
+}
+
+func powerState(vm *simulator.VirtualMachine) string {
+	return enum(string(vm.Runtime.PowerState))
+}
+
+func summary(vm *simulator.VirtualMachine) vmapi.Summary {
+	cpu := int(vm.Config.Hardware.NumCPU)
+	mem := int(vm.Config.Hardware.MemoryMB)
+
+	return vmapi.Summary{
+		VM:         vm.Self.Value,
+		Name:       vm.Name,
+		PowerState: powerState(vm),
+		CPUCount:   &cpu,
+		MemorySize: &mem,
+	}
+}
+
+// ancestor returns the first ancestor of the given entity with the given type.
+func (h *Handler) ancestor(ref types.ManagedObjectReference, kind string) string {
+	for {
+		obj := h.registry.Get(ref)
+		if obj == nil {
+			return ""
+		}
+		e, ok := obj.(mo.Entity)
+		if !ok {
+			return ""
+		}
+		parent := e.Entity().Parent
+		if parent == nil {
+			return ""
+		}
+		if parent.Type == kind {
+			return parent.Value
+		}
+		ref = *parent
+	}
+}
+
+func match(filter []string, val string) bool {
+	return len(filter) == 0 || slices.Contains(filter, val)
+}
+
+// path "/api/vcenter/vm"
+func (h *Handler) handleVms(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listVMs(w, r)
+	case http.MethodPost:
+		h.createVM(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) listVMs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	res := []vmapi.Summary{}
+
+	for _, obj := range h.registry.All(typeVM) {
+		vm := obj.(*simulator.VirtualMachine)
+
+		h.read(vm, func() {
+			if vm.Config == nil || vm.Config.Template {
+				return
+			}
+
+			var host, cluster, pool, datacenter string
+			if vm.Runtime.Host != nil {
+				host = vm.Runtime.Host.Value
+				cluster = h.ancestor(*vm.Runtime.Host, "ClusterComputeResource")
+			}
+			if vm.ResourcePool != nil {
+				pool = vm.ResourcePool.Value
+			}
+			datacenter = h.ancestor(vm.Self, "Datacenter")
+
+			if match(q["vms"], vm.Self.Value) &&
+				match(q["names"], vm.Name) &&
+				match(q["folders"], vm.Parent.Value) &&
+				match(q["datacenters"], datacenter) &&
+				match(q["hosts"], host) &&
+				match(q["clusters"], cluster) &&
+				match(q["resource_pools"], pool) &&
+				match(q["power_states"], powerState(vm)) {
+				res = append(res, summary(vm))
+			}
+		})
+	}
+
+	slices.SortFunc(res, func(a, b vmapi.Summary) int {
+		return strings.Compare(a.VM, b.VM)
+	})
+
+	vapi.StatusOK(w, res)
+}
+
+// controllerType returns the REST disk type of the given controller.
+func controllerType(devices object.VirtualDeviceList, key int32) string {
+	switch devices.FindByKey(key).(type) {
+	case *types.VirtualIDEController:
+		return "IDE"
+	case *types.VirtualAHCIController:
+		return "SATA"
+	case *types.VirtualNVMEController:
+		return "NVME"
+	default:
+		return "SCSI"
+	}
+}
+
+func (h *Handler) info(vm *simulator.VirtualMachine) *vmapi.Info {
+	config := vm.Config
+	devices := object.VirtualDeviceList(config.Hardware.Device)
+
+	info := &vmapi.Info{
+		Name:    vm.Name,
+		GuestOS: guestOS(config.GuestId),
+		Identity: vmapi.IdentityInfo{
+			Name:         vm.Name,
+			BiosUUID:     config.Uuid,
+			InstanceUUID: config.InstanceUuid,
+		},
+		PowerState: powerState(vm),
+		Hardware:   hardware(vm),
+		CPU: vmapi.CPUInfo{
+			Count:            int(config.Hardware.NumCPU),
+			CoresPerSocket:   int(config.Hardware.NumCoresPerSocket),
+			HotAddEnabled:    config.CpuHotAddEnabled != nil && *config.CpuHotAddEnabled,
+			HotRemoveEnabled: config.CpuHotRemoveEnabled != nil && *config.CpuHotRemoveEnabled,
+		},
+		Memory: vmapi.MemoryInfo{
+			Size:          int(config.Hardware.MemoryMB),
+			HotAddEnabled: config.MemoryHotAddEnabled != nil && *config.MemoryHotAddEnabled,
+		},
+	}
+
+	for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		disk := device.(*types.VirtualDisk)
+		if info.Disks == nil {
+			info.Disks = make(map[string]vmapi.DiskInfo)
+		}
+
+		d := vmapi.DiskInfo{
+			Label:    disk.DeviceInfo.GetDescription().Label,
+			Type:     controllerType(devices, disk.ControllerKey),
+			Capacity: disk.CapacityInBytes,
+		}
+		if b, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo); ok {
+			d.Backing = vmapi.DiskBacking{Type: "VMDK_FILE", VmdkFile: b.GetVirtualDeviceFileBackingInfo().FileName}
+		}
+
+		info.Disks[strconv.Itoa(int(disk.Key))] = d
+	}
+
+	for _, device := range devices.SelectByType((*types.VirtualEthernetCard)(nil)) {
+		card := device.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+		if info.Nics == nil {
+			info.Nics = make(map[string]vmapi.NicInfo)
+		}
+
+		nic := vmapi.NicInfo{
+			Label:      card.DeviceInfo.GetDescription().Label,
+			Type:       strings.ToUpper(devices.Type(device)),
+			MacType:    enum(card.AddressType),
+			MacAddress: card.MacAddress,
+			State:      "NOT_CONNECTED",
+		}
+		if c := card.Connectable; c != nil {
+			if c.Connected {
+				nic.State = "CONNECTED"
+			}
+			nic.StartConnected = c.StartConnected
+		}
+
+		switch b := card.Backing.(type) {
+		case *types.VirtualEthernetCardNetworkBackingInfo:
+			nic.Backing.Type = "STANDARD_PORTGROUP"
+			nic.Backing.NetworkName = b.DeviceName
+			if b.Network != nil {
+				nic.Backing.Network = b.Network.Value
+			}
+		case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+			nic.Backing.Type = "DISTRIBUTED_PORTGROUP"
+			nic.Backing.Network = b.Port.PortgroupKey
+		case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+			nic.Backing.Type = "OPAQUE_NETWORK"
+			nic.Backing.NetworkName = b.OpaqueNetworkId
+		}
+
+		info.Nics[strconv.Itoa(int(card.Key))] = nic
+	}
+
+	return info
+}
+
+func hardware(vm *simulator.VirtualMachine) vmapi.HardwareInfo {
+	info := vmapi.HardwareInfo{
+		Version:       hardwareVersion(vm.Config.Version),
+		UpgradePolicy: vmapi.HardwareUpgradePolicyNever,
+		UpgradeStatus: "NONE",
+	}
+
+	if u := vm.Config.ScheduledHardwareUpgradeInfo; u != nil {
+		if p, ok := hardwareUpgradePolicies[u.UpgradePolicy]; ok {
+			info.UpgradePolicy = p
+		}
+		if info.UpgradePolicy != vmapi.HardwareUpgradePolicyNever {
+			info.UpgradeVersion = hardwareVersion(u.VersionKey)
+		}
+		if u.ScheduledHardwareUpgradeStatus != "" {
+			info.UpgradeStatus = enum(u.ScheduledHardwareUpgradeStatus)
+		}
+	}
+
+	return info
+}
+
+// path "/api/vcenter/vm/{}"
+func (h *Handler) getVM(w http.ResponseWriter, vm *simulator.VirtualMachine) {
+	vapi.StatusOK(w, h.info(vm))
+}
+
+// placement resolves the folder, resource pool, host and datastore of the given spec.
+func (h *Handler) placement(spec *vmapi.PlacementSpec) (folder, pool, host, ds *types.ManagedObjectReference, ok bool) {
+	if spec == nil || spec.Folder == "" || spec.Datastore == "" {
+		return
+	}
+
+	ref := func(kind, id string) *types.ManagedObjectReference {
+		if id == "" {
+			return nil
+		}
+		r := types.ManagedObjectReference{Type: kind, Value: id}
+		if h.registry.Get(r) == nil {
+			return nil
+		}
+		return &r
+	}
+
+	if folder = ref("Folder", spec.Folder); folder == nil {
+		return
+	}
+	if ds = ref("Datastore", spec.Datastore); ds == nil {
+		return
+	}
+
+	if spec.Host != "" {
+		if host = ref("HostSystem", spec.Host); host == nil {
+			return
+		}
+	}
+
+	switch {
+	case spec.ResourcePool != "":
+		pool = ref("ResourcePool", spec.ResourcePool)
+	case spec.Cluster != "":
+		if c := ref("ClusterComputeResource", spec.Cluster); c != nil {
+			pool = h.registry.Get(*c).(*simulator.ClusterComputeResource).ResourcePool
+		}
+	case host != nil:
+		parent := h.registry.Get(*host).(*simulator.HostSystem).Parent
+		if cr, ok := h.registry.Get(*parent).(mo.Reference); ok {
+			switch cr := cr.(type) {
+			case *simulator.ClusterComputeResource:
+				pool = cr.ResourcePool
+			case *mo.ComputeResource:
+				pool = cr.ResourcePool
+			}
+		}
+	}
+
+	return folder, pool, host, ds, pool != nil
+}
+
+// network returns the reference of the network with the given ID.
+func (h *Handler) network(id string) *types.ManagedObjectReference {
+	for _, kind := range []string{"Network", "DistributedVirtualPortgroup", "OpaqueNetwork"} {
+		ref := types.ManagedObjectReference{Type: kind, Value: id}
+		if h.registry.Get(ref) != nil {
+			return &ref
+		}
+	}
+	return nil
+}
+
+func (h *Handler) createVM(w http.ResponseWriter, r *http.Request) {
+	var spec vmapi.CreateSpec
+	if !vapi.Decode(r, w, &spec) {
+		return
+	}
+
+	id, ok := guestID(spec.GuestOS)
+	if !ok {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	folder, pool, host, ds, ok := h.placement(spec.Placement)
+	if !ok {
+		vapi.ApiErrorInvalidArgument(w)
+		return
+	}
+
+	name := spec.Name
+	if name == "" {
+		name = "Virtual Machine"
+	}
+
+	config := types.VirtualMachineConfigSpec{
+		Name:     name,
+		GuestId:  id,
+		NumCPUs:  1,
+		MemoryMB: defaultMemoryMiB,
+		Files: &types.VirtualMachineFileInfo{
+			VmPathName: "[" + h.registry.Get(*ds).(*simulator.Datastore).Name + "]",
+		},
+	}
+	if spec.HardwareVersion != "" {
+		config.Version = vmxVersion(spec.HardwareVersion)
+	}
+	if spec.CPU != nil {
+		if spec.CPU.Count != 0 {
+			config.NumCPUs = int32(spec.CPU.Count)
+		}
+		config.NumCoresPerSocket = int32(spec.CPU.CoresPerSocket)
+		config.CpuHotAddEnabled = spec.CPU.HotAddEnabled
+		config.CpuHotRemoveEnabled = spec.CPU.HotRemoveEnabled
+	}
+	if spec.Memory != nil {
+		if spec.Memory.Size != 0 {
+			config.MemoryMB = int64(spec.Memory.Size)
+		}
+		config.MemoryHotAddEnabled = spec.Memory.HotAddEnabled
+	}
+
+	var devices object.VirtualDeviceList
+
+	if len(spec.Disks) != 0 {
+		scsi, err := devices.CreateSCSIController("pvscsi")
+		if err != nil {
+			vapi.ApiErrorGeneral(w)
+			return
+		}
+		devices = append(devices, scsi)
+		controller := scsi.(types.BaseVirtualController)
+
+		for _, d := range spec.Disks {
+			size := int64(defaultDiskSize)
+			if d.NewVmdk != nil && d.NewVmdk.Capacity != 0 {
+				size = d.NewVmdk.Capacity
+			}
+			disk := &types.VirtualDisk{
+				VirtualDevice: types.VirtualDevice{
+					Key: devices.NewKey(),
+					Backing: &types.VirtualDiskFlatVer2BackingInfo{
+						DiskMode:        string(types.VirtualDiskModePersistent),
+						ThinProvisioned: types.NewBool(true),
+					},
+				},
+				CapacityInKB: size / 1024,
+			}
+			devices.AssignController(disk, controller)
+			devices = append(devices, disk)
+		}
+	}
+
+	for _, nic := range spec.Nics {
+		if _, ok := nicTypes[nic.Type]; nic.Type != "" && !ok {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		if h.network(nic.Backing.Network) == nil {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+	}
+
+	var ref types.ManagedObjectReference
+
+	ok = h.withClient(w, r, func(ctx context.Context, c *vim25.Client) error {
+		for _, nic := range spec.Nics {
+			kind := "vmxnet3"
+			if nic.Type != "" {
+				kind = nicTypes[nic.Type]
+			}
+			net := object.NewReference(c, *h.network(nic.Backing.Network)).(object.NetworkReference)
+			backing, err := net.EthernetCardBackingInfo(ctx)
+			if err != nil {
+				return err
+			}
+			card, err := devices.CreateEthernetCard(kind, backing)
+			if err != nil {
+				return err
+			}
+			card.GetVirtualDevice().Connectable = &types.VirtualDeviceConnectInfo{
+				StartConnected: nic.StartConnected,
+			}
+			devices = append(devices, card)
+		}
+
+		var err error
+		config.DeviceChange, err = devices.ConfigSpec(types.VirtualDeviceConfigSpecOperationAdd)
+		if err != nil {
+			return err
+		}
+
+		var hs *object.HostSystem
+		if host != nil {
+			hs = object.NewHostSystem(c, *host)
+		}
+
+		task, err := object.NewFolder(c, *folder).CreateVM(ctx, config, object.NewResourcePool(c, *pool), hs)
+		if err != nil {
+			return err
+		}
+
+		res, err := task.WaitForResult(ctx)
+		if err != nil {
+			return err
+		}
+
+		ref = res.Result.(types.ManagedObjectReference)
+		return nil
+	})
+
+	if ok {
+		vapi.StatusOK(w, ref.Value)
+	}
+}
+
+// path "/api/vcenter/vm/{}/hardware/..."
+func (h *Handler) handleVmHardware(w http.ResponseWriter, r *http.Request, tail []string, vm *simulator.VirtualMachine) {
+	if len(tail) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var info vmapi.HardwareInfo
+			h.read(vm, func() { info = hardware(vm) })
+			vapi.StatusOK(w, info)
+		case http.MethodPatch:
+			h.updateHardware(w, r, vm)
+		case http.MethodPost:
+			h.upgradeHardware(w, r, vm)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	switch tail[0] {
+	case "cpu":
+		h.handleVmCPU(w, r, vm)
+	case "memory":
+		h.handleVmMemory(w, r, vm)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) updateHardware(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	var spec vmapi.HardwareUpdateSpec
+	if !vapi.Decode(r, w, &spec) {
+		return
+	}
+
+	var info types.ScheduledHardwareUpgradeInfo
+	h.read(vm, func() {
+		if u := vm.Config.ScheduledHardwareUpgradeInfo; u != nil {
+			info = *u
+		}
+	})
+
+	if spec.UpgradePolicy != "" {
+		policy, ok := reverse(hardwareUpgradePolicies, spec.UpgradePolicy)
+		if !ok {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		info.UpgradePolicy = policy
+	}
+	if spec.UpgradeVersion != "" {
+		info.VersionKey = vmxVersion(spec.UpgradeVersion)
+	}
+	if info.UpgradePolicy != "" && info.UpgradePolicy != string(types.ScheduledHardwareUpgradeInfoHardwareUpgradePolicyNever) {
+		info.ScheduledHardwareUpgradeStatus = string(types.ScheduledHardwareUpgradeInfoHardwareUpgradeStatusPending)
+	} else {
+		info.ScheduledHardwareUpgradeStatus = string(types.ScheduledHardwareUpgradeInfoHardwareUpgradeStatusNone)
+	}
+
+	h.reconfigure(w, r, vm.Self, types.VirtualMachineConfigSpec{ScheduledHardwareUpgradeInfo: &info})
+}
+
+func (h *Handler) upgradeHardware(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	if r.URL.Query().Get("action") != "upgrade" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var spec struct {
+		Version string `json:"version,omitempty"`
+	}
+	if !vapi.Decode(r, w, &spec) {
+		return
+	}
+
+	ok := h.withClient(w, r, func(ctx context.Context, c *vim25.Client) error {
+		task, err := object.NewVirtualMachine(c, vm.Self).UpgradeVM(ctx, vmxVersion(spec.Version))
+		if err != nil {
+			return err
+		}
+		return task.Wait(ctx)
+	})
+	if ok {
+		vapi.StatusOK(w)
+	}
+}
+
+// path "/api/vcenter/vm/{}/hardware/cpu"
+func (h *Handler) handleVmCPU(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	switch r.Method {
+	case http.MethodGet:
+		var info vmapi.CPUInfo
+		h.read(vm, func() { info = h.info(vm).CPU })
+		vapi.StatusOK(w, info)
+	case http.MethodPatch:
+		var spec vmapi.CPUUpdateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		h.reconfigure(w, r, vm.Self, types.VirtualMachineConfigSpec{
+			NumCPUs:             int32(spec.Count),
+			NumCoresPerSocket:   int32(spec.CoresPerSocket),
+			CpuHotAddEnabled:    spec.HotAddEnabled,
+			CpuHotRemoveEnabled: spec.HotRemoveEnabled,
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/vcenter/vm/{}/hardware/memory"
+func (h *Handler) handleVmMemory(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	switch r.Method {
+	case http.MethodGet:
+		var info vmapi.MemoryInfo
+		h.read(vm, func() { info = h.info(vm).Memory })
+		vapi.StatusOK(w, info)
+	case http.MethodPatch:
+		var spec vmapi.MemoryUpdateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		h.reconfigure(w, r, vm.Self, types.VirtualMachineConfigSpec{
+			MemoryMB:            int64(spec.Size),
+			MemoryHotAddEnabled: spec.HotAddEnabled,
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// path "/api/vcenter/vm/{}/power"
+func (h *Handler) handleVmPower(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	switch r.Method {
+	case http.MethodGet:
+		var info vmapi.PowerInfo
+		h.read(vm, func() {
+			info.State = powerState(vm)
+			if info.State == vmapi.PowerStatePoweredOff {
+				info.CleanPowerOff = types.NewBool(true)
+			}
+		})
+		vapi.StatusOK(w, info)
+	case http.MethodPost:
+		action := r.URL.Query().Get("action")
+		if !slices.Contains([]string{"start", "stop", "suspend", "reset"}, action) {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		ok := h.withClient(w, r, func(ctx context.Context, c *vim25.Client) error {
+			obj := object.NewVirtualMachine(c, vm.Self)
+			var task *object.Task
+			var err error
+			switch action {
+			case "start":
+				task, err = obj.PowerOn(ctx)
+			case "stop":
+				task, err = obj.PowerOff(ctx)
+			case "suspend":
+				task, err = obj.Suspend(ctx)
+			case "reset":
+				task, err = obj.Reset(ctx)
+			}
+			if err != nil {
+				return err
+			}
+			return task.Wait(ctx)
+		})
+		if ok {
+			vapi.StatusOK(w)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// toolsRunning returns true if VMware Tools is running in the guest, otherwise responds with SERVICE_UNAVAILABLE.
+func toolsRunning(w http.ResponseWriter, vm *simulator.VirtualMachine) bool {
+	if vm.Guest == nil || vm.Guest.ToolsRunningStatus != string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
+		vapi.ApiErrorServiceUnavailable(w)
+		return false
+	}
+	return true
+}
+
+// path "/api/vcenter/vm/{}/guest/..."
+func (h *Handler) handleVmGuest(w http.ResponseWriter, r *http.Request, tail []string, vm *simulator.VirtualMachine) {
+	if len(tail) == 1 && tail[0] == "customization" {
+		h.handleVmGuestCustomization(w, r, vm)
+		return
+	}
+
+	if r.Method != http.MethodGet || len(tail) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.read(vm, func() {
+		switch strings.Join(tail, "/") {
+		case "identity":
+			if toolsRunning(w, vm) {
+				vapi.StatusOK(w, guestIdentity(vm))
+			}
+		case "networking":
+			if toolsRunning(w, vm) {
+				vapi.StatusOK(w, guestNetworking(vm))
+			}
+		case "networking/interfaces":
+			if toolsRunning(w, vm) {
+				vapi.StatusOK(w, guestInterfaces(vm))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func guestIdentity(vm *simulator.VirtualMachine) vmapi.GuestIdentityInfo {
+	g := vm.Guest
+
+	id := g.GuestId
+	if id == "" {
+		id = vm.Config.GuestId
+	}
+	name := g.GuestFullName
+	if name == "" {
+		name = vm.Config.GuestFullName
+	}
+
+	return vmapi.GuestIdentityInfo{
+		Name:   guestOS(id),
+		Family: enum(g.GuestFamily),
+		FullName: vmapi.LocalizableMessage{
+			ID:             "vmsg.guestos." + id + ".label",
+			DefaultMessage: name,
+			Args:           []string{},
+		},
+		HostName:  g.HostName,
+		IPAddress: g.IpAddress,
+	}
+}
+
+func guestNetworking(vm *simulator.VirtualMachine) vmapi.GuestNetworkingInfo {
+	var info vmapi.GuestNetworkingInfo
+
+	for _, stack := range vm.Guest.IpStack {
+		if dns := stack.DnsConfig; dns != nil {
+			info.DNSValues = &vmapi.DNSValues{
+				DomainName:    dns.DomainName,
+				SearchDomains: dns.SearchDomain,
+			}
+			break
+		}
+	}
+
+	return info
+}
+
+func guestInterfaces(vm *simulator.VirtualMachine) []vmapi.GuestInterfaceInfo {
+	res := []vmapi.GuestInterfaceInfo{}
+
+	for _, nic := range vm.Guest.Net {
+		iface := vmapi.GuestInterfaceInfo{
+			MacAddress: nic.MacAddress,
+			IP:         &vmapi.IPInfo{IPAddresses: []vmapi.IPAddressInfo{}},
+		}
+		if nic.DeviceConfigId != 0 {
+			iface.Nic = strconv.Itoa(int(nic.DeviceConfigId))
+		}
+
+		if nic.IpConfig != nil {
+			for _, ip := range nic.IpConfig.IpAddress {
+				iface.IP.IPAddresses = append(iface.IP.IPAddresses, vmapi.IPAddressInfo{
+					IPAddress:    ip.IpAddress,
+					PrefixLength: ip.PrefixLength,
+					Origin:       enum(ip.Origin),
+					State:        enum(ip.State),
+				})
+			}
+		} else {
+			for _, ip := range nic.IpAddress {
+				iface.IP.IPAddresses = append(iface.IP.IPAddresses, vmapi.IPAddressInfo{
+					IPAddress: ip,
+					State:     "PREFERRED",
+				})
+			}
+		}
+
+		res = append(res, iface)
+	}
+
+	return res
+}
+
+// path "/api/vcenter/vm/{}/tools"
+func (h *Handler) handleVmTools(w http.ResponseWriter, r *http.Request, vm *simulator.VirtualMachine) {
+	switch r.Method {
+	case http.MethodGet:
+		var info vmapi.ToolsInfo
+		h.read(vm, func() { info = tools(vm) })
+		vapi.StatusOK(w, info)
+	case http.MethodPatch:
+		var spec vmapi.ToolsUpdateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		policy, ok := reverse(toolsUpgradePolicies, spec.UpgradePolicy)
+		if !ok {
+			vapi.ApiErrorInvalidArgument(w)
+			return
+		}
+		var config types.ToolsConfigInfo
+		h.read(vm, func() {
+			if vm.Config.Tools != nil {
+				config = *vm.Config.Tools
+			}
+		})
+		config.ToolsUpgradePolicy = policy
+		h.reconfigure(w, r, vm.Self, types.VirtualMachineConfigSpec{Tools: &config})
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "upgrade" {
+			http.NotFound(w, r)
+			return
+		}
+		h.read(vm, func() {
+			if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn || !toolsInstalled(vm) {
+				vapi.ApiErrorNotAllowedInCurrentState(w)
+				return
+			}
+			vm.Guest.ToolsStatus = types.VirtualMachineToolsStatusToolsOk
+			vm.Guest.ToolsVersionStatus2 = string(types.VirtualMachineToolsVersionStatusGuestToolsCurrent)
+			vapi.StatusOK(w)
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func toolsInstalled(vm *simulator.VirtualMachine) bool {
+	return vm.Guest != nil && vm.Guest.ToolsStatus != types.VirtualMachineToolsStatusToolsNotInstalled
+}
+
+func tools(vm *simulator.VirtualMachine) vmapi.ToolsInfo {
+	info := vmapi.ToolsInfo{
+		AutoUpdateSupported: false,
+		UpgradePolicy:       vmapi.ToolsUpgradePolicyManual,
+		RunState:            vmapi.ToolsRunStateNotRunning,
+		VersionStatus:       "NOT_INSTALLED",
+	}
+
+	if t := vm.Config.Tools; t != nil {
+		if p, ok := toolsUpgradePolicies[t.ToolsUpgradePolicy]; ok {
+			info.UpgradePolicy = p
+		}
+	}
+
+	if g := vm.Guest; g != nil {
+		if s, ok := toolsRunStates[g.ToolsRunningStatus]; ok {
+			info.RunState = s
+		}
+		if toolsInstalled(vm) {
+			info.AutoUpdateSupported = true
+			info.Version = g.ToolsVersion
+			info.VersionNumber, _ = strconv.Atoi(g.ToolsVersion)
+			info.InstallType = enum(g.ToolsInstallType)
+			info.VersionStatus = enum(strings.TrimPrefix(g.ToolsVersionStatus2, "guestTools"))
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,507 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vm
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+	"github.com/vmware/govmomi/vapi/vm/internal"
+)
+
+// Manager extends rest.Client, adding virtual machine related methods.
+//
+// See https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/vm/
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Power states of a virtual machine.
+const (
+	PowerStatePoweredOff = "POWERED_OFF"
+	PowerStatePoweredOn  = "POWERED_ON"
+	PowerStateSuspended  = "SUSPENDED"
+)
+
+// Virtual hardware upgrade policies.
+const (
+	HardwareUpgradePolicyNever              = "NEVER"
+	HardwareUpgradePolicyAfterCleanShutdown = "AFTER_CLEAN_SHUTDOWN"
+	HardwareUpgradePolicyAlways             = "ALWAYS"
+)
+
+// VMware Tools upgrade policies.
+const (
+	ToolsUpgradePolicyManual              = "MANUAL"
+	ToolsUpgradePolicyUpgradeAtPowerCycle = "UPGRADE_AT_POWER_CYCLE"
+)
+
+// VMware Tools run states.
+const (
+	ToolsRunStateNotRunning       = "NOT_RUNNING"
+	ToolsRunStateRunning          = "RUNNING"
+	ToolsRunStateExecutingScripts = "EXECUTING_SCRIPTS"
+)
+
+// Guest customization states.
+const (
+	CustomizationStatusIdle      = "IDLE"
+	CustomizationStatusPending   = "PENDING"
+	CustomizationStatusRunning   = "RUNNING"
+	CustomizationStatusSucceeded = "SUCCEEDED"
+	CustomizationStatusFailed    = "FAILED"
+)
+
+// Summary contains commonly used information about a virtual machine.
+type Summary struct {
+	VM         string `json:"vm"`
+	Name       string `json:"name"`
+	PowerState string `json:"power_state"`
+	CPUCount   *int   `json:"cpu_count,omitempty"`
+	MemorySize *int   `json:"memory_size_MiB,omitempty"`
+}
+
+// FilterSpec contains properties used to filter the result of ListVMs.
+// Each non-empty field must match for a virtual machine to be listed.
+type FilterSpec struct {
+	VMs           []string
+	Names         []string
+	Folders       []string
+	Datacenters   []string
+	Hosts         []string
+	Clusters      []string
+	ResourcePools []string
+	PowerStates   []string
+}
+
+func (f *FilterSpec) params(r *rest.Resource) *rest.Resource {
+	params := []struct {
+		name   string
+		values []string
+	}{
+		{"vms", f.VMs},
+		{"names", f.Names},
+		{"folders", f.Folders},
+		{"datacenters", f.Datacenters},
+		{"hosts", f.Hosts},
+		{"clusters", f.Clusters},
+		{"resource_pools", f.ResourcePools},
+		{"power_states", f.PowerStates},
+	}
+
+	for _, p := range params {
+		for _, v := range p.values {
+			r.WithParam(p.name, v)
+		}
+	}
+
+	return r
+}
+
+// IdentityInfo contains the identity of a virtual machine.
+type IdentityInfo struct {
+	Name         string `json:"name"`
+	BiosUUID     string `json:"bios_uuid"`
+	InstanceUUID string `json:"instance_uuid"`
+}
+
+// HardwareInfo contains the virtual hardware version and upgrade settings of a virtual machine.
+type HardwareInfo struct {
+	Version        string `json:"version"`
+	UpgradePolicy  string `json:"upgrade_policy"`
+	UpgradeVersion string `json:"upgrade_version,omitempty"`
+	UpgradeStatus  string `json:"upgrade_status"`
+}
+
+// HardwareUpdateSpec describes the hardware upgrade settings to change.
+type HardwareUpdateSpec struct {
+	UpgradePolicy  string `json:"upgrade_policy,omitempty"`
+	UpgradeVersion string `json:"upgrade_version,omitempty"`
+}
+
+// CPUInfo contains the CPU configuration of a virtual machine.
+type CPUInfo struct {
+	Count            int  `json:"count"`
+	CoresPerSocket   int  `json:"cores_per_socket"`
+	HotAddEnabled    bool `json:"hot_add_enabled"`
+	HotRemoveEnabled bool `json:"hot_remove_enabled"`
+}
+
+// CPUUpdateSpec describes the CPU configuration to change.
+type CPUUpdateSpec struct {
+	Count            int   `json:"count,omitempty"`
+	CoresPerSocket   int   `json:"cores_per_socket,omitempty"`
+	HotAddEnabled    *bool `json:"hot_add_enabled,omitempty"`
+	HotRemoveEnabled *bool `json:"hot_remove_enabled,omitempty"`
+}
+
+// MemoryInfo contains the memory configuration of a virtual machine.
+type MemoryInfo struct {
+	Size          int  `json:"size_MiB"`
+	HotAddEnabled bool `json:"hot_add_enabled"`
+}
+
+// MemoryUpdateSpec describes the memory configuration to change.
+type MemoryUpdateSpec struct {
+	Size          int   `json:"size_MiB,omitempty"`
+	HotAddEnabled *bool `json:"hot_add_enabled,omitempty"`
+}
+
+// DiskBacking describes the backing of a virtual disk.
+type DiskBacking struct {
+	Type     string `json:"type"`
+	VmdkFile string `json:"vmdk_file,omitempty"`
+}
+
+// DiskInfo contains information about a virtual disk.
+type DiskInfo struct {
+	Label    string      `json:"label"`
+	Type     string      `json:"type"`
+	Capacity int64       `json:"capacity,omitempty"`
+	Backing  DiskBacking `json:"backing"`
+}
+
+// NicBacking describes the network backing of a virtual Ethernet adapter.
+type NicBacking struct {
+	Type        string `json:"type"`
+	Network     string `json:"network,omitempty"`
+	NetworkName string `json:"network_name,omitempty"`
+}
+
+// NicInfo contains information about a virtual Ethernet adapter.
+type NicInfo struct {
+	Label          string     `json:"label"`
+	Type           string     `json:"type"`
+	MacType        string     `json:"mac_type"`
+	MacAddress     string     `json:"mac_address,omitempty"`
+	State          string     `json:"state"`
+	StartConnected bool       `json:"start_connected"`
+	Backing        NicBacking `json:"backing"`
+}
+
+// Info contains information about a virtual machine.
+type Info struct {
+	Name       string              `json:"name"`
+	GuestOS    string              `json:"guest_OS"`
+	Identity   IdentityInfo        `json:"identity"`
+	PowerState string              `json:"power_state"`
+	Hardware   HardwareInfo        `json:"hardware"`
+	CPU        CPUInfo             `json:"cpu"`
+	Memory     MemoryInfo          `json:"memory"`
+	Disks      map[string]DiskInfo `json:"disks,omitempty"`
+	Nics       map[string]NicInfo  `json:"nics,omitempty"`
+}
+
+// PlacementSpec describes where a virtual machine is created.
+type PlacementSpec struct {
+	Folder       string `json:"folder,omitempty"`
+	ResourcePool string `json:"resource_pool,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Cluster      string `json:"cluster,omitempty"`
+	Datastore    string `json:"datastore,omitempty"`
+}
+
+// VmdkCreateSpec describes a new virtual disk file.
+type VmdkCreateSpec struct {
+	Name     string `json:"name,omitempty"`
+	Capacity int64  `json:"capacity,omitempty"`
+}
+
+// DiskCreateSpec describes a virtual disk to create.
+type DiskCreateSpec struct {
+	Type    string          `json:"type,omitempty"`
+	NewVmdk *VmdkCreateSpec `json:"new_vmdk,omitempty"`
+}
+
+// NicCreateSpec describes a virtual Ethernet adapter to create.
+type NicCreateSpec struct {
+	Type           string     `json:"type,omitempty"`
+	StartConnected bool       `json:"start_connected"`
+	Backing        NicBacking `json:"backing"`
+}
+
+// CreateSpec describes a virtual machine to create.
+type CreateSpec struct {
+	Name            string            `json:"name,omitempty"`
+	GuestOS         string            `json:"guest_OS"`
+	Placement       *PlacementSpec    `json:"placement,omitempty"`
+	HardwareVersion string            `json:"hardware_version,omitempty"`
+	CPU             *CPUUpdateSpec    `json:"cpu,omitempty"`
+	Memory          *MemoryUpdateSpec `json:"memory,omitempty"`
+	Disks           []DiskCreateSpec  `json:"disks,omitempty"`
+	Nics            []NicCreateSpec   `json:"nics,omitempty"`
+}
+
+// PowerInfo contains the power state of a virtual machine.
+type PowerInfo struct {
+	State         string `json:"state"`
+	CleanPowerOff *bool  `json:"clean_power_off,omitempty"`
+}
+
+// LocalizableMessage is a message that can be localized.
+type LocalizableMessage struct {
+	ID             string   `json:"id"`
+	DefaultMessage string   `json:"default_message"`
+	Args           []string `json:"args"`
+}
+
+// GuestIdentityInfo contains information about the guest operating system, as reported by VMware Tools.
+type GuestIdentityInfo struct {
+	Name      string             `json:"name"`
+	Family    string             `json:"family"`
+	FullName  LocalizableMessage `json:"full_name"`
+	HostName  string             `json:"host_name"`
+	IPAddress string             `json:"ip_address,omitempty"`
+}
+
+// DNSValues contains the DNS settings of the guest operating system.
+type DNSValues struct {
+	DomainName    string   `json:"domain_name,omitempty"`
+	SearchDomains []string `json:"search_domains,omitempty"`
+}
+
+// GuestNetworkingInfo contains the network configuration of the guest operating system.
+type GuestNetworkingInfo struct {
+	DNSValues *DNSValues `json:"dns_values,omitempty"`
+}
+
+// IPAddressInfo describes an IP address assigned to a guest network interface.
+type IPAddressInfo struct {
+	IPAddress    string `json:"ip_address"`
+	PrefixLength int32  `json:"prefix_length"`
+	Origin       string `json:"origin,omitempty"`
+	State        string `json:"state"`
+}
+
+// IPInfo contains the IP configuration of a guest network interface.
+type IPInfo struct {
+	IPAddresses []IPAddressInfo `json:"ip_addresses"`
+}
+
+// GuestInterfaceInfo describes a network interface of the guest operating system.
+type GuestInterfaceInfo struct {
+	Nic        string  `json:"nic,omitempty"`
+	MacAddress string  `json:"mac_address,omitempty"`
+	IP         *IPInfo `json:"ip,omitempty"`
+}
+
+// ToolsInfo contains information about VMware Tools in a virtual machine.
+type ToolsInfo struct {
+	AutoUpdateSupported bool   `json:"auto_update_supported"`
+	InstallAttemptCount int    `json:"install_attempt_count,omitempty"`
+	VersionNumber       int    `json:"version_number,omitempty"`
+	Version             string `json:"version,omitempty"`
+	UpgradePolicy       string `json:"upgrade_policy"`
+	VersionStatus       string `json:"version_status,omitempty"`
+	InstallType         string `json:"install_type,omitempty"`
+	RunState            string `json:"run_state"`
+}
+
+// ToolsUpdateSpec describes the VMware Tools settings to change.
+type ToolsUpdateSpec struct {
+	UpgradePolicy string `json:"upgrade_policy,omitempty"`
+}
+
+// CustomizationSetSpec describes the guest customization to apply at the next power on.
+// Either Name, the name of an existing customization specification, or Spec must be set.
+type CustomizationSetSpec struct {
+	Name string                   `json:"name,omitempty"`
+	Spec *guest.CustomizationSpec `json:"spec,omitempty"`
+}
+
+// CustomizationInfo contains the status of the guest customization of a virtual machine.
+type CustomizationInfo struct {
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+}
+
+func (c *Manager) resource(id string, subpath ...string) *rest.Resource {
+	r := c.Resource(internal.VCenterVMPath)
+	if id != "" {
+		r.WithSubpath(id)
+	}
+	for _, p := range subpath {
+		r.WithSubpath(p)
+	}
+	return r
+}
+
+// ListVMs returns the virtual machines matching the given filter.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/vm/get
+func (c *Manager) ListVMs(ctx context.Context, filter FilterSpec) ([]Summary, error) {
+	url := filter.params(c.resource(""))
+	var res []Summary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetVM returns information about a virtual machine.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/vm/vm/get
+func (c *Manager) GetVM(ctx context.Context, id string) (*Info, error) {
+	url := c.resource(id)
+	var res Info
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateVM creates a virtual machine, returning the identifier of the new virtual machine.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/vm/post
+func (c *Manager) CreateVM(ctx context.Context, spec CreateSpec) (string, error) {
+	url := c.resource("")
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// DeleteVM deletes a powered off virtual machine.
+// https://developer.broadcom.com/xapis/vsphere-automation-api/latest/vcenter/api/vcenter/vm/vm/delete
+func (c *Manager) DeleteVM(ctx context.Context, id string) error {
+	url := c.resource(id)
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// GetHardware returns the virtual hardware settings of a virtual machine.
+func (c *Manager) GetHardware(ctx context.Context, id string) (*HardwareInfo, error) {
+	url := c.resource(id, "hardware")
+	var res HardwareInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// UpdateHardware updates the virtual hardware upgrade settings of a virtual machine.
+func (c *Manager) UpdateHardware(ctx context.Context, id string, spec HardwareUpdateSpec) error {
+	url := c.resource(id, "hardware")
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// UpgradeHardware upgrades the virtual hardware of a powered off virtual machine to the given version,
+// or to the latest supported version if version is empty.
+func (c *Manager) UpgradeHardware(ctx context.Context, id string, version string) error {
+	url := c.resource(id, "hardware").WithParam("action", "upgrade")
+	spec := struct {
+		Version string `json:"version,omitempty"`
+	}{version}
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
+
+// GetCPU returns the CPU configuration of a virtual machine.
+func (c *Manager) GetCPU(ctx context.Context, id string) (*CPUInfo, error) {
+	url := c.resource(id, "hardware", "cpu")
+	var res CPUInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// UpdateCPU updates the CPU configuration of a virtual machine.
+func (c *Manager) UpdateCPU(ctx context.Context, id string, spec CPUUpdateSpec) error {
+	url := c.resource(id, "hardware", "cpu")
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// GetMemory returns the memory configuration of a virtual machine.
+func (c *Manager) GetMemory(ctx context.Context, id string) (*MemoryInfo, error) {
+	url := c.resource(id, "hardware", "memory")
+	var res MemoryInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// UpdateMemory updates the memory configuration of a virtual machine.
+func (c *Manager) UpdateMemory(ctx context.Context, id string, spec MemoryUpdateSpec) error {
+	url := c.resource(id, "hardware", "memory")
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// GetPower returns the power state of a virtual machine.
+func (c *Manager) GetPower(ctx context.Context, id string) (*PowerInfo, error) {
+	url := c.resource(id, "power")
+	var res PowerInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+func (c *Manager) power(ctx context.Context, id string, action string) error {
+	url := c.resource(id, "power").WithParam("action", action)
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
+
+// Start powers on a virtual machine.
+func (c *Manager) Start(ctx context.Context, id string) error {
+	return c.power(ctx, id, "start")
+}
+
+// Stop powers off a virtual machine.
+func (c *Manager) Stop(ctx context.Context, id string) error {
+	return c.power(ctx, id, "stop")
+}
+
+// Suspend suspends a powered on virtual machine.
+func (c *Manager) Suspend(ctx context.Context, id string) error {
+	return c.power(ctx, id, "suspend")
+}
+
+// Reset resets a powered on virtual machine.
+func (c *Manager) Reset(ctx context.Context, id string) error {
+	return c.power(ctx, id, "reset")
+}
+
+// GetGuestIdentity returns the guest operating system identity, as reported by VMware Tools.
+func (c *Manager) GetGuestIdentity(ctx context.Context, id string) (*GuestIdentityInfo, error) {
+	url := c.resource(id, "guest", "identity")
+	var res GuestIdentityInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetGuestNetworking returns the guest operating system network configuration, as reported by VMware Tools.
+func (c *Manager) GetGuestNetworking(ctx context.Context, id string) (*GuestNetworkingInfo, error) {
+	url := c.resource(id, "guest", "networking")
+	var res GuestNetworkingInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// ListGuestInterfaces returns the guest operating system network interfaces, as reported by VMware Tools.
+func (c *Manager) ListGuestInterfaces(ctx context.Context, id string) ([]GuestInterfaceInfo, error) {
+	url := c.resource(id, "guest", "networking", "interfaces")
+	var res []GuestInterfaceInfo
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// SetGuestCustomization applies a guest customization specification to a powered off virtual machine.
+// The customization is performed when the virtual machine is next powered on.
+func (c *Manager) SetGuestCustomization(ctx context.Context, id string, spec CustomizationSetSpec) error {
+	url := c.resource(id, "guest", "customization")
+	return c.Do(ctx, url.Request(http.MethodPut, spec), nil)
+}
+
+// GetGuestCustomization returns the status of the guest customization of a virtual machine.
+func (c *Manager) GetGuestCustomization(ctx context.Context, id string) (*CustomizationInfo, error) {
+	url := c.resource(id, "guest", "customization")
+	var res CustomizationInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetTools returns information about VMware Tools in a virtual machine.
+func (c *Manager) GetTools(ctx context.Context, id string) (*ToolsInfo, error) {
+	url := c.resource(id, "tools")
+	var res ToolsInfo
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// UpdateTools updates the VMware Tools settings of a virtual machine.
+func (c *Manager) UpdateTools(ctx context.Context, id string, spec ToolsUpdateSpec) error {
+	url := c.resource(id, "tools")
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// UpgradeTools upgrades VMware Tools in a powered on virtual machine.
+func (c *Manager) UpgradeTools(ctx context.Context, id string) error {
+	url := c.resource(id, "tools").WithParam("action", "upgrade")
+	return c.Do(ctx, url.Request(http.MethodPost, struct{}{}), nil)
+}
@@ -0,0 +1,278 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package vm_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter/guest"
+	"github.com/vmware/govmomi/vapi/vm"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vapi/vm/simulator"
+)
+
+func TestVM(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := vm.NewManager(c)
+		finder := find.NewFinder(vc)
+
+		vms, err := m.ListVMs(ctx, vm.FilterSpec{})
+		require.NoError(t, err)
+		assert.Len(t, vms, 4)
+
+		vms, err = m.ListVMs(ctx, vm.FilterSpec{Names: []string{"DC0_H0_VM0"}})
+		require.NoError(t, err)
+		require.Len(t, vms, 1)
+		assert.Equal(t, vm.PowerStatePoweredOn, vms[0].PowerState)
+
+		obj, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		require.NoError(t, err)
+		assert.Equal(t, obj.Reference().Value, vms[0].VM)
+
+		_, err = m.GetVM(ctx, "enoent")
+		assert.True(t, rest.IsStatusError(err, http.StatusNotFound))
+
+		// create a VM via the REST API and verify it is visible via the SOAP API
+		folder, err := finder.DefaultFolder(ctx)
+		require.NoError(t, err)
+		pool, err := obj.ResourcePool(ctx)
+		require.NoError(t, err)
+		ds, err := finder.DefaultDatastore(ctx)
+		require.NoError(t, err)
+		net, err := finder.Network(ctx, "VM Network")
+		require.NoError(t, err)
+
+		spec := vm.CreateSpec{
+			Name:    "rest-vm",
+			GuestOS: "OTHER_LINUX_64",
+			Placement: &vm.PlacementSpec{
+				Folder:       folder.Reference().Value,
+				ResourcePool: pool.Reference().Value,
+				Datastore:    ds.Reference().Value,
+			},
+			CPU:    &vm.CPUUpdateSpec{Count: 2},
+			Memory: &vm.MemoryUpdateSpec{Size: 2048},
+			Disks:  []vm.DiskCreateSpec{{NewVmdk: &vm.VmdkCreateSpec{Capacity: 1 << 30}}},
+			Nics: []vm.NicCreateSpec{{
+				StartConnected: true,
+				Backing:        vm.NicBacking{Type: "STANDARD_PORTGROUP", Network: net.Reference().Value},
+			}},
+		}
+
+		_, err = m.CreateVM(ctx, vm.CreateSpec{Name: "invalid", GuestOS: "OTHER_LINUX_64"})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		id, err := m.CreateVM(ctx, spec)
+		require.NoError(t, err)
+
+		ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: id}
+		var props mo.VirtualMachine
+		require.NoError(t, object.NewVirtualMachine(vc, ref).Properties(ctx, ref, []string{"config"}, &props))
+		assert.Equal(t, "rest-vm", props.Config.Name)
+		assert.Equal(t, "otherLinux64Guest", props.Config.GuestId)
+
+		info, err := m.GetVM(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "rest-vm", info.Name)
+		assert.Equal(t, "OTHER_LINUX_64", info.GuestOS)
+		assert.Equal(t, vm.PowerStatePoweredOff, info.PowerState)
+		assert.Equal(t, props.Config.Uuid, info.Identity.BiosUUID)
+		assert.Equal(t, 2, info.CPU.Count)
+		assert.Equal(t, 2048, info.Memory.Size)
+		require.Len(t, info.Disks, 1)
+		for _, disk := range info.Disks {
+			assert.Equal(t, int64(1<<30), disk.Capacity)
+			assert.Equal(t, "SCSI", disk.Type)
+		}
+		require.Len(t, info.Nics, 1)
+		for _, nic := range info.Nics {
+			assert.Equal(t, net.Reference().Value, nic.Backing.Network)
+			assert.True(t, nic.StartConnected)
+		}
+
+		// hardware
+		require.NoError(t, m.UpdateCPU(ctx, id, vm.CPUUpdateSpec{Count: 4, HotAddEnabled: types.NewBool(true)}))
+		cpu, err := m.GetCPU(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, 4, cpu.Count)
+		assert.True(t, cpu.HotAddEnabled)
+
+		require.NoError(t, m.UpdateMemory(ctx, id, vm.MemoryUpdateSpec{Size: 4096}))
+		mem, err := m.GetMemory(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, 4096, mem.Size)
+
+		hw, err := m.GetHardware(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.HardwareUpgradePolicyNever, hw.UpgradePolicy)
+
+		require.NoError(t, m.UpdateHardware(ctx, id, vm.HardwareUpdateSpec{
+			UpgradePolicy:  vm.HardwareUpgradePolicyAfterCleanShutdown,
+			UpgradeVersion: "VMX_21",
+		}))
+		hw, err = m.GetHardware(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.HardwareUpgradePolicyAfterCleanShutdown, hw.UpgradePolicy)
+		assert.Equal(t, "VMX_21", hw.UpgradeVersion)
+		assert.Equal(t, "PENDING", hw.UpgradeStatus)
+
+		require.NoError(t, m.UpgradeHardware(ctx, id, "VMX_21"))
+		hw, err = m.GetHardware(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "VMX_21", hw.Version)
+
+		// power
+		require.NoError(t, m.Start(ctx, id))
+		err = m.Start(ctx, id)
+		assert.ErrorContains(t, err, "ALREADY_IN_DESIRED_STATE")
+
+		power, err := m.GetPower(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.PowerStatePoweredOn, power.State)
+
+		state, err := object.NewVirtualMachine(vc, ref).PowerState(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, types.VirtualMachinePowerStatePoweredOn, state)
+
+		vms, err = m.ListVMs(ctx, vm.FilterSpec{PowerStates: []string{vm.PowerStatePoweredOn}, Names: []string{"rest-vm"}})
+		require.NoError(t, err)
+		assert.Len(t, vms, 1)
+
+		// tools and guest
+		tools, err := m.GetTools(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.ToolsRunStateNotRunning, tools.RunState)
+
+		_, err = m.GetGuestIdentity(ctx, id)
+		assert.True(t, rest.IsStatusError(err, http.StatusServiceUnavailable))
+
+		require.NoError(t, m.UpdateTools(ctx, id, vm.ToolsUpdateSpec{UpgradePolicy: vm.ToolsUpgradePolicyUpgradeAtPowerCycle}))
+		tools, err = m.GetTools(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.ToolsUpgradePolicyUpgradeAtPowerCycle, tools.UpgradePolicy)
+
+		// guest info is only available while tools are running
+		id0 := obj.Reference().Value
+		sim := simulator.Map(ctx).Get(obj.Reference()).(*simulator.VirtualMachine)
+		sim.Guest.ToolsRunningStatus = string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)
+		sim.Guest.ToolsStatus = types.VirtualMachineToolsStatusToolsOld
+		sim.Guest.ToolsVersionStatus2 = string(types.VirtualMachineToolsVersionStatusGuestToolsSupportedOld)
+
+		tools, err = m.GetTools(ctx, id0)
+		require.NoError(t, err)
+		assert.Equal(t, vm.ToolsRunStateRunning, tools.RunState)
+		assert.Equal(t, "SUPPORTED_OLD", tools.VersionStatus)
+		require.NoError(t, m.UpgradeTools(ctx, id0))
+		tools, err = m.GetTools(ctx, id0)
+		require.NoError(t, err)
+		assert.Equal(t, "CURRENT", tools.VersionStatus)
+
+		identity, err := m.GetGuestIdentity(ctx, id0)
+		require.NoError(t, err)
+		assert.NotEmpty(t, identity.Name)
+
+		_, err = m.GetGuestNetworking(ctx, id0)
+		require.NoError(t, err)
+
+		_, err = m.ListGuestInterfaces(ctx, id0)
+		require.NoError(t, err)
+
+		require.NoError(t, m.Suspend(ctx, id))
+		require.NoError(t, m.Start(ctx, id))
+		require.NoError(t, m.Reset(ctx, id))
+		require.NoError(t, m.Stop(ctx, id))
+
+		require.NoError(t, m.DeleteVM(ctx, id))
+		_, err = m.GetVM(ctx, id)
+		assert.True(t, rest.IsStatusError(err, http.StatusNotFound))
+	})
+}
+
+func TestGuestCustomization(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		require.NoError(t, c.Login(ctx, simulator.DefaultLogin))
+
+		m := vm.NewManager(c)
+
+		obj, err := find.NewFinder(vc).VirtualMachine(ctx, "DC0_H0_VM0")
+		require.NoError(t, err)
+		id := obj.Reference().Value
+
+		info, err := m.GetGuestCustomization(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.CustomizationStatusIdle, info.Status)
+
+		spec := vm.CustomizationSetSpec{Name: "vcsim-linux"}
+
+		err = m.SetGuestCustomization(ctx, id, spec)
+		assert.ErrorContains(t, err, "NOT_ALLOWED_IN_CURRENT_STATE")
+
+		require.NoError(t, m.Stop(ctx, id))
+
+		err = m.SetGuestCustomization(ctx, id, vm.CustomizationSetSpec{})
+		assert.ErrorContains(t, err, "INVALID_ARGUMENT")
+
+		err = m.SetGuestCustomization(ctx, id, vm.CustomizationSetSpec{Name: "enoent"})
+		assert.True(t, rest.IsStatusError(err, http.StatusNotFound))
+
+		require.NoError(t, m.SetGuestCustomization(ctx, id, spec))
+
+		info, err = m.GetGuestCustomization(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.CustomizationStatusPending, info.Status)
+
+		err = m.SetGuestCustomization(ctx, id, spec)
+		assert.ErrorContains(t, err, "NOT_ALLOWED_IN_CURRENT_STATE")
+
+		// customization is applied at power on
+		require.NoError(t, m.Start(ctx, id))
+
+		info, err = m.GetGuestCustomization(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, vm.CustomizationStatusSucceeded, info.Status)
+		assert.NotNil(t, info.StartTime)
+		assert.NotNil(t, info.EndTime)
+
+		// inline spec
+		require.NoError(t, m.Stop(ctx, id))
+		require.NoError(t, m.SetGuestCustomization(ctx, id, vm.CustomizationSetSpec{
+			Spec: &guest.CustomizationSpec{
+				ConfigurationSpec: guest.ConfigurationSpec{
+					LinuxConfig: &guest.LinuxConfiguration{
+						Hostname: &guest.HostnameGenerator{Type: guest.HostnameFixed, FixedName: "inline"},
+					},
+				},
+				Interfaces: []guest.AdapterMapping{{
+					Adapter: guest.IPSettings{
+						Ipv4: &guest.Ipv4{Type: guest.IPStatic, IPAddress: "10.0.0.42", Prefix: 24},
+					},
+				}},
+			},
+		}))
+		require.NoError(t, m.Start(ctx, id))
+
+		var props mo.VirtualMachine
+		require.NoError(t, obj.Properties(ctx, obj.Reference(), []string{"guest"}, &props))
+		assert.Equal(t, "inline", props.Guest.HostName)
+		assert.Equal(t, "10.0.0.42", props.Guest.IpAddress)
+	})
+}
@@ -9,4 +9,8 @@ require (
 	github.com/vmware/govmomi v0.0.0-00010101000000-000000000000
 )
 
-require golang.org/x/text v0.28.0 // indirect
+require (
+	github.com/rasky/go-xdr v0.0.0-20170124162913-1a41d1a06c93 // indirect
+	github.com/vmware/vmw-guestinfo v0.0.0-20220317130741-510905f0efa3 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
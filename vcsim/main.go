@@ -41,7 +41,10 @@ import (
 	_ "github.com/vmware/govmomi/vapi/esx/settings/simulator"
 	_ "github.com/vmware/govmomi/vapi/namespace/simulator"
 	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vapi/vcenter/certificates/simulator"
 	_ "github.com/vmware/govmomi/vapi/vcenter/consumptiondomains/simulator"
+	_ "github.com/vmware/govmomi/vapi/vcenter/guest/simulator"
+	_ "github.com/vmware/govmomi/vapi/vcenter/identity/simulator"
 	_ "github.com/vmware/govmomi/vapi/vm/simulator"
 	_ "github.com/vmware/govmomi/vsan/simulator"
 	_ "github.com/vmware/govmomi/vslm/simulator"
@@ -70,6 +73,7 @@ func main() {
 	flag.IntVar(&model.OpaqueNetwork, "nsx", model.OpaqueNetwork, "Number of NSX backed opaque networks")
 	flag.IntVar(&model.Folder, "folder", model.Folder, "Number of folders")
 	flag.BoolVar(&model.Autostart, "autostart", model.Autostart, "Autostart model created VMs")
+	flag.BoolVar(&model.Toolbox, "toolbox", model.Toolbox, "Back model created VMs with an in-process toolbox")
 	v := &model.ServiceContent.About.ApiVersion
 	flag.StringVar(v, "api-version", *v, "API version")
 
@@ -152,6 +156,7 @@ func main() {
 		model.Datastore = opts.Datastore
 		model.Machine = opts.Machine
 		model.Autostart = opts.Autostart
+		model.Toolbox = opts.Toolbox
 		model.DelayConfig.Delay = opts.DelayConfig.Delay
 		model.DelayConfig.MethodDelay = opts.DelayConfig.MethodDelay
 		model.DelayConfig.DelayJitter = opts.DelayConfig.DelayJitter
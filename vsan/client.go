@@ -44,6 +44,14 @@ var (
 		Type:  "VimClusterVsanVcStretchedClusterSystem",
 		Value: "vsan-stretched-cluster-system",
 	}
+	VsanFileServiceSystemInstance = vimtypes.ManagedObjectReference{
+		Type:  "VsanFileServiceSystem",
+		Value: "vsan-cluster-file-service-system",
+	}
+	VsanVcClusterHealthSystemInstance = vimtypes.ManagedObjectReference{
+		Type:  "VsanVcClusterHealthSystem",
+		Value: "vsan-cluster-health-system",
+	}
 )
 
 // Client used for accessing vsan health APIs.
@@ -111,6 +119,25 @@ func (c *Client) VsanPerfQueryPerf(ctx context.Context, cluster *vimtypes.Manage
 	return res.Returnval, nil
 }
 
+// VsanQueryVcClusterHealthSummary returns the health summary of the given cluster.
+// If fields is empty, all fields of the summary are returned.
+func (c *Client) VsanQueryVcClusterHealthSummary(ctx context.Context, cluster vimtypes.ManagedObjectReference, fields []string, fetchFromCache bool) (*vsantypes.VsanClusterHealthSummary, error) {
+	req := vsantypes.VsanQueryVcClusterHealthSummary{
+		This:            VsanVcClusterHealthSystemInstance,
+		Cluster:         &cluster,
+		Fields:          fields,
+		IncludeObjUuids: vimtypes.NewBool(true),
+		FetchFromCache:  &fetchFromCache,
+	}
+
+	res, err := methods.VsanQueryVcClusterHealthSummary(ctx, c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.Returnval, nil
+}
+
 // VsanQueryObjectIdentities return host uuid
 func (c *Client) VsanQueryObjectIdentities(ctx context.Context, cluster vimtypes.ManagedObjectReference) (*vsantypes.VsanObjectIdentityAndHealth, error) {
 	req := vsantypes.VsanQueryObjectIdentities{
@@ -164,3 +191,82 @@ func (c *Client) VsanHostGetConfig(ctx context.Context, vsanSystem vimtypes.Mana
 		return nil, errors.New("host vSAN config not found")
 	}
 }
+
+// EnableFileService enables the vSAN file service on the given cluster, with the given configuration.
+func (c *Client) EnableFileService(ctx context.Context, cluster vimtypes.ManagedObjectReference, config vsantypes.VsanFileServiceConfig) (*object.Task, error) {
+	config.Enabled = true
+
+	return c.VsanClusterReconfig(ctx, cluster, vsantypes.VimVsanReconfigSpec{
+		FileServiceConfig: &config,
+	})
+}
+
+// CreateFileShare calls the vSAN file service's VsanCreateFileShare API.
+func (c *Client) CreateFileShare(ctx context.Context, cluster vimtypes.ManagedObjectReference, config vsantypes.VsanFileShareConfig) (*object.Task, error) {
+	req := vsantypes.VsanCreateFileShare{
+		This:    VsanFileServiceSystemInstance,
+		Config:  config,
+		Cluster: &cluster,
+	}
+
+	res, err := methods.VsanCreateFileShare(ctx, c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.NewTask(c.vim25Client, res.Returnval), nil
+}
+
+// QueryFileShares calls the vSAN file service's VsanClusterQueryFileShares API.
+func (c *Client) QueryFileShares(ctx context.Context, cluster vimtypes.ManagedObjectReference, spec vsantypes.VsanFileShareQuerySpec) (*vsantypes.FileShareQueryResult, error) {
+	req := vsantypes.VsanClusterQueryFileShares{
+		This:      VsanFileServiceSystemInstance,
+		QuerySpec: spec,
+		Cluster:   &cluster,
+	}
+
+	res, err := methods.VsanClusterQueryFileShares(ctx, c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, nil
+}
+
+// ReconfigureFileShare calls the vSAN file service's VsanReconfigureFileShare API.
+// Shares managed by another solution, such as CNS, can only be changed with force.
+func (c *Client) ReconfigureFileShare(ctx context.Context, cluster vimtypes.ManagedObjectReference, uuid string, config vsantypes.VsanFileShareConfig, force bool, deleteLabelKeys ...string) (*object.Task, error) {
+	req := vsantypes.VsanReconfigureFileShare{
+		This:            VsanFileServiceSystemInstance,
+		ShareUuid:       uuid,
+		Config:          config,
+		Cluster:         &cluster,
+		DeleteLabelKeys: deleteLabelKeys,
+		Force:           &force,
+	}
+
+	res, err := methods.VsanReconfigureFileShare(ctx, c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.NewTask(c.vim25Client, res.Returnval), nil
+}
+
+// RemoveFileShare calls the vSAN file service's VsanRemoveFileShare API.
+// Shares managed by another solution, such as CNS, can only be removed with force.
+func (c *Client) RemoveFileShare(ctx context.Context, cluster vimtypes.ManagedObjectReference, uuid string, force bool) (*object.Task, error) {
+	req := vsantypes.VsanRemoveFileShare{
+		This:      VsanFileServiceSystemInstance,
+		ShareUuid: uuid,
+		Cluster:   &cluster,
+		Force:     &force,
+	}
+
+	res, err := methods.VsanRemoveFileShare(ctx, c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.NewTask(c.vim25Client, res.Returnval), nil
+}
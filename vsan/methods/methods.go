@@ -3311,6 +3311,26 @@ func VsanReconfigureFileShare(ctx context.Context, r soap.RoundTripper, req *typ
 	return resBody.Res, nil
 }
 
+type VsanRemoveFileShareBody struct {
+	Req    *types.VsanRemoveFileShare         `xml:"urn:vsan VsanRemoveFileShare,omitempty"`
+	Res    *types.VsanRemoveFileShareResponse `xml:"urn:vsan VsanRemoveFileShareResponse,omitempty"`
+	Fault_ *soap.Fault                        `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *VsanRemoveFileShareBody) Fault() *soap.Fault { return b.Fault_ }
+
+func VsanRemoveFileShare(ctx context.Context, r soap.RoundTripper, req *types.VsanRemoveFileShare) (*types.VsanRemoveFileShareResponse, error) {
+	var reqBody, resBody VsanRemoveFileShareBody
+
+	reqBody.Req = req
+
+	if err := r.RoundTrip(ctx, &reqBody, &resBody); err != nil {
+		return nil, err
+	}
+
+	return resBody.Res, nil
+}
+
 type VsanClusterQueryFileSharesBody struct {
 	Req    *types.VsanClusterQueryFileShares         `xml:"urn:vsan VsanClusterQueryFileShares,omitempty"`
 	Res    *types.VsanClusterQueryFileSharesResponse `xml:"urn:vsan VsanClusterQueryFileSharesResponse,omitempty"`
@@ -0,0 +1,388 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	vim "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vsan"
+	"github.com/vmware/govmomi/vsan/methods"
+	"github.com/vmware/govmomi/vsan/types"
+)
+
+// File share protocols and the solutions that manage shares.
+const (
+	ProtocolNFSv3 = "NFSv3"
+	ProtocolNFSv4 = "NFSv4"
+	ProtocolSMB   = "SMB"
+
+	ManagedByUser = "user"
+	ManagedByCNS  = "cns"
+)
+
+// fileServerAddress is used for share access points when no file server IP is configured.
+const fileServerAddress = "10.0.0.10"
+
+// defaultQueryLimit is the number of shares returned per query page when the spec has no limit.
+const defaultQueryLimit = 32
+
+type fileShare struct {
+	types.VsanFileShare
+
+	cluster vim.ManagedObjectReference
+}
+
+type FileServiceSystem struct {
+	vim.ManagedObjectReference
+
+	shares map[string]*fileShare
+}
+
+// enabled returns a fault if the vSAN file service is not enabled on the given cluster.
+func (s *FileServiceSystem) enabled(ctx *simulator.Context, cluster *vim.ManagedObjectReference) vim.BaseMethodFault {
+	if cluster == nil {
+		return &vim.InvalidArgument{InvalidProperty: "cluster"}
+	}
+
+	if _, ok := ctx.For(vim25.Path).Map.Get(*cluster).(*simulator.ClusterComputeResource); !ok {
+		return &vim.ManagedObjectNotFound{Obj: *cluster}
+	}
+
+	vctx := ctx.For(vsan.Path)
+	config := vctx.Map.Get(vsan.VsanVcClusterConfigSystemInstance).(*ClusterConfigSystem)
+
+	var enabled bool
+	vctx.WithLock(config, func() {
+		fs := config.info(*cluster).FileServiceConfig
+		enabled = fs != nil && fs.Enabled
+	})
+
+	if !enabled {
+		return &vim.InvalidState{}
+	}
+
+	return nil
+}
+
+// address returns the file server address of the cluster's file service domain, if any.
+func address(ctx *simulator.Context, cluster vim.ManagedObjectReference) string {
+	vctx := ctx.For(vsan.Path)
+	config := vctx.Map.Get(vsan.VsanVcClusterConfigSystemInstance).(*ClusterConfigSystem)
+
+	addr := fileServerAddress
+	vctx.WithLock(config, func() {
+		fs := config.info(cluster).FileServiceConfig
+		if fs == nil {
+			return
+		}
+		for _, domain := range fs.Domains {
+			for _, ip := range domain.FileServerIpConfig {
+				if ip.IpAddress != "" {
+					addr = ip.IpAddress
+					return
+				}
+			}
+		}
+	})
+
+	return addr
+}
+
+func accessPoints(addr, name string, protocols []string) []vim.KeyValue {
+	var points []vim.KeyValue
+
+	for _, p := range protocols {
+		switch p {
+		case ProtocolNFSv3:
+			points = append(points, vim.KeyValue{Key: p, Value: fmt.Sprintf("%s:/%s", addr, name)})
+		case ProtocolNFSv4:
+			points = append(points, vim.KeyValue{Key: "NFSv4.1", Value: fmt.Sprintf("%s:/vsanfs/%s", addr, name)})
+		case ProtocolSMB:
+			points = append(points, vim.KeyValue{Key: p, Value: fmt.Sprintf(`\\%s\%s`, addr, name)})
+		}
+	}
+
+	return points
+}
+
+func validProtocols(protocols []string) bool {
+	for _, p := range protocols {
+		switch p {
+		case ProtocolNFSv3, ProtocolNFSv4, ProtocolSMB:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// CreateShare creates a file share on the given cluster, managed by the given solution.
+// The vSAN file service must be enabled on the cluster.
+func (s *FileServiceSystem) CreateShare(ctx *simulator.Context, cluster *vim.ManagedObjectReference, config types.VsanFileShareConfig, managedBy string) (*types.VsanFileShare, vim.BaseMethodFault) {
+	if err := s.enabled(ctx, cluster); err != nil {
+		return nil, err
+	}
+
+	if config.Name == "" {
+		return nil, &vim.InvalidArgument{InvalidProperty: "config.name"}
+	}
+
+	for _, share := range s.shares {
+		if share.cluster == *cluster && share.Config.Name == config.Name {
+			return nil, &vim.AlreadyExists{Name: config.Name}
+		}
+	}
+
+	if len(config.Protocols) == 0 {
+		config.Protocols = []string{ProtocolNFSv3, ProtocolNFSv4}
+	}
+	if !validProtocols(config.Protocols) {
+		return nil, &vim.InvalidArgument{InvalidProperty: "config.protocols"}
+	}
+
+	addr := address(ctx, *cluster)
+
+	share := &fileShare{
+		VsanFileShare: types.VsanFileShare{
+			Uuid:   uuid.New().String(),
+			Config: &config,
+			Runtime: &types.VsanFileShareRuntimeInfo{
+				Address:         addr,
+				VsanObjectUuids: []string{uuid.New().String()},
+				AccessPoints:    accessPoints(addr, config.Name, config.Protocols),
+				ManagedBy:       managedBy,
+			},
+		},
+		cluster: *cluster,
+	}
+
+	if s.shares == nil {
+		s.shares = make(map[string]*fileShare)
+	}
+	s.shares[share.Uuid] = share
+
+	return &share.VsanFileShare, nil
+}
+
+// share returns the file share with the given uuid, which must be on the given cluster if not nil.
+func (s *FileServiceSystem) share(cluster *vim.ManagedObjectReference, uuid string, force bool) (*fileShare, vim.BaseMethodFault) {
+	share, ok := s.shares[uuid]
+	if !ok || (cluster != nil && share.cluster != *cluster) {
+		return nil, &vim.NotFound{}
+	}
+
+	if share.Runtime.ManagedBy != ManagedByUser && !force {
+		return nil, &vim.InvalidArgument{InvalidProperty: "force"}
+	}
+
+	return share, nil
+}
+
+// Share returns the file share with the given uuid, if any.
+func (s *FileServiceSystem) Share(uuid string) *types.VsanFileShare {
+	if share, ok := s.shares[uuid]; ok {
+		return &share.VsanFileShare
+	}
+	return nil
+}
+
+// ReconfigureShare updates the file share with the given uuid on the given cluster,
+// or on any cluster if nil, as with CNS which addresses shares by uuid only.
+// Config fields that are not set are left unchanged, labels are merged with the existing labels.
+// Shares managed by another solution can only be changed with force.
+func (s *FileServiceSystem) ReconfigureShare(cluster *vim.ManagedObjectReference, uuid string, config types.VsanFileShareConfig, deleteLabelKeys []string, force bool) vim.BaseMethodFault {
+	share, err := s.share(cluster, uuid, force)
+	if err != nil {
+		return err
+	}
+
+	current := share.Config
+
+	if config.Name != "" && config.Name != current.Name {
+		return &vim.InvalidArgument{InvalidProperty: "config.name"}
+	}
+	if config.Quota != "" {
+		current.Quota = config.Quota
+	}
+	if config.SoftQuota != "" {
+		current.SoftQuota = config.SoftQuota
+	}
+	if config.StoragePolicy != nil {
+		current.StoragePolicy = config.StoragePolicy
+	}
+	if config.Permission != nil {
+		current.Permission = config.Permission
+	}
+	if config.SmbOptions != nil {
+		current.SmbOptions = config.SmbOptions
+	}
+	if config.NfsSecType != "" {
+		current.NfsSecType = config.NfsSecType
+	}
+	if len(config.Protocols) != 0 {
+		if !validProtocols(config.Protocols) {
+			return &vim.InvalidArgument{InvalidProperty: "config.protocols"}
+		}
+		current.Protocols = config.Protocols
+		share.Runtime.AccessPoints = accessPoints(share.Runtime.Address, current.Name, current.Protocols)
+	}
+
+	current.Labels = slices.DeleteFunc(current.Labels, func(kv vim.KeyValue) bool {
+		return slices.Contains(deleteLabelKeys, kv.Key)
+	})
+
+	for _, label := range config.Labels {
+		i := slices.IndexFunc(current.Labels, func(kv vim.KeyValue) bool {
+			return kv.Key == label.Key
+		})
+		if i == -1 {
+			current.Labels = append(current.Labels, label)
+		} else {
+			current.Labels[i] = label
+		}
+	}
+
+	return nil
+}
+
+// RemoveShare removes the file share with the given uuid on the given cluster, or on any cluster if nil.
+// Shares managed by another solution can only be removed with force.
+func (s *FileServiceSystem) RemoveShare(cluster *vim.ManagedObjectReference, uuid string, force bool) vim.BaseMethodFault {
+	if _, err := s.share(cluster, uuid, force); err != nil {
+		return err
+	}
+
+	delete(s.shares, uuid)
+
+	return nil
+}
+
+func matchesShare(spec types.VsanFileShareQuerySpec, share *fileShare) bool {
+	if len(spec.Uuids) != 0 && !slices.Contains(spec.Uuids, share.Uuid) {
+		return false
+	}
+	if len(spec.Names) != 0 && !slices.Contains(spec.Names, share.Config.Name) {
+		return false
+	}
+	if len(spec.ManagedBy) != 0 && !slices.Contains(spec.ManagedBy, share.Runtime.ManagedBy) {
+		return false
+	}
+	if len(spec.Protocols) != 0 && !slices.ContainsFunc(spec.Protocols, func(p string) bool {
+		return slices.Contains(share.Config.Protocols, p)
+	}) {
+		return false
+	}
+	return true
+}
+
+func (s *FileServiceSystem) VsanCreateFileShare(ctx *simulator.Context, req *types.VsanCreateFileShare) soap.HasFault {
+	task := simulator.CreateTask(s, "createFileShare", func(*simulator.Task) (vim.AnyType, vim.BaseMethodFault) {
+		share, err := s.CreateShare(ctx, req.Cluster, req.Config, ManagedByUser)
+		if err != nil {
+			return nil, err
+		}
+		return share.Uuid, nil
+	})
+
+	return &methods.VsanCreateFileShareBody{
+		Res: &types.VsanCreateFileShareResponse{
+			Returnval: task.Run(ctx),
+		},
+	}
+}
+
+func (s *FileServiceSystem) VsanReconfigureFileShare(ctx *simulator.Context, req *types.VsanReconfigureFileShare) soap.HasFault {
+	task := simulator.CreateTask(s, "reconfigureFileShare", func(*simulator.Task) (vim.AnyType, vim.BaseMethodFault) {
+		if err := s.enabled(ctx, req.Cluster); err != nil {
+			return nil, err
+		}
+		force := req.Force != nil && *req.Force
+		return nil, s.ReconfigureShare(req.Cluster, req.ShareUuid, req.Config, req.DeleteLabelKeys, force)
+	})
+
+	return &methods.VsanReconfigureFileShareBody{
+		Res: &types.VsanReconfigureFileShareResponse{
+			Returnval: task.Run(ctx),
+		},
+	}
+}
+
+func (s *FileServiceSystem) VsanRemoveFileShare(ctx *simulator.Context, req *types.VsanRemoveFileShare) soap.HasFault {
+	task := simulator.CreateTask(s, "removeFileShare", func(*simulator.Task) (vim.AnyType, vim.BaseMethodFault) {
+		if err := s.enabled(ctx, req.Cluster); err != nil {
+			return nil, err
+		}
+		force := req.Force != nil && *req.Force
+		return nil, s.RemoveShare(req.Cluster, req.ShareUuid, force)
+	})
+
+	return &methods.VsanRemoveFileShareBody{
+		Res: &types.VsanRemoveFileShareResponse{
+			Returnval: task.Run(ctx),
+		},
+	}
+}
+
+func (s *FileServiceSystem) VsanClusterQueryFileShares(ctx *simulator.Context, req *types.VsanClusterQueryFileShares) soap.HasFault {
+	body := new(methods.VsanClusterQueryFileSharesBody)
+
+	if err := s.enabled(ctx, req.Cluster); err != nil {
+		body.Fault_ = simulator.Fault("", err)
+		return body
+	}
+
+	offset := 0
+	if req.QuerySpec.Offset != "" {
+		var err error
+		offset, err = strconv.Atoi(req.QuerySpec.Offset)
+		if err != nil || offset < 0 {
+			body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "querySpec.offset"})
+			return body
+		}
+	}
+
+	limit := int(req.QuerySpec.Limit)
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+
+	var matches []types.VsanFileShare
+	for _, share := range s.shares {
+		if share.cluster == *req.Cluster && matchesShare(req.QuerySpec, share) {
+			matches = append(matches, share.VsanFileShare)
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b types.VsanFileShare) int {
+		return strings.Compare(a.Config.Name, b.Config.Name)
+	})
+
+	res := &types.FileShareQueryResult{
+		TotalShareCount: int64(len(matches)),
+	}
+
+	if offset < len(matches) {
+		end := min(offset+limit, len(matches))
+		res.FileShares = matches[offset:end]
+		if end < len(matches) {
+			res.NextOffset = strconv.Itoa(end)
+		}
+	}
+
+	body.Res = &types.VsanClusterQueryFileSharesResponse{
+		Returnval: res,
+	}
+
+	return body
+}
@@ -0,0 +1,418 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	vim "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vsan"
+	"github.com/vmware/govmomi/vsan/methods"
+	"github.com/vmware/govmomi/vsan/types"
+)
+
+// Health states of vSAN health tests, groups and clusters.
+const (
+	HealthGreen  = "green"
+	HealthYellow = "yellow"
+	HealthRed    = "red"
+)
+
+// Health states of vSAN objects.
+const (
+	ObjectHealthy      = "healthy"
+	ObjectInaccessible = "inaccessible"
+	ObjectReduced      = "reducedavailabilitywithnorebuild"
+)
+
+// testPrefix is the prefix of health group and test IDs.
+const testPrefix = "com.vmware.vsan.health.test."
+
+type healthTest struct {
+	id, name, description string
+}
+
+type healthGroup struct {
+	id, name string
+	tests    []healthTest
+}
+
+// healthGroups is the catalog of simulated health tests.
+var healthGroups = []healthGroup{
+	{"cluster", "Cluster", []healthTest{
+		{"clomdliveness", "CLOMD liveness", "Checks whether the CLOMD service is alive on all hosts."},
+		{"advcfgsync", "Advanced vSAN configuration in sync", "Checks that advanced vSAN settings are consistent across hosts."},
+	}},
+	{"network", "Network", []healthTest{
+		{"hostdisconnected", "Hosts disconnected from VC", "Checks that all hosts in the cluster are connected to vCenter."},
+		{"clusterpartition", "vSAN cluster partition", "Checks that all hosts are in a single vSAN network partition."},
+		{"vsanvmknic", "All hosts have a vSAN vmknic configured", "Checks that every host has a VMkernel adapter tagged for vSAN traffic."},
+	}},
+	{"physicaldisks", "Physical disk", []healthTest{
+		{"physdiskoverall", "Operation health", "Checks the operational state of all physical disks."},
+		{"physdiskcapacity", "Disk capacity", "Checks the used capacity of all physical disks."},
+	}},
+	{"data", "Data", []healthTest{
+		{"objecthealth", "vSAN object health", "Checks the availability of all vSAN objects."},
+	}},
+	{"perfsvc", "Performance service", []healthTest{
+		{"statsdb", "Stats DB object", "Checks the health of the performance service stats object."},
+	}},
+}
+
+// worse returns the more severe of the given health states.
+func worse(a, b string) string {
+	rank := func(s string) int {
+		return slices.Index([]string{HealthGreen, HealthYellow, HealthRed}, s)
+	}
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
+// clusterHosts returns the hosts of the given cluster.
+func clusterHosts(ctx *simulator.Context, cluster vim.ManagedObjectReference) ([]*simulator.HostSystem, vim.BaseMethodFault) {
+	vctx := ctx.For(vim25.Path)
+
+	c, ok := vctx.Map.Get(cluster).(*simulator.ClusterComputeResource)
+	if !ok {
+		return nil, &vim.ManagedObjectNotFound{Obj: cluster}
+	}
+
+	var hosts []*simulator.HostSystem
+	for _, ref := range c.Host {
+		hosts = append(hosts, vctx.Map.Get(ref).(*simulator.HostSystem))
+	}
+
+	return hosts, nil
+}
+
+type ObjectSystem struct {
+	vim.ManagedObjectReference
+
+	health map[string]string
+}
+
+// SetObjectHealth sets the health state of the vSAN object with the given uuid.
+// An empty health resets the object to healthy.
+func (s *ObjectSystem) SetObjectHealth(uuid, health string) {
+	if s.health == nil {
+		s.health = make(map[string]string)
+	}
+
+	if health == "" {
+		delete(s.health, uuid)
+	} else {
+		s.health[uuid] = health
+	}
+}
+
+type vsanObject struct {
+	types.VsanObjectIdentity
+
+	health string
+}
+
+// objects returns the vSAN objects of the given cluster, backed by the cluster's file shares.
+func (s *ObjectSystem) objects(ctx *simulator.Context, cluster vim.ManagedObjectReference) []vsanObject {
+	vctx := ctx.For(vsan.Path)
+	fs := vctx.Map.Get(vsan.VsanFileServiceSystemInstance).(*FileServiceSystem)
+
+	var objects []vsanObject
+	vctx.WithLock(fs, func() {
+		for _, share := range fs.shares {
+			if share.cluster != cluster {
+				continue
+			}
+			for _, id := range share.Runtime.VsanObjectUuids {
+				health := s.health[id]
+				if health == "" {
+					health = ObjectHealthy
+				}
+				objects = append(objects, vsanObject{
+					VsanObjectIdentity: types.VsanObjectIdentity{
+						Uuid:        id,
+						Type:        "fileShare",
+						Description: share.Config.Name,
+					},
+					health: health,
+				})
+			}
+		}
+	})
+
+	slices.SortFunc(objects, func(a, b vsanObject) int {
+		return strings.Compare(a.Uuid, b.Uuid)
+	})
+
+	return objects
+}
+
+// overall summarizes the health of the given objects, grouped by health state.
+func overall(objects []vsanObject) *types.VsanObjectOverallHealth {
+	res := &types.VsanObjectOverallHealth{
+		ObjectVersionCompliance: vim.NewBool(true),
+	}
+
+	for _, obj := range objects {
+		i := slices.IndexFunc(res.ObjectHealthDetail, func(h types.VsanObjectHealth) bool {
+			return h.Health == obj.health
+		})
+		if i == -1 {
+			res.ObjectHealthDetail = append(res.ObjectHealthDetail, types.VsanObjectHealth{Health: obj.health})
+			i = len(res.ObjectHealthDetail) - 1
+		}
+		res.ObjectHealthDetail[i].NumObjects++
+		res.ObjectHealthDetail[i].ObjUuids = append(res.ObjectHealthDetail[i].ObjUuids, obj.Uuid)
+	}
+
+	return res
+}
+
+func (s *ObjectSystem) VsanQueryObjectIdentities(ctx *simulator.Context, req *types.VsanQueryObjectIdentities) soap.HasFault {
+	body := new(methods.VsanQueryObjectIdentitiesBody)
+
+	if req.Cluster == nil {
+		body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "cluster"})
+		return body
+	}
+
+	if _, err := clusterHosts(ctx, *req.Cluster); err != nil {
+		body.Fault_ = simulator.Fault("", err)
+		return body
+	}
+
+	var objects []vsanObject
+	for _, obj := range s.objects(ctx, *req.Cluster) {
+		if len(req.ObjUuids) != 0 && !slices.Contains(req.ObjUuids, obj.Uuid) {
+			continue
+		}
+		if len(req.ObjTypes) != 0 && !slices.Contains(req.ObjTypes, obj.Type) {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+
+	res := &types.VsanObjectIdentityAndHealth{}
+
+	if req.IncludeObjIdentity == nil || *req.IncludeObjIdentity {
+		for _, obj := range objects {
+			res.Identities = append(res.Identities, obj.VsanObjectIdentity)
+		}
+	}
+
+	if req.IncludeHealth != nil && *req.IncludeHealth {
+		res.Health = overall(objects)
+	}
+
+	body.Res = &types.VsanQueryObjectIdentitiesResponse{
+		Returnval: res,
+	}
+
+	return body
+}
+
+type HealthSystem struct {
+	vim.ManagedObjectReference
+
+	tests map[vim.ManagedObjectReference]map[string]string
+}
+
+// SetTestHealth overrides the result of the health test with the given ID on the given cluster,
+// for example to simulate a failing check.  The ID may be given with or without the
+// "com.vmware.vsan.health.test." prefix.  An empty health removes the override.
+func (s *HealthSystem) SetTestHealth(cluster vim.ManagedObjectReference, testID, health string) {
+	testID = strings.TrimPrefix(testID, testPrefix)
+
+	if s.tests == nil {
+		s.tests = make(map[vim.ManagedObjectReference]map[string]string)
+	}
+
+	tests := s.tests[cluster]
+	if tests == nil {
+		tests = make(map[string]string)
+		s.tests[cluster] = tests
+	}
+
+	if health == "" {
+		delete(tests, testID)
+	} else {
+		tests[testID] = health
+	}
+}
+
+// hostTest evaluates the test against each host in the cluster.
+func hostTest(test *types.VsanClusterHealthTest, hosts []*simulator.HostSystem, healthy func(*simulator.HostSystem) bool) {
+	table := &types.VsanClusterHealthResultTable{
+		Columns: []types.VsanClusterHealthResultColumnInfo{
+			{Label: "Host", Type: "string"},
+			{Label: "Status", Type: "health"},
+		},
+	}
+
+	test.TestHealth = HealthGreen
+	test.TestAllEntities = int32(len(hosts))
+
+	for _, host := range hosts {
+		status := HealthGreen
+		if healthy(host) {
+			test.TestHealthyEntities++
+		} else {
+			status = HealthRed
+			test.TestHealth = HealthRed
+		}
+		table.Rows = append(table.Rows, types.VsanClusterHealthResultRow{
+			Values: []string{host.Name, status},
+		})
+	}
+
+	test.TestDetails = []types.BaseVsanClusterHealthResultBase{table}
+}
+
+// objectTest evaluates the test against each vSAN object in the cluster.
+func objectTest(test *types.VsanClusterHealthTest, objects []vsanObject) {
+	test.TestHealth = HealthGreen
+	test.TestAllEntities = int32(len(objects))
+
+	for _, obj := range objects {
+		switch obj.health {
+		case ObjectHealthy:
+			test.TestHealthyEntities++
+		case ObjectInaccessible:
+			test.TestHealth = HealthRed
+		default:
+			test.TestHealth = worse(test.TestHealth, HealthYellow)
+		}
+	}
+}
+
+func connected(host *simulator.HostSystem) bool {
+	return host.Runtime.ConnectionState == vim.HostSystemConnectionStateConnected
+}
+
+func (s *HealthSystem) groups(cluster vim.ManagedObjectReference, hosts []*simulator.HostSystem, objects []vsanObject) ([]types.VsanClusterHealthGroup, string) {
+	var groups []types.VsanClusterHealthGroup
+	health := HealthGreen
+
+	for _, g := range healthGroups {
+		group := types.VsanClusterHealthGroup{
+			GroupId:     testPrefix + g.id,
+			GroupName:   g.name,
+			GroupHealth: HealthGreen,
+			InProgress:  vim.NewBool(false),
+		}
+
+		for _, t := range g.tests {
+			test := types.VsanClusterHealthTest{
+				TestId:               testPrefix + t.id,
+				TestName:             t.name,
+				TestDescription:      t.description,
+				TestShortDescription: t.description,
+				TestHealth:           HealthGreen,
+			}
+
+			switch t.id {
+			case "hostdisconnected":
+				hostTest(&test, hosts, connected)
+			case "objecthealth":
+				objectTest(&test, objects)
+			}
+
+			if h, ok := s.tests[cluster][t.id]; ok {
+				test.TestHealth = h
+			}
+
+			group.GroupHealth = worse(group.GroupHealth, test.TestHealth)
+			group.GroupTests = append(group.GroupTests, test)
+		}
+
+		health = worse(health, group.GroupHealth)
+		groups = append(groups, group)
+	}
+
+	return groups, health
+}
+
+func (s *HealthSystem) VsanQueryVcClusterHealthSummary(ctx *simulator.Context, req *types.VsanQueryVcClusterHealthSummary) soap.HasFault {
+	body := new(methods.VsanQueryVcClusterHealthSummaryBody)
+
+	if req.Cluster == nil {
+		body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "cluster"})
+		return body
+	}
+
+	hosts, err := clusterHosts(ctx, *req.Cluster)
+	if err != nil {
+		body.Fault_ = simulator.Fault("", err)
+		return body
+	}
+
+	vctx := ctx.For(vsan.Path)
+	objs := vctx.Map.Get(vsan.VsanQueryObjectIdentitiesInstance).(*ObjectSystem)
+	var objects []vsanObject
+	vctx.WithLock(objs, func() {
+		objects = objs.objects(ctx, *req.Cluster)
+	})
+
+	include := func(field string) bool {
+		return len(req.Fields) == 0 || slices.Contains(req.Fields, field)
+	}
+
+	groups, health := s.groups(*req.Cluster, hosts, objects)
+
+	now := time.Now()
+	summary := types.VsanClusterHealthSummary{
+		Cluster:                  req.Cluster,
+		Timestamp:                &now,
+		OverallHealth:            health,
+		OverallHealthDescription: fmt.Sprintf("Overall health status is %s", health),
+	}
+
+	if include("groups") {
+		summary.Groups = groups
+	}
+
+	if include("objectHealth") {
+		summary.ObjectHealth = overall(objects)
+		if req.IncludeObjUuids == nil || !*req.IncludeObjUuids {
+			for i := range summary.ObjectHealth.ObjectHealthDetail {
+				summary.ObjectHealth.ObjectHealthDetail[i].ObjUuids = nil
+			}
+		}
+	}
+
+	if include("clusterStatus") {
+		status := &types.VsanClusterHealthSystemStatusResult{
+			Status:    HealthGreen,
+			GoalState: "installed",
+		}
+		for _, host := range hosts {
+			hs := types.VsanHostHealthSystemStatusResult{
+				Hostname: host.Name,
+				Status:   HealthGreen,
+			}
+			if !connected(host) {
+				hs.Status = HealthRed
+				hs.Issues = []string{"Host is not connected"}
+				status.Status = HealthRed
+			}
+			status.TrackedHostsStatus = append(status.TrackedHostsStatus, hs)
+		}
+		summary.ClusterStatus = status
+	}
+
+	body.Res = &types.VsanQueryVcClusterHealthSummaryResponse{
+		Returnval: summary,
+	}
+
+	return body
+}
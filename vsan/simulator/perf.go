@@ -0,0 +1,233 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/soap"
+	vim "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vsan"
+	"github.com/vmware/govmomi/vsan/methods"
+	"github.com/vmware/govmomi/vsan/types"
+)
+
+// Performance entity types supported by the simulator.
+const (
+	PerfClusterDomClient  = "cluster-domclient"
+	PerfClusterDomCompMgr = "cluster-domcompmgr"
+	PerfHostDomClient     = "host-domclient"
+	PerfHostDomCompMgr    = "host-domcompmgr"
+	PerfDiskGroup         = "disk-group"
+)
+
+// perfInterval is the default sample interval in seconds.
+const perfInterval = 300
+
+// perfMaxRange bounds the time range of a query, limiting the number of samples generated.
+const perfMaxRange = 24 * time.Hour
+
+// perfTimeFormat is the format of timestamps in VsanPerfEntityMetricCSV.SampleInfo.
+const perfTimeFormat = "2006-01-02 15:04:05"
+
+type perfMetric struct {
+	label string
+	max   uint32
+}
+
+var (
+	domMetrics = []perfMetric{
+		{"iopsRead", 2000},
+		{"iopsWrite", 2000},
+		{"throughputRead", 100 << 20},
+		{"throughputWrite", 100 << 20},
+		{"latencyAvgRead", 5000},
+		{"latencyAvgWrite", 5000},
+		{"congestion", 10},
+		{"oio", 32},
+	}
+
+	diskGroupMetrics = []perfMetric{
+		{"iopsRead", 2000},
+		{"iopsWrite", 2000},
+		{"latencyAvgRead", 5000},
+		{"latencyAvgWrite", 5000},
+		{"capacityUsed", 1 << 30},
+		{"wbFreePct", 100},
+	}
+
+	perfMetrics = map[string][]perfMetric{
+		PerfClusterDomClient:  domMetrics,
+		PerfClusterDomCompMgr: domMetrics,
+		PerfHostDomClient:     domMetrics,
+		PerfHostDomCompMgr:    domMetrics,
+		PerfDiskGroup:         diskGroupMetrics,
+	}
+)
+
+type PerformanceManager struct {
+	vim.ManagedObjectReference
+}
+
+// diskGroupUUID returns the uuid of the simulated disk group of the host with the given vSAN node uuid.
+func diskGroupUUID(node string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(node+"/disk-group")).String()
+}
+
+// entities returns the uuids of the cluster's entities of the given type.
+func (m *PerformanceManager) entities(ctx *simulator.Context, cluster vim.ManagedObjectReference, kind string) ([]string, vim.BaseMethodFault) {
+	hosts, err := clusterHosts(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	switch kind {
+	case PerfClusterDomClient, PerfClusterDomCompMgr:
+		vctx := ctx.For(vsan.Path)
+		config := vctx.Map.Get(vsan.VsanVcClusterConfigSystemInstance).(*ClusterConfigSystem)
+		vctx.WithLock(config, func() {
+			ids = append(ids, config.info(cluster).DefaultConfig.Uuid)
+		})
+	case PerfHostDomClient, PerfHostDomCompMgr:
+		for _, host := range hosts {
+			ids = append(ids, host.Summary.Hardware.Uuid)
+		}
+	case PerfDiskGroup:
+		for _, host := range hosts {
+			ids = append(ids, diskGroupUUID(host.Summary.Hardware.Uuid))
+		}
+	}
+
+	return ids, nil
+}
+
+// sample returns a stable pseudo-random value for the given entity metric at time t.
+func sample(entity string, metric perfMetric, t time.Time) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(entity + metric.label + t.Format(time.RFC3339)))
+	return strconv.FormatUint(uint64(h.Sum32()%metric.max), 10)
+}
+
+// queryRange returns the sample interval and the time range of the given spec,
+// defaulting to the last hour.
+func queryRange(spec types.VsanPerfQuerySpec) (time.Duration, time.Time, time.Time) {
+	interval := time.Duration(spec.Interval) * time.Second
+	if interval <= 0 {
+		interval = perfInterval * time.Second
+	}
+
+	end := time.Now().UTC()
+	if spec.EndTime != nil {
+		end = spec.EndTime.UTC()
+	}
+	start := end.Add(-time.Hour)
+	if spec.StartTime != nil {
+		start = spec.StartTime.UTC()
+	}
+
+	return interval, start, end
+}
+
+// query returns the time series of the given entity between the spec start and end times.
+func query(spec types.VsanPerfQuerySpec, kind, id string) types.VsanPerfEntityMetricCSV {
+	interval, start, end := queryRange(spec)
+
+	var times []time.Time
+	for t := start.Truncate(interval); !t.After(end); t = t.Add(interval) {
+		if !t.Before(start) {
+			times = append(times, t)
+		}
+	}
+
+	entity := kind + ":" + id
+
+	res := types.VsanPerfEntityMetricCSV{
+		EntityRefId: entity,
+	}
+
+	info := make([]string, len(times))
+	for i, t := range times {
+		info[i] = t.Format(perfTimeFormat)
+	}
+	res.SampleInfo = strings.Join(info, ",")
+
+	for _, metric := range perfMetrics[kind] {
+		if len(spec.Labels) != 0 && !slices.Contains(spec.Labels, metric.label) {
+			continue
+		}
+
+		values := make([]string, len(times))
+		for i, t := range times {
+			values[i] = sample(entity, metric, t)
+		}
+
+		res.Value = append(res.Value, types.VsanPerfMetricSeriesCSV{
+			MetricId: types.VsanPerfMetricId{
+				Label:                  metric.label,
+				Group:                  spec.Group,
+				MetricsCollectInterval: int32(interval.Seconds()),
+			},
+			Values: strings.Join(values, ","),
+		})
+	}
+
+	return res
+}
+
+func (m *PerformanceManager) VsanPerfQueryPerf(ctx *simulator.Context, req *types.VsanPerfQueryPerf) soap.HasFault {
+	body := new(methods.VsanPerfQueryPerfBody)
+
+	if req.Cluster == nil {
+		body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "cluster"})
+		return body
+	}
+
+	var res []types.VsanPerfEntityMetricCSV
+
+	for _, spec := range req.QuerySpecs {
+		kind, id, _ := strings.Cut(spec.EntityRefId, ":")
+		if _, ok := perfMetrics[kind]; !ok {
+			body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "querySpecs.entityRefId"})
+			return body
+		}
+
+		_, start, end := queryRange(spec)
+		if end.Before(start) {
+			body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "querySpecs.endTime"})
+			return body
+		}
+		if end.Sub(start) > perfMaxRange {
+			body.Fault_ = simulator.Fault("", &vim.InvalidArgument{InvalidProperty: "querySpecs.startTime"})
+			return body
+		}
+
+		ids, err := m.entities(ctx, *req.Cluster, kind)
+		if err != nil {
+			body.Fault_ = simulator.Fault("", err)
+			return body
+		}
+
+		for _, entity := range ids {
+			if id == "" || id == "*" || id == entity {
+				res = append(res, query(spec, kind, entity))
+			}
+		}
+	}
+
+	body.Res = &types.VsanPerfQueryPerfResponse{
+		Returnval: res,
+	}
+
+	return body
+}
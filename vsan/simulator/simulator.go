@@ -36,6 +36,22 @@ func New() *simulator.Registry {
 		ManagedObjectReference: vsan.VsanVcClusterConfigSystemInstance,
 	})
 
+	r.Put(&FileServiceSystem{
+		ManagedObjectReference: vsan.VsanFileServiceSystemInstance,
+	})
+
+	r.Put(&HealthSystem{
+		ManagedObjectReference: vsan.VsanVcClusterHealthSystemInstance,
+	})
+
+	r.Put(&ObjectSystem{
+		ManagedObjectReference: vsan.VsanQueryObjectIdentitiesInstance,
+	})
+
+	r.Put(&PerformanceManager{
+		ManagedObjectReference: vsan.VsanPerformanceManagerInstance,
+	})
+
 	return r
 }
 
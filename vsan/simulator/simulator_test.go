@@ -0,0 +1,241 @@
+// © Broadcom. All Rights Reserved.
+// The term "Broadcom" refers to Broadcom Inc. and/or its subsidiaries.
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	vim "github.com/vmware/govmomi/vim25/types"
+	"github.com/vmware/govmomi/vsan"
+	"github.com/vmware/govmomi/vsan/types"
+)
+
+func TestHealth(t *testing.T) {
+	model := simulator.VPX()
+	model.Cluster = 1
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		r := New()
+		model.Service.RegisterSDK(r)
+		hs := r.Get(vsan.VsanVcClusterHealthSystemInstance).(*HealthSystem)
+		objs := r.Get(vsan.VsanQueryObjectIdentitiesInstance).(*ObjectSystem)
+
+		vc, err := vsan.NewClient(ctx, c)
+		require.NoError(t, err)
+
+		cluster, err := find.NewFinder(c).ClusterComputeResource(ctx, "DC0_C0")
+		require.NoError(t, err)
+		ref := cluster.Reference()
+
+		test := func(summary *types.VsanClusterHealthSummary, id string) *types.VsanClusterHealthTest {
+			for _, g := range summary.Groups {
+				for i := range g.GroupTests {
+					if strings.HasSuffix(g.GroupTests[i].TestId, "."+id) {
+						return &g.GroupTests[i]
+					}
+				}
+			}
+			t.Fatalf("test %s not found", id)
+			return nil
+		}
+
+		summary, err := vc.VsanQueryVcClusterHealthSummary(ctx, ref, nil, false)
+		require.NoError(t, err)
+		assert.Equal(t, HealthGreen, summary.OverallHealth)
+		assert.Len(t, summary.ClusterStatus.TrackedHostsStatus, 3)
+
+		disconnected := test(summary, "hostdisconnected")
+		assert.Equal(t, int32(3), disconnected.TestAllEntities)
+		assert.Equal(t, int32(3), disconnected.TestHealthyEntities)
+		require.Len(t, disconnected.TestDetails, 1)
+		assert.Len(t, disconnected.TestDetails[0].(*types.VsanClusterHealthResultTable).Rows, 3)
+
+		// inject a failing check
+		hs.SetTestHealth(ref, "clomdliveness", HealthRed)
+
+		summary, err = vc.VsanQueryVcClusterHealthSummary(ctx, ref, []string{"groups"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, HealthRed, summary.OverallHealth)
+		assert.Equal(t, HealthRed, test(summary, "clomdliveness").TestHealth)
+		assert.Nil(t, summary.ObjectHealth)
+		assert.Nil(t, summary.ClusterStatus)
+
+		hs.SetTestHealth(ref, "com.vmware.vsan.health.test.clomdliveness", "")
+
+		// object health is backed by file shares
+		task, err := vc.EnableFileService(ctx, ref, types.VsanFileServiceConfig{})
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		task, err = vc.CreateFileShare(ctx, ref, types.VsanFileShareConfig{Name: "share1"})
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		shares, err := vc.QueryFileShares(ctx, ref, types.VsanFileShareQuerySpec{})
+		require.NoError(t, err)
+		require.Len(t, shares.FileShares, 1)
+		objUuid := shares.FileShares[0].Runtime.VsanObjectUuids[0]
+
+		objs.SetObjectHealth(objUuid, ObjectInaccessible)
+
+		summary, err = vc.VsanQueryVcClusterHealthSummary(ctx, ref, nil, false)
+		require.NoError(t, err)
+		assert.Equal(t, HealthRed, summary.OverallHealth)
+		assert.Equal(t, HealthRed, test(summary, "objecthealth").TestHealth)
+		require.Len(t, summary.ObjectHealth.ObjectHealthDetail, 1)
+		assert.Equal(t, ObjectInaccessible, summary.ObjectHealth.ObjectHealthDetail[0].Health)
+		assert.Equal(t, []string{objUuid}, summary.ObjectHealth.ObjectHealthDetail[0].ObjUuids)
+
+		ids, err := vc.VsanQueryObjectIdentities(ctx, ref)
+		require.NoError(t, err)
+		require.Len(t, ids.Identities, 1)
+		assert.Equal(t, objUuid, ids.Identities[0].Uuid)
+
+		objs.SetObjectHealth(objUuid, "")
+
+		summary, err = vc.VsanQueryVcClusterHealthSummary(ctx, ref, nil, false)
+		require.NoError(t, err)
+		assert.Equal(t, HealthGreen, summary.OverallHealth)
+
+		_, err = vc.VsanQueryVcClusterHealthSummary(ctx, vim.ManagedObjectReference{Type: "ClusterComputeResource", Value: "enoent"}, nil, false)
+		assert.Error(t, err)
+
+		return nil
+	}, model)
+}
+
+func TestPerf(t *testing.T) {
+	model := simulator.VPX()
+	model.Cluster = 1
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		vc, err := vsan.NewClient(ctx, c)
+		require.NoError(t, err)
+
+		cluster, err := find.NewFinder(c).ClusterComputeResource(ctx, "DC0_C0")
+		require.NoError(t, err)
+		ref := cluster.Reference()
+
+		end := time.Now().Truncate(time.Hour)
+		start := end.Add(-time.Hour)
+
+		query := func(entity string, labels ...string) []types.VsanPerfEntityMetricCSV {
+			t.Helper()
+			res, err := vc.VsanPerfQueryPerf(ctx, &ref, []types.VsanPerfQuerySpec{{
+				EntityRefId: entity,
+				StartTime:   &start,
+				EndTime:     &end,
+				Labels:      labels,
+			}})
+			require.NoError(t, err)
+			return res
+		}
+
+		res := query(PerfClusterDomClient + ":*")
+		require.Len(t, res, 1)
+		assert.Len(t, strings.Split(res[0].SampleInfo, ","), 13)
+		assert.Len(t, res[0].Value, 8)
+		for _, v := range res[0].Value {
+			assert.Len(t, strings.Split(v.Values, ","), 13)
+		}
+
+		// samples are stable across queries
+		assert.Equal(t, res, query(PerfClusterDomClient+":*"))
+
+		res = query(PerfHostDomClient+":*", "iopsRead", "latencyAvgRead")
+		require.Len(t, res, 3)
+		assert.Len(t, res[0].Value, 2)
+
+		host := strings.TrimPrefix(res[0].EntityRefId, PerfHostDomClient+":")
+		assert.Len(t, query(PerfHostDomClient+":"+host), 1)
+
+		assert.Len(t, query(PerfDiskGroup+":*"), 3)
+
+		_, err = vc.VsanPerfQueryPerf(ctx, &ref, []types.VsanPerfQuerySpec{{EntityRefId: "enoent:*"}})
+		assert.Error(t, err)
+
+		epoch := time.Time{}
+		_, err = vc.VsanPerfQueryPerf(ctx, &ref, []types.VsanPerfQuerySpec{{
+			EntityRefId: PerfClusterDomClient + ":*",
+			StartTime:   &epoch,
+			Interval:    1,
+		}})
+		assert.Error(t, err)
+
+		return nil
+	}, model)
+}
+
+func TestFileShare(t *testing.T) {
+	model := simulator.VPX()
+	model.Cluster = 2
+
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		vc, err := vsan.NewClient(ctx, c)
+		require.NoError(t, err)
+
+		finder := find.NewFinder(c)
+		var refs []vim.ManagedObjectReference
+		for _, name := range []string{"DC0_C0", "DC0_C1"} {
+			cluster, err := finder.ClusterComputeResource(ctx, name)
+			require.NoError(t, err)
+			refs = append(refs, cluster.Reference())
+
+			task, err := vc.EnableFileService(ctx, cluster.Reference(), types.VsanFileServiceConfig{})
+			require.NoError(t, err)
+			require.NoError(t, task.Wait(ctx))
+		}
+		a, b := refs[0], refs[1]
+
+		// more shares than the default page limit
+		for i := 0; i < defaultQueryLimit+2; i++ {
+			task, err := vc.CreateFileShare(ctx, a, types.VsanFileShareConfig{Name: fmt.Sprintf("share%02d", i)})
+			require.NoError(t, err)
+			require.NoError(t, task.Wait(ctx))
+		}
+
+		res, err := vc.QueryFileShares(ctx, a, types.VsanFileShareQuerySpec{})
+		require.NoError(t, err)
+		assert.Len(t, res.FileShares, defaultQueryLimit)
+		assert.EqualValues(t, defaultQueryLimit+2, res.TotalShareCount)
+		require.NotEmpty(t, res.NextOffset)
+
+		res, err = vc.QueryFileShares(ctx, a, types.VsanFileShareQuerySpec{Offset: res.NextOffset})
+		require.NoError(t, err)
+		assert.Len(t, res.FileShares, 2)
+		assert.Empty(t, res.NextOffset)
+
+		uuid := res.FileShares[1].Uuid
+
+		// shares are scoped to their cluster
+		task, err := vc.ReconfigureFileShare(ctx, b, uuid, types.VsanFileShareConfig{Quota: "1GB"}, false)
+		require.NoError(t, err)
+		assert.Error(t, task.Wait(ctx))
+
+		task, err = vc.RemoveFileShare(ctx, b, uuid, false)
+		require.NoError(t, err)
+		assert.Error(t, task.Wait(ctx))
+
+		task, err = vc.ReconfigureFileShare(ctx, a, uuid, types.VsanFileShareConfig{Quota: "1GB"}, false)
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		task, err = vc.RemoveFileShare(ctx, a, uuid, false)
+		require.NoError(t, err)
+		require.NoError(t, task.Wait(ctx))
+
+		return nil
+	}, model)
+}
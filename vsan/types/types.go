@@ -3300,6 +3300,27 @@ type VsanReconfigureFileShareResponse struct {
 	Returnval types.ManagedObjectReference `xml:"returnval"`
 }
 
+type VsanRemoveFileShare VsanRemoveFileShareRequestType
+
+func init() {
+	types.Add("vsan:VsanRemoveFileShare", reflect.TypeOf((*VsanRemoveFileShare)(nil)).Elem())
+}
+
+type VsanRemoveFileShareRequestType struct {
+	This      types.ManagedObjectReference  `xml:"_this"`
+	ShareUuid string                        `xml:"shareUuid"`
+	Cluster   *types.ManagedObjectReference `xml:"cluster,omitempty"`
+	Force     *bool                         `xml:"force"`
+}
+
+func init() {
+	types.Add("vsan:VsanRemoveFileShareRequestType", reflect.TypeOf((*VsanRemoveFileShareRequestType)(nil)).Elem())
+}
+
+type VsanRemoveFileShareResponse struct {
+	Returnval types.ManagedObjectReference `xml:"returnval"`
+}
+
 type VsanClusterQueryFileShares VsanClusterQueryFileSharesRequestType
 
 func init() {